@@ -2,13 +2,17 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"mq-toolkit/internal/config"
+	"mq-toolkit/internal/bootstrap"
 	"mq-toolkit/internal/database"
 	"mq-toolkit/internal/logger"
+	"mq-toolkit/internal/server"
 	"mq-toolkit/internal/service"
+	"mq-toolkit/internal/transfer"
 	"mq-toolkit/pkg/types"
 	"os"
+	"strings"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
@@ -19,6 +23,7 @@ type App struct {
 	db         *database.Database
 	logger     *logger.Logger
 	appService *service.AppService
+	server     *server.Server // 本地 HTTP API，未启用时为 nil
 }
 
 // NewApp creates a new App application struct
@@ -26,36 +31,6 @@ func NewApp() *App {
 	return &App{}
 }
 
-// getUserDataDir 获取用户数据目录
-func getUserDataDir() (string, error) {
-	// 获取用户配置目录
-	configDir, err := os.UserConfigDir()
-	if err != nil {
-		// 如果获取失败，使用用户主目录
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return "", fmt.Errorf("failed to get user directories: %w", err)
-		}
-		configDir = homeDir
-	}
-
-	// 创建应用专用目录 - 使用正确的路径分隔符
-	appDataDir := configDir + string(os.PathSeparator) + "MQToolkit"
-	return appDataDir, nil
-}
-
-// joinPath 跨平台路径拼接
-func joinPath(parts ...string) string {
-	if len(parts) == 0 {
-		return ""
-	}
-	result := parts[0]
-	for i := 1; i < len(parts); i++ {
-		result += string(os.PathSeparator) + parts[i]
-	}
-	return result
-}
-
 // startup is called when the app starts. The context is saved
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
@@ -69,53 +44,37 @@ func (a *App) startup(ctx context.Context) {
 		runtime.EventsEmit(a.ctx, "log:new", entry)
 	})
 
-	// 获取用户数据目录
-	userDataDir, err := getUserDataDir()
-	if err != nil {
-		a.logger.Error("App", fmt.Sprintf("Failed to get user data directory: %v", err))
-		userDataDir = "."
-	}
-
-	// 尝试从用户数据目录加载配置
-	configPath := joinPath(userDataDir, "config", "app.json")
-	cfg, err := config.Load(configPath)
+	// 服务事件同时推送给前端和 HTTP API 的 SSE 客户端
+	hub := server.NewHub()
+	rt, err := bootstrap.Start(ctx, a.logger, bootstrap.Options{
+		Emit: func(name string, data interface{}) {
+			runtime.EventsEmit(a.ctx, name, data)
+			hub.Emit(name, data)
+		},
+		MemoryFallback: true,
+	})
 	if err != nil {
-		a.logger.Error("App", fmt.Sprintf("Failed to load config from %s: %v", configPath, err))
-		// 使用用户数据目录的默认配置
-		cfg = &config.Config{
-			Database: config.DatabaseConfig{
-				Path: joinPath(userDataDir, "data", "mq-toolkit.db"),
-			},
-		}
-		a.logger.Info("App", fmt.Sprintf("Using default configuration with data dir: %s", userDataDir))
-	} else {
-		// 如果配置文件存在但数据库路径是相对路径，转换为绝对路径
-		if cfg.Database.Path == "data/mq-toolkit.db" || cfg.Database.Path == joinPath("data", "mq-toolkit.db") {
-			cfg.Database.Path = joinPath(userDataDir, "data", "mq-toolkit.db")
-			a.logger.Info("App", fmt.Sprintf("Updated database path to: %s", cfg.Database.Path))
-		}
+		a.logger.Error("App", fmt.Sprintf("Failed to initialize application: %v", err))
+		return
 	}
+	a.db = rt.DB
+	a.appService = rt.AppService
 
-	a.db, err = database.New(cfg.Database.Path)
-	if err != nil {
-		a.logger.Error("App", fmt.Sprintf("Failed to initialize database: %v", err))
-		a.db, err = database.New(":memory:")
-		if err != nil {
-			a.logger.Error("App", fmt.Sprintf("Failed to initialize memory database: %v", err))
-			return
+	if rt.Config.Server.Enabled {
+		if a.server, err = rt.StartServer(a.logger, hub, ""); err != nil {
+			a.logger.Error("App", fmt.Sprintf("Failed to start HTTP API: %v", err))
 		}
-		a.logger.Info("App", "Using in-memory database")
 	}
 
-	// 初始化应用服务, 传入ctx
-	a.appService = service.NewAppService(a.ctx, a.db, a.logger)
-
 	a.logger.Info("App", "Application started successfully")
 }
 
 // shutdown is called when the app terminates
 func (a *App) shutdown(ctx context.Context) {
 	a.logger.Info("App", "Application shutting down...")
+	if a.server != nil {
+		a.server.Close()
+	}
 	if a.appService != nil {
 		a.appService.Shutdown()
 	}
@@ -137,9 +96,24 @@ func (a *App) UpdateConnection(config *types.ConnectionConfig) error {
 	return a.appService.GetConfigService().UpdateConnection(a.ctx, config)
 }
 
-// DeleteConnection 删除连接配置
+// DeleteConnection 删除连接配置，同时关闭该连接缓存的客户端
 func (a *App) DeleteConnection(connectionID string) error {
-	return a.appService.GetConfigService().DeleteConnection(a.ctx, connectionID)
+	return a.appService.DeleteConnection(a.ctx, connectionID)
+}
+
+// ListConnectionStatuses 获取连接和订阅的健康状态
+func (a *App) ListConnectionStatuses() []*types.ConnectionStatus {
+	return a.appService.ListConnectionStatuses()
+}
+
+// ListBackends 获取支持的消息队列类型及其连接表单描述
+func (a *App) ListBackends() []types.BackendInfo {
+	return a.appService.ListBackends()
+}
+
+// GetCapabilities 获取连接支持的能力，前端据此显示对应的操作
+func (a *App) GetCapabilities(connectionID string) ([]types.Capability, error) {
+	return a.appService.Capabilities(a.ctx, connectionID)
 }
 
 // TestConnection 测试连接
@@ -186,6 +160,76 @@ func (a *App) ClearHistory() error {
 	return a.appService.GetHistoryService().ClearRecords(a.ctx)
 }
 
+// SearchMessages 检索已持久化的消费消息
+func (a *App) SearchMessages(query *types.MessageQuery) (*types.MessageSearchResult, error) {
+	return a.appService.GetMessageService().SearchMessages(a.ctx, query)
+}
+
+// DeleteMessages 删除符合条件的已存储消息
+func (a *App) DeleteMessages(query *types.MessageQuery) (int64, error) {
+	return a.appService.GetMessageService().DeleteMessages(a.ctx, query)
+}
+
+// StartReplay 开始在后台重放消息，返回任务ID
+func (a *App) StartReplay(req *types.ReplayRequest) (string, error) {
+	return a.appService.GetReplayService().StartReplay(req)
+}
+
+// StopReplay 停止重放任务
+func (a *App) StopReplay(replayID string) {
+	a.appService.GetReplayService().StopReplay(replayID)
+}
+
+// ListReplays 列出重放任务
+func (a *App) ListReplays() []types.ReplayStatus {
+	return a.appService.GetReplayService().ListReplays()
+}
+
+// StartBridge 启动跨连接桥接任务，返回任务ID
+func (a *App) StartBridge(req *types.BridgeRequest) (string, error) {
+	return a.appService.GetBridgeService().StartBridge(req)
+}
+
+// PauseBridge 暂停桥接任务
+func (a *App) PauseBridge(bridgeID string) error {
+	return a.appService.GetBridgeService().PauseBridge(bridgeID)
+}
+
+// ResumeBridge 恢复桥接任务
+func (a *App) ResumeBridge(bridgeID string) error {
+	return a.appService.GetBridgeService().ResumeBridge(bridgeID)
+}
+
+// StopBridge 停止桥接任务
+func (a *App) StopBridge(bridgeID string) {
+	a.appService.GetBridgeService().StopBridge(bridgeID)
+}
+
+// ListBridges 列出桥接任务及吞吐计数
+func (a *App) ListBridges() []types.BridgeStatus {
+	return a.appService.GetBridgeService().ListBridges()
+}
+
+// StartBenchmark 启动生产或消费压测，返回压测ID
+func (a *App) StartBenchmark(req *types.BenchmarkRequest) (string, error) {
+	return a.appService.GetBenchmarkService().StartBenchmark(req)
+}
+
+// StopBenchmark 提前结束压测
+func (a *App) StopBenchmark(benchmarkID string) {
+	a.appService.GetBenchmarkService().StopBenchmark(benchmarkID)
+}
+
+// ListBenchmarkReports 分页列出压测报告
+func (a *App) ListBenchmarkReports(limit, offset int) ([]*types.BenchmarkReport, error) {
+	return a.appService.GetBenchmarkService().ListReports(a.ctx, limit, offset)
+}
+
+// DeleteBenchmarkReport 删除压测报告
+func (a *App) DeleteBenchmarkReport(id string) error {
+	return a.appService.GetBenchmarkService().DeleteReport(a.ctx, id)
+}
+
 // GetLogs 获取当前日志
 func (a *App) GetLogs() []types.LogEntry {
 	return a.logger.GetEntries()
@@ -197,13 +241,118 @@ func (a *App) ListTemplates() ([]*types.MessageTemplate, error) {
 }
 
 // CreateTemplate creates a new message template
-func (a *App) CreateTemplate(name, content string) (*types.MessageTemplate, error) {
-	return a.appService.GetTemplateService().CreateTemplate(a.ctx, name, content)
+func (a *App) CreateTemplate(template *types.MessageTemplate) (*types.MessageTemplate, error) {
+	return a.appService.GetTemplateService().CreateTemplate(a.ctx, template)
 }
 
 // UpdateTemplate updates an existing message template
-func (a *App) UpdateTemplate(id, name, content string) error {
-	return a.appService.GetTemplateService().UpdateTemplate(a.ctx, id, name, content)
+func (a *App) UpdateTemplate(template *types.MessageTemplate) error {
+	return a.appService.GetTemplateService().UpdateTemplate(a.ctx, template)
+}
+
+// FindTemplates returns templates filtered by collection and tag
+func (a *App) FindTemplates(collectionID, tag string) ([]*types.MessageTemplate, error) {
+	return a.appService.GetTemplateService().FindTemplates(a.ctx, collectionID, tag)
+}
+
+// DuplicateTemplate copies a message template
+func (a *App) DuplicateTemplate(id string) (*types.MessageTemplate, error) {
+	return a.appService.GetTemplateService().DuplicateTemplate(a.ctx, id)
+}
+
+// ListCollections returns all template collections and folders
+func (a *App) ListCollections() ([]*types.TemplateCollection, error) {
+	return a.appService.GetTemplateService().ListCollections(a.ctx)
+}
+
+// CreateCollection creates a template collection or folder
+func (a *App) CreateCollection(collection *types.TemplateCollection) (*types.TemplateCollection, error) {
+	return a.appService.GetTemplateService().CreateCollection(a.ctx, collection)
+}
+
+// UpdateCollection renames or moves a template collection
+func (a *App) UpdateCollection(collection *types.TemplateCollection) error {
+	return a.appService.GetTemplateService().UpdateCollection(a.ctx, collection)
+}
+
+// DeleteCollection deletes a collection with its folders and templates
+func (a *App) DeleteCollection(id string) error {
+	return a.appService.GetTemplateService().DeleteCollection(a.ctx, id)
+}
+
+// DuplicateCollection deep copies a template collection
+func (a *App) DuplicateCollection(id string) (*types.TemplateCollection, error) {
+	return a.appService.GetTemplateService().DuplicateCollection(a.ctx, id)
+}
+
+// ExportCollection saves a collection as JSON, an empty ID exports all collections and templates
+func (a *App) ExportCollection(id string) (string, error) {
+	export, err := a.appService.GetTemplateService().ExportCollection(a.ctx, id)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode collection: %w", err)
+	}
+
+	filename := "templates.json"
+	if id != "" && len(export.Collections) > 0 {
+		filename = export.Collections[0].Name + ".json"
+	}
+	return a.SaveFile(filename, string(data))
+}
+
+// ImportCollection imports a collection JSON file under parentID, prompting for the file when filePath is empty
+func (a *App) ImportCollection(filePath, parentID string) (*types.CollectionImportResult, error) {
+	if filePath == "" {
+		selectedPath, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+			Title: "导入模板集合",
+			Filters: []runtime.FileFilter{
+				{
+					DisplayName: "JSON文件 (*.json)",
+					Pattern:     "*.json",
+				},
+				{
+					DisplayName: "所有文件 (*.*)",
+					Pattern:     "*.*",
+				},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to show open dialog: %w", err)
+		}
+		if selectedPath == "" {
+			return nil, fmt.Errorf("import cancelled by user")
+		}
+		filePath = selectedPath
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read collection file: %w", err)
+	}
+	var export types.CollectionExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("invalid collection file: %w", err)
+	}
+
+	result, err := a.appService.GetTemplateService().ImportCollection(a.ctx, &export, parentID)
+	if err != nil {
+		return nil, err
+	}
+	result.FilePath = filePath
+	return result, nil
+}
+
+// RenderTemplate renders a template with variables for preview
+func (a *App) RenderTemplate(req *types.RenderRequest) ([]string, error) {
+	return a.appService.GetTemplateService().Render(a.ctx, req)
+}
+
+// ProduceRendered renders a template N times and sends the results
+func (a *App) ProduceRendered(req *types.RenderProduceRequest) (*types.RenderProduceResult, error) {
+	return a.appService.ProduceRendered(a.ctx, req)
 }
 
 // DeleteTemplate deletes a message template
@@ -211,6 +360,105 @@ func (a *App) DeleteTemplate(id string) error {
 	return a.appService.GetTemplateService().DeleteTemplate(a.ctx, id)
 }
 
+// GetSecretStatus 获取凭据加密状态
+func (a *App) GetSecretStatus() *types.SecretStatus {
+	return a.appService.GetSecretStatus()
+}
+
+// UnlockSecrets 使用主密码解锁已保存的凭据
+func (a *App) UnlockSecrets(passphrase string) error {
+	return a.appService.UnlockSecrets(passphrase)
+}
+
+// RotateSecretKey 更换加密密钥，passphrase 为空时使用本地密钥文件
+func (a *App) RotateSecretKey(passphrase string) error {
+	return a.appService.RotateSecretKey(a.ctx, passphrase)
+}
+
+// ExportBundle 导出连接、模板和环境的共享包，格式为 json 或 yaml
+func (a *App) ExportBundle(req *types.BundleExportRequest) (string, error) {
+	bundle, err := a.appService.GetBundleService().Export(a.ctx, req)
+	if err != nil {
+		return "", err
+	}
+	data, err := service.EncodeBundle(bundle, req.Format)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode bundle: %w", err)
+	}
+
+	filename := "bundle.json"
+	if format := strings.ToLower(req.Format); format == "yaml" || format == "yml" {
+		filename = "bundle.yaml"
+	}
+	return a.SaveFile(filename, string(data))
+}
+
+// ImportBundle 导入共享包，FilePath 为空时弹出文件选择框
+func (a *App) ImportBundle(req *types.BundleImportRequest) (*types.BundleImportResult, error) {
+	if req.FilePath == "" {
+		selectedPath, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+			Title: "导入共享包",
+			Filters: []runtime.FileFilter{
+				{
+					DisplayName: "共享包 (*.json, *.yaml, *.yml)",
+					Pattern:     "*.json;*.yaml;*.yml",
+				},
+				{
+					DisplayName: "所有文件 (*.*)",
+					Pattern:     "*.*",
+				},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to show open dialog: %w", err)
+		}
+		if selectedPath == "" {
+			return nil, fmt.Errorf("import cancelled by user")
+		}
+		req.FilePath = selectedPath
+	}
+
+	data, err := os.ReadFile(req.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle file: %w", err)
+	}
+	bundle, err := service.DecodeBundle(data)
+	if err != nil {
+		return nil, err
+	}
+	return a.appService.GetBundleService().Import(a.ctx, bundle, req)
+}
+
+// ImportConnectionURI 从 amqp://、kafka://、rocketmq:// 形式的 URI 导入连接
+func (a *App) ImportConnectionURI(uri, name string, conflict types.ImportConflictMode) (*types.ImportItemResult, error) {
+	return a.appService.GetBundleService().ImportConnectionURI(a.ctx, uri, name, conflict)
+}
+
+// ListEnvironments 列出所有环境，密文变量以掩码返回
+func (a *App) ListEnvironments() ([]*types.Environment, error) {
+	return a.appService.GetEnvironmentService().ListEnvironments(a.ctx)
+}
+
+// CreateEnvironment 创建环境
+func (a *App) CreateEnvironment(env *types.Environment) error {
+	return a.appService.GetEnvironmentService().CreateEnvironment(a.ctx, env)
+}
+
+// UpdateEnvironment 更新环境
+func (a *App) UpdateEnvironment(env *types.Environment) error {
+	return a.appService.GetEnvironmentService().UpdateEnvironment(a.ctx, env)
+}
+
+// DeleteEnvironment 删除环境
+func (a *App) DeleteEnvironment(id string) error {
+	return a.appService.GetEnvironmentService().DeleteEnvironment(a.ctx, id)
+}
+
+// ActivateEnvironment 激活环境，id 为空时取消激活
+func (a *App) ActivateEnvironment(id string) error {
+	return a.appService.ActivateEnvironment(a.ctx, id)
+}
+
 // ListTopics 列出主题
 func (a *App) ListTopics(connectionID string) ([]types.TopicInfo, error) {
 	return a.appService.ListTopics(a.ctx, connectionID)
@@ -226,6 +474,166 @@ func (a *App) DeleteTopic(req *types.DeleteTopicRequest) error {
 	return a.appService.DeleteTopic(a.ctx, req)
 }
 
+// ListConsumerGroups 列出消费组
+func (a *App) ListConsumerGroups(connectionID string) ([]types.ConsumerGroup, error) {
+	return a.appService.ListConsumerGroups(a.ctx, connectionID)
+}
+
+// SeekGroup 按时间重置消费组位置
+func (a *App) SeekGroup(req *types.SeekGroupRequest) error {
+	return a.appService.SeekGroup(a.ctx, req)
+}
+
+// Browse 浏览主题中的消息，不移动任何消费位置
+func (a *App) Browse(req *types.BrowseRequest) ([]types.Message, error) {
+	return a.appService.Browse(a.ctx, req)
+}
+
+// GetStreamInfo 查看流详情（Redis Streams）
+func (a *App) GetStreamInfo(connectionID, stream string) (*types.StreamInfo, error) {
+	return a.appService.GetStreamInfo(a.ctx, connectionID, stream)
+}
+
+// TrimStream 裁剪流，返回删除的条目数
+func (a *App) TrimStream(req *types.TrimStreamRequest) (int64, error) {
+	return a.appService.TrimStream(a.ctx, req)
+}
+
+// CreateStreamGroup 创建流消费组
+func (a *App) CreateStreamGroup(req *types.StreamGroupRequest) error {
+	return a.appService.CreateStreamGroup(a.ctx, req)
+}
+
+// DeleteStreamGroup 删除流消费组
+func (a *App) DeleteStreamGroup(req *types.StreamGroupRequest) error {
+	return a.appService.DeleteStreamGroup(a.ctx, req)
+}
+
+// ListPendingEntries 列出流消费组中未确认的条目
+func (a *App) ListPendingEntries(connectionID, stream, group string, count int64) ([]types.PendingEntry, error) {
+	return a.appService.ListPendingEntries(a.ctx, connectionID, stream, group, count)
+}
+
+// ClaimPendingEntries 转移未确认的条目
+func (a *App) ClaimPendingEntries(req *types.ClaimPendingRequest) ([]string, error) {
+	return a.appService.ClaimPendingEntries(a.ctx, req)
+}
+
+// ListTenants 列出 Pulsar 租户
+func (a *App) ListTenants(connectionID string) ([]string, error) {
+	return a.appService.ListTenants(a.ctx, connectionID)
+}
+
+// ListNamespaces 列出租户下的命名空间
+func (a *App) ListNamespaces(connectionID, tenant string) ([]string, error) {
+	return a.appService.ListNamespaces(a.ctx, connectionID, tenant)
+}
+
+// CreateNamespace 创建命名空间
+func (a *App) CreateNamespace(req *types.NamespaceRequest) error {
+	return a.appService.CreateNamespace(a.ctx, req)
+}
+
+// DeleteNamespace 删除命名空间
+func (a *App) DeleteNamespace(req *types.NamespaceRequest) error {
+	return a.appService.DeleteNamespace(a.ctx, req)
+}
+
+// ListSubscriptions 列出主题上的订阅
+func (a *App) ListSubscriptions(connectionID, topic string) ([]types.SubscriptionInfo, error) {
+	return a.appService.ListSubscriptions(a.ctx, connectionID, topic)
+}
+
+// DeleteSubscription 删除订阅
+func (a *App) DeleteSubscription(req *types.SubscriptionRequest) error {
+	return a.appService.DeleteSubscription(a.ctx, req)
+}
+
+// ClearBacklog 清空订阅的积压消息
+func (a *App) ClearBacklog(req *types.SubscriptionRequest) error {
+	return a.appService.ClearBacklog(a.ctx, req)
+}
+
+// ResetSubscription 将订阅重置到指定时间
+func (a *App) ResetSubscription(req *types.ResetSubscriptionRequest) error {
+	return a.appService.ResetSubscription(a.ctx, req)
+}
+
+// ListAddresses 列出 AMQP 1.0 broker 上的地址
+func (a *App) ListAddresses(connectionID string) ([]types.AddressInfo, error) {
+	return a.appService.ListAddresses(a.ctx, connectionID)
+}
+
+// CreateAddress 创建地址
+func (a *App) CreateAddress(req *types.CreateAddressRequest) error {
+	return a.appService.CreateAddress(a.ctx, req)
+}
+
+// ListQueues 列出队列，address 为空时列出所有队列
+func (a *App) ListQueues(connectionID, address string) ([]types.QueueInfo, error) {
+	return a.appService.ListQueues(a.ctx, connectionID, address)
+}
+
+// CreateQueue 在地址上创建队列
+func (a *App) CreateQueue(req *types.CreateQueueRequest) error {
+	return a.appService.CreateQueue(a.ctx, req)
+}
+
+// DeleteQueue 删除队列
+func (a *App) DeleteQueue(connectionID, queue string) error {
+	return a.appService.DeleteQueue(a.ctx, connectionID, queue)
+}
+
+// GetQueueAttributes 查询 SQS 队列属性
+func (a *App) GetQueueAttributes(connectionID, queue string) (*types.QueueAttributes, error) {
+	return a.appService.GetQueueAttributes(a.ctx, connectionID, queue)
+}
+
+// SetQueueAttributes 修改队列属性
+func (a *App) SetQueueAttributes(req *types.SetQueueAttributesRequest) error {
+	return a.appService.SetQueueAttributes(a.ctx, req)
+}
+
+// PurgeQueue 清空队列
+func (a *App) PurgeQueue(connectionID, queue string) error {
+	return a.appService.PurgeQueue(a.ctx, connectionID, queue)
+}
+
+// ListDeadLetterSources 列出以该队列为死信队列的源队列
+func (a *App) ListDeadLetterSources(connectionID, queue string) ([]string, error) {
+	return a.appService.ListDeadLetterSources(a.ctx, connectionID, queue)
+}
+
+// ListExchanges 列出交换机（RabbitMQ）
+func (a *App) ListExchanges(connectionID string) ([]types.ExchangeInfo, error) {
+	return a.appService.ListExchanges(a.ctx, connectionID)
+}
+
+// CreateExchange 创建交换机
+func (a *App) CreateExchange(req *types.CreateExchangeRequest) error {
+	return a.appService.CreateExchange(a.ctx, req)
+}
+
+// DeleteExchange 删除交换机
+func (a *App) DeleteExchange(connectionID, exchange string) error {
+	return a.appService.DeleteExchange(a.ctx, connectionID, exchange)
+}
+
+// ListBindings 列出交换机到队列的绑定
+func (a *App) ListBindings(connectionID, exchange string) ([]types.BindingInfo, error) {
+	return a.appService.ListBindings(a.ctx, connectionID, exchange)
+}
+
+// BindQueue 将队列绑定到交换机
+func (a *App) BindQueue(req *types.BindingRequest) error {
+	return a.appService.BindQueue(a.ctx, req)
+}
+
+// UnbindQueue 解除队列与交换机的绑定
+func (a *App) UnbindQueue(req *types.BindingRequest) error {
+	return a.appService.UnbindQueue(a.ctx, req)
+}
+
 // SaveFile 保存文件，支持用户选择路径
 func (a *App) SaveFile(filename, content string) (string, error) {
 	// 添加mq-toolkit前缀
@@ -270,3 +678,69 @@ func (a *App) SaveFile(filename, content string) (string, error) {
 	a.logger.Info("App", fmt.Sprintf("File saved to: %s", selectedPath))
 	return selectedPath, nil
 }
+
+// ExportMessages 将已存储的消息导出为 NDJSON、CSV 或 kcat 格式，返回保存路径
+func (a *App) ExportMessages(req *types.ExportRequest) (string, error) {
+	format, err := transfer.ParseFormat(string(req.Format))
+	if err != nil {
+		return "", err
+	}
+	req.Format = format
+	ext := transfer.FileExtension(format)
+
+	options := runtime.SaveDialogOptions{
+		DefaultFilename: "mq-toolkit-messages" + ext,
+		Title:           "导出消息",
+		Filters: []runtime.FileFilter{
+			{
+				DisplayName: fmt.Sprintf("%s文件 (*%s)", strings.ToUpper(string(format)), ext),
+				Pattern:     "*" + ext,
+			},
+			{
+				DisplayName: "所有文件 (*.*)",
+				Pattern:     "*.*",
+			},
+		},
+	}
+
+	selectedPath, err := runtime.SaveFileDialog(a.ctx, options)
+	if err != nil {
+		return "", fmt.Errorf("failed to show save dialog: %w", err)
+	}
+	if selectedPath == "" {
+		return "", fmt.Errorf("export cancelled by user")
+	}
+
+	if _, err := a.appService.ExportMessages(a.ctx, req, selectedPath); err != nil {
+		return "", err
+	}
+	return selectedPath, nil
+}
+
+// ImportMessages 导入消息文件并批量发送，未指定文件路径时弹出选择对话框
+func (a *App) ImportMessages(req *types.ImportRequest) (*types.ImportResult, error) {
+	if req.FilePath == "" {
+		selectedPath, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+			Title: "导入消息",
+			Filters: []runtime.FileFilter{
+				{
+					DisplayName: "消息文件 (*.ndjson;*.jsonl;*.csv;*.txt)",
+					Pattern:     "*.ndjson;*.jsonl;*.csv;*.txt",
+				},
+				{
+					DisplayName: "所有文件 (*.*)",
+					Pattern:     "*.*",
+				},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to show open dialog: %w", err)
+		}
+		if selectedPath == "" {
+			return nil, fmt.Errorf("import cancelled by user")
+		}
+		req.FilePath = selectedPath
+	}
+
+	return a.appService.ImportMessages(a.ctx, req)
+}
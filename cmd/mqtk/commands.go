@@ -0,0 +1,1115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"mq-toolkit/internal/config"
+	"mq-toolkit/pkg/types"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// resolveConnection 按 ID 或名称查找已保存的连接，返回连接 ID
+func (c *cli) resolveConnection(ctx context.Context, ref string) (string, error) {
+	connections, err := c.app.GetConfigService().ListConnections(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, conn := range connections {
+		if conn.ID == ref {
+			return conn.ID, nil
+		}
+	}
+	var matched []string
+	for _, conn := range connections {
+		if conn.Name == ref {
+			matched = append(matched, conn.ID)
+		}
+	}
+	switch len(matched) {
+	case 0:
+		return "", fmt.Errorf("connection not found: %s", ref)
+	case 1:
+		return matched[0], nil
+	default:
+		return "", fmt.Errorf("connection name %q is ambiguous, use one of the IDs: %s", ref, strings.Join(matched, ", "))
+	}
+}
+
+// printJSON 以缩进的 JSON 输出 v
+func (c *cli) printJSON(v interface{}) error {
+	encoder := json.NewEncoder(c.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// printTable 输出以制表符分隔的表格
+func (c *cli) printTable(header string, rows [][]string) error {
+	w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, header)
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+func (c *cli) connList(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("conn list", flag.ContinueOnError)
+	if _, err := parseFlags(fs, args, 0, ""); err != nil {
+		return err
+	}
+
+	connections, err := c.app.GetConfigService().ListConnections(ctx)
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(connections)
+	}
+	rows := make([][]string, 0, len(connections))
+	for _, conn := range connections {
+		rows = append(rows, []string{conn.ID, conn.Name, string(conn.Type), fmt.Sprintf("%s:%d", conn.Host, conn.Port)})
+	}
+	return c.printTable("ID\tNAME\tTYPE\tADDRESS", rows)
+}
+
+func (c *cli) connTypes(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("conn types", flag.ContinueOnError)
+	if _, err := parseFlags(fs, args, 0, ""); err != nil {
+		return err
+	}
+
+	backends := c.app.ListBackends()
+	if c.json {
+		return c.printJSON(backends)
+	}
+	rows := make([][]string, 0, len(backends))
+	for _, backend := range backends {
+		capabilities := make([]string, 0, len(backend.Capabilities))
+		for _, capability := range backend.Capabilities {
+			capabilities = append(capabilities, string(capability))
+		}
+		rows = append(rows, []string{string(backend.Type), backend.DisplayName, fmt.Sprint(backend.DefaultPort), strings.Join(capabilities, ",")})
+	}
+	return c.printTable("TYPE\tNAME\tPORT\tCAPABILITIES", rows)
+}
+
+func (c *cli) connTest(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("conn test", flag.ContinueOnError)
+	refs, err := parseFlags(fs, args, 1, "<connection>...")
+	if err != nil {
+		return err
+	}
+
+	type testOutput struct {
+		Connection string `json:"connection"`
+		*types.TestResult
+	}
+	results := make([]testOutput, 0, len(refs))
+	failed := 0
+	for _, ref := range refs {
+		connectionID, err := c.resolveConnection(ctx, ref)
+		if err != nil {
+			return err
+		}
+		result := c.app.TestConnection(ctx, connectionID)
+		if !result.Success {
+			failed++
+		}
+		results = append(results, testOutput{Connection: ref, TestResult: result})
+	}
+
+	if c.json {
+		if err := c.printJSON(results); err != nil {
+			return err
+		}
+	} else {
+		rows := make([][]string, 0, len(results))
+		for _, r := range results {
+			status := "OK"
+			if !r.Success {
+				status = "FAILED"
+			}
+			rows = append(rows, []string{r.Connection, status, fmt.Sprintf("%dms", r.Latency), r.Message})
+		}
+		if err := c.printTable("CONNECTION\tSTATUS\tLATENCY\tMESSAGE", rows); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d connection test(s) failed", failed, len(results))
+	}
+	return nil
+}
+
+func (c *cli) topicsList(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("topics list", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 1, "<connection>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	topics, err := c.app.ListTopics(ctx, connectionID)
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(topics)
+	}
+	rows := make([][]string, 0, len(topics))
+	for _, topic := range topics {
+		rows = append(rows, []string{topic.Name, fmt.Sprint(topic.Partitions), fmt.Sprint(topic.Replicas)})
+	}
+	return c.printTable("NAME\tPARTITIONS\tREPLICAS", rows)
+}
+
+func (c *cli) topicsCreate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("topics create", flag.ContinueOnError)
+	partitions := fs.Int("partitions", 1, "number of partitions")
+	replicas := fs.Int("replicas", 1, "replication factor")
+	positional, err := parseFlags(fs, args, 2, "<connection> <topic>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return c.app.CreateTopic(ctx, &types.CreateTopicRequest{
+		ConnectionID: connectionID,
+		Topic:        positional[1],
+		Partitions:   int32(*partitions),
+		Replicas:     int16(*replicas),
+	})
+}
+
+func (c *cli) topicsDelete(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("topics delete", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 2, "<connection> <topic>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return c.app.DeleteTopic(ctx, &types.DeleteTopicRequest{
+		ConnectionID: connectionID,
+		Topic:        positional[1],
+	})
+}
+
+func (c *cli) groups(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("groups", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 1, "<connection>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	groups, err := c.app.ListConsumerGroups(ctx, connectionID)
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(groups)
+	}
+	rows := make([][]string, 0, len(groups))
+	for _, group := range groups {
+		rows = append(rows, []string{group.ID, fmt.Sprint(len(group.Members)), strings.Join(group.Topics, ",")})
+	}
+	return c.printTable("GROUP\tMEMBERS\tTOPICS", rows)
+}
+
+func (c *cli) seek(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("seek", flag.ContinueOnError)
+	at := fs.String("time", "", "RFC 3339 time to move the group to (required)")
+	positional, err := parseFlags(fs, args, 3, "<connection> <topic> <group> --time T")
+	if err != nil {
+		return err
+	}
+	if *at == "" {
+		return &exitError{code: 2, err: fmt.Errorf("--time is required")}
+	}
+	t, err := time.Parse(time.RFC3339, *at)
+	if err != nil {
+		return &exitError{code: 2, err: fmt.Errorf("seek: invalid --time: %w", err)}
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return c.app.SeekGroup(ctx, &types.SeekGroupRequest{
+		ConnectionID: connectionID,
+		Topic:        positional[1],
+		Group:        positional[2],
+		Timestamp:    t,
+	})
+}
+
+func (c *cli) browse(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("browse", flag.ContinueOnError)
+	limit := fs.Int("limit", 0, "maximum number of messages, 0 for the default")
+	positional, err := parseFlags(fs, args, 2, "<connection> <topic>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	messages, err := c.app.Browse(ctx, &types.BrowseRequest{
+		ConnectionID: connectionID,
+		Topic:        positional[1],
+		Limit:        *limit,
+	})
+	if err != nil {
+		return err
+	}
+	// 与 consume 一致，每行输出一条 JSON 消息
+	encoder := json.NewEncoder(c.stdout)
+	for i := range messages {
+		if err := encoder.Encode(&messages[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *cli) streamsInfo(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("streams info", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 2, "<connection> <stream>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	info, err := c.app.GetStreamInfo(ctx, connectionID, positional[1])
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(info)
+	}
+	fmt.Fprintf(c.stdout, "Stream %s: %d entries, first %s, last %s\n", info.Name, info.Length, orDash(info.FirstEntryID), orDash(info.LastEntryID))
+	rows := make([][]string, 0, len(info.Groups))
+	for _, group := range info.Groups {
+		consumers := make([]string, 0, len(group.Consumers))
+		for _, consumer := range group.Consumers {
+			consumers = append(consumers, fmt.Sprintf("%s(%d)", consumer.Name, consumer.Pending))
+		}
+		rows = append(rows, []string{group.Name, fmt.Sprint(group.Pending), fmt.Sprint(group.Lag), group.LastDeliveredID, strings.Join(consumers, ",")})
+	}
+	return c.printTable("GROUP\tPENDING\tLAG\tLAST DELIVERED\tCONSUMERS", rows)
+}
+
+func (c *cli) streamsTrim(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("streams trim", flag.ContinueOnError)
+	maxLen := fs.Int64("maxlen", -1, "number of newest entries to keep (required)")
+	positional, err := parseFlags(fs, args, 2, "<connection> <stream> --maxlen N")
+	if err != nil {
+		return err
+	}
+	if *maxLen < 0 {
+		return &exitError{code: 2, err: fmt.Errorf("--maxlen is required")}
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	removed, err := c.app.TrimStream(ctx, &types.TrimStreamRequest{
+		ConnectionID: connectionID,
+		Stream:       positional[1],
+		MaxLen:       *maxLen,
+	})
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(map[string]int64{"removed": removed})
+	}
+	fmt.Fprintf(c.stdout, "removed %d entries\n", removed)
+	return nil
+}
+
+func (c *cli) streamsGroupCreate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("streams group-create", flag.ContinueOnError)
+	start := fs.String("start", "$", "entry ID to start after: $ for new entries only, 0 for the whole stream")
+	positional, err := parseFlags(fs, args, 3, "<connection> <stream> <group>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return c.app.CreateStreamGroup(ctx, &types.StreamGroupRequest{
+		ConnectionID: connectionID,
+		Stream:       positional[1],
+		Group:        positional[2],
+		StartID:      *start,
+	})
+}
+
+func (c *cli) streamsGroupDelete(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("streams group-delete", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 3, "<connection> <stream> <group>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return c.app.DeleteStreamGroup(ctx, &types.StreamGroupRequest{
+		ConnectionID: connectionID,
+		Stream:       positional[1],
+		Group:        positional[2],
+	})
+}
+
+func (c *cli) streamsPending(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("streams pending", flag.ContinueOnError)
+	count := fs.Int64("count", 100, "maximum number of entries to list")
+	positional, err := parseFlags(fs, args, 3, "<connection> <stream> <group>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	entries, err := c.app.ListPendingEntries(ctx, connectionID, positional[1], positional[2], *count)
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(entries)
+	}
+	rows := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		idle := (time.Duration(entry.Idle) * time.Millisecond).Round(time.Second)
+		rows = append(rows, []string{entry.ID, entry.Consumer, idle.String(), fmt.Sprint(entry.DeliveryCount)})
+	}
+	return c.printTable("ID\tCONSUMER\tIDLE\tDELIVERIES", rows)
+}
+
+func (c *cli) streamsClaim(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("streams claim", flag.ContinueOnError)
+	minIdle := fs.Duration("min-idle", 0, "only claim entries idle for at least this long")
+	positional, err := parseFlags(fs, args, 5, "<connection> <stream> <group> <consumer> <id>...")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	claimed, err := c.app.ClaimPendingEntries(ctx, &types.ClaimPendingRequest{
+		ConnectionID: connectionID,
+		Stream:       positional[1],
+		Group:        positional[2],
+		Consumer:     positional[3],
+		MinIdle:      minIdle.Milliseconds(),
+		IDs:          positional[4:],
+	})
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(claimed)
+	}
+	for _, id := range claimed {
+		fmt.Fprintln(c.stdout, id)
+	}
+	return nil
+}
+
+func (c *cli) namespacesTenants(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("namespaces tenants", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 1, "<connection>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	tenants, err := c.app.ListTenants(ctx, connectionID)
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(tenants)
+	}
+	for _, tenant := range tenants {
+		fmt.Fprintln(c.stdout, tenant)
+	}
+	return nil
+}
+
+func (c *cli) namespacesList(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("namespaces list", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 2, "<connection> <tenant>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	namespaces, err := c.app.ListNamespaces(ctx, connectionID, positional[1])
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(namespaces)
+	}
+	for _, namespace := range namespaces {
+		fmt.Fprintln(c.stdout, namespace)
+	}
+	return nil
+}
+
+func (c *cli) namespacesCreate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("namespaces create", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 2, "<connection> <tenant/namespace>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return c.app.CreateNamespace(ctx, &types.NamespaceRequest{
+		ConnectionID: connectionID,
+		Namespace:    positional[1],
+	})
+}
+
+func (c *cli) namespacesDelete(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("namespaces delete", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 2, "<connection> <tenant/namespace>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return c.app.DeleteNamespace(ctx, &types.NamespaceRequest{
+		ConnectionID: connectionID,
+		Namespace:    positional[1],
+	})
+}
+
+func (c *cli) subsList(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("subs list", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 2, "<connection> <topic>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	subs, err := c.app.ListSubscriptions(ctx, connectionID, positional[1])
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(subs)
+	}
+	rows := make([][]string, 0, len(subs))
+	for _, sub := range subs {
+		rows = append(rows, []string{sub.Name, orDash(sub.Type), fmt.Sprint(sub.Backlog), strings.Join(sub.Consumers, ",")})
+	}
+	return c.printTable("SUBSCRIPTION\tTYPE\tBACKLOG\tCONSUMERS", rows)
+}
+
+func (c *cli) subsDelete(ctx context.Context, args []string) error {
+	return c.subscriptionCommand(ctx, "subs delete", args, c.app.DeleteSubscription)
+}
+
+func (c *cli) subsClear(ctx context.Context, args []string) error {
+	return c.subscriptionCommand(ctx, "subs clear", args, c.app.ClearBacklog)
+}
+
+// subscriptionCommand 执行只需要主题和订阅名的订阅命令
+func (c *cli) subscriptionCommand(ctx context.Context, name string, args []string, fn func(context.Context, *types.SubscriptionRequest) error) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 3, "<connection> <topic> <subscription>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return fn(ctx, &types.SubscriptionRequest{
+		ConnectionID: connectionID,
+		Topic:        positional[1],
+		Subscription: positional[2],
+	})
+}
+
+func (c *cli) subsReset(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("subs reset", flag.ContinueOnError)
+	at := fs.String("time", "", "RFC 3339 time to reset the cursor to (required)")
+	positional, err := parseFlags(fs, args, 3, "<connection> <topic> <subscription> --time T")
+	if err != nil {
+		return err
+	}
+	if *at == "" {
+		return &exitError{code: 2, err: fmt.Errorf("--time is required")}
+	}
+	t, err := time.Parse(time.RFC3339, *at)
+	if err != nil {
+		return &exitError{code: 2, err: fmt.Errorf("subs reset: invalid --time: %w", err)}
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return c.app.ResetSubscription(ctx, &types.ResetSubscriptionRequest{
+		ConnectionID: connectionID,
+		Topic:        positional[1],
+		Subscription: positional[2],
+		Timestamp:    t,
+	})
+}
+
+func (c *cli) addressesList(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("addresses list", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 1, "<connection>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	addresses, err := c.app.ListAddresses(ctx, connectionID)
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(addresses)
+	}
+	rows := make([][]string, 0, len(addresses))
+	for _, address := range addresses {
+		rows = append(rows, []string{address.Name, orDash(strings.Join(address.RoutingTypes, ",")), fmt.Sprint(address.QueueCount)})
+	}
+	return c.printTable("ADDRESS\tROUTING\tQUEUES", rows)
+}
+
+func (c *cli) addressesCreate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("addresses create", flag.ContinueOnError)
+	routing := fs.String("routing", "anycast", "routing type: anycast or multicast")
+	positional, err := parseFlags(fs, args, 2, "<connection> <address>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return c.app.CreateAddress(ctx, &types.CreateAddressRequest{
+		ConnectionID: connectionID,
+		Address:      positional[1],
+		RoutingType:  *routing,
+	})
+}
+
+func (c *cli) queuesList(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("queues list", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 1, "<connection> [address]")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	address := ""
+	if len(positional) == 2 {
+		address = positional[1]
+	}
+
+	queues, err := c.app.ListQueues(ctx, connectionID, address)
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(queues)
+	}
+	rows := make([][]string, 0, len(queues))
+	for _, queue := range queues {
+		rows = append(rows, []string{queue.Name, queue.Address, orDash(queue.RoutingType), fmt.Sprint(queue.MessageCount), fmt.Sprint(queue.ConsumerCount), fmt.Sprint(queue.Durable)})
+	}
+	return c.printTable("QUEUE\tADDRESS\tROUTING\tMESSAGES\tCONSUMERS\tDURABLE", rows)
+}
+
+func (c *cli) queuesCreate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("queues create", flag.ContinueOnError)
+	routing := fs.String("routing", "anycast", "routing type: anycast or multicast")
+	nonDurable := fs.Bool("non-durable", false, "create a non-durable queue")
+	positional, err := parseFlags(fs, args, 3, "<connection> <address> <queue>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return c.app.CreateQueue(ctx, &types.CreateQueueRequest{
+		ConnectionID: connectionID,
+		Address:      positional[1],
+		Queue:        positional[2],
+		RoutingType:  *routing,
+		Durable:      !*nonDurable,
+	})
+}
+
+func (c *cli) queuesDelete(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("queues delete", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 2, "<connection> <queue>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return c.app.DeleteQueue(ctx, connectionID, positional[1])
+}
+
+func (c *cli) queuesAttributes(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("queues attributes", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 2, "<connection> <queue>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	attributes, err := c.app.GetQueueAttributes(ctx, connectionID, positional[1])
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(attributes)
+	}
+	names := make([]string, 0, len(attributes.Attributes))
+	for name := range attributes.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, []string{name, attributes.Attributes[name]})
+	}
+	return c.printTable("ATTRIBUTE\tVALUE", rows)
+}
+
+func (c *cli) queuesSet(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("queues set", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 3, "<connection> <queue> <name=value>...")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	attributes := headerFlags{}
+	for _, arg := range positional[2:] {
+		if err := attributes.Set(arg); err != nil {
+			return err
+		}
+	}
+
+	return c.app.SetQueueAttributes(ctx, &types.SetQueueAttributesRequest{
+		ConnectionID: connectionID,
+		Queue:        positional[1],
+		Attributes:   attributes,
+	})
+}
+
+func (c *cli) queuesPurge(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("queues purge", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 2, "<connection> <queue>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return c.app.PurgeQueue(ctx, connectionID, positional[1])
+}
+
+func (c *cli) queuesDeadLetterSources(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("queues dlq-sources", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 2, "<connection> <queue>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	sources, err := c.app.ListDeadLetterSources(ctx, connectionID, positional[1])
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(sources)
+	}
+	rows := make([][]string, 0, len(sources))
+	for _, source := range sources {
+		rows = append(rows, []string{source})
+	}
+	return c.printTable("SOURCE QUEUE", rows)
+}
+
+func (c *cli) exchangesList(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("exchanges list", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 1, "<connection>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	exchanges, err := c.app.ListExchanges(ctx, connectionID)
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(exchanges)
+	}
+	rows := make([][]string, 0, len(exchanges))
+	for _, exchange := range exchanges {
+		rows = append(rows, []string{exchange.Name, exchange.Kind, fmt.Sprint(exchange.Durable), fmt.Sprint(exchange.AutoDelete), fmt.Sprint(exchange.Internal)})
+	}
+	return c.printTable("EXCHANGE\tKIND\tDURABLE\tAUTO-DELETE\tINTERNAL", rows)
+}
+
+func (c *cli) exchangesCreate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("exchanges create", flag.ContinueOnError)
+	kind := fs.String("kind", "direct", "exchange type: direct, fanout, topic, headers or a plugin type")
+	durable := fs.Bool("durable", true, "survive broker restarts")
+	positional, err := parseFlags(fs, args, 2, "<connection> <exchange>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return c.app.CreateExchange(ctx, &types.CreateExchangeRequest{
+		ConnectionID: connectionID,
+		Exchange:     positional[1],
+		Kind:         *kind,
+		Durable:      *durable,
+	})
+}
+
+func (c *cli) exchangesDelete(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("exchanges delete", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 2, "<connection> <exchange>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return c.app.DeleteExchange(ctx, connectionID, positional[1])
+}
+
+func (c *cli) exchangesBindings(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("exchanges bindings", flag.ContinueOnError)
+	positional, err := parseFlags(fs, args, 2, "<connection> <exchange>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	bindings, err := c.app.ListBindings(ctx, connectionID, positional[1])
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(bindings)
+	}
+	rows := make([][]string, 0, len(bindings))
+	for _, binding := range bindings {
+		rows = append(rows, []string{binding.Queue, orDash(binding.RoutingKey)})
+	}
+	return c.printTable("QUEUE\tROUTING KEY", rows)
+}
+
+func (c *cli) exchangesBind(ctx context.Context, args []string) error {
+	return c.bindingCommand(ctx, "exchanges bind", args, c.app.BindQueue)
+}
+
+func (c *cli) exchangesUnbind(ctx context.Context, args []string) error {
+	return c.bindingCommand(ctx, "exchanges unbind", args, c.app.UnbindQueue)
+}
+
+// bindingCommand 解析 <connection> <exchange> <queue> --key 后执行绑定操作
+func (c *cli) bindingCommand(ctx context.Context, name string, args []string, fn func(context.Context, *types.BindingRequest) error) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	key := fs.String("key", "", "routing key")
+	positional, err := parseFlags(fs, args, 3, "<connection> <exchange> <queue>")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return fn(ctx, &types.BindingRequest{
+		ConnectionID: connectionID,
+		Exchange:     positional[1],
+		Queue:        positional[2],
+		RoutingKey:   *key,
+	})
+}
+
+// orDash 空字符串显示为 -
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// headerFlags 可重复的 key=value 消息头参数
+type headerFlags map[string]string
+
+func (h headerFlags) String() string { return fmt.Sprint(map[string]string(h)) }
+
+func (h headerFlags) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("header must be key=value: %s", value)
+	}
+	h[key] = val
+	return nil
+}
+
+func (c *cli) produce(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("produce", flag.ContinueOnError)
+	key := fs.String("key", "", "message key")
+	file := fs.String("file", "", "read the message value from a file, - for stdin")
+	lines := fs.Bool("lines", false, "send each non-empty input line as a separate message")
+	headers := headerFlags{}
+	fs.Var(headers, "header", "message header as key=value, repeatable")
+	positional, err := parseFlags(fs, args, 2, "<connection> <topic> [value...]")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	var value string
+	switch {
+	case len(positional) > 2:
+		if *file != "" {
+			return &exitError{code: 2, err: fmt.Errorf("produce: value arguments and --file are mutually exclusive")}
+		}
+		value = strings.Join(positional[2:], " ")
+	case *file != "" && *file != "-":
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return fmt.Errorf("failed to read message file: %w", err)
+		}
+		value = string(data)
+	default:
+		data, err := io.ReadAll(c.stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+		value = string(data)
+	}
+
+	values := []string{value}
+	if *lines {
+		values = values[:0]
+		for _, line := range strings.Split(value, "\n") {
+			if line = strings.TrimRight(line, "\r"); line != "" {
+				values = append(values, line)
+			}
+		}
+	}
+
+	for i, v := range values {
+		err := c.app.ProduceMessage(ctx, &types.ProduceRequest{
+			ConnectionID: connectionID,
+			Topic:        positional[1],
+			Key:          *key,
+			Value:        v,
+			Headers:      headers,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to send message %d of %d: %w", i+1, len(values), err)
+		}
+	}
+	if c.json {
+		return c.printJSON(map[string]int{"sent": len(values)})
+	}
+	return nil
+}
+
+func (c *cli) consume(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("consume", flag.ContinueOnError)
+	group := fs.String("group", "", "consumer group, defaults to the connection's group")
+	fromBeginning := fs.Bool("from-beginning", false, "start from the earliest offset")
+	startSeq := fs.Uint64("start-seq", 0, "replay from this stream sequence (NATS JetStream)")
+	startTime := fs.String("start-time", "", "replay from this RFC 3339 time (NATS JetStream, Redis Streams, Pulsar)")
+	startID := fs.String("start-id", "", "browse from this message ID ledger:entry[:partition[:batch]] (Pulsar, without --group)")
+	count := fs.Int("count", 0, "exit after receiving this many messages, 0 for no limit")
+	timeout := fs.Duration("timeout", 0, "exit after this duration, 0 for no limit; with --count, not receiving enough messages in time is an error")
+	positional, err := parseFlags(fs, args, 2, "<connection> <topic>...")
+	if err != nil {
+		return err
+	}
+	connectionID, err := c.resolveConnection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	req := &types.ConsumeRequest{
+		ConnectionID:   connectionID,
+		Topics:         positional[1:],
+		GroupID:        *group,
+		AutoCommit:     true,
+		FromBeginning:  *fromBeginning,
+		StartSequence:  *startSeq,
+		StartMessageID: *startID,
+	}
+	if *startTime != "" {
+		t, err := time.Parse(time.RFC3339, *startTime)
+		if err != nil {
+			return &exitError{code: 2, err: fmt.Errorf("consume: invalid --start-time: %w", err)}
+		}
+		req.StartTime = &t
+	}
+	defer close(c.done)
+
+	subscriptionID, err := c.app.StartConsuming(req)
+	if err != nil {
+		return err
+	}
+	defer c.app.StopConsuming(subscriptionID)
+
+	var deadline <-chan time.Time
+	if *timeout > 0 {
+		timer := time.NewTimer(*timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	encoder := json.NewEncoder(c.stdout)
+	received := 0
+	for *count == 0 || received < *count {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-deadline:
+			if *count > 0 {
+				return fmt.Errorf("timed out after %s with %d of %d message(s)", *timeout, received, *count)
+			}
+			return nil
+		case ev := <-c.events:
+			switch ev.name {
+			case "consumer:error":
+				if detail, ok := ev.data.(map[string]string); ok {
+					return fmt.Errorf("consumer stopped: %s", detail["error"])
+				}
+				return fmt.Errorf("consumer stopped: %v", ev.data)
+			case "message:received":
+				if err := encoder.Encode(ev.data); err != nil {
+					return err
+				}
+				received++
+			}
+		}
+	}
+	return nil
+}
+
+func (c *cli) serve(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", "", "listen address, defaults to server.addr in config/app.json or "+config.DefaultServerAddr)
+	if _, err := parseFlags(fs, args, 0, ""); err != nil {
+		return err
+	}
+	// consume 未使用，丢弃转发给它的事件
+	close(c.done)
+
+	srv, err := c.rt.StartServer(c.log, c.hub, *addr)
+	if err != nil {
+		return err
+	}
+	defer srv.Close()
+
+	fmt.Fprintf(os.Stderr, "HTTP API listening on http://%s\n", srv.Addr())
+	if c.rt.Config.Server.Token == "" {
+		fmt.Fprintf(os.Stderr, "API token is stored in %s\n", c.rt.TokenPath())
+	}
+	<-ctx.Done()
+	return nil
+}
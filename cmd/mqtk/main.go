@@ -0,0 +1,309 @@
+// Command mqtk 是 MQToolkit 的命令行版本
+//
+// 与桌面端共用 AppService 和同一个 SQLite 连接库，可以在终端或 CI 中使用已保存的连接。
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"mq-toolkit/internal/bootstrap"
+	"mq-toolkit/internal/logger"
+	"mq-toolkit/internal/server"
+	"mq-toolkit/internal/service"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const usage = `Usage: mqtk [global flags] <command> [args]
+
+Commands:
+  conn list                              list saved connections
+  conn test <connection>...              test saved connections
+  conn types                             list supported connection types
+  produce <connection> <topic> [value]   send a message from args, --file or stdin
+  consume <connection> <topic>...        print messages to stdout as JSON lines
+  topics list <connection>               list topics
+  topics create <connection> <topic>     create a topic
+  topics delete <connection> <topic>     delete a topic
+  groups <connection>                    list consumer groups
+  seek <connection> <topic> <group>      move a consumer group to --time
+  browse <connection> <topic>            print messages without consuming them
+  streams info <connection> <stream>     show stream groups and consumers (Redis)
+  streams trim <connection> <stream>     trim a stream to --maxlen entries
+  streams group-create|group-delete <connection> <stream> <group>
+  streams pending <connection> <stream> <group>
+                                         list unacknowledged entries
+  streams claim <connection> <stream> <group> <consumer> <id>...
+                                         move pending entries to another consumer
+  namespaces tenants <connection>        list tenants (Pulsar)
+  namespaces list <connection> <tenant>  list namespaces of a tenant
+  namespaces create|delete <connection> <tenant/namespace>
+  subs list <connection> <topic>         list subscriptions and backlog (Pulsar)
+  subs delete|clear <connection> <topic> <subscription>
+                                         delete a subscription or skip its backlog
+  subs reset <connection> <topic> <subscription>
+                                         reset the cursor to --time
+  addresses list <connection>            list addresses (AMQP 1.0 / Artemis)
+  addresses create <connection> <address>
+  queues list <connection> [address]     list queues with message and consumer counts
+  queues create <connection> <address> <queue>
+  queues delete <connection> <queue>
+  queues attributes <connection> <queue>
+                                         show queue attributes and redrive policy (SQS)
+  queues set <connection> <queue> <name=value>...
+                                         change queue attributes
+  queues purge <connection> <queue>      delete all messages in a queue
+  queues dlq-sources <connection> <queue>
+                                         list queues redriving to a dead-letter queue
+  exchanges list <connection>            list exchanges (RabbitMQ)
+  exchanges create|delete <connection> <exchange>
+  exchanges bindings <connection> <exchange>
+                                         list queues bound to an exchange
+  exchanges bind|unbind <connection> <exchange> <queue>
+                                         bind a queue with --key or remove the binding
+  serve                                  run the local HTTP API until interrupted
+
+Connections are referenced by ID or name. Run "mqtk <command> -h" for command flags.
+
+Global flags:
+`
+
+// cli 命令行运行时
+type cli struct {
+	app    *service.AppService
+	rt     *bootstrap.Runtime
+	log    *logger.Logger
+	hub    *server.Hub // serve 使用
+	stdin  io.Reader
+	stdout io.Writer
+	json   bool
+	events chan event    // 服务推送的事件，consume 使用
+	done   chan struct{} // consume 结束后关闭，之后的事件被丢弃
+}
+
+// event 服务推送的事件
+type event struct {
+	name string
+	data interface{}
+}
+
+// exitError 指定退出码的错误
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	global := flag.NewFlagSet("mqtk", flag.ContinueOnError)
+	global.Usage = func() {
+		fmt.Fprint(global.Output(), usage)
+		global.PrintDefaults()
+	}
+	dataDir := global.String("data-dir", os.Getenv("MQTK_DATA_DIR"), "user data directory, defaults to the desktop app's (env MQTK_DATA_DIR)")
+	jsonOutput := global.Bool("json", false, "print results as JSON")
+	verbose := global.Bool("v", false, "write logs to stderr")
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if global.NArg() == 0 {
+		global.Usage()
+		return 2
+	}
+
+	log := logger.New(logger.LevelInfo, nil)
+	if *verbose {
+		log.SetOutput(os.Stderr)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	c := &cli{
+		log:    log,
+		hub:    server.NewHub(),
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		json:   *jsonOutput,
+		events: make(chan event, 64),
+		done:   make(chan struct{}),
+	}
+	rt, err := bootstrap.Start(ctx, log, bootstrap.Options{
+		DataDir: *dataDir,
+		Emit:    c.emit,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mqtk: %v\n", err)
+		return 1
+	}
+	defer rt.Close()
+	c.rt, c.app = rt, rt.AppService
+
+	if passphrase := os.Getenv("MQTK_PASSPHRASE"); passphrase != "" && rt.Secrets.Locked() {
+		if err := c.app.UnlockSecrets(passphrase); err != nil {
+			fmt.Fprintf(os.Stderr, "mqtk: %v\n", err)
+			return 1
+		}
+	}
+
+	if err := c.dispatch(ctx, global.Args()); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "mqtk: %v\n", err)
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			return exitErr.code
+		}
+		return 1
+	}
+	return 0
+}
+
+// emit 将事件分发给 HTTP API 的订阅者，并转发 consume 需要的事件，输出跟不上时阻塞消费以免丢失消息
+func (c *cli) emit(name string, data interface{}) {
+	c.hub.Emit(name, data)
+	if name != "message:received" && name != "consumer:error" {
+		return
+	}
+	select {
+	case c.events <- event{name: name, data: data}:
+	case <-c.done:
+	}
+}
+
+func (c *cli) dispatch(ctx context.Context, args []string) error {
+	command, rest := args[0], args[1:]
+	switch command {
+	case "conn":
+		return c.subcommand(ctx, "conn", rest, map[string]func(context.Context, []string) error{
+			"list":  c.connList,
+			"test":  c.connTest,
+			"types": c.connTypes,
+		})
+	case "topics":
+		return c.subcommand(ctx, "topics", rest, map[string]func(context.Context, []string) error{
+			"list":   c.topicsList,
+			"create": c.topicsCreate,
+			"delete": c.topicsDelete,
+		})
+	case "groups":
+		return c.groups(ctx, rest)
+	case "seek":
+		return c.seek(ctx, rest)
+	case "browse":
+		return c.browse(ctx, rest)
+	case "streams":
+		return c.subcommand(ctx, "streams", rest, map[string]func(context.Context, []string) error{
+			"info":         c.streamsInfo,
+			"trim":         c.streamsTrim,
+			"group-create": c.streamsGroupCreate,
+			"group-delete": c.streamsGroupDelete,
+			"pending":      c.streamsPending,
+			"claim":        c.streamsClaim,
+		})
+	case "namespaces":
+		return c.subcommand(ctx, "namespaces", rest, map[string]func(context.Context, []string) error{
+			"tenants": c.namespacesTenants,
+			"list":    c.namespacesList,
+			"create":  c.namespacesCreate,
+			"delete":  c.namespacesDelete,
+		})
+	case "subs":
+		return c.subcommand(ctx, "subs", rest, map[string]func(context.Context, []string) error{
+			"list":   c.subsList,
+			"delete": c.subsDelete,
+			"clear":  c.subsClear,
+			"reset":  c.subsReset,
+		})
+	case "addresses":
+		return c.subcommand(ctx, "addresses", rest, map[string]func(context.Context, []string) error{
+			"list":   c.addressesList,
+			"create": c.addressesCreate,
+		})
+	case "queues":
+		return c.subcommand(ctx, "queues", rest, map[string]func(context.Context, []string) error{
+			"list":        c.queuesList,
+			"create":      c.queuesCreate,
+			"delete":      c.queuesDelete,
+			"attributes":  c.queuesAttributes,
+			"set":         c.queuesSet,
+			"purge":       c.queuesPurge,
+			"dlq-sources": c.queuesDeadLetterSources,
+		})
+	case "exchanges":
+		return c.subcommand(ctx, "exchanges", rest, map[string]func(context.Context, []string) error{
+			"list":     c.exchangesList,
+			"create":   c.exchangesCreate,
+			"delete":   c.exchangesDelete,
+			"bindings": c.exchangesBindings,
+			"bind":     c.exchangesBind,
+			"unbind":   c.exchangesUnbind,
+		})
+	case "produce":
+		return c.produce(ctx, rest)
+	case "consume":
+		return c.consume(ctx, rest)
+	case "serve":
+		return c.serve(ctx, rest)
+	default:
+		return &exitError{code: 2, err: fmt.Errorf("unknown command %q", command)}
+	}
+}
+
+func (c *cli) subcommand(ctx context.Context, name string, args []string, commands map[string]func(context.Context, []string) error) error {
+	if len(args) == 0 {
+		return &exitError{code: 2, err: fmt.Errorf("%s requires a subcommand", name)}
+	}
+	fn, ok := commands[args[0]]
+	if !ok {
+		return &exitError{code: 2, err: fmt.Errorf("unknown %s subcommand %q", name, args[0])}
+	}
+	return fn(ctx, args[1:])
+}
+
+// parseFlags 解析参数，允许标志出现在位置参数之后，"--" 之后的参数都作为位置参数
+func parseFlags(fs *flag.FlagSet, args []string, minArgs int, argsUsage string) ([]string, error) {
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: mqtk %s %s\n", fs.Name(), argsUsage)
+		fs.PrintDefaults()
+	}
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, &exitError{code: 2, err: err}
+		}
+		rest := fs.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			positional = append(positional, rest...)
+			break
+		}
+		if len(rest) == 0 {
+			break
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+
+	if len(positional) < minArgs {
+		fs.Usage()
+		return nil, &exitError{code: 2, err: fmt.Errorf("%s: expected %s", fs.Name(), argsUsage)}
+	}
+	return positional, nil
+}
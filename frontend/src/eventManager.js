@@ -1,5 +1,5 @@
 import { EventsOn } from '../wailsjs/runtime';
-import { consumerMessages, consumerState } from './store.js';
+import { consumerMessages, consumerState, connectionStatuses } from './store.js';
 import { get } from 'svelte/store';
 
 // 全局事件监听器管理器
@@ -59,6 +59,36 @@ class EventManager {
       }
     });
 
+    // 缓存客户端因认证或配置错误停止重连
+    EventsOn('connection:error', (error) => {
+      if (this.notificationCallback) {
+        this.notificationCallback(`连接已停止重连: ${error.error}`, 'error');
+      }
+    });
+
+    // 连接健康状态事件
+    EventsOn('connection:status', (status) => {
+      const key = status.subscription_id ? `${status.connection_id}/${status.subscription_id}` : status.connection_id;
+      const previous = get(connectionStatuses)[key];
+      connectionStatuses.update(statuses => {
+        const next = { ...statuses };
+        if (status.state === 'disconnected') {
+          delete next[key];
+        } else {
+          next[key] = status;
+        }
+        return next;
+      });
+
+      // 只提示订阅的断线和恢复，缓存客户端的状态显示在连接列表中
+      if (!status.subscription_id || !this.notificationCallback) return;
+      if (status.state === 'reconnecting' && status.attempt === 1) {
+        this.notificationCallback(`消费连接中断，正在重连: ${status.error}`, 'warning');
+      } else if (status.state === 'connected' && previous && previous.state === 'reconnecting') {
+        this.notificationCallback('消费连接已恢复', 'success');
+      }
+    });
+
     this.listenersSetup = true;
   }
 
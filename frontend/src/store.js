@@ -1,4 +1,4 @@
-import { writable } from 'svelte/store';
+import { writable, derived } from 'svelte/store';
 
 /**
  * @typedef {import('../../wailsjs/go/models').types.ConnectionConfig} ConnectionConfig
@@ -16,6 +16,21 @@ export const testResults = writable({});
 
 export const loading = writable(false);
 
+// 连接健康状态，由 connection:status 事件更新 [connection_id 或 connection_id/subscription_id -> 状态]
+export const connectionStatuses = writable({});
+
+// 已注册的消息队列后端描述，启动时由 ListBackends 加载
+export const backends = writable([]);
+
+// 当前连接的后端描述，类型未注册时为 null
+export const selectedBackend = derived([backends, selectedConnection], ([$backends, $selectedConnection]) =>
+  ($selectedConnection && $backends.find(b => b.type === $selectedConnection.type)) || null);
+
+// 检查后端是否具有能力，能力名见 types.Capability
+export function hasCapability(backend, capability) {
+  return !!backend && (backend.capabilities || []).includes(capability);
+}
+
 // 持久化主题选择
 function createPersistedStore(key, defaultValue) {
   const stored = localStorage.getItem(key);
@@ -2,40 +2,186 @@
 // This file is automatically generated. DO NOT EDIT
 import {types} from '../models';
 
+export function ActivateEnvironment(arg1:string):Promise<void>;
+
+export function BindQueue(arg1:types.BindingRequest):Promise<void>;
+
+export function Browse(arg1:types.BrowseRequest):Promise<Array<types.Message>>;
+
+export function ClaimPendingEntries(arg1:types.ClaimPendingRequest):Promise<Array<string>>;
+
+export function ClearBacklog(arg1:types.SubscriptionRequest):Promise<void>;
+
 export function ClearHistory():Promise<void>;
 
+export function CreateAddress(arg1:types.CreateAddressRequest):Promise<void>;
+
+export function CreateCollection(arg1:types.TemplateCollection):Promise<types.TemplateCollection>;
+
 export function CreateConnection(arg1:types.ConnectionConfig):Promise<void>;
 
-export function CreateTemplate(arg1:string,arg2:string):Promise<types.MessageTemplate>;
+export function CreateEnvironment(arg1:types.Environment):Promise<void>;
+
+export function CreateExchange(arg1:types.CreateExchangeRequest):Promise<void>;
+
+export function CreateNamespace(arg1:types.NamespaceRequest):Promise<void>;
+
+export function CreateQueue(arg1:types.CreateQueueRequest):Promise<void>;
+
+export function CreateStreamGroup(arg1:types.StreamGroupRequest):Promise<void>;
+
+export function CreateTemplate(arg1:types.MessageTemplate):Promise<types.MessageTemplate>;
 
 export function CreateTopic(arg1:types.CreateTopicRequest):Promise<void>;
 
+export function DeleteBenchmarkReport(arg1:string):Promise<void>;
+
+export function DeleteCollection(arg1:string):Promise<void>;
+
 export function DeleteConnection(arg1:string):Promise<void>;
 
+export function DeleteEnvironment(arg1:string):Promise<void>;
+
+export function DeleteExchange(arg1:string,arg2:string):Promise<void>;
+
+export function DeleteMessages(arg1:types.MessageQuery):Promise<number>;
+
+export function DeleteNamespace(arg1:types.NamespaceRequest):Promise<void>;
+
+export function DeleteQueue(arg1:string,arg2:string):Promise<void>;
+
+export function DeleteStreamGroup(arg1:types.StreamGroupRequest):Promise<void>;
+
+export function DeleteSubscription(arg1:types.SubscriptionRequest):Promise<void>;
+
 export function DeleteTemplate(arg1:string):Promise<void>;
 
 export function DeleteTopic(arg1:types.DeleteTopicRequest):Promise<void>;
 
+export function DuplicateCollection(arg1:string):Promise<types.TemplateCollection>;
+
+export function DuplicateTemplate(arg1:string):Promise<types.MessageTemplate>;
+
+export function ExportBundle(arg1:types.BundleExportRequest):Promise<string>;
+
+export function ExportCollection(arg1:string):Promise<string>;
+
+export function ExportMessages(arg1:types.ExportRequest):Promise<string>;
+
+export function FindTemplates(arg1:string,arg2:string):Promise<Array<types.MessageTemplate>>;
+
+export function GetCapabilities(arg1:string):Promise<Array<types.Capability>>;
+
 export function GetConnections():Promise<Array<types.ConnectionConfig>>;
 
 export function GetHistory(arg1:number,arg2:number):Promise<Array<types.HistoryRecord>>;
 
 export function GetLogs():Promise<Array<types.LogEntry>>;
 
+export function GetQueueAttributes(arg1:string,arg2:string):Promise<types.QueueAttributes>;
+
+export function GetSecretStatus():Promise<types.SecretStatus>;
+
+export function GetStreamInfo(arg1:string,arg2:string):Promise<types.StreamInfo>;
+
+export function ImportBundle(arg1:types.BundleImportRequest):Promise<types.BundleImportResult>;
+
+export function ImportCollection(arg1:string,arg2:string):Promise<types.CollectionImportResult>;
+
+export function ImportConnectionURI(arg1:string,arg2:string,arg3:types.ImportConflictMode):Promise<types.ImportItemResult>;
+
+export function ImportMessages(arg1:types.ImportRequest):Promise<types.ImportResult>;
+
+export function ListAddresses(arg1:string):Promise<Array<types.AddressInfo>>;
+
+export function ListBackends():Promise<Array<types.BackendInfo>>;
+
+export function ListBenchmarkReports(arg1:number,arg2:number):Promise<Array<types.BenchmarkReport>>;
+
+export function ListBindings(arg1:string,arg2:string):Promise<Array<types.BindingInfo>>;
+
+export function ListBridges():Promise<Array<types.BridgeStatus>>;
+
+export function ListCollections():Promise<Array<types.TemplateCollection>>;
+
+export function ListConnectionStatuses():Promise<Array<types.ConnectionStatus>>;
+
+export function ListConsumerGroups(arg1:string):Promise<Array<types.ConsumerGroup>>;
+
+export function ListDeadLetterSources(arg1:string,arg2:string):Promise<Array<string>>;
+
+export function ListEnvironments():Promise<Array<types.Environment>>;
+
+export function ListExchanges(arg1:string):Promise<Array<types.ExchangeInfo>>;
+
+export function ListNamespaces(arg1:string,arg2:string):Promise<Array<string>>;
+
+export function ListPendingEntries(arg1:string,arg2:string,arg3:string,arg4:number):Promise<Array<types.PendingEntry>>;
+
+export function ListQueues(arg1:string,arg2:string):Promise<Array<types.QueueInfo>>;
+
+export function ListReplays():Promise<Array<types.ReplayStatus>>;
+
+export function ListSubscriptions(arg1:string,arg2:string):Promise<Array<types.SubscriptionInfo>>;
+
 export function ListTemplates():Promise<Array<types.MessageTemplate>>;
 
+export function ListTenants(arg1:string):Promise<Array<string>>;
+
 export function ListTopics(arg1:string):Promise<Array<types.TopicInfo>>;
 
+export function PauseBridge(arg1:string):Promise<void>;
+
 export function ProduceMessage(arg1:types.ProduceRequest):Promise<void>;
 
+export function ProduceRendered(arg1:types.RenderProduceRequest):Promise<types.RenderProduceResult>;
+
+export function PurgeQueue(arg1:string,arg2:string):Promise<void>;
+
+export function RenderTemplate(arg1:types.RenderRequest):Promise<Array<string>>;
+
+export function ResetSubscription(arg1:types.ResetSubscriptionRequest):Promise<void>;
+
+export function ResumeBridge(arg1:string):Promise<void>;
+
+export function RotateSecretKey(arg1:string):Promise<void>;
+
 export function SaveFile(arg1:string,arg2:string):Promise<string>;
 
+export function SearchMessages(arg1:types.MessageQuery):Promise<types.MessageSearchResult>;
+
+export function SeekGroup(arg1:types.SeekGroupRequest):Promise<void>;
+
+export function SetQueueAttributes(arg1:types.SetQueueAttributesRequest):Promise<void>;
+
+export function StartBenchmark(arg1:types.BenchmarkRequest):Promise<string>;
+
+export function StartBridge(arg1:types.BridgeRequest):Promise<string>;
+
 export function StartConsuming(arg1:types.ConsumeRequest):Promise<string>;
 
+export function StartReplay(arg1:types.ReplayRequest):Promise<string>;
+
+export function StopBenchmark(arg1:string):Promise<void>;
+
+export function StopBridge(arg1:string):Promise<void>;
+
 export function StopConsuming(arg1:string):Promise<void>;
 
+export function StopReplay(arg1:string):Promise<void>;
+
 export function TestConnection(arg1:string):Promise<types.TestResult>;
 
+export function TrimStream(arg1:types.TrimStreamRequest):Promise<number>;
+
+export function UnbindQueue(arg1:types.BindingRequest):Promise<void>;
+
+export function UnlockSecrets(arg1:string):Promise<void>;
+
+export function UpdateCollection(arg1:types.TemplateCollection):Promise<void>;
+
 export function UpdateConnection(arg1:types.ConnectionConfig):Promise<void>;
 
-export function UpdateTemplate(arg1:string,arg2:string,arg3:string):Promise<void>;
+export function UpdateEnvironment(arg1:types.Environment):Promise<void>;
+
+export function UpdateTemplate(arg1:types.MessageTemplate):Promise<void>;
@@ -2,26 +2,110 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function ActivateEnvironment(arg1) {
+  return window['go']['main']['App']['ActivateEnvironment'](arg1);
+}
+
+export function BindQueue(arg1) {
+  return window['go']['main']['App']['BindQueue'](arg1);
+}
+
+export function Browse(arg1) {
+  return window['go']['main']['App']['Browse'](arg1);
+}
+
+export function ClaimPendingEntries(arg1) {
+  return window['go']['main']['App']['ClaimPendingEntries'](arg1);
+}
+
+export function ClearBacklog(arg1) {
+  return window['go']['main']['App']['ClearBacklog'](arg1);
+}
+
 export function ClearHistory() {
   return window['go']['main']['App']['ClearHistory']();
 }
 
+export function CreateAddress(arg1) {
+  return window['go']['main']['App']['CreateAddress'](arg1);
+}
+
+export function CreateCollection(arg1) {
+  return window['go']['main']['App']['CreateCollection'](arg1);
+}
+
 export function CreateConnection(arg1) {
   return window['go']['main']['App']['CreateConnection'](arg1);
 }
 
-export function CreateTemplate(arg1, arg2) {
-  return window['go']['main']['App']['CreateTemplate'](arg1, arg2);
+export function CreateEnvironment(arg1) {
+  return window['go']['main']['App']['CreateEnvironment'](arg1);
+}
+
+export function CreateExchange(arg1) {
+  return window['go']['main']['App']['CreateExchange'](arg1);
+}
+
+export function CreateNamespace(arg1) {
+  return window['go']['main']['App']['CreateNamespace'](arg1);
+}
+
+export function CreateQueue(arg1) {
+  return window['go']['main']['App']['CreateQueue'](arg1);
+}
+
+export function CreateStreamGroup(arg1) {
+  return window['go']['main']['App']['CreateStreamGroup'](arg1);
+}
+
+export function CreateTemplate(arg1) {
+  return window['go']['main']['App']['CreateTemplate'](arg1);
 }
 
 export function CreateTopic(arg1) {
   return window['go']['main']['App']['CreateTopic'](arg1);
 }
 
+export function DeleteBenchmarkReport(arg1) {
+  return window['go']['main']['App']['DeleteBenchmarkReport'](arg1);
+}
+
+export function DeleteCollection(arg1) {
+  return window['go']['main']['App']['DeleteCollection'](arg1);
+}
+
 export function DeleteConnection(arg1) {
   return window['go']['main']['App']['DeleteConnection'](arg1);
 }
 
+export function DeleteEnvironment(arg1) {
+  return window['go']['main']['App']['DeleteEnvironment'](arg1);
+}
+
+export function DeleteExchange(arg1, arg2) {
+  return window['go']['main']['App']['DeleteExchange'](arg1, arg2);
+}
+
+export function DeleteMessages(arg1) {
+  return window['go']['main']['App']['DeleteMessages'](arg1);
+}
+
+export function DeleteNamespace(arg1) {
+  return window['go']['main']['App']['DeleteNamespace'](arg1);
+}
+
+export function DeleteQueue(arg1, arg2) {
+  return window['go']['main']['App']['DeleteQueue'](arg1, arg2);
+}
+
+export function DeleteStreamGroup(arg1) {
+  return window['go']['main']['App']['DeleteStreamGroup'](arg1);
+}
+
+export function DeleteSubscription(arg1) {
+  return window['go']['main']['App']['DeleteSubscription'](arg1);
+}
+
 export function DeleteTemplate(arg1) {
   return window['go']['main']['App']['DeleteTemplate'](arg1);
 }
@@ -30,6 +114,34 @@ export function DeleteTopic(arg1) {
   return window['go']['main']['App']['DeleteTopic'](arg1);
 }
 
+export function DuplicateCollection(arg1) {
+  return window['go']['main']['App']['DuplicateCollection'](arg1);
+}
+
+export function DuplicateTemplate(arg1) {
+  return window['go']['main']['App']['DuplicateTemplate'](arg1);
+}
+
+export function ExportBundle(arg1) {
+  return window['go']['main']['App']['ExportBundle'](arg1);
+}
+
+export function ExportCollection(arg1) {
+  return window['go']['main']['App']['ExportCollection'](arg1);
+}
+
+export function ExportMessages(arg1) {
+  return window['go']['main']['App']['ExportMessages'](arg1);
+}
+
+export function FindTemplates(arg1, arg2) {
+  return window['go']['main']['App']['FindTemplates'](arg1, arg2);
+}
+
+export function GetCapabilities(arg1) {
+  return window['go']['main']['App']['GetCapabilities'](arg1);
+}
+
 export function GetConnections() {
   return window['go']['main']['App']['GetConnections']();
 }
@@ -42,38 +154,218 @@ export function GetLogs() {
   return window['go']['main']['App']['GetLogs']();
 }
 
+export function GetQueueAttributes(arg1, arg2) {
+  return window['go']['main']['App']['GetQueueAttributes'](arg1, arg2);
+}
+
+export function GetSecretStatus() {
+  return window['go']['main']['App']['GetSecretStatus']();
+}
+
+export function GetStreamInfo(arg1, arg2) {
+  return window['go']['main']['App']['GetStreamInfo'](arg1, arg2);
+}
+
+export function ImportBundle(arg1) {
+  return window['go']['main']['App']['ImportBundle'](arg1);
+}
+
+export function ImportCollection(arg1, arg2) {
+  return window['go']['main']['App']['ImportCollection'](arg1, arg2);
+}
+
+export function ImportConnectionURI(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ImportConnectionURI'](arg1, arg2, arg3);
+}
+
+export function ImportMessages(arg1) {
+  return window['go']['main']['App']['ImportMessages'](arg1);
+}
+
+export function ListAddresses(arg1) {
+  return window['go']['main']['App']['ListAddresses'](arg1);
+}
+
+export function ListBackends() {
+  return window['go']['main']['App']['ListBackends']();
+}
+
+export function ListBenchmarkReports(arg1, arg2) {
+  return window['go']['main']['App']['ListBenchmarkReports'](arg1, arg2);
+}
+
+export function ListBindings(arg1, arg2) {
+  return window['go']['main']['App']['ListBindings'](arg1, arg2);
+}
+
+export function ListBridges() {
+  return window['go']['main']['App']['ListBridges']();
+}
+
+export function ListCollections() {
+  return window['go']['main']['App']['ListCollections']();
+}
+
+export function ListConnectionStatuses() {
+  return window['go']['main']['App']['ListConnectionStatuses']();
+}
+
+export function ListConsumerGroups(arg1) {
+  return window['go']['main']['App']['ListConsumerGroups'](arg1);
+}
+
+export function ListDeadLetterSources(arg1, arg2) {
+  return window['go']['main']['App']['ListDeadLetterSources'](arg1, arg2);
+}
+
+export function ListEnvironments() {
+  return window['go']['main']['App']['ListEnvironments']();
+}
+
+export function ListExchanges(arg1) {
+  return window['go']['main']['App']['ListExchanges'](arg1);
+}
+
+export function ListNamespaces(arg1, arg2) {
+  return window['go']['main']['App']['ListNamespaces'](arg1, arg2);
+}
+
+export function ListPendingEntries(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ListPendingEntries'](arg1, arg2, arg3, arg4);
+}
+
+export function ListQueues(arg1, arg2) {
+  return window['go']['main']['App']['ListQueues'](arg1, arg2);
+}
+
+export function ListReplays() {
+  return window['go']['main']['App']['ListReplays']();
+}
+
+export function ListSubscriptions(arg1, arg2) {
+  return window['go']['main']['App']['ListSubscriptions'](arg1, arg2);
+}
+
 export function ListTemplates() {
   return window['go']['main']['App']['ListTemplates']();
 }
 
+export function ListTenants(arg1) {
+  return window['go']['main']['App']['ListTenants'](arg1);
+}
+
 export function ListTopics(arg1) {
   return window['go']['main']['App']['ListTopics'](arg1);
 }
 
+export function PauseBridge(arg1) {
+  return window['go']['main']['App']['PauseBridge'](arg1);
+}
+
 export function ProduceMessage(arg1) {
   return window['go']['main']['App']['ProduceMessage'](arg1);
 }
 
+export function ProduceRendered(arg1) {
+  return window['go']['main']['App']['ProduceRendered'](arg1);
+}
+
+export function PurgeQueue(arg1, arg2) {
+  return window['go']['main']['App']['PurgeQueue'](arg1, arg2);
+}
+
+export function RenderTemplate(arg1) {
+  return window['go']['main']['App']['RenderTemplate'](arg1);
+}
+
+export function ResetSubscription(arg1) {
+  return window['go']['main']['App']['ResetSubscription'](arg1);
+}
+
+export function ResumeBridge(arg1) {
+  return window['go']['main']['App']['ResumeBridge'](arg1);
+}
+
+export function RotateSecretKey(arg1) {
+  return window['go']['main']['App']['RotateSecretKey'](arg1);
+}
+
 export function SaveFile(arg1, arg2) {
   return window['go']['main']['App']['SaveFile'](arg1, arg2);
 }
 
+export function SearchMessages(arg1) {
+  return window['go']['main']['App']['SearchMessages'](arg1);
+}
+
+export function SeekGroup(arg1) {
+  return window['go']['main']['App']['SeekGroup'](arg1);
+}
+
+export function SetQueueAttributes(arg1) {
+  return window['go']['main']['App']['SetQueueAttributes'](arg1);
+}
+
+export function StartBenchmark(arg1) {
+  return window['go']['main']['App']['StartBenchmark'](arg1);
+}
+
+export function StartBridge(arg1) {
+  return window['go']['main']['App']['StartBridge'](arg1);
+}
+
 export function StartConsuming(arg1) {
   return window['go']['main']['App']['StartConsuming'](arg1);
 }
 
+export function StartReplay(arg1) {
+  return window['go']['main']['App']['StartReplay'](arg1);
+}
+
+export function StopBenchmark(arg1) {
+  return window['go']['main']['App']['StopBenchmark'](arg1);
+}
+
+export function StopBridge(arg1) {
+  return window['go']['main']['App']['StopBridge'](arg1);
+}
+
 export function StopConsuming(arg1) {
   return window['go']['main']['App']['StopConsuming'](arg1);
 }
 
+export function StopReplay(arg1) {
+  return window['go']['main']['App']['StopReplay'](arg1);
+}
+
 export function TestConnection(arg1) {
   return window['go']['main']['App']['TestConnection'](arg1);
 }
 
+export function TrimStream(arg1) {
+  return window['go']['main']['App']['TrimStream'](arg1);
+}
+
+export function UnbindQueue(arg1) {
+  return window['go']['main']['App']['UnbindQueue'](arg1);
+}
+
+export function UnlockSecrets(arg1) {
+  return window['go']['main']['App']['UnlockSecrets'](arg1);
+}
+
+export function UpdateCollection(arg1) {
+  return window['go']['main']['App']['UpdateCollection'](arg1);
+}
+
 export function UpdateConnection(arg1) {
   return window['go']['main']['App']['UpdateConnection'](arg1);
 }
 
-export function UpdateTemplate(arg1, arg2, arg3) {
-  return window['go']['main']['App']['UpdateTemplate'](arg1, arg2, arg3);
+export function UpdateEnvironment(arg1) {
+  return window['go']['main']['App']['UpdateEnvironment'](arg1);
+}
+
+export function UpdateTemplate(arg1) {
+  return window['go']['main']['App']['UpdateTemplate'](arg1);
 }
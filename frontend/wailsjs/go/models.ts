@@ -1,39 +1,1613 @@
 export namespace types {
 	
-	export class ConnectionConfig {
-	    id: string;
+	export class AddressInfo {
 	    name: string;
+	    routing_types: string[];
+	    queue_count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AddressInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.routing_types = source["routing_types"];
+	        this.queue_count = source["queue_count"];
+	    }
+	}
+	export class FieldOption {
+	    value: string;
+	    label: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FieldOption(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.value = source["value"];
+	        this.label = source["label"];
+	    }
+	}
+	export class ConfigField {
+	    key: string;
+	    label: string;
+	    kind: string;
+	    options?: FieldOption[];
+	    default?: string;
+	    show_if?: string;
+	    connection?: boolean;
+	    mono?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigField(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.label = source["label"];
+	        this.kind = source["kind"];
+	        this.options = this.convertValues(source["options"], FieldOption);
+	        this.default = source["default"];
+	        this.show_if = source["show_if"];
+	        this.connection = source["connection"];
+	        this.mono = source["mono"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BackendInfo {
 	    type: string;
-	    host: string;
-	    port: number;
-	    username: string;
-	    password: string;
-	    vhost: string;
+	    display_name: string;
+	    order: number;
+	    default_host: string;
+	    default_port: number;
+	    default_username?: string;
+	    endpoint_label?: string;
+	    settings_title?: string;
+	    settings_help?: string;
+	    fields: ConfigField[];
+	    consume_hint?: string;
+	    create_topic_hint?: string;
+	    capabilities: string[];
+	    tunnel_help?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackendInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.display_name = source["display_name"];
+	        this.order = source["order"];
+	        this.default_host = source["default_host"];
+	        this.default_port = source["default_port"];
+	        this.default_username = source["default_username"];
+	        this.endpoint_label = source["endpoint_label"];
+	        this.settings_title = source["settings_title"];
+	        this.settings_help = source["settings_help"];
+	        this.fields = this.convertValues(source["fields"], ConfigField);
+	        this.consume_hint = source["consume_hint"];
+	        this.create_topic_hint = source["create_topic_hint"];
+	        this.capabilities = source["capabilities"];
+	        this.tunnel_help = source["tunnel_help"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BenchmarkStats {
+	    messages: number;
+	    errors: number;
+	    bytes: number;
+	    elapsed_ms: number;
+	    msgs_per_sec: number;
+	    mb_per_sec: number;
+	    latency_p50: number;
+	    latency_p95: number;
+	    latency_p99: number;
+	    latency_max: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BenchmarkStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.messages = source["messages"];
+	        this.errors = source["errors"];
+	        this.bytes = source["bytes"];
+	        this.elapsed_ms = source["elapsed_ms"];
+	        this.msgs_per_sec = source["msgs_per_sec"];
+	        this.mb_per_sec = source["mb_per_sec"];
+	        this.latency_p50 = source["latency_p50"];
+	        this.latency_p95 = source["latency_p95"];
+	        this.latency_p99 = source["latency_p99"];
+	        this.latency_max = source["latency_max"];
+	    }
+	}
+	export class BenchmarkRequest {
+	    mode: string;
+	    connection_id: string;
+	    topic: string;
 	    group_id: string;
-	    extra: Record<string, string>;
+	    message_count: number;
+	    duration_seconds: number;
+	    rate_per_second: number;
+	    concurrency: number;
+	    payload_size: number;
+	    payload: string;
+	    template: string;
+	    variables: Record<string, string>;
+	    key_distribution: string;
+	    key: string;
+	    key_count: number;
+	    headers: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new BenchmarkRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.connection_id = source["connection_id"];
+	        this.topic = source["topic"];
+	        this.group_id = source["group_id"];
+	        this.message_count = source["message_count"];
+	        this.duration_seconds = source["duration_seconds"];
+	        this.rate_per_second = source["rate_per_second"];
+	        this.concurrency = source["concurrency"];
+	        this.payload_size = source["payload_size"];
+	        this.payload = source["payload"];
+	        this.template = source["template"];
+	        this.variables = source["variables"];
+	        this.key_distribution = source["key_distribution"];
+	        this.key = source["key"];
+	        this.key_count = source["key_count"];
+	        this.headers = source["headers"];
+	    }
+	}
+	export class BenchmarkReport {
+	    id: string;
+	    mode: string;
+	    connection_id: string;
+	    topic: string;
+	    state: string;
+	    error?: string;
+	    request: BenchmarkRequest;
+	    stats: BenchmarkStats;
+	    // Go type: time
+	    started_at: any;
+	    // Go type: time
+	    finished_at?: any;
 	    // Go type: time
 	    created: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new BenchmarkReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.mode = source["mode"];
+	        this.connection_id = source["connection_id"];
+	        this.topic = source["topic"];
+	        this.state = source["state"];
+	        this.error = source["error"];
+	        this.request = this.convertValues(source["request"], BenchmarkRequest);
+	        this.stats = this.convertValues(source["stats"], BenchmarkStats);
+	        this.started_at = this.convertValues(source["started_at"], null);
+	        this.finished_at = this.convertValues(source["finished_at"], null);
+	        this.created = this.convertValues(source["created"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class BindingInfo {
+	    exchange: string;
+	    queue: string;
+	    routing_key: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BindingInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.exchange = source["exchange"];
+	        this.queue = source["queue"];
+	        this.routing_key = source["routing_key"];
+	    }
+	}
+	export class BindingRequest {
+	    connection_id: string;
+	    exchange: string;
+	    queue: string;
+	    routing_key: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BindingRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connection_id = source["connection_id"];
+	        this.exchange = source["exchange"];
+	        this.queue = source["queue"];
+	        this.routing_key = source["routing_key"];
+	    }
+	}
+	export class TopicTransform {
+	    replace: string;
+	    with: string;
+	    prefix: string;
+	    suffix: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TopicTransform(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.replace = source["replace"];
+	        this.with = source["with"];
+	        this.prefix = source["prefix"];
+	        this.suffix = source["suffix"];
+	    }
+	}
+	export class BridgeRequest {
+	    name: string;
+	    source_connection_id: string;
+	    source_topics: string[];
+	    group_id: string;
+	    from_beginning: boolean;
+	    target_connection_id: string;
+	    target_topic: string;
+	    topic_transform?: TopicTransform;
+	    key_from: string;
+	    header_map: Record<string, string>;
+	    set_headers: Record<string, string>;
+	    filter: string;
+	    rate_per_second: number;
+	    max_retries: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BridgeRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.source_connection_id = source["source_connection_id"];
+	        this.source_topics = source["source_topics"];
+	        this.group_id = source["group_id"];
+	        this.from_beginning = source["from_beginning"];
+	        this.target_connection_id = source["target_connection_id"];
+	        this.target_topic = source["target_topic"];
+	        this.topic_transform = this.convertValues(source["topic_transform"], TopicTransform);
+	        this.key_from = source["key_from"];
+	        this.header_map = source["header_map"];
+	        this.set_headers = source["set_headers"];
+	        this.filter = source["filter"];
+	        this.rate_per_second = source["rate_per_second"];
+	        this.max_retries = source["max_retries"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BridgeStatus {
+	    id: string;
+	    name: string;
+	    source_connection_id: string;
+	    source_topics: string[];
+	    target_connection_id: string;
+	    target_topic: string;
+	    state: string;
+	    consumed: number;
+	    produced: number;
+	    filtered: number;
+	    failed: number;
+	    rate: number;
+	    error?: string;
 	    // Go type: time
-	    updated: any;
+	    started_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new BridgeStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.source_connection_id = source["source_connection_id"];
+	        this.source_topics = source["source_topics"];
+	        this.target_connection_id = source["target_connection_id"];
+	        this.target_topic = source["target_topic"];
+	        this.state = source["state"];
+	        this.consumed = source["consumed"];
+	        this.produced = source["produced"];
+	        this.filtered = source["filtered"];
+	        this.failed = source["failed"];
+	        this.rate = source["rate"];
+	        this.error = source["error"];
+	        this.started_at = this.convertValues(source["started_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BrowseRequest {
+	    connection_id: string;
+	    topic: string;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BrowseRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connection_id = source["connection_id"];
+	        this.topic = source["topic"];
+	        this.limit = source["limit"];
+	    }
+	}
+	export class BundleExportRequest {
+	    connection_ids: string[];
+	    template_ids: string[];
+	    collection_ids: string[];
+	    environment_ids: string[];
+	    secret_mode: string;
+	    passphrase: string;
+	    format: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BundleExportRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connection_ids = source["connection_ids"];
+	        this.template_ids = source["template_ids"];
+	        this.collection_ids = source["collection_ids"];
+	        this.environment_ids = source["environment_ids"];
+	        this.secret_mode = source["secret_mode"];
+	        this.passphrase = source["passphrase"];
+	        this.format = source["format"];
+	    }
+	}
+	export class BundleImportRequest {
+	    file_path: string;
+	    passphrase: string;
+	    conflict: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BundleImportRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.file_path = source["file_path"];
+	        this.passphrase = source["passphrase"];
+	        this.conflict = source["conflict"];
+	    }
+	}
+	export class ImportItemResult {
+	    kind: string;
+	    name: string;
+	    action: string;
+	    new_name?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportItemResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.name = source["name"];
+	        this.action = source["action"];
+	        this.new_name = source["new_name"];
+	    }
+	}
+	export class BundleImportResult {
+	    file_path: string;
+	    items: ImportItemResult[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BundleImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.file_path = source["file_path"];
+	        this.items = this.convertValues(source["items"], ImportItemResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ClaimPendingRequest {
+	    connection_id: string;
+	    stream: string;
+	    group: string;
+	    consumer: string;
+	    min_idle: number;
+	    ids: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ClaimPendingRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connection_id = source["connection_id"];
+	        this.stream = source["stream"];
+	        this.group = source["group"];
+	        this.consumer = source["consumer"];
+	        this.min_idle = source["min_idle"];
+	        this.ids = source["ids"];
+	    }
+	}
+	export class CollectionImportResult {
+	    filePath: string;
+	    collections: number;
+	    templates: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CollectionImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.collections = source["collections"];
+	        this.templates = source["templates"];
+	    }
+	}
+	
+	export class TimeoutConfig {
+	    dial?: number;
+	    request?: number;
+	    produce?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TimeoutConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dial = source["dial"];
+	        this.request = source["request"];
+	        this.produce = source["produce"];
+	    }
+	}
+	export class TunnelConfig {
+	    type: string;
+	    host: string;
+	    port: number;
+	    username: string;
+	    password: string;
+	    private_key: string;
+	    private_key_path: string;
+	    passphrase: string;
+	    use_agent: boolean;
+	    known_hosts_path: string;
+	    insecure_ignore_host_key: boolean;
+	    address_map?: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new TunnelConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.username = source["username"];
+	        this.password = source["password"];
+	        this.private_key = source["private_key"];
+	        this.private_key_path = source["private_key_path"];
+	        this.passphrase = source["passphrase"];
+	        this.use_agent = source["use_agent"];
+	        this.known_hosts_path = source["known_hosts_path"];
+	        this.insecure_ignore_host_key = source["insecure_ignore_host_key"];
+	        this.address_map = source["address_map"];
+	    }
+	}
+	export class ConnectionConfig {
+	    id: string;
+	    name: string;
+	    type: string;
+	    host: string;
+	    port: number;
+	    endpoints: string[];
+	    username: string;
+	    password: string;
+	    vhost: string;
+	    group_id: string;
+	    region: string;
+	    access_key: string;
+	    secret_key: string;
+	    session_token: string;
+	    extra: Record<string, string>;
+	    tunnel?: TunnelConfig;
+	    timeouts?: TimeoutConfig;
+	    // Go type: time
+	    created: any;
+	    // Go type: time
+	    updated: any;
+	    resolve_variables: boolean;
+	    resolve_credentials: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConnectionConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.endpoints = source["endpoints"];
+	        this.username = source["username"];
+	        this.password = source["password"];
+	        this.vhost = source["vhost"];
+	        this.group_id = source["group_id"];
+	        this.region = source["region"];
+	        this.access_key = source["access_key"];
+	        this.secret_key = source["secret_key"];
+	        this.session_token = source["session_token"];
+	        this.extra = source["extra"];
+	        this.tunnel = this.convertValues(source["tunnel"], TunnelConfig);
+	        this.timeouts = this.convertValues(source["timeouts"], TimeoutConfig);
+	        this.created = this.convertValues(source["created"], null);
+	        this.updated = this.convertValues(source["updated"], null);
+	        this.resolve_variables = source["resolve_variables"];
+	        this.resolve_credentials = source["resolve_credentials"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConnectionStatus {
+	    connection_id: string;
+	    subscription_id?: string;
+	    state: string;
+	    error?: string;
+	    attempt?: number;
+	    // Go type: time
+	    next_retry?: any;
+	    latency?: number;
+	    // Go type: time
+	    checked_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConnectionStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connection_id = source["connection_id"];
+	        this.subscription_id = source["subscription_id"];
+	        this.state = source["state"];
+	        this.error = source["error"];
+	        this.attempt = source["attempt"];
+	        this.next_retry = this.convertValues(source["next_retry"], null);
+	        this.latency = source["latency"];
+	        this.checked_at = this.convertValues(source["checked_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConsumeRequest {
+	    connection_id: string;
+	    topics: string[];
+	    group_id: string;
+	    auto_commit: boolean;
+	    from_beginning: boolean;
+	    start_sequence?: number;
+	    // Go type: time
+	    start_time?: any;
+	    start_message_id?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConsumeRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connection_id = source["connection_id"];
+	        this.topics = source["topics"];
+	        this.group_id = source["group_id"];
+	        this.auto_commit = source["auto_commit"];
+	        this.from_beginning = source["from_beginning"];
+	        this.start_sequence = source["start_sequence"];
+	        this.start_time = this.convertValues(source["start_time"], null);
+	        this.start_message_id = source["start_message_id"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConsumerGroup {
+	    id: string;
+	    members: string[];
+	    topics: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ConsumerGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.members = source["members"];
+	        this.topics = source["topics"];
+	    }
+	}
+	export class CreateAddressRequest {
+	    connection_id: string;
+	    address: string;
+	    routing_type: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CreateAddressRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connection_id = source["connection_id"];
+	        this.address = source["address"];
+	        this.routing_type = source["routing_type"];
+	    }
+	}
+	export class CreateExchangeRequest {
+	    connection_id: string;
+	    exchange: string;
+	    kind: string;
+	    durable: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CreateExchangeRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connection_id = source["connection_id"];
+	        this.exchange = source["exchange"];
+	        this.kind = source["kind"];
+	        this.durable = source["durable"];
+	    }
+	}
+	export class CreateQueueRequest {
+	    connection_id: string;
+	    address: string;
+	    queue: string;
+	    routing_type: string;
+	    durable: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CreateQueueRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connection_id = source["connection_id"];
+	        this.address = source["address"];
+	        this.queue = source["queue"];
+	        this.routing_type = source["routing_type"];
+	        this.durable = source["durable"];
+	    }
+	}
+	export class CreateTopicRequest {
+	    connection_id: string;
+	    topic: string;
+	    partitions: number;
+	    replicas: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CreateTopicRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connection_id = source["connection_id"];
+	        this.topic = source["topic"];
+	        this.partitions = source["partitions"];
+	        this.replicas = source["replicas"];
+	    }
+	}
+	export class DeleteTopicRequest {
+	    connection_id: string;
+	    topic: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeleteTopicRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connection_id = source["connection_id"];
+	        this.topic = source["topic"];
+	    }
+	}
+	export class EnvironmentVariable {
+	    key: string;
+	    value: string;
+	    secret: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new EnvironmentVariable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.value = source["value"];
+	        this.secret = source["secret"];
+	    }
+	}
+	export class Environment {
+	    id: string;
+	    name: string;
+	    description: string;
+	    variables: EnvironmentVariable[];
+	    active: boolean;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Environment(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.variables = this.convertValues(source["variables"], EnvironmentVariable);
+	        this.active = source["active"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ExchangeInfo {
+	    name: string;
+	    kind: string;
+	    durable: boolean;
+	    auto_delete: boolean;
+	    internal: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExchangeInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.kind = source["kind"];
+	        this.durable = source["durable"];
+	        this.auto_delete = source["auto_delete"];
+	        this.internal = source["internal"];
+	    }
+	}
+	export class MessageQuery {
+	    connection_id: string;
+	    subscription_id: string;
+	    topic: string;
+	    key: string;
+	    header_key: string;
+	    header_value: string;
+	    // Go type: time
+	    start?: any;
+	    // Go type: time
+	    end?: any;
+	    search: string;
+	    limit: number;
+	    offset: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MessageQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connection_id = source["connection_id"];
+	        this.subscription_id = source["subscription_id"];
+	        this.topic = source["topic"];
+	        this.key = source["key"];
+	        this.header_key = source["header_key"];
+	        this.header_value = source["header_value"];
+	        this.start = this.convertValues(source["start"], null);
+	        this.end = this.convertValues(source["end"], null);
+	        this.search = source["search"];
+	        this.limit = source["limit"];
+	        this.offset = source["offset"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ExportRequest {
+	    query: MessageQuery;
+	    format: string;
+	    delimiter: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.query = this.convertValues(source["query"], MessageQuery);
+	        this.format = source["format"];
+	        this.delimiter = source["delimiter"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class HistoryRecord {
+	    id: string;
+	    connection_id: string;
+	    type: string;
+	    topic: string;
+	    success: boolean;
+	    message: string;
+	    latency: number;
+	    // Go type: time
+	    created: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryRecord(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.connection_id = source["connection_id"];
+	        this.type = source["type"];
+	        this.topic = source["topic"];
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.latency = source["latency"];
+	        this.created = this.convertValues(source["created"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ImportRequest {
+	    file_path: string;
+	    format: string;
+	    delimiter: string;
+	    connection_id: string;
+	    topic: string;
+	    topic_map: Record<string, string>;
+	    key_map: Record<string, string>;
+	    preserve_partition: boolean;
+	    batch_size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.file_path = source["file_path"];
+	        this.format = source["format"];
+	        this.delimiter = source["delimiter"];
+	        this.connection_id = source["connection_id"];
+	        this.topic = source["topic"];
+	        this.topic_map = source["topic_map"];
+	        this.key_map = source["key_map"];
+	        this.preserve_partition = source["preserve_partition"];
+	        this.batch_size = source["batch_size"];
+	    }
+	}
+	export class ImportResult {
+	    file_path: string;
+	    total: number;
+	    produced: number;
+	    latency: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.file_path = source["file_path"];
+	        this.total = source["total"];
+	        this.produced = source["produced"];
+	        this.latency = source["latency"];
+	    }
+	}
+	export class LogEntry {
+	    level: string;
+	    message: string;
+	    // Go type: time
+	    timestamp: any;
+	    source: string;
+	    extra?: Record<string, any>;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.level = source["level"];
+	        this.message = source["message"];
+	        this.timestamp = this.convertValues(source["timestamp"], null);
+	        this.source = source["source"];
+	        this.extra = source["extra"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Message {
+	    id: string;
+	    topic: string;
+	    key: string;
+	    value: string;
+	    headers: Record<string, string>;
+	    partition: number;
+	    offset: number;
+	    // Go type: time
+	    timestamp: any;
+	    subscription_id?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Message(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.topic = source["topic"];
+	        this.key = source["key"];
+	        this.value = source["value"];
+	        this.headers = source["headers"];
+	        this.partition = source["partition"];
+	        this.offset = source["offset"];
+	        this.timestamp = this.convertValues(source["timestamp"], null);
+	        this.subscription_id = source["subscription_id"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class StoredMessage {
+	    id: string;
+	    message_id: string;
+	    subscription_id: string;
+	    connection_id: string;
+	    topic: string;
+	    key: string;
+	    value: string;
+	    headers: Record<string, string>;
+	    partition: number;
+	    offset: number;
+	    // Go type: time
+	    timestamp: any;
+	    // Go type: time
+	    created: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new StoredMessage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.message_id = source["message_id"];
+	        this.subscription_id = source["subscription_id"];
+	        this.connection_id = source["connection_id"];
+	        this.topic = source["topic"];
+	        this.key = source["key"];
+	        this.value = source["value"];
+	        this.headers = source["headers"];
+	        this.partition = source["partition"];
+	        this.offset = source["offset"];
+	        this.timestamp = this.convertValues(source["timestamp"], null);
+	        this.created = this.convertValues(source["created"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MessageSearchResult {
+	    total: number;
+	    messages: StoredMessage[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MessageSearchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.total = source["total"];
+	        this.messages = this.convertValues(source["messages"], StoredMessage);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MessageTemplate {
+	    id: string;
+	    name: string;
+	    description: string;
+	    collectionId: string;
+	    tags: string[];
+	    connectionId: string;
+	    topic: string;
+	    key: string;
+	    headers: Record<string, string>;
+	    partition?: number;
+	    content: string;
+	    variables: Record<string, string>;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new MessageTemplate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.collectionId = source["collectionId"];
+	        this.tags = source["tags"];
+	        this.connectionId = source["connectionId"];
+	        this.topic = source["topic"];
+	        this.key = source["key"];
+	        this.headers = source["headers"];
+	        this.partition = source["partition"];
+	        this.content = source["content"];
+	        this.variables = source["variables"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class NamespaceRequest {
+	    connection_id: string;
+	    namespace: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new NamespaceRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connection_id = source["connection_id"];
+	        this.namespace = source["namespace"];
+	    }
+	}
+	export class PendingEntry {
+	    id: string;
+	    consumer: string;
+	    idle: number;
+	    delivery_count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PendingEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.consumer = source["consumer"];
+	        this.idle = source["idle"];
+	        this.delivery_count = source["delivery_count"];
+	    }
+	}
+	export class ProduceRequest {
+	    connection_id: string;
+	    topic: string;
+	    key: string;
+	    value: string;
+	    headers: Record<string, string>;
+	    partition?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProduceRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connection_id = source["connection_id"];
+	        this.topic = source["topic"];
+	        this.key = source["key"];
+	        this.value = source["value"];
+	        this.headers = source["headers"];
+	        this.partition = source["partition"];
+	    }
+	}
+	export class RedrivePolicy {
+	    dead_letter_target_arn: string;
+	    max_receive_count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RedrivePolicy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dead_letter_target_arn = source["dead_letter_target_arn"];
+	        this.max_receive_count = source["max_receive_count"];
+	    }
+	}
+	export class QueueAttributes {
+	    name: string;
+	    url: string;
+	    arn: string;
+	    fifo: boolean;
+	    messages: number;
+	    in_flight: number;
+	    delayed: number;
+	    attributes: Record<string, string>;
+	    redrive?: RedrivePolicy;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueueAttributes(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.url = source["url"];
+	        this.arn = source["arn"];
+	        this.fifo = source["fifo"];
+	        this.messages = source["messages"];
+	        this.in_flight = source["in_flight"];
+	        this.delayed = source["delayed"];
+	        this.attributes = source["attributes"];
+	        this.redrive = this.convertValues(source["redrive"], RedrivePolicy);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class QueueInfo {
+	    name: string;
+	    address: string;
+	    routing_type: string;
+	    message_count: number;
+	    consumer_count: number;
+	    durable: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueueInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.address = source["address"];
+	        this.routing_type = source["routing_type"];
+	        this.message_count = source["message_count"];
+	        this.consumer_count = source["consumer_count"];
+	        this.durable = source["durable"];
+	    }
+	}
+	
+	export class RenderProduceRequest {
+	    connection_id: string;
+	    template_id: string;
+	    content: string;
+	    variables: Record<string, string>;
+	    count: number;
+	    seq_start: number;
+	    topic: string;
+	    key: string;
+	    headers: Record<string, string>;
+	    partition?: number;
+	    batch_size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RenderProduceRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connection_id = source["connection_id"];
+	        this.template_id = source["template_id"];
+	        this.content = source["content"];
+	        this.variables = source["variables"];
+	        this.count = source["count"];
+	        this.seq_start = source["seq_start"];
+	        this.topic = source["topic"];
+	        this.key = source["key"];
+	        this.headers = source["headers"];
+	        this.partition = source["partition"];
+	        this.batch_size = source["batch_size"];
+	    }
+	}
+	export class RenderProduceResult {
+	    total: number;
+	    produced: number;
+	    latency: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new ConnectionConfig(source);
+	        return new RenderProduceResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.type = source["type"];
-	        this.host = source["host"];
-	        this.port = source["port"];
-	        this.username = source["username"];
-	        this.password = source["password"];
-	        this.vhost = source["vhost"];
-	        this.group_id = source["group_id"];
-	        this.extra = source["extra"];
-	        this.created = this.convertValues(source["created"], null);
-	        this.updated = this.convertValues(source["updated"], null);
+	        this.total = source["total"];
+	        this.produced = source["produced"];
+	        this.latency = source["latency"];
+	    }
+	}
+	export class RenderRequest {
+	    template_id: string;
+	    content: string;
+	    variables: Record<string, string>;
+	    count: number;
+	    seq_start: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RenderRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.template_id = source["template_id"];
+	        this.content = source["content"];
+	        this.variables = source["variables"];
+	        this.count = source["count"];
+	        this.seq_start = source["seq_start"];
+	    }
+	}
+	export class ReplayRequest {
+	    message_ids: string[];
+	    query?: MessageQuery;
+	    capture_file: string;
+	    capture_format: string;
+	    connection_id: string;
+	    topic: string;
+	    topic_transform?: TopicTransform;
+	    preserve_keys: boolean;
+	    preserve_headers: boolean;
+	    preserve_timing: boolean;
+	    rate_per_second: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplayRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.message_ids = source["message_ids"];
+	        this.query = this.convertValues(source["query"], MessageQuery);
+	        this.capture_file = source["capture_file"];
+	        this.capture_format = source["capture_format"];
+	        this.connection_id = source["connection_id"];
+	        this.topic = source["topic"];
+	        this.topic_transform = this.convertValues(source["topic_transform"], TopicTransform);
+	        this.preserve_keys = source["preserve_keys"];
+	        this.preserve_headers = source["preserve_headers"];
+	        this.preserve_timing = source["preserve_timing"];
+	        this.rate_per_second = source["rate_per_second"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -54,83 +1628,122 @@ export namespace types {
 		    return a;
 		}
 	}
-	export class ConsumeRequest {
+	export class ReplayStatus {
+	    id: string;
 	    connection_id: string;
-	    topics: string[];
-	    group_id: string;
-	    auto_commit: boolean;
-	    from_beginning: boolean;
+	    state: string;
+	    total: number;
+	    sent: number;
+	    failed: number;
+	    error?: string;
+	    // Go type: time
+	    started_at: any;
+	    // Go type: time
+	    finished_at?: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new ConsumeRequest(source);
+	        return new ReplayStatus(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
 	        this.connection_id = source["connection_id"];
-	        this.topics = source["topics"];
-	        this.group_id = source["group_id"];
-	        this.auto_commit = source["auto_commit"];
-	        this.from_beginning = source["from_beginning"];
+	        this.state = source["state"];
+	        this.total = source["total"];
+	        this.sent = source["sent"];
+	        this.failed = source["failed"];
+	        this.error = source["error"];
+	        this.started_at = this.convertValues(source["started_at"], null);
+	        this.finished_at = this.convertValues(source["finished_at"], null);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class CreateTopicRequest {
+	export class ResetSubscriptionRequest {
 	    connection_id: string;
 	    topic: string;
-	    partitions: number;
-	    replicas: number;
+	    subscription: string;
+	    // Go type: time
+	    timestamp: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new CreateTopicRequest(source);
+	        return new ResetSubscriptionRequest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.connection_id = source["connection_id"];
 	        this.topic = source["topic"];
-	        this.partitions = source["partitions"];
-	        this.replicas = source["replicas"];
+	        this.subscription = source["subscription"];
+	        this.timestamp = this.convertValues(source["timestamp"], null);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class DeleteTopicRequest {
-	    connection_id: string;
-	    topic: string;
+	export class SecretStatus {
+	    locked: boolean;
+	    uses_passphrase: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new DeleteTopicRequest(source);
+	        return new SecretStatus(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.connection_id = source["connection_id"];
-	        this.topic = source["topic"];
+	        this.locked = source["locked"];
+	        this.uses_passphrase = source["uses_passphrase"];
 	    }
 	}
-	export class HistoryRecord {
-	    id: string;
+	export class SeekGroupRequest {
 	    connection_id: string;
-	    type: string;
+	    group: string;
 	    topic: string;
-	    success: boolean;
-	    message: string;
-	    latency: number;
 	    // Go type: time
-	    created: any;
+	    timestamp: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new HistoryRecord(source);
+	        return new SeekGroupRequest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
 	        this.connection_id = source["connection_id"];
-	        this.type = source["type"];
+	        this.group = source["group"];
 	        this.topic = source["topic"];
-	        this.success = source["success"];
-	        this.message = source["message"];
-	        this.latency = source["latency"];
-	        this.created = this.convertValues(source["created"], null);
+	        this.timestamp = this.convertValues(source["timestamp"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -151,25 +1764,57 @@ export namespace types {
 		    return a;
 		}
 	}
-	export class LogEntry {
-	    level: string;
-	    message: string;
-	    // Go type: time
-	    timestamp: any;
-	    source: string;
-	    extra?: Record<string, any>;
+	export class SetQueueAttributesRequest {
+	    connection_id: string;
+	    queue: string;
+	    attributes: Record<string, string>;
 	
 	    static createFrom(source: any = {}) {
-	        return new LogEntry(source);
+	        return new SetQueueAttributesRequest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.level = source["level"];
-	        this.message = source["message"];
-	        this.timestamp = this.convertValues(source["timestamp"], null);
-	        this.source = source["source"];
-	        this.extra = source["extra"];
+	        this.connection_id = source["connection_id"];
+	        this.queue = source["queue"];
+	        this.attributes = source["attributes"];
+	    }
+	}
+	
+	export class StreamConsumerInfo {
+	    name: string;
+	    pending: number;
+	    idle: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StreamConsumerInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.pending = source["pending"];
+	        this.idle = source["idle"];
+	    }
+	}
+	export class StreamGroupInfo {
+	    name: string;
+	    pending: number;
+	    last_delivered_id: string;
+	    lag: number;
+	    consumers: StreamConsumerInfo[];
+	
+	    static createFrom(source: any = {}) {
+	        return new StreamGroupInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.pending = source["pending"];
+	        this.last_delivered_id = source["last_delivered_id"];
+	        this.lag = source["lag"];
+	        this.consumers = this.convertValues(source["consumers"], StreamConsumerInfo);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -190,26 +1835,44 @@ export namespace types {
 		    return a;
 		}
 	}
-	export class MessageTemplate {
-	    id: string;
+	export class StreamGroupRequest {
+	    connection_id: string;
+	    stream: string;
+	    group: string;
+	    start_id: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StreamGroupRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connection_id = source["connection_id"];
+	        this.stream = source["stream"];
+	        this.group = source["group"];
+	        this.start_id = source["start_id"];
+	    }
+	}
+	export class StreamInfo {
 	    name: string;
-	    content: string;
-	    // Go type: time
-	    createdAt: any;
-	    // Go type: time
-	    updatedAt: any;
+	    length: number;
+	    first_entry_id: string;
+	    last_entry_id: string;
+	    last_generated_id: string;
+	    groups: StreamGroupInfo[];
 	
 	    static createFrom(source: any = {}) {
-	        return new MessageTemplate(source);
+	        return new StreamInfo(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
 	        this.name = source["name"];
-	        this.content = source["content"];
-	        this.createdAt = this.convertValues(source["createdAt"], null);
-	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	        this.length = source["length"];
+	        this.first_entry_id = source["first_entry_id"];
+	        this.last_entry_id = source["last_entry_id"];
+	        this.last_generated_id = source["last_generated_id"];
+	        this.groups = this.convertValues(source["groups"], StreamGroupInfo);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -230,27 +1893,81 @@ export namespace types {
 		    return a;
 		}
 	}
-	export class ProduceRequest {
+	export class SubscriptionInfo {
+	    name: string;
+	    type: string;
+	    backlog: number;
+	    consumers: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SubscriptionInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.backlog = source["backlog"];
+	        this.consumers = source["consumers"];
+	    }
+	}
+	export class SubscriptionRequest {
 	    connection_id: string;
 	    topic: string;
-	    key: string;
-	    value: string;
-	    headers: Record<string, string>;
-	    partition?: number;
+	    subscription: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new ProduceRequest(source);
+	        return new SubscriptionRequest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.connection_id = source["connection_id"];
 	        this.topic = source["topic"];
-	        this.key = source["key"];
-	        this.value = source["value"];
-	        this.headers = source["headers"];
-	        this.partition = source["partition"];
+	        this.subscription = source["subscription"];
+	    }
+	}
+	export class TemplateCollection {
+	    id: string;
+	    name: string;
+	    description: string;
+	    parentId: string;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new TemplateCollection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.parentId = source["parentId"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class TestResult {
 	    success: boolean;
@@ -268,6 +1985,7 @@ export namespace types {
 	        this.latency = source["latency"];
 	    }
 	}
+	
 	export class TopicInfo {
 	    name: string;
 	    partitions: number;
@@ -284,6 +2002,23 @@ export namespace types {
 	        this.replicas = source["replicas"];
 	    }
 	}
+	
+	export class TrimStreamRequest {
+	    connection_id: string;
+	    stream: string;
+	    max_len: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TrimStreamRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connection_id = source["connection_id"];
+	        this.stream = source["stream"];
+	        this.max_len = source["max_len"];
+	    }
+	}
 
 }
 
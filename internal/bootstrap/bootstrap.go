@@ -0,0 +1,161 @@
+// Package bootstrap 加载用户数据目录下的配置、数据库和凭据密钥并创建 AppService
+//
+// 桌面端和命令行共用同一套初始化流程，因此两者读写同一个 SQLite 连接库。
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/internal/config"
+	"mq-toolkit/internal/database"
+	"mq-toolkit/internal/logger"
+	"mq-toolkit/internal/secret"
+	"mq-toolkit/internal/server"
+	"mq-toolkit/internal/service"
+	"os"
+	"path/filepath"
+)
+
+// Options 初始化选项
+type Options struct {
+	// DataDir 用户数据目录，为空时使用系统配置目录下的 MQToolkit
+	DataDir string
+	// Emit 接收服务推送的事件
+	Emit service.EventEmitter
+	// MemoryFallback 数据库打开失败时改用内存数据库
+	MemoryFallback bool
+}
+
+// Runtime 初始化完成的应用依赖
+type Runtime struct {
+	DataDir    string
+	Config     *config.Config
+	DB         *database.Database
+	Secrets    *secret.Store
+	AppService *service.AppService
+}
+
+// UserDataDir 获取用户数据目录
+func UserDataDir() (string, error) {
+	// 获取用户配置目录
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		// 如果获取失败，使用用户主目录
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user directories: %w", err)
+		}
+		configDir = homeDir
+	}
+	return filepath.Join(configDir, "MQToolkit"), nil
+}
+
+// Start 加载配置、打开数据库和凭据密钥，并创建 AppService
+func Start(ctx context.Context, log *logger.Logger, opts Options) (*Runtime, error) {
+	userDataDir := opts.DataDir
+	if userDataDir == "" {
+		dir, err := UserDataDir()
+		if err != nil {
+			log.Error("Bootstrap", fmt.Sprintf("Failed to get user data directory: %v", err))
+			dir = "."
+		}
+		userDataDir = dir
+	}
+
+	cfg := loadConfig(log, userDataDir)
+
+	db, err := database.New(cfg.Database.Path)
+	if err != nil {
+		if !opts.MemoryFallback {
+			return nil, err
+		}
+		log.Error("Bootstrap", fmt.Sprintf("Failed to initialize database: %v", err))
+		db, err = database.New(":memory:")
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize memory database: %w", err)
+		}
+		log.Info("Bootstrap", "Using in-memory database")
+	}
+
+	// 打开凭据加密密钥
+	secrets, err := secret.Open(filepath.Join(userDataDir, "config"))
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open secret key file: %w", err)
+	}
+	if secrets.Locked() {
+		log.Info("Bootstrap", "Stored credentials are protected by a master passphrase, unlock to connect")
+	}
+
+	appService := service.NewAppService(ctx, db, log, secrets, opts.Emit)
+	appService.GetConfigService().SetDefaultTimeouts(cfg.Timeouts)
+	if days := cfg.Database.MessageRetentionDays; days > 0 {
+		if err := appService.GetMessageService().ClearOldMessages(ctx, days); err != nil {
+			log.Error("Bootstrap", fmt.Sprintf("Failed to clear messages older than %d days: %v", days, err))
+		}
+	}
+
+	return &Runtime{
+		DataDir:    userDataDir,
+		Config:     cfg,
+		DB:         db,
+		Secrets:    secrets,
+		AppService: appService,
+	}, nil
+}
+
+// loadConfig 从用户数据目录加载配置，失败时使用默认配置
+func loadConfig(log *logger.Logger, userDataDir string) *config.Config {
+	defaultDBPath := filepath.Join(userDataDir, "data", "mq-toolkit.db")
+
+	configPath := filepath.Join(userDataDir, "config", "app.json")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		log.Error("Bootstrap", fmt.Sprintf("Failed to load config from %s: %v", configPath, err))
+		cfg = config.DefaultConfig()
+		cfg.Database.Path = defaultDBPath
+		log.Info("Bootstrap", fmt.Sprintf("Using default configuration with data dir: %s", userDataDir))
+		return cfg
+	}
+
+	// 数据库路径是默认的相对路径时，转换为用户数据目录下的绝对路径
+	if filepath.Clean(cfg.Database.Path) == filepath.Join("data", "mq-toolkit.db") {
+		cfg.Database.Path = defaultDBPath
+		log.Info("Bootstrap", fmt.Sprintf("Updated database path to: %s", cfg.Database.Path))
+	}
+	return cfg
+}
+
+// StartServer 启动本地 HTTP API，addr 为空时使用配置的地址；配置未设置令牌时使用 config/api-token 中的令牌
+func (r *Runtime) StartServer(log *logger.Logger, hub *server.Hub, addr string) (*server.Server, error) {
+	if addr == "" {
+		addr = r.Config.Server.Addr
+	}
+	if addr == "" {
+		addr = config.DefaultServerAddr
+	}
+	token := r.Config.Server.Token
+	if token == "" {
+		var err error
+		if token, err = server.LoadToken(r.TokenPath()); err != nil {
+			return nil, err
+		}
+	}
+
+	srv := server.New(r.AppService, log, hub, token)
+	if err := srv.Start(addr); err != nil {
+		return nil, err
+	}
+	return srv, nil
+}
+
+// TokenPath 自动生成的 HTTP API 令牌文件路径
+func (r *Runtime) TokenPath() string {
+	return filepath.Join(r.DataDir, "config", "api-token")
+}
+
+// Close 停止后台任务、关闭客户端和数据库
+func (r *Runtime) Close() error {
+	r.AppService.Shutdown()
+	return r.DB.Close()
+}
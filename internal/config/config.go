@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"mq-toolkit/pkg/types"
 	"os"
 	"path/filepath"
 )
@@ -10,11 +11,27 @@ import (
 type Config struct {
 	Database DatabaseConfig `json:"database"`
 	Log      LogConfig      `json:"log"`
+	// Timeouts 全局默认超时（毫秒），连接未单独设置时使用
+	Timeouts types.TimeoutConfig `json:"timeouts"`
+	Server   ServerConfig        `json:"server"`
+}
+
+// DefaultServerAddr 本地 HTTP API 的默认监听地址，只接受本机连接
+const DefaultServerAddr = "127.0.0.1:7790"
+
+// ServerConfig 本地 HTTP API 配置
+type ServerConfig struct {
+	Enabled bool   `json:"enabled"`
+	Addr    string `json:"addr"`
+	// Token 访问令牌，为空时自动生成并保存在 config/api-token
+	Token string `json:"token"`
 }
 
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
 	Path string `json:"path"`
+	// MessageRetentionDays 启动时删除早于该天数保存的消费消息，0 表示一直保留
+	MessageRetentionDays int `json:"message_retention_days"`
 }
 
 // LogConfig 日志配置
@@ -33,6 +50,14 @@ func DefaultConfig() *Config {
 			Level:  "info",
 			Format: "json",
 		},
+		Timeouts: types.TimeoutConfig{
+			Dial:    types.DefaultDialTimeout.Milliseconds(),
+			Request: types.DefaultRequestTimeout.Milliseconds(),
+			Produce: types.DefaultProduceTimeout.Milliseconds(),
+		},
+		Server: ServerConfig{
+			Addr: DefaultServerAddr,
+		},
 	}
 }
 
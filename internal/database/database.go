@@ -13,7 +13,8 @@ import (
 
 // Database 数据库管理器
 type Database struct {
-	db *gorm.DB
+	db             *gorm.DB
+	fullTextSearch bool
 }
 
 // New 创建新的数据库实例
@@ -35,7 +36,7 @@ func New(dbPath string) (*Database, error) {
 	database := &Database{db: db}
 
 	// 自动迁移数据库表
-		if err := db.AutoMigrate(&types.ConnectionConfig{}, &types.HistoryRecord{}, &types.MessageTemplate{}); err != nil {
+	if err := database.migrate(); err != nil {
 		return nil, fmt.Errorf("failed to auto-migrate database schema: %w", err)
 	}
 
@@ -44,11 +45,69 @@ func New(dbPath string) (*Database, error) {
 
 // migrate 执行数据库迁移
 func (d *Database) migrate() error {
-	return d.db.AutoMigrate(
+	if err := d.db.AutoMigrate(
 		&types.ConnectionConfig{},
 		&types.HistoryRecord{},
 		&types.MessageTemplate{},
-	)
+		&types.TemplateCollection{},
+		&types.Environment{},
+		&types.StoredMessage{},
+		&types.BenchmarkReport{},
+	); err != nil {
+		return err
+	}
+
+	// 早期版本以 broker 的消息ID作为记录ID，补全这些记录的 message_id
+	if err := d.db.Exec("UPDATE consumed_messages SET message_id = id WHERE message_id IS NULL OR message_id = ''").Error; err != nil {
+		return err
+	}
+
+	d.setupMessageSearch()
+	return nil
+}
+
+// setupMessageSearch 为消费消息创建 FTS5 全文索引
+// 需要以 sqlite_fts5 标签编译，否则跳过，查询时回退为 LIKE 匹配
+func (d *Database) setupMessageSearch() {
+	err := d.db.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS consumed_messages_fts USING fts5(
+		topic, key, value, headers,
+		content='consumed_messages', content_rowid='rowid'
+	)`).Error
+	if err != nil {
+		// 当前构建不支持 FTS5，移除触发器，避免写入消费消息时出错
+		d.db.Exec("DROP TRIGGER IF EXISTS consumed_messages_ai")
+		d.db.Exec("DROP TRIGGER IF EXISTS consumed_messages_ad")
+		return
+	}
+
+	var triggers int64
+	d.db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name IN ('consumed_messages_ai', 'consumed_messages_ad')").Scan(&triggers)
+	if triggers < 2 {
+		statements := []string{
+			`CREATE TRIGGER IF NOT EXISTS consumed_messages_ai AFTER INSERT ON consumed_messages BEGIN
+				INSERT INTO consumed_messages_fts(rowid, topic, key, value, headers)
+				VALUES (new.rowid, new.topic, new.key, new.value, new.headers);
+			END`,
+			`CREATE TRIGGER IF NOT EXISTS consumed_messages_ad AFTER DELETE ON consumed_messages BEGIN
+				INSERT INTO consumed_messages_fts(consumed_messages_fts, rowid, topic, key, value, headers)
+				VALUES ('delete', old.rowid, old.topic, old.key, old.value, old.headers);
+			END`,
+			// 触发器缺失期间写入的消息需要重建索引
+			`INSERT INTO consumed_messages_fts(consumed_messages_fts) VALUES ('rebuild')`,
+		}
+		for _, stmt := range statements {
+			if err := d.db.Exec(stmt).Error; err != nil {
+				return
+			}
+		}
+	}
+
+	d.fullTextSearch = true
+}
+
+// FullTextSearch 是否启用了消息全文索引
+func (d *Database) FullTextSearch() bool {
+	return d.fullTextSearch
 }
 
 // GetDB 获取数据库实例
@@ -84,10 +143,11 @@ func (d *Database) Stats() (map[string]interface{}, error) {
 	stats := sqlDB.Stats()
 
 	// 获取表记录数
-	var connectionCount, historyCount, templateCount int64
+	var connectionCount, historyCount, templateCount, messageCount int64
 	d.db.Model(&types.ConnectionConfig{}).Count(&connectionCount)
 	d.db.Model(&types.HistoryRecord{}).Count(&historyCount)
 	d.db.Model(&types.MessageTemplate{}).Count(&templateCount)
+	d.db.Model(&types.StoredMessage{}).Count(&messageCount)
 
 	return map[string]interface{}{
 		"max_open_connections": stats.MaxOpenConnections,
@@ -102,6 +162,7 @@ func (d *Database) Stats() (map[string]interface{}, error) {
 		"connection_count":     connectionCount,
 		"history_count":        historyCount,
 		"template_count":       templateCount,
+		"message_count":        messageCount,
 	}, nil
 }
 
@@ -153,6 +214,22 @@ func (d *Database) Clean() error {
 		return fmt.Errorf("failed to clean message_templates: %w", err)
 	}
 
+	if err := d.db.Exec("DELETE FROM template_collections").Error; err != nil {
+		return fmt.Errorf("failed to clean template_collections: %w", err)
+	}
+
+	if err := d.db.Exec("DELETE FROM environments").Error; err != nil {
+		return fmt.Errorf("failed to clean environments: %w", err)
+	}
+
+	if err := d.db.Exec("DELETE FROM consumed_messages").Error; err != nil {
+		return fmt.Errorf("failed to clean consumed_messages: %w", err)
+	}
+
+	if err := d.db.Exec("DELETE FROM benchmark_reports").Error; err != nil {
+		return fmt.Errorf("failed to clean benchmark_reports: %w", err)
+	}
+
 	// 重置自增ID
 	if err := d.db.Exec("DELETE FROM sqlite_sequence").Error; err != nil {
 		return fmt.Errorf("failed to reset auto increment: %w", err)
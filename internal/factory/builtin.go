@@ -0,0 +1,15 @@
+package factory
+
+// Built-in backends, each registered by its package's init function.
+import (
+	_ "mq-toolkit/internal/mq/amqp10"
+	_ "mq-toolkit/internal/mq/kafka"
+	_ "mq-toolkit/internal/mq/memory"
+	_ "mq-toolkit/internal/mq/mqtt"
+	_ "mq-toolkit/internal/mq/nats"
+	_ "mq-toolkit/internal/mq/pulsar"
+	_ "mq-toolkit/internal/mq/rabbitmq"
+	_ "mq-toolkit/internal/mq/redis"
+	_ "mq-toolkit/internal/mq/rocketmq"
+	_ "mq-toolkit/internal/mq/sqs"
+)
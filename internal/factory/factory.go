@@ -1,11 +1,23 @@
+// Package factory creates message queue clients from the backends registered
+// with mq.Register.
+//
+// The built-in backends are linked in by builtin.go. An out-of-tree backend
+// registers itself from the init function of its own package; to build it into
+// the application, add a file to this package that imports the package behind
+// a build tag, for example internal/factory/backend_kinesis.go:
+//
+//	//go:build kinesis
+//
+//	package factory
+//
+//	import _ "example.com/mq-toolkit-kinesis"
+//
+// and build with -tags kinesis.
 package factory
 
 import (
 	"fmt"
 	"mq-toolkit/internal/mq"
-	"mq-toolkit/internal/mq/kafka"
-	"mq-toolkit/internal/mq/rabbitmq"
-	"mq-toolkit/internal/mq/rocketmq"
 	"mq-toolkit/pkg/types"
 )
 
@@ -24,54 +36,42 @@ func NewFactory() Factory {
 
 type factory struct{}
 
-func (f *factory) CreateClient(mqType types.MQType) (mq.Client, error) {
-	switch mqType {
-	case types.MQTypeKafka:
-		return kafka.NewClient(), nil
-	case types.MQTypeRabbitMQ:
-		return rabbitmq.NewClient(), nil
-	case types.MQTypeRocketMQ:
-		return rocketmq.NewClient(), nil
-	default:
+func lookup(mqType types.MQType) (*mq.Descriptor, error) {
+	d, ok := mq.Lookup(mqType)
+	if !ok {
 		return nil, fmt.Errorf("unsupported mq type: %s", mqType)
 	}
+	return d, nil
+}
+
+func (f *factory) CreateClient(mqType types.MQType) (mq.Client, error) {
+	d, err := lookup(mqType)
+	if err != nil {
+		return nil, err
+	}
+	return d.NewClient(), nil
 }
 
 func (f *factory) CreateAdmin(mqType types.MQType) (mq.Admin, error) {
-	switch mqType {
-	case types.MQTypeKafka:
-		return kafka.NewAdmin(), nil
-	case types.MQTypeRabbitMQ:
-		return rabbitmq.NewAdmin(), nil
-	case types.MQTypeRocketMQ:
-		return rocketmq.NewAdmin(), nil
-	default:
-		return nil, fmt.Errorf("unsupported mq type: %s", mqType)
+	d, err := lookup(mqType)
+	if err != nil {
+		return nil, err
 	}
+	return d.NewAdmin(), nil
 }
 
 func (f *factory) CreateProducer(mqType types.MQType) (mq.Producer, error) {
-	switch mqType {
-	case types.MQTypeKafka:
-		return kafka.NewProducer(), nil
-	case types.MQTypeRabbitMQ:
-		return rabbitmq.NewProducer(), nil
-	case types.MQTypeRocketMQ:
-		return rocketmq.NewProducer(), nil
-	default:
-		return nil, fmt.Errorf("unsupported mq type: %s", mqType)
+	d, err := lookup(mqType)
+	if err != nil {
+		return nil, err
 	}
+	return d.NewProducer(), nil
 }
 
 func (f *factory) CreateConsumer(mqType types.MQType) (mq.Consumer, error) {
-	switch mqType {
-	case types.MQTypeKafka:
-		return kafka.NewConsumer(), nil
-	case types.MQTypeRabbitMQ:
-		return rabbitmq.NewConsumer(), nil
-	case types.MQTypeRocketMQ:
-		return rocketmq.NewConsumer(), nil
-	default:
-		return nil, fmt.Errorf("unsupported mq type: %s", mqType)
+	d, err := lookup(mqType)
+	if err != nil {
+		return nil, err
 	}
+	return d.NewConsumer(), nil
 }
@@ -0,0 +1,318 @@
+// Package filter 实现用于筛选消息的简单表达式
+//
+// 语法示例：
+//
+//	topic == "orders" && headers.type != "test"
+//	key startsWith "user-" || value contains "error"
+//	partition >= 2 && !(value matches "^\\s*$")
+//
+// 字段：topic、key、value、partition、offset、headers.<名称>（或 header("名称")）。
+// 运算符：== != > >= < <= contains startsWith endsWith matches（=~）。
+// 单独的字段表示该字段非空。
+package filter
+
+import (
+	"fmt"
+	"mq-toolkit/pkg/types"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Filter 编译后的过滤表达式
+type Filter struct {
+	source string
+	root   node
+}
+
+// Compile 编译过滤表达式，空表达式返回 nil，nil 过滤器匹配所有消息
+func Compile(expr string) (*Filter, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.peek().text, p.peek().pos)
+	}
+	return &Filter{source: expr, root: root}, nil
+}
+
+// Match 判断消息是否满足表达式
+func (f *Filter) Match(msg *types.Message) bool {
+	if f == nil {
+		return true
+	}
+	return f.root.eval(msg)
+}
+
+// String 返回原始表达式
+func (f *Filter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.source
+}
+
+// node 表达式语法树节点
+type node interface {
+	eval(msg *types.Message) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n *andNode) eval(msg *types.Message) bool { return n.left.eval(msg) && n.right.eval(msg) }
+
+type orNode struct{ left, right node }
+
+func (n *orNode) eval(msg *types.Message) bool { return n.left.eval(msg) || n.right.eval(msg) }
+
+type notNode struct{ inner node }
+
+func (n *notNode) eval(msg *types.Message) bool { return !n.inner.eval(msg) }
+
+// field 消息字段访问
+type field struct {
+	name   string
+	header string
+}
+
+func (f field) value(msg *types.Message) string {
+	switch f.name {
+	case "topic":
+		return msg.Topic
+	case "key":
+		return msg.Key
+	case "value":
+		return msg.Value
+	case "partition":
+		return strconv.FormatInt(int64(msg.Partition), 10)
+	case "offset":
+		return strconv.FormatInt(msg.Offset, 10)
+	case "headers":
+		return msg.Headers[f.header]
+	}
+	return ""
+}
+
+type existsNode struct{ field field }
+
+func (n *existsNode) eval(msg *types.Message) bool { return n.field.value(msg) != "" }
+
+type compareNode struct {
+	field   field
+	op      string
+	literal string
+	number  *float64
+	pattern *regexp.Regexp
+}
+
+func (n *compareNode) eval(msg *types.Message) bool {
+	actual := n.field.value(msg)
+	switch n.op {
+	case "==":
+		return actual == n.literal
+	case "!=":
+		return actual != n.literal
+	case "contains":
+		return strings.Contains(actual, n.literal)
+	case "startsWith":
+		return strings.HasPrefix(actual, n.literal)
+	case "endsWith":
+		return strings.HasSuffix(actual, n.literal)
+	case "matches":
+		return n.pattern.MatchString(actual)
+	}
+
+	// 数值比较，字段无法解析为数字时视为不匹配
+	value, err := strconv.ParseFloat(strings.TrimSpace(actual), 64)
+	if err != nil || n.number == nil {
+		return false
+	}
+	switch n.op {
+	case ">":
+		return value > *n.number
+	case ">=":
+		return value >= *n.number
+	case "<":
+		return value < *n.number
+	case "<=":
+		return value <= *n.number
+	}
+	return false
+}
+
+// parser 递归下降解析器
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool { return p.pos >= len(p.tokens) }
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{kind: tokenEOF, text: "end of expression", pos: -1}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if !p.done() {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOperator && p.peek().text == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOperator && p.peek().text == "&&" {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenOperator && t.text == "!":
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{inner: inner}, nil
+	case t.kind == tokenLParen:
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokenRParen {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	f, err := p.parseField()
+	if err != nil {
+		return nil, err
+	}
+
+	op := p.peek()
+	if !isComparison(op) {
+		return &existsNode{field: f}, nil
+	}
+	p.next()
+
+	lit := p.next()
+	if lit.kind != tokenString && lit.kind != tokenNumber {
+		return nil, fmt.Errorf("expected a value after %q at position %d", op.text, op.pos)
+	}
+
+	n := &compareNode{field: f, op: normalizeOp(op.text), literal: lit.text}
+	switch n.op {
+	case "matches":
+		pattern, err := regexp.Compile(lit.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", lit.text, err)
+		}
+		n.pattern = pattern
+	case ">", ">=", "<", "<=":
+		number, err := strconv.ParseFloat(lit.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("operator %s requires a number, got %q", op.text, lit.text)
+		}
+		n.number = &number
+	}
+	return n, nil
+}
+
+func (p *parser) parseField() (field, error) {
+	t := p.next()
+	if t.kind != tokenIdent {
+		return field{}, fmt.Errorf("expected a field at position %d, got %q", t.pos, t.text)
+	}
+
+	name := t.text
+	switch {
+	case name == "topic" || name == "key" || name == "value" || name == "partition" || name == "offset":
+		return field{name: name}, nil
+	case strings.HasPrefix(name, "headers.") && len(name) > len("headers."):
+		return field{name: "headers", header: strings.TrimPrefix(name, "headers.")}, nil
+	case name == "header" || name == "headers":
+		// header("名称") 形式，用于包含特殊字符的头名称
+		if p.next().kind != tokenLParen {
+			return field{}, fmt.Errorf("expected ( after %s", name)
+		}
+		arg := p.next()
+		if arg.kind != tokenString {
+			return field{}, fmt.Errorf("expected a header name string at position %d", arg.pos)
+		}
+		if p.next().kind != tokenRParen {
+			return field{}, fmt.Errorf("expected ) after header name")
+		}
+		return field{name: "headers", header: arg.text}, nil
+	}
+	return field{}, fmt.Errorf("unknown field %q", name)
+}
+
+func isComparison(t token) bool {
+	if t.kind == tokenOperator {
+		switch t.text {
+		case "==", "!=", ">", ">=", "<", "<=", "=~":
+			return true
+		}
+	}
+	if t.kind == tokenIdent {
+		switch t.text {
+		case "contains", "startsWith", "endsWith", "matches":
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeOp(op string) string {
+	if op == "=~" {
+		return "matches"
+	}
+	return op
+}
@@ -0,0 +1,116 @@
+package filter
+
+import (
+	"mq-toolkit/pkg/types"
+	"testing"
+)
+
+// testMessage 各用例共用的消息
+var testMessage = &types.Message{
+	Topic:     "orders",
+	Key:       "user-42",
+	Value:     `{"status": "error"}`,
+	Partition: 3,
+	Offset:    100,
+	Headers:   map[string]string{"type": "created", "x-trace.id": "abc"},
+}
+
+func TestMatchOperators(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`topic == "orders"`, true},
+		{`topic == "payments"`, false},
+		{`topic != "payments"`, true},
+		{`key startsWith "user-"`, true},
+		{`key endsWith "-42"`, true},
+		{`key endsWith "-7"`, false},
+		{`value contains "error"`, true},
+		{`value matches "^\\{.*\\}$"`, true},
+		{`value =~ "^ok"`, false},
+		{`partition > 2`, true},
+		{`partition >= 3`, true},
+		{`partition < 3`, false},
+		{`partition <= 3`, true},
+		{`offset > -1.5`, true},
+		{`key > 1`, false}, // 非数字字段与数字比较不匹配
+		{`headers.type == "created"`, true},
+		{`header("x-trace.id") == 'abc'`, true},
+		{`headers.missing == ""`, true},
+		{`headers.type`, true},
+		{`headers.missing`, false},
+		{`!headers.missing`, true},
+	}
+	for _, tt := range tests {
+		f, err := Compile(tt.expr)
+		if err != nil {
+			t.Errorf("Compile(%s) failed: %v", tt.expr, err)
+			continue
+		}
+		if got := f.Match(testMessage); got != tt.want {
+			t.Errorf("%s matched %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestMatchPrecedence(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		// && 优先于 ||
+		{`topic == "orders" || topic == "x" && key == "y"`, true},
+		{`topic == "x" && key == "y" || partition == 3`, true},
+		{`(topic == "orders" || topic == "x") && key == "y"`, false},
+		// ! 作用于紧随的比较或括号，优先于 &&
+		{`!topic == "x" && key == "user-42"`, true},
+		{`!(topic == "orders" && key == "user-42")`, false},
+		{`!!topic`, true},
+		// 同级运算从左到右结合
+		{`topic == "x" || key == "y" || partition == 3`, true},
+		{`topic == "orders" && key == "user-42" && partition == 4`, false},
+	}
+	for _, tt := range tests {
+		f, err := Compile(tt.expr)
+		if err != nil {
+			t.Errorf("Compile(%s) failed: %v", tt.expr, err)
+			continue
+		}
+		if got := f.Match(testMessage); got != tt.want {
+			t.Errorf("%s matched %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []string{
+		`value ==`,
+		`value == "unterminated`,
+		`size > 1`,
+		`partition > "two"`,
+		`value matches "("`,
+		`(topic == "orders"`,
+		`topic == "orders")`,
+		`topic == "orders" &&`,
+		`topic # "orders"`,
+		`header(type) == "created"`,
+		`header("type" == "created"`,
+		`&& topic`,
+	}
+	for _, expr := range tests {
+		if _, err := Compile(expr); err == nil {
+			t.Errorf("Compile(%s) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestEmptyFilterMatchesAll(t *testing.T) {
+	f, err := Compile("  ")
+	if err != nil || f != nil {
+		t.Fatalf("Compile of a blank expression returned %v, %v; want a nil filter", f, err)
+	}
+	if !f.Match(testMessage) || f.String() != "" {
+		t.Fatal("nil filter should match every message and print as an empty string")
+	}
+}
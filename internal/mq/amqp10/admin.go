@@ -0,0 +1,636 @@
+package amqp10
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-amqp"
+)
+
+// listPageSize 分页列出地址、队列和消费者时每页的条数
+const listPageSize = 500
+
+// Admin AMQP 1.0管理客户端实现
+//
+// 连接测试通过 AMQP 连接完成，适用于任何 AMQP 1.0 broker。地址和队列的管理通过 ActiveMQ Artemis 的
+// Jolokia 管理接口调用 broker 的 MBean 操作，管理接口地址取 Extra["management_url"]，默认为第一个
+// broker 主机的 8161 端口（/console/jolokia）。主题对应地址，消费组对应队列。其他 broker（如 Service Bus
+// 模拟器）只能收发消息，管理操作返回连接错误。
+type Admin struct {
+	conn    *amqp.Conn
+	config  *types.ConnectionConfig
+	tunnel  *tunnel.Tunnel
+	baseURL string
+	http    *http.Client
+
+	mu     sync.Mutex
+	broker string // broker 的 MBean 名称，第一次调用管理接口时查询
+}
+
+// NewAdmin 创建AMQP 1.0管理客户端
+func NewAdmin() mq.Admin {
+	return &Admin{}
+}
+
+// jolokiaError Jolokia 返回的错误
+type jolokiaError struct {
+	Status  int
+	Type    string // Java 异常类名
+	Message string
+}
+
+func (e *jolokiaError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("management API returned status %d", e.Status)
+	}
+	return fmt.Sprintf("management API returned status %d: %s", e.Status, e.Message)
+}
+
+// Connect 连接到AMQP 1.0 broker，管理接口在第一次调用时才访问
+func (a *Admin) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	if config.Type != types.MQTypeAMQP10 {
+		return utils.NewValidationError("Invalid MQ type for AMQP 1.0 admin", string(config.Type))
+	}
+
+	t, err := tunnel.OpenIfEnabled(config.Tunnel)
+	if err != nil {
+		return utils.NewConnectionError("Failed to open tunnel", err)
+	}
+
+	conn, err := dial(ctx, config, t, "")
+	if err != nil {
+		t.Close()
+		return err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.Extra["tls_insecure"] == "true" {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	if t != nil {
+		transport.Proxy = nil
+		transport.DialContext = t.DialContext
+	}
+
+	a.conn = conn
+	a.config = config
+	a.tunnel = t
+	a.baseURL = managementURL(config)
+	a.http = &http.Client{Transport: transport}
+	return nil
+}
+
+// managementURL 返回 Jolokia 管理接口地址，默认为第一个 broker 主机的 8161 端口
+func managementURL(config *types.ConnectionConfig) string {
+	if u := config.Extra["management_url"]; u != "" {
+		return strings.TrimRight(u, "/")
+	}
+	host := config.Host
+	if addresses := config.Addresses(); len(addresses) > 0 {
+		if h, _, err := net.SplitHostPort(addresses[0]); err == nil {
+			host = h
+		}
+	}
+	scheme := "http"
+	if config.Extra["tls"] == "true" {
+		scheme = "https"
+	}
+	return scheme + "://" + net.JoinHostPort(host, "8161") + "/console/jolokia"
+}
+
+// TestConnection 测试连接，建立并关闭一个会话，并报告 broker 的产品名称和版本
+func (a *Admin) TestConnection(ctx context.Context) *types.TestResult {
+	start := time.Now()
+
+	if a.conn == nil {
+		return &types.TestResult{
+			Success: false,
+			Message: "Not connected to AMQP broker",
+			Latency: time.Since(start).Milliseconds(),
+		}
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := a.ping(ctx); err != nil {
+		err = utils.WrapTimeout(ctx, err, "Opening AMQP session", timeout)
+		return &types.TestResult{
+			Success: false,
+			Message: fmt.Sprintf("Failed to open AMQP session: %v", err),
+			Latency: time.Since(start).Milliseconds(),
+		}
+	}
+
+	message := "Connected successfully to AMQP broker"
+	if product, ok := a.conn.Properties()["product"]; ok {
+		message += fmt.Sprintf(" %v", product)
+		if version, ok := a.conn.Properties()["version"]; ok {
+			message += fmt.Sprintf(" %v", version)
+		}
+	}
+	return &types.TestResult{
+		Success: true,
+		Message: message,
+		Latency: time.Since(start).Milliseconds(),
+	}
+}
+
+// Ping 建立并关闭一个会话，broker 不可达时返回错误
+func (a *Admin) Ping(ctx context.Context) error {
+	if a.conn == nil {
+		return utils.NewConnectionError("Not connected to AMQP broker", nil)
+	}
+	if err := a.ping(ctx); err != nil {
+		return utils.NewConnectionError("AMQP broker is unreachable", err)
+	}
+	return nil
+}
+
+func (a *Admin) ping(ctx context.Context) error {
+	session, err := a.conn.NewSession(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return session.Close(ctx)
+}
+
+// jolokia 发送 Jolokia 请求，out 不为 nil 时解析响应中的 value
+func (a *Admin) jolokia(ctx context.Context, request map[string]interface{}, out interface{}) error {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	// Artemis 只接受 Origin 与允许列表匹配的请求
+	if u, err := url.Parse(a.baseURL); err == nil {
+		req.Header.Set("Origin", u.Scheme+"://"+u.Host)
+	}
+	if a.config.Username != "" {
+		req.SetBasicAuth(a.config.Username, a.config.Password)
+	}
+
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return &jolokiaError{Status: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+
+	var envelope struct {
+		Status    int             `json:"status"`
+		Value     json.RawMessage `json:"value"`
+		ErrorType string          `json:"error_type"`
+		Error     string          `json:"error"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("failed to parse management API response: %v", err)
+	}
+	if envelope.Status != http.StatusOK {
+		return &jolokiaError{Status: envelope.Status, Type: envelope.ErrorType, Message: envelope.Error}
+	}
+	if out == nil || len(envelope.Value) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Value, out); err != nil {
+		return fmt.Errorf("failed to parse management API response: %v", err)
+	}
+	return nil
+}
+
+// brokerMBean 返回 broker 的 MBean 名称，例如 org.apache.activemq.artemis:broker="0.0.0.0"
+func (a *Admin) brokerMBean(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.broker != "" {
+		return a.broker, nil
+	}
+	var names []string
+	err := a.jolokia(ctx, map[string]interface{}{
+		"type":  "search",
+		"mbean": "org.apache.activemq.artemis:broker=*",
+	}, &names)
+	if err != nil {
+		return "", err
+	}
+	if len(names) == 0 {
+		return "", fmt.Errorf("no Artemis broker MBean found")
+	}
+	a.broker = names[0]
+	return a.broker, nil
+}
+
+// exec 调用 broker MBean 的操作，operation 带参数签名以区分重载
+func (a *Admin) exec(ctx context.Context, operation string, args []interface{}, out interface{}) error {
+	mbean, err := a.brokerMBean(ctx)
+	if err != nil {
+		return err
+	}
+	return a.jolokia(ctx, map[string]interface{}{
+		"type":      "exec",
+		"mbean":     mbean,
+		"operation": operation,
+		"arguments": args,
+	}, out)
+}
+
+// list 分页调用 listAddresses、listQueues 或 listConsumers，返回所有行
+func (a *Admin) list(ctx context.Context, operation string, filter map[string]string) ([]map[string]interface{}, error) {
+	options := map[string]string{"field": "", "operation": "", "value": "", "sortOrder": "asc", "sortColumn": "name", "sortField": "name"}
+	for key, value := range filter {
+		options[key] = value
+	}
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]interface{}
+	for page := 1; ; page++ {
+		var text string
+		if err := a.exec(ctx, operation+"(java.lang.String,int,int)", []interface{}{string(optionsJSON), page, listPageSize}, &text); err != nil {
+			return nil, err
+		}
+		var result struct {
+			Data  []map[string]interface{} `json:"data"`
+			Count int                      `json:"count"`
+		}
+		if err := json.Unmarshal([]byte(text), &result); err != nil {
+			return nil, fmt.Errorf("failed to parse %s result: %v", operation, err)
+		}
+		rows = append(rows, result.Data...)
+		if len(result.Data) < listPageSize || len(rows) >= result.Count {
+			return rows, nil
+		}
+	}
+}
+
+// managementFailure 把管理接口的错误转换为应用错误，resource 不为空时对象不存在转换为未找到错误
+func (a *Admin) managementFailure(message string, err error, resource, id string) error {
+	var jErr *jolokiaError
+	if !errors.As(err, &jErr) {
+		return utils.NewConnectionError(message, err)
+	}
+	switch {
+	case jErr.Status == http.StatusUnauthorized || jErr.Status == http.StatusForbidden || strings.Contains(jErr.Type, "SecurityException"):
+		return utils.NewAuthError(message + ": " + jErr.Error())
+	case resource != "" && (strings.Contains(jErr.Type, "NonExistent") || strings.Contains(jErr.Type, "DoesNotExist")):
+		return utils.NewNotFoundError(resource, id)
+	case strings.Contains(jErr.Type, "Exists") || strings.Contains(jErr.Type, "IllegalArgument") || strings.Contains(jErr.Type, "IllegalState"):
+		return utils.NewValidationError(message, jErr.Message)
+	default:
+		return utils.NewConnectionError(message, err)
+	}
+}
+
+// ListTopics 列出地址，不包含 broker 内部地址
+func (a *Admin) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
+	addresses, err := a.ListAddresses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	topics := make([]types.TopicInfo, 0, len(addresses))
+	for _, address := range addresses {
+		topics = append(topics, types.TopicInfo{
+			Name:       address.Name,
+			Partitions: 1, // 地址没有分区概念
+			Replicas:   1,
+		})
+	}
+	return topics, nil
+}
+
+// CreateTopic 创建 ANYCAST 地址和同名的持久队列，发送到该地址的消息保存在队列中；partitions 和 replicas 不适用
+func (a *Admin) CreateTopic(ctx context.Context, topic string, partitions int32, replicas int16) error {
+	return a.CreateQueue(ctx, topic, topic, "ANYCAST", true)
+}
+
+// DeleteTopic 删除地址及其上的所有队列
+func (a *Admin) DeleteTopic(ctx context.Context, topic string) error {
+	if a.conn == nil {
+		return utils.NewConnectionError("Not connected to AMQP broker", nil)
+	}
+	if topic == "" {
+		return utils.NewValidationError("Address is required", "")
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := a.exec(ctx, "deleteAddress(java.lang.String,boolean)", []interface{}{topic, true}, nil); err != nil {
+		return utils.WrapTimeout(ctx, a.managementFailure("Failed to delete address "+topic, err, "Address", topic), "Deleting address", timeout)
+	}
+	return nil
+}
+
+// ListConsumerGroups 列出队列，成员为连接到队列的消费者
+func (a *Admin) ListConsumerGroups(ctx context.Context) ([]types.ConsumerGroup, error) {
+	if a.conn == nil {
+		return nil, utils.NewConnectionError("Not connected to AMQP broker", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	queues, err := a.queues(ctx, "")
+	if err != nil {
+		return nil, utils.WrapTimeout(ctx, a.managementFailure("Failed to list queues", err, "", ""), "Listing consumer groups", timeout)
+	}
+	consumers, err := a.list(ctx, "listConsumers", nil)
+	if err != nil {
+		return nil, utils.WrapTimeout(ctx, a.managementFailure("Failed to list consumers", err, "", ""), "Listing consumer groups", timeout)
+	}
+
+	members := make(map[string]map[string]bool)
+	for _, consumer := range consumers {
+		queue := stringField(consumer, "queue")
+		name := stringField(consumer, "clientID")
+		if name == "" {
+			name = stringField(consumer, "remoteAddress")
+		}
+		if queue == "" || name == "" {
+			continue
+		}
+		if members[queue] == nil {
+			members[queue] = make(map[string]bool)
+		}
+		members[queue][name] = true
+	}
+
+	groups := make([]types.ConsumerGroup, 0, len(queues))
+	for _, queue := range queues {
+		groups = append(groups, types.ConsumerGroup{
+			ID:      queue.Name,
+			Members: sortedKeys(members[queue.Name]),
+			Topics:  []string{queue.Address},
+		})
+	}
+	return groups, nil
+}
+
+// ListAddresses 列出地址，不包含 broker 内部地址
+func (a *Admin) ListAddresses(ctx context.Context) ([]types.AddressInfo, error) {
+	if a.conn == nil {
+		return nil, utils.NewConnectionError("Not connected to AMQP broker", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	rows, err := a.list(ctx, "listAddresses", nil)
+	if err != nil {
+		return nil, utils.WrapTimeout(ctx, a.managementFailure("Failed to list addresses", err, "", ""), "Listing addresses", timeout)
+	}
+
+	addresses := make([]types.AddressInfo, 0, len(rows))
+	for _, row := range rows {
+		if boolField(row, "internal") {
+			continue
+		}
+		addresses = append(addresses, types.AddressInfo{
+			Name:         stringField(row, "name"),
+			RoutingTypes: parseRoutingTypes(stringField(row, "routingTypes")),
+			QueueCount:   int(intField(row, "queueCount")),
+		})
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i].Name < addresses[j].Name })
+	return addresses, nil
+}
+
+// CreateAddress 创建地址，routingType 为空时使用 ANYCAST
+func (a *Admin) CreateAddress(ctx context.Context, address, routingType string) error {
+	if a.conn == nil {
+		return utils.NewConnectionError("Not connected to AMQP broker", nil)
+	}
+	if address == "" {
+		return utils.NewValidationError("Address is required", "")
+	}
+	rt, err := routingTypeOf(routingType)
+	if err != nil {
+		return err
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := a.exec(ctx, "createAddress(java.lang.String,java.lang.String)", []interface{}{address, rt}, nil); err != nil {
+		return utils.WrapTimeout(ctx, a.managementFailure("Failed to create address "+address, err, "", ""), "Creating address", timeout)
+	}
+	return nil
+}
+
+// ListQueues 列出队列，address 不为空时只列出该地址上的队列
+func (a *Admin) ListQueues(ctx context.Context, address string) ([]types.QueueInfo, error) {
+	if a.conn == nil {
+		return nil, utils.NewConnectionError("Not connected to AMQP broker", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	queues, err := a.queues(ctx, address)
+	if err != nil {
+		return nil, utils.WrapTimeout(ctx, a.managementFailure("Failed to list queues", err, "", ""), "Listing queues", timeout)
+	}
+	return queues, nil
+}
+
+// queues 返回队列，按名称排序
+func (a *Admin) queues(ctx context.Context, address string) ([]types.QueueInfo, error) {
+	var filter map[string]string
+	if address != "" {
+		filter = map[string]string{"field": "address", "operation": "EQUALS", "value": address}
+	}
+	rows, err := a.list(ctx, "listQueues", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	queues := make([]types.QueueInfo, 0, len(rows))
+	for _, row := range rows {
+		queue := types.QueueInfo{
+			Name:          stringField(row, "name"),
+			Address:       stringField(row, "address"),
+			RoutingType:   stringField(row, "routingType"),
+			MessageCount:  intField(row, "messageCount"),
+			ConsumerCount: int(intField(row, "consumerCount")),
+			Durable:       boolField(row, "durable"),
+		}
+		if address != "" && queue.Address != address {
+			continue
+		}
+		queues = append(queues, queue)
+	}
+	sort.Slice(queues, func(i, j int) bool { return queues[i].Name < queues[j].Name })
+	return queues, nil
+}
+
+// CreateQueue 在地址上创建队列，地址不存在时以相同的路由类型自动创建；address 为空时使用队列名
+func (a *Admin) CreateQueue(ctx context.Context, address, queue, routingType string, durable bool) error {
+	if a.conn == nil {
+		return utils.NewConnectionError("Not connected to AMQP broker", nil)
+	}
+	if queue == "" {
+		return utils.NewValidationError("Queue name is required", "")
+	}
+	if address == "" {
+		address = queue
+	}
+	rt, err := routingTypeOf(routingType)
+	if err != nil {
+		return err
+	}
+
+	configuration, err := json.Marshal(map[string]interface{}{
+		"name":                queue,
+		"address":             address,
+		"routing-type":        rt,
+		"durable":             durable,
+		"auto-create-address": true,
+	})
+	if err != nil {
+		return err
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := a.exec(ctx, "createQueue(java.lang.String,boolean)", []interface{}{string(configuration), false}, nil); err != nil {
+		return utils.WrapTimeout(ctx, a.managementFailure("Failed to create queue "+queue, err, "", ""), "Creating queue", timeout)
+	}
+	return nil
+}
+
+// DeleteQueue 删除队列，断开队列上的消费者
+func (a *Admin) DeleteQueue(ctx context.Context, queue string) error {
+	if a.conn == nil {
+		return utils.NewConnectionError("Not connected to AMQP broker", nil)
+	}
+	if queue == "" {
+		return utils.NewValidationError("Queue name is required", "")
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := a.exec(ctx, "destroyQueue(java.lang.String,boolean)", []interface{}{queue, true}, nil); err != nil {
+		return utils.WrapTimeout(ctx, a.managementFailure("Failed to delete queue "+queue, err, "Queue", queue), "Deleting queue", timeout)
+	}
+	return nil
+}
+
+// routingTypeOf 规范化路由类型，为空时使用 ANYCAST
+func routingTypeOf(value string) (string, error) {
+	switch strings.ToUpper(value) {
+	case "", "ANYCAST":
+		return "ANYCAST", nil
+	case "MULTICAST":
+		return "MULTICAST", nil
+	default:
+		return "", utils.NewValidationError("Invalid routing type, expected ANYCAST or MULTICAST", value)
+	}
+}
+
+// parseRoutingTypes 解析 "[ANYCAST, MULTICAST]" 或 "ANYCAST,MULTICAST" 形式的路由类型列表
+func parseRoutingTypes(text string) []string {
+	text = strings.Trim(text, "[] ")
+	routingTypes := []string{}
+	for _, part := range strings.Split(text, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			routingTypes = append(routingTypes, part)
+		}
+	}
+	sort.Strings(routingTypes)
+	return routingTypes
+}
+
+// 不同版本的 broker 把数值和布尔字段编码为 JSON 数值、布尔值或字符串，以下函数统一转换
+
+func stringField(row map[string]interface{}, key string) string {
+	switch v := row[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func intField(row map[string]interface{}, key string) int64 {
+	switch v := row[key].(type) {
+	case float64:
+		return int64(v)
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	default:
+		return 0
+	}
+}
+
+func boolField(row map[string]interface{}, key string) bool {
+	switch v := row[key].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
+
+// Close 关闭管理客户端
+func (a *Admin) Close() error {
+	if a.conn != nil {
+		a.conn.Close()
+		a.conn = nil
+	}
+	if a.http != nil {
+		a.http.CloseIdleConnections()
+		a.http = nil
+	}
+	a.config = nil
+	a.broker = ""
+	a.tunnel.Close()
+	a.tunnel = nil
+	return nil
+}
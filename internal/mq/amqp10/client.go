@@ -0,0 +1,382 @@
+package amqp10
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/go-amqp"
+)
+
+// 消息属性（properties 段）和消息头（header 段）的字段对应的消息头名称。
+// 发送时这些消息头写入对应字段，其余消息头作为应用属性（application-properties）；
+// 消费时字段和应用属性都放入消息头，DeliveryCountHeader 只在消费时出现。
+const (
+	MessageIDHeader     = "Amqp-Message-Id"
+	CorrelationIDHeader = "Amqp-Correlation-Id"
+	SubjectHeader       = "Amqp-Subject"
+	ReplyToHeader       = "Amqp-Reply-To"
+	ContentTypeHeader   = "Amqp-Content-Type"
+	ToHeader            = "Amqp-To"
+	PriorityHeader      = "Amqp-Priority"
+	TTLHeader           = "Amqp-Ttl" // 毫秒
+	DeliveryCountHeader = "Amqp-Delivery-Count"
+)
+
+// Client AMQP 1.0完整客户端实现
+type Client struct {
+	producer mq.Producer
+	consumer mq.Consumer
+	admin    mq.Admin
+	config   *types.ConnectionConfig
+}
+
+// NewClient 创建AMQP 1.0完整客户端
+func NewClient() mq.Client {
+	return &Client{
+		producer: NewProducer(),
+		consumer: NewConsumer(),
+		admin:    NewAdmin(),
+	}
+}
+
+// Connect 连接到AMQP 1.0 broker
+func (c *Client) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	c.config = config
+
+	if err := c.producer.Connect(ctx, config); err != nil {
+		return err
+	}
+
+	if err := c.consumer.Connect(ctx, config); err != nil {
+		c.producer.Close()
+		return err
+	}
+
+	if err := c.admin.Connect(ctx, config); err != nil {
+		c.producer.Close()
+		c.consumer.Close()
+		return err
+	}
+
+	return nil
+}
+
+// Produce 发送消息
+func (c *Client) Produce(ctx context.Context, req *types.ProduceRequest) error {
+	return c.producer.Produce(ctx, req)
+}
+
+// ProduceBatch 批量发送消息
+func (c *Client) ProduceBatch(ctx context.Context, reqs []*types.ProduceRequest) error {
+	return c.producer.ProduceBatch(ctx, reqs)
+}
+
+// Subscribe 订阅地址
+func (c *Client) Subscribe(ctx context.Context, req *types.ConsumeRequest) error {
+	return c.consumer.Subscribe(ctx, req)
+}
+
+// Consume 消费消息
+func (c *Client) Consume(ctx context.Context, handler mq.MessageHandler) error {
+	return c.consumer.Consume(ctx, handler)
+}
+
+// TestConnection 测试连接
+func (c *Client) TestConnection(ctx context.Context) *types.TestResult {
+	return c.admin.TestConnection(ctx)
+}
+
+// ListTopics 列出地址
+func (c *Client) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
+	return c.admin.ListTopics(ctx)
+}
+
+// CreateTopic 创建地址
+func (c *Client) CreateTopic(ctx context.Context, topic string, partitions int32, replicas int16) error {
+	return c.admin.CreateTopic(ctx, topic, partitions, replicas)
+}
+
+// DeleteTopic 删除地址
+func (c *Client) DeleteTopic(ctx context.Context, topic string) error {
+	return c.admin.DeleteTopic(ctx, topic)
+}
+
+// ListConsumerGroups 列出队列
+func (c *Client) ListConsumerGroups(ctx context.Context) ([]types.ConsumerGroup, error) {
+	return c.admin.(mq.GroupAdmin).ListConsumerGroups(ctx)
+}
+
+// ListAddresses 列出地址及其路由类型
+func (c *Client) ListAddresses(ctx context.Context) ([]types.AddressInfo, error) {
+	return c.admin.(mq.AddressAdmin).ListAddresses(ctx)
+}
+
+// CreateAddress 创建地址
+func (c *Client) CreateAddress(ctx context.Context, address, routingType string) error {
+	return c.admin.(mq.AddressAdmin).CreateAddress(ctx, address, routingType)
+}
+
+// ListQueues 列出队列
+func (c *Client) ListQueues(ctx context.Context, address string) ([]types.QueueInfo, error) {
+	return c.admin.(mq.AddressAdmin).ListQueues(ctx, address)
+}
+
+// CreateQueue 在地址上创建队列
+func (c *Client) CreateQueue(ctx context.Context, address, queue, routingType string, durable bool) error {
+	return c.admin.(mq.AddressAdmin).CreateQueue(ctx, address, queue, routingType, durable)
+}
+
+// DeleteQueue 删除队列
+func (c *Client) DeleteQueue(ctx context.Context, queue string) error {
+	return c.admin.(mq.AddressAdmin).DeleteQueue(ctx, queue)
+}
+
+// Close 关闭客户端
+func (c *Client) Close() error {
+	var lastErr error
+
+	if err := c.producer.Close(); err != nil {
+		lastErr = err
+	}
+
+	if err := c.consumer.Close(); err != nil {
+		lastErr = err
+	}
+
+	if err := c.admin.Close(); err != nil {
+		lastErr = err
+	}
+
+	return lastErr
+}
+
+// IsConnected 检查连接状态
+func (c *Client) IsConnected() bool {
+	return c.producer.IsConnected() && c.consumer.IsConnected()
+}
+
+// Ping 通过管理连接探测 broker 是否可达
+func (c *Client) Ping(ctx context.Context) error {
+	return c.admin.(mq.Pinger).Ping(ctx)
+}
+
+// dial 依次尝试配置的地址，返回第一个建立成功的 AMQP 连接，t 不为 nil 时经隧道连接
+//
+// 配置了用户名时使用 SASL PLAIN 认证，否则使用 SASL ANONYMOUS。Extra["tls"] 为 true 时使用 TLS（amqps），
+// Extra["tls_insecure"] 为 true 时跳过证书校验。VHost 不为空时作为 open 帧中的 hostname，
+// 部分 broker（如 Service Bus）据此选择命名空间。containerID 为空时随机生成。
+func dial(ctx context.Context, config *types.ConnectionConfig, t *tunnel.Tunnel, containerID string) (*amqp.Conn, error) {
+	addresses := config.Addresses()
+	if len(addresses) == 0 {
+		return nil, utils.NewConnectionError("Failed to connect to AMQP broker", fmt.Errorf("no AMQP address configured"))
+	}
+
+	sasl := amqp.SASLTypeAnonymous()
+	if config.Username != "" {
+		sasl = amqp.SASLTypePlain(config.Username, config.Password)
+	}
+	opts := &amqp.ConnOptions{
+		ContainerID: containerID,
+		HostName:    config.VHost,
+		SASLType:    sasl,
+	}
+
+	timeout := config.Timeouts.DialTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lastErr error
+	for _, address := range addresses {
+		netConn, err := dialAddress(ctx, config, t, address)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		conn, err := amqp.NewConn(ctx, netConn, opts)
+		if err != nil {
+			netConn.Close()
+			lastErr = err
+			continue
+		}
+		return conn, nil
+	}
+	return nil, utils.WrapTimeout(ctx, connectFailure(lastErr), "Connecting to AMQP broker", timeout)
+}
+
+// dialAddress 建立到 address 的 TCP 连接，需要时完成 TLS 握手
+func dialAddress(ctx context.Context, config *types.ConnectionConfig, t *tunnel.Tunnel, address string) (net.Conn, error) {
+	var conn net.Conn
+	var err error
+	if t != nil {
+		conn, err = t.DialContext(ctx, "tcp", address)
+	} else {
+		var d net.Dialer
+		conn, err = d.DialContext(ctx, "tcp", address)
+	}
+	if err != nil || config.Extra["tls"] != "true" {
+		return conn, err
+	}
+
+	host, _, _ := net.SplitHostPort(address)
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: config.Extra["tls_insecure"] == "true",
+	})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+// connectFailure 把连接错误转换为应用错误，SASL 认证失败时返回认证错误
+func connectFailure(err error) error {
+	if err != nil && strings.Contains(err.Error(), "SASL") {
+		return utils.NewAuthError("AMQP authentication failed: " + err.Error())
+	}
+	return utils.NewConnectionError("Failed to connect to AMQP broker", err)
+}
+
+// newAMQPMessage 由发送请求构造 AMQP 消息
+//
+// 消息体作为 data 段发送，消息键作为 group-id，消息持久化。
+func newAMQPMessage(req *types.ProduceRequest) (*amqp.Message, error) {
+	msg := amqp.NewMessage([]byte(req.Value))
+	msg.Header = &amqp.MessageHeader{Durable: true, Priority: 4}
+	msg.Properties = &amqp.MessageProperties{}
+	if req.Key != "" {
+		msg.Properties.GroupID = &req.Key
+	}
+
+	for name, value := range req.Headers {
+		value := value
+		switch name {
+		case MessageIDHeader:
+			msg.Properties.MessageID = value
+		case CorrelationIDHeader:
+			msg.Properties.CorrelationID = value
+		case SubjectHeader:
+			msg.Properties.Subject = &value
+		case ReplyToHeader:
+			msg.Properties.ReplyTo = &value
+		case ContentTypeHeader:
+			msg.Properties.ContentType = &value
+		case ToHeader:
+			msg.Properties.To = &value
+		case PriorityHeader:
+			n, err := strconv.ParseUint(value, 10, 8)
+			if err != nil || n > 9 {
+				return nil, utils.NewValidationError("Invalid message priority, expected 0-9", value)
+			}
+			msg.Header.Priority = uint8(n)
+		case TTLHeader:
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || n < 0 {
+				return nil, utils.NewValidationError("Invalid message TTL in milliseconds", value)
+			}
+			msg.Header.TTL = time.Duration(n) * time.Millisecond
+		default:
+			if msg.ApplicationProperties == nil {
+				msg.ApplicationProperties = make(map[string]any)
+			}
+			msg.ApplicationProperties[name] = value
+		}
+	}
+	return msg, nil
+}
+
+// newMessage 由 AMQP 消息构造消息
+//
+// 消息体取 data 段，没有 data 段时取 amqp-value 段（如 JMS TextMessage）。消息没有分区和偏移量，
+// 时间戳取 creation-time，未设置时使用接收时间。
+func newMessage(address string, m *amqp.Message) *types.Message {
+	msg := &types.Message{
+		ID:        utils.GenerateID(),
+		Topic:     address,
+		Value:     messageBody(m),
+		Headers:   make(map[string]string, len(m.ApplicationProperties)+4),
+		Timestamp: time.Now(),
+	}
+	for name, value := range m.ApplicationProperties {
+		msg.Headers[name] = fmt.Sprint(value)
+	}
+
+	if h := m.Header; h != nil {
+		msg.Headers[PriorityHeader] = strconv.Itoa(int(h.Priority))
+		if h.TTL > 0 {
+			msg.Headers[TTLHeader] = strconv.FormatInt(h.TTL.Milliseconds(), 10)
+		}
+		if h.DeliveryCount > 0 {
+			msg.Headers[DeliveryCountHeader] = strconv.FormatUint(uint64(h.DeliveryCount), 10)
+		}
+	}
+
+	p := m.Properties
+	if p == nil {
+		return msg
+	}
+	if p.GroupID != nil {
+		msg.Key = *p.GroupID
+	}
+	if p.CreationTime != nil && !p.CreationTime.IsZero() {
+		msg.Timestamp = *p.CreationTime
+	}
+	if p.MessageID != nil {
+		msg.Headers[MessageIDHeader] = fmt.Sprint(p.MessageID)
+	}
+	if p.CorrelationID != nil {
+		msg.Headers[CorrelationIDHeader] = fmt.Sprint(p.CorrelationID)
+	}
+	for name, value := range map[string]*string{
+		SubjectHeader:     p.Subject,
+		ReplyToHeader:     p.ReplyTo,
+		ContentTypeHeader: p.ContentType,
+		ToHeader:          p.To,
+	} {
+		if value != nil {
+			msg.Headers[name] = *value
+		}
+	}
+	return msg
+}
+
+// messageBody 返回消息体文本
+func messageBody(m *amqp.Message) string {
+	if len(m.Data) > 0 {
+		var b strings.Builder
+		for _, data := range m.Data {
+			b.Write(data)
+		}
+		return b.String()
+	}
+	switch v := m.Value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// sortedKeys 返回按字母排序的键
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,213 @@
+package amqp10
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"sync"
+
+	"github.com/Azure/go-amqp"
+)
+
+// receiverCredit 每条接收链路预取的消息数
+const receiverCredit = 100
+
+// Consumer AMQP 1.0消费者实现
+//
+// 每个地址创建一条接收链路，处理成功后接受（accept）消息，处理失败时以 delivery-failed 修改（modify）
+// 消息，broker 增加投递次数后重新投递。指定消费组时以消费组名作为容器 ID、地址作为链路名创建持久订阅，
+// 在 Artemis 的 MULTICAST 地址上对应名为 消费组.地址 的订阅队列，断开后继续保留消息。
+// Artemis 可以用 地址::队列（FQQN）直接从指定队列接收。
+type Consumer struct {
+	conn      *amqp.Conn
+	session   *amqp.Session
+	config    *types.ConnectionConfig
+	tunnel    *tunnel.Tunnel
+	container string
+	receivers []*amqp.Receiver
+}
+
+// NewConsumer 创建AMQP 1.0消费者
+func NewConsumer() mq.Consumer {
+	return &Consumer{}
+}
+
+// Connect 连接到AMQP 1.0 broker
+func (c *Consumer) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	if config.Type != types.MQTypeAMQP10 {
+		return utils.NewValidationError("Invalid MQ type for AMQP 1.0 consumer", string(config.Type))
+	}
+
+	t, err := tunnel.OpenIfEnabled(config.Tunnel)
+	if err != nil {
+		return utils.NewConnectionError("Failed to open tunnel", err)
+	}
+
+	c.config = config
+	c.tunnel = t
+	if err := c.open(ctx, ""); err != nil {
+		c.tunnel = nil
+		c.config = nil
+		t.Close()
+		return err
+	}
+	return nil
+}
+
+// open 以 containerID 建立连接和会话，替换已有的连接
+func (c *Consumer) open(ctx context.Context, containerID string) error {
+	conn, err := dial(ctx, c.config, c.tunnel, containerID)
+	if err != nil {
+		return err
+	}
+	session, err := conn.NewSession(ctx, nil)
+	if err != nil {
+		conn.Close()
+		return utils.NewConnectionError("Failed to begin AMQP session", err)
+	}
+
+	if c.conn != nil {
+		c.conn.Close()
+	}
+	c.conn = conn
+	c.session = session
+	c.container = containerID
+	return nil
+}
+
+// Subscribe 为每个地址创建接收链路
+//
+// Extra["settle_mode"] 为 settled 时请求 broker 预结算投递（至多一次），处理失败的消息不会重新投递。
+func (c *Consumer) Subscribe(ctx context.Context, req *types.ConsumeRequest) error {
+	if c.conn == nil {
+		return utils.NewConnectionError("Consumer not connected", nil)
+	}
+
+	if len(req.Topics) == 0 {
+		return utils.NewValidationError("No topics specified for subscription", "")
+	}
+	mode, err := senderSettleMode(c.config.Extra["settle_mode"])
+	if err != nil {
+		return err
+	}
+
+	// 关闭链路会删除持久订阅，因此通过重新连接断开已有的链路；
+	// 持久订阅由容器 ID 和链路名确定，消费组变化时同样以新的容器 ID 重新连接
+	if len(c.receivers) > 0 || req.GroupID != c.container {
+		c.receivers = nil
+		if err := c.open(ctx, req.GroupID); err != nil {
+			return err
+		}
+	}
+
+	for _, address := range req.Topics {
+		opts := &amqp.ReceiverOptions{
+			Credit:                    receiverCredit,
+			RequestedSenderSettleMode: &mode,
+		}
+		if req.GroupID != "" {
+			opts.Name = address
+			opts.Durability = amqp.DurabilityUnsettledState
+			opts.ExpiryPolicy = amqp.ExpiryPolicyNever
+		}
+		receiver, err := c.session.NewReceiver(ctx, address, opts)
+		if err != nil {
+			return utils.NewSubscriptionError(fmt.Sprintf("Failed to attach receiver to %s", address), err)
+		}
+		c.receivers = append(c.receivers, receiver)
+	}
+	return nil
+}
+
+// received 接收到的消息
+type received struct {
+	msg      *amqp.Message
+	receiver *amqp.Receiver
+}
+
+// Consume 消费消息，所有地址的消息按到达顺序依次处理
+func (c *Consumer) Consume(ctx context.Context, handler mq.MessageHandler) error {
+	if c.conn == nil || len(c.receivers) == 0 {
+		return utils.NewConnectionError("Consumer not subscribed. Call Subscribe first.", nil)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	msgs := make(chan received)
+	errs := make(chan error, len(c.receivers))
+	var wg sync.WaitGroup
+	for _, receiver := range c.receivers {
+		wg.Add(1)
+		go func(receiver *amqp.Receiver) {
+			defer wg.Done()
+			for {
+				m, err := receiver.Receive(ctx, nil)
+				if err != nil {
+					if ctx.Err() == nil {
+						errs <- err
+					}
+					return
+				}
+				select {
+				case msgs <- received{msg: m, receiver: receiver}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(receiver)
+	}
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errs:
+			return utils.NewConnectionError("Failed to receive from AMQP broker", err)
+		case r := <-msgs:
+			address := r.receiver.Address()
+			err := handler(newMessage(address, r.msg))
+			if ctx.Err() != nil {
+				// 不结算消息，连接关闭后由 broker 重新投递
+				return ctx.Err()
+			}
+			if err != nil {
+				// 标记为投递失败且不再投递给本链路，由 broker 按投递次数转入死信队列
+				err = r.receiver.ModifyMessage(ctx, r.msg, &amqp.ModifyMessageOptions{DeliveryFailed: true, UndeliverableHere: true})
+			} else {
+				err = r.receiver.AcceptMessage(ctx, r.msg)
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return utils.NewConnectionError(fmt.Sprintf("Failed to settle message from %s", address), err)
+			}
+		}
+	}
+}
+
+// Close 关闭消费者
+func (c *Consumer) Close() error {
+	// 直接关闭连接，链路随连接断开但不关闭，持久订阅保留在 broker 中
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+		c.session = nil
+	}
+	c.receivers = nil
+	c.config = nil
+	c.container = ""
+	c.tunnel.Close()
+	c.tunnel = nil
+	return nil
+}
+
+// IsConnected 检查连接状态
+func (c *Consumer) IsConnected() bool {
+	return c.conn != nil
+}
@@ -0,0 +1,193 @@
+package amqp10
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"sync"
+
+	"github.com/Azure/go-amqp"
+)
+
+// Producer AMQP 1.0生产者实现，每个地址创建一条发送链路并复用
+type Producer struct {
+	conn    *amqp.Conn
+	session *amqp.Session
+	config  *types.ConnectionConfig
+	tunnel  *tunnel.Tunnel
+	mu      sync.Mutex
+	senders map[string]*amqp.Sender
+}
+
+// NewProducer 创建AMQP 1.0生产者
+func NewProducer() mq.Producer {
+	return &Producer{}
+}
+
+// Connect 连接到AMQP 1.0 broker 并创建会话
+func (p *Producer) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	if config.Type != types.MQTypeAMQP10 {
+		return utils.NewValidationError("Invalid MQ type for AMQP 1.0 producer", string(config.Type))
+	}
+
+	t, err := tunnel.OpenIfEnabled(config.Tunnel)
+	if err != nil {
+		return utils.NewConnectionError("Failed to open tunnel", err)
+	}
+
+	conn, err := dial(ctx, config, t, "")
+	if err != nil {
+		t.Close()
+		return err
+	}
+
+	session, err := conn.NewSession(ctx, nil)
+	if err != nil {
+		conn.Close()
+		t.Close()
+		return utils.NewConnectionError("Failed to begin AMQP session", err)
+	}
+
+	p.conn = conn
+	p.session = session
+	p.config = config
+	p.tunnel = t
+	p.senders = make(map[string]*amqp.Sender)
+	return nil
+}
+
+// Produce 发送单条消息
+//
+// Extra["settle_mode"] 为 settled 时消息预结算，发出即返回；默认为 unsettled，等待 broker 确认接收。
+func (p *Producer) Produce(ctx context.Context, req *types.ProduceRequest) error {
+	if p.conn == nil {
+		return utils.NewConnectionError("Producer not connected", nil)
+	}
+
+	msg, err := newAMQPMessage(req)
+	if err != nil {
+		return err
+	}
+
+	timeout := p.config.Timeouts.ProduceTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	sender, err := p.sender(ctx, req.Topic)
+	if err != nil {
+		return err
+	}
+	if err := sender.Send(ctx, msg, nil); err != nil {
+		p.dropSender(req.Topic, sender)
+		return utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to send message to "+req.Topic, err), "Producing to AMQP broker", timeout)
+	}
+	return nil
+}
+
+// ProduceBatch 批量发送消息，按顺序逐条发送，遇到失败立即返回
+func (p *Producer) ProduceBatch(ctx context.Context, reqs []*types.ProduceRequest) error {
+	if p.conn == nil {
+		return utils.NewConnectionError("Producer not connected", nil)
+	}
+
+	if len(reqs) == 0 {
+		return utils.NewValidationError("Empty message batch", "")
+	}
+
+	msgs := make([]*amqp.Message, 0, len(reqs))
+	for _, req := range reqs {
+		msg, err := newAMQPMessage(req)
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, msg)
+	}
+
+	timeout := p.config.Timeouts.ProduceTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for i, req := range reqs {
+		sender, err := p.sender(ctx, req.Topic)
+		if err != nil {
+			return err
+		}
+		if err := sender.Send(ctx, msgs[i], nil); err != nil {
+			p.dropSender(req.Topic, sender)
+			return utils.WrapTimeout(ctx, utils.NewConnectionError(fmt.Sprintf("Failed to send message %d of %d to %s", i+1, len(reqs), req.Topic), err),
+				"Producing to AMQP broker", timeout)
+		}
+	}
+	return nil
+}
+
+// sender 返回地址的发送链路，不存在时创建
+func (p *Producer) sender(ctx context.Context, address string) (*amqp.Sender, error) {
+	if address == "" {
+		return nil, utils.NewValidationError("Address is required", "")
+	}
+	mode, err := senderSettleMode(p.config.Extra["settle_mode"])
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if sender, ok := p.senders[address]; ok {
+		return sender, nil
+	}
+	sender, err := p.session.NewSender(ctx, address, &amqp.SenderOptions{SettlementMode: &mode})
+	if err != nil {
+		return nil, utils.NewConnectionError("Failed to attach sender to "+address, err)
+	}
+	p.senders[address] = sender
+	return sender, nil
+}
+
+// dropSender 发送失败后丢弃链路，链路可能已被 broker 关闭，下次发送时重新创建
+func (p *Producer) dropSender(address string, sender *amqp.Sender) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.senders[address] == sender {
+		delete(p.senders, address)
+		go sender.Close(context.Background())
+	}
+}
+
+// senderSettleMode 解析发送端结算模式，为空时使用 unsettled
+func senderSettleMode(value string) (amqp.SenderSettleMode, error) {
+	switch value {
+	case "", "unsettled":
+		return amqp.SenderSettleModeUnsettled, nil
+	case "settled":
+		return amqp.SenderSettleModeSettled, nil
+	default:
+		return amqp.SenderSettleModeUnsettled, utils.NewValidationError("Invalid settle mode, expected settled or unsettled", value)
+	}
+}
+
+// Close 关闭所有发送链路和连接
+func (p *Producer) Close() error {
+	p.mu.Lock()
+	p.senders = nil
+	p.mu.Unlock()
+
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+		p.session = nil
+	}
+	p.config = nil
+	p.tunnel.Close()
+	p.tunnel = nil
+	return nil
+}
+
+// IsConnected 检查连接状态
+func (p *Producer) IsConnected() bool {
+	return p.conn != nil
+}
@@ -0,0 +1,38 @@
+package amqp10
+
+import (
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+)
+
+// init 注册 AMQP 1.0 后端
+func init() {
+	mq.Register(mq.Descriptor{
+		BackendInfo: types.BackendInfo{
+			Type:          types.MQTypeAMQP10,
+			DisplayName:   "AMQP 1.0（Artemis 等）",
+			Order:         80,
+			DefaultHost:   "localhost",
+			DefaultPort:   5672,
+			EndpointLabel: "其他 broker（按顺序故障转移）",
+			SettingsTitle: "AMQP 1.0 设置",
+			SettingsHelp:  "填写用户名时使用 SASL PLAIN 认证，否则使用 ANONYMOUS；地址和队列管理需要 Artemis 的 Jolokia 管理接口",
+			Fields: []types.ConfigField{
+				{Key: "vhost", Label: "open 帧 hostname（可选）", Kind: "text", Connection: true, Mono: true},
+				{Key: "settle_mode", Label: "结算模式", Kind: "text", Options: []types.FieldOption{{Value: "unsettled", Label: "等待 broker 确认（unsettled）"}, {Value: "settled", Label: "预结算（settled，至多一次）"}}, Default: "unsettled"},
+				{Key: "management_url", Label: "Jolokia 地址（默认 http://主机:8161/console/jolokia）", Kind: "text", Mono: true},
+				{Key: "tls", Label: "使用 TLS（amqps）", Kind: "bool"},
+				{Key: "tls_insecure", Label: "跳过证书校验", Kind: "bool", ShowIf: "tls"},
+			},
+			ConsumeHint:     "接收后即从队列移除；填写消费组时在 MULTICAST 地址上创建持久订阅（消费组.地址），可用 地址::队列 直接接收指定队列",
+			CreateTopicHint: "创建 ANYCAST 地址和同名的持久队列；其他路由类型请在“地址与队列”中创建",
+			Capabilities: []types.Capability{
+				types.CapabilityTunnel, types.CapabilityEndpoints, types.CapabilityUserPassword, types.CapabilityListTopics, types.CapabilityMultiTopic,
+			},
+		},
+		NewClient:   NewClient,
+		NewProducer: NewProducer,
+		NewConsumer: NewConsumer,
+		NewAdmin:    NewAdmin,
+	})
+}
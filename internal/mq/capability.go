@@ -0,0 +1,43 @@
+package mq
+
+import "mq-toolkit/pkg/types"
+
+// adminCapabilities maps the optional admin interfaces to the capabilities
+// they provide.
+var adminCapabilities = []struct {
+	capability types.Capability
+	implements func(Client) bool
+}{
+	{types.CapabilityConsumerGroups, func(c Client) bool { _, ok := c.(GroupAdmin); return ok }},
+	{types.CapabilitySeekGroup, func(c Client) bool { _, ok := c.(OffsetSeeker); return ok }},
+	{types.CapabilityBrowse, func(c Client) bool { _, ok := c.(Browser); return ok }},
+	{types.CapabilityExchanges, func(c Client) bool { _, ok := c.(ExchangeAdmin); return ok }},
+	{types.CapabilityStreams, func(c Client) bool { _, ok := c.(StreamAdmin); return ok }},
+	{types.CapabilityNamespaces, func(c Client) bool { _, ok := c.(NamespaceAdmin); return ok }},
+	{types.CapabilitySubscriptions, func(c Client) bool { _, ok := c.(SubscriptionAdmin); return ok }},
+	{types.CapabilityAddresses, func(c Client) bool { _, ok := c.(AddressAdmin); return ok }},
+	{types.CapabilityQueueAttributes, func(c Client) bool { _, ok := c.(QueueAttributeAdmin); return ok }},
+}
+
+// discoverCapabilities returns the capabilities of the optional admin
+// interfaces that client implements. The client does not need to be connected.
+func discoverCapabilities(client Client) []types.Capability {
+	var capabilities []types.Capability
+	for _, admin := range adminCapabilities {
+		if admin.implements(client) {
+			capabilities = append(capabilities, admin.capability)
+		}
+	}
+	return capabilities
+}
+
+// Capabilities returns the capabilities of the backend registered for mqType:
+// those declared in its descriptor followed by those of the optional admin
+// interfaces its client implements.
+func Capabilities(mqType types.MQType) ([]types.Capability, bool) {
+	d, ok := Lookup(mqType)
+	if !ok {
+		return nil, false
+	}
+	return append([]types.Capability(nil), d.Capabilities...), true
+}
@@ -3,6 +3,7 @@ package mq
 import (
 	"context"
 	"mq-toolkit/pkg/types"
+	"time"
 )
 
 // Client is the interface that wraps the basic methods of a message queue client.
@@ -15,6 +16,92 @@ type Client interface {
 	IsConnected() bool
 }
 
+// Pinger is implemented by clients that can actively probe whether the
+// underlying connection is still alive. IsConnected only reports local state.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// GroupAdmin is implemented by admins of brokers that track consumer groups
+// on the server. Backends without server-side groups leave it unimplemented.
+type GroupAdmin interface {
+	ListConsumerGroups(ctx context.Context) ([]types.ConsumerGroup, error)
+}
+
+// OffsetSeeker is implemented by admins that can move the committed position
+// of a consumer group on a topic to the first message at or after t. The group
+// usually has to be idle while its position is moved.
+type OffsetSeeker interface {
+	SeekGroup(ctx context.Context, group, topic string, t time.Time) error
+}
+
+// Browser is implemented by admins that can read up to limit messages from a
+// topic or queue without acknowledging, removing or committing them.
+type Browser interface {
+	Browse(ctx context.Context, topic string, limit int) ([]types.Message, error)
+}
+
+// ExchangeAdmin is implemented by admins of brokers that route messages from
+// exchanges to queues through bindings (RabbitMQ).
+type ExchangeAdmin interface {
+	ListExchanges(ctx context.Context) ([]types.ExchangeInfo, error)
+	CreateExchange(ctx context.Context, exchange, kind string, durable bool) error
+	DeleteExchange(ctx context.Context, exchange string) error
+	ListBindings(ctx context.Context, exchange string) ([]types.BindingInfo, error)
+	BindQueue(ctx context.Context, exchange, queue, routingKey string) error
+	UnbindQueue(ctx context.Context, exchange, queue, routingKey string) error
+}
+
+// StreamAdmin is implemented by admins of log-structured streams (Redis Streams)
+// whose consumer groups are created per stream and track unacknowledged entries.
+type StreamAdmin interface {
+	StreamInfo(ctx context.Context, stream string) (*types.StreamInfo, error)
+	TrimStream(ctx context.Context, stream string, maxLen int64) (int64, error)
+	CreateStreamGroup(ctx context.Context, stream, group, startID string) error
+	DeleteStreamGroup(ctx context.Context, stream, group string) error
+	ListPending(ctx context.Context, stream, group string, count int64) ([]types.PendingEntry, error)
+	ClaimPending(ctx context.Context, stream, group, consumer string, minIdle time.Duration, ids []string) ([]string, error)
+}
+
+// NamespaceAdmin is implemented by admins of brokers that group topics into
+// tenants and namespaces (Pulsar). Namespaces are written as "tenant/namespace".
+type NamespaceAdmin interface {
+	ListTenants(ctx context.Context) ([]string, error)
+	ListNamespaces(ctx context.Context, tenant string) ([]string, error)
+	CreateNamespace(ctx context.Context, namespace string) error
+	DeleteNamespace(ctx context.Context, namespace string) error
+}
+
+// SubscriptionAdmin is implemented by admins whose subscriptions belong to a
+// single topic and keep their own cursor and backlog (Pulsar).
+type SubscriptionAdmin interface {
+	ListSubscriptions(ctx context.Context, topic string) ([]types.SubscriptionInfo, error)
+	DeleteSubscription(ctx context.Context, topic, subscription string) error
+	ClearBacklog(ctx context.Context, topic, subscription string) error
+	ResetSubscription(ctx context.Context, topic, subscription string, t time.Time) error
+}
+
+// AddressAdmin is implemented by admins of brokers that route messages from
+// addresses to queues bound to them (ActiveMQ Artemis). The routing type is
+// ANYCAST or MULTICAST.
+type AddressAdmin interface {
+	ListAddresses(ctx context.Context) ([]types.AddressInfo, error)
+	CreateAddress(ctx context.Context, address, routingType string) error
+	ListQueues(ctx context.Context, address string) ([]types.QueueInfo, error)
+	CreateQueue(ctx context.Context, address, queue, routingType string, durable bool) error
+	DeleteQueue(ctx context.Context, queue string) error
+}
+
+// QueueAttributeAdmin is implemented by admins of hosted queues that are
+// configured through named attributes and redrive failed messages to
+// dead-letter queues (Amazon SQS). Queues are given by name or URL.
+type QueueAttributeAdmin interface {
+	GetQueueAttributes(ctx context.Context, queue string) (*types.QueueAttributes, error)
+	SetQueueAttributes(ctx context.Context, queue string, attributes map[string]string) error
+	PurgeQueue(ctx context.Context, queue string) error
+	ListDeadLetterSources(ctx context.Context, queue string) ([]string, error)
+}
+
 // Producer is the interface that wraps the basic methods of a message queue producer.
 type Producer interface {
 	Connect(ctx context.Context, config *types.ConnectionConfig) error
@@ -33,7 +120,28 @@ type Consumer interface {
 	IsConnected() bool
 }
 
-// MessageHandler is the handler for consumed messages.
+// MessageHandler is the handler for consumed messages. Consumers that read
+// several topics or queues in parallel may call it concurrently.
+//
+// Every backend applies the same contract to its return value:
+//
+//   - nil acknowledges the message (ack, commit, delete or accept).
+//   - An error leaves the message unacknowledged and consumption goes on with
+//     the next message. The message is never redelivered to the same consumer
+//     straight away: what happens to it is up to the broker (an uncommitted
+//     offset is covered by the next commit, a Redis entry stays pending, an SQS
+//     or JetStream message comes back after its visibility timeout or ack wait,
+//     Pulsar redelivers after the nack delay, RabbitMQ dead-letters or drops a
+//     rejected message, AMQP 1.0 marks it undeliverable here). MQTT and core
+//     NATS have no acknowledgement, so the message is simply skipped.
+//   - If ctx is done by the time the handler returns, the message is left
+//     unsettled and Consume returns, so the message is delivered again to the
+//     next subscriber. A caller that has to stop on a handler error cancels ctx
+//     before returning the error.
+//
+// Adapters do not log handler errors; the handler reports its own failures.
+// Consume returns an error only when receiving or acknowledging fails, which
+// callers treat as a broken subscription.
 type MessageHandler func(msg *types.Message) error
 
 // Admin is the interface that wraps the basic methods of a message queue admin client.
@@ -43,6 +151,5 @@ type Admin interface {
 	ListTopics(ctx context.Context) ([]types.TopicInfo, error)
 	CreateTopic(ctx context.Context, topic string, partitions int32, replicas int16) error
 	DeleteTopic(ctx context.Context, topic string) error
-	ListConsumerGroups(ctx context.Context) ([]types.ConsumerGroup, error)
 	Close() error
 }
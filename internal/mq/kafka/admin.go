@@ -2,11 +2,14 @@ package kafka
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
 	"mq-toolkit/pkg/types"
 	"mq-toolkit/pkg/utils"
 	"net"
+	"sort"
 	"strconv"
 	"time"
 
@@ -18,6 +21,10 @@ type Admin struct {
 	conn      *kafka.Conn
 	connected bool
 	config    *types.ConnectionConfig
+	tunnel    *tunnel.Tunnel
+	dialer    *kafka.Dialer
+	client    *kafka.Client // 消费组请求需要发往协调者，由 Client 按集群元数据路由
+	transport *kafka.Transport
 }
 
 // NewAdmin 创建Kafka管理客户端
@@ -34,11 +41,26 @@ func (a *Admin) Connect(ctx context.Context, config *types.ConnectionConfig) err
 	// 保存配置
 	a.config = config
 
+	t, err := tunnel.Open(config.Tunnel)
+	if err != nil {
+		return utils.NewConnectionError("Failed to open tunnel", err)
+	}
+	a.tunnel = t
+	a.dialer = newDialer(t, config.Timeouts.DialTimeout())
+	a.transport = &kafka.Transport{Dial: t.DialContext, DialTimeout: config.Timeouts.DialTimeout()}
+	a.client = &kafka.Client{
+		Addr:      kafka.TCP(config.Addresses()...),
+		Timeout:   config.Timeouts.RequestTimeout(),
+		Transport: a.transport,
+	}
+
 	// 建立连接
-	address := fmt.Sprintf("%s:%d", config.Host, config.Port)
-	conn, err := kafka.Dial("tcp", address)
+	dialCtx, cancel := context.WithTimeout(ctx, config.Timeouts.DialTimeout())
+	defer cancel()
+	conn, err := dialBootstrap(dialCtx, a.dialer, config.Addresses())
 	if err != nil {
-		return utils.NewConnectionError("Failed to connect to Kafka", err)
+		a.closeTunnel()
+		return utils.WrapTimeout(dialCtx, utils.NewConnectionError("Failed to connect to Kafka", err), "Connecting to Kafka", config.Timeouts.DialTimeout())
 	}
 
 	a.conn = conn
@@ -59,8 +81,13 @@ func (a *Admin) TestConnection(ctx context.Context) *types.TestResult {
 	}
 
 	// 尝试获取broker信息
+	ctx, cancel := context.WithTimeout(ctx, a.config.Timeouts.RequestTimeout())
+	defer cancel()
+	setDeadline(ctx, a.conn)
 	brokers, err := a.conn.Brokers()
+	a.conn.SetDeadline(time.Time{})
 	if err != nil {
+		err = utils.WrapTimeout(ctx, err, "Fetching brokers", a.config.Timeouts.RequestTimeout())
 		return &types.TestResult{
 			Success: false,
 			Message: fmt.Sprintf("Failed to get brokers: %v", err),
@@ -75,19 +102,39 @@ func (a *Admin) TestConnection(ctx context.Context) *types.TestResult {
 	}
 }
 
+// Ping 在管理连接上请求 broker 元数据，失败或超时说明连接已断开
+func (a *Admin) Ping(ctx context.Context) error {
+	if !a.connected || a.conn == nil {
+		return utils.NewConnectionError("Not connected to Kafka", nil)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(10 * time.Second)
+	}
+	a.conn.SetDeadline(deadline)
+	defer a.conn.SetDeadline(time.Time{})
+	if _, err := a.conn.Brokers(); err != nil {
+		return utils.NewConnectionError("Kafka broker is unreachable", err)
+	}
+	return nil
+}
+
 // ListTopics 列出所有主题
-func (a *Admin) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
+func (a *Admin) ListTopics(ctx context.Context) (topics []types.TopicInfo, err error) {
 	if !a.connected || a.conn == nil {
 		return nil, utils.NewConnectionError("Not connected to Kafka", nil)
 	}
+	ctx, cancel := context.WithTimeout(ctx, a.config.Timeouts.RequestTimeout())
+	defer cancel()
+	defer func() { err = utils.WrapTimeout(ctx, err, "Listing topics", a.config.Timeouts.RequestTimeout()) }()
 
 	// 重新建立连接以确保连接有效
-	address := fmt.Sprintf("%s:%d", a.config.Host, a.config.Port)
-	conn, err := kafka.Dial("tcp", address)
+	conn, err := dialBootstrap(ctx, a.dialer, a.config.Addresses())
 	if err != nil {
 		return nil, utils.NewConnectionError("Failed to reconnect to Kafka", err)
 	}
 	defer conn.Close()
+	setDeadline(ctx, conn)
 
 	// 获取分区信息
 	partitions, err := conn.ReadPartitions()
@@ -112,7 +159,6 @@ func (a *Admin) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
 	}
 
 	// 转换为切片
-	var topics []types.TopicInfo
 	for _, topic := range topicMap {
 		topics = append(topics, *topic)
 	}
@@ -121,7 +167,7 @@ func (a *Admin) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
 }
 
 // CreateTopic 创建主题
-func (a *Admin) CreateTopic(ctx context.Context, topic string, partitions int32, replicas int16) error {
+func (a *Admin) CreateTopic(ctx context.Context, topic string, partitions int32, replicas int16) (err error) {
 	if !a.connected || a.conn == nil {
 		return utils.NewConnectionError("Not connected to Kafka", nil)
 	}
@@ -129,14 +175,17 @@ func (a *Admin) CreateTopic(ctx context.Context, topic string, partitions int32,
 	if !utils.IsValidTopic(topic) {
 		return utils.NewValidationError("Invalid topic name", topic)
 	}
+	ctx, cancel := context.WithTimeout(ctx, a.config.Timeouts.RequestTimeout())
+	defer cancel()
+	defer func() { err = utils.WrapTimeout(ctx, err, "Creating topic", a.config.Timeouts.RequestTimeout()) }()
 
 	// 重新建立连接以确保连接有效
-	address := fmt.Sprintf("%s:%d", a.config.Host, a.config.Port)
-	conn, err := kafka.Dial("tcp", address)
+	conn, err := dialBootstrap(ctx, a.dialer, a.config.Addresses())
 	if err != nil {
 		return utils.NewConnectionError("Failed to reconnect to Kafka", err)
 	}
 	defer conn.Close()
+	setDeadline(ctx, conn)
 
 	// 获取控制器连接
 	controller, err := conn.Controller()
@@ -144,11 +193,12 @@ func (a *Admin) CreateTopic(ctx context.Context, topic string, partitions int32,
 		return utils.NewConnectionError("Failed to get controller", err)
 	}
 
-	controllerConn, err := kafka.Dial("tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
+	controllerConn, err := a.dialer.DialContext(ctx, "tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
 	if err != nil {
 		return utils.NewConnectionError("Failed to connect to controller", err)
 	}
 	defer controllerConn.Close()
+	setDeadline(ctx, controllerConn)
 
 	// 创建主题配置
 	topicConfigs := []kafka.TopicConfig{
@@ -169,7 +219,7 @@ func (a *Admin) CreateTopic(ctx context.Context, topic string, partitions int32,
 }
 
 // DeleteTopic 删除主题
-func (a *Admin) DeleteTopic(ctx context.Context, topic string) error {
+func (a *Admin) DeleteTopic(ctx context.Context, topic string) (err error) {
 	if !a.connected || a.conn == nil {
 		return utils.NewConnectionError("Not connected to Kafka", nil)
 	}
@@ -177,14 +227,17 @@ func (a *Admin) DeleteTopic(ctx context.Context, topic string) error {
 	if !utils.IsValidTopic(topic) {
 		return utils.NewValidationError("Invalid topic name", topic)
 	}
+	ctx, cancel := context.WithTimeout(ctx, a.config.Timeouts.RequestTimeout())
+	defer cancel()
+	defer func() { err = utils.WrapTimeout(ctx, err, "Deleting topic", a.config.Timeouts.RequestTimeout()) }()
 
 	// 重新建立连接以确保连接有效
-	address := fmt.Sprintf("%s:%d", a.config.Host, a.config.Port)
-	conn, err := kafka.Dial("tcp", address)
+	conn, err := dialBootstrap(ctx, a.dialer, a.config.Addresses())
 	if err != nil {
 		return utils.NewConnectionError("Failed to reconnect to Kafka", err)
 	}
 	defer conn.Close()
+	setDeadline(ctx, conn)
 
 	// 获取控制器连接
 	controller, err := conn.Controller()
@@ -192,11 +245,12 @@ func (a *Admin) DeleteTopic(ctx context.Context, topic string) error {
 		return utils.NewConnectionError("Failed to get controller", err)
 	}
 
-	controllerConn, err := kafka.Dial("tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
+	controllerConn, err := a.dialer.DialContext(ctx, "tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
 	if err != nil {
 		return utils.NewConnectionError("Failed to connect to controller", err)
 	}
 	defer controllerConn.Close()
+	setDeadline(ctx, controllerConn)
 
 	// 删除主题
 	err = controllerConn.DeleteTopics(topic)
@@ -207,19 +261,167 @@ func (a *Admin) DeleteTopic(ctx context.Context, topic string) error {
 	return nil
 }
 
-// ListConsumerGroups 列出消费组
-func (a *Admin) ListConsumerGroups(ctx context.Context) ([]types.ConsumerGroup, error) {
-	if !a.connected || a.conn == nil {
+// ListConsumerGroups 列出消费组，成员和主题取自组内成员订阅的主题及已提交位移的主题
+func (a *Admin) ListConsumerGroups(ctx context.Context) (groups []types.ConsumerGroup, err error) {
+	if !a.connected || a.client == nil {
 		return nil, utils.NewConnectionError("Not connected to Kafka", nil)
 	}
+	ctx, cancel := context.WithTimeout(ctx, a.config.Timeouts.RequestTimeout())
+	defer cancel()
+	defer func() {
+		err = utils.WrapTimeout(ctx, err, "Listing consumer groups", a.config.Timeouts.RequestTimeout())
+	}()
+
+	listed, err := a.client.ListGroups(ctx, &kafka.ListGroupsRequest{})
+	if err != nil {
+		return nil, utils.NewConnectionError("Failed to list consumer groups", err)
+	}
+	if listed.Error != nil {
+		return nil, utils.NewConnectionError("Failed to list consumer groups", listed.Error)
+	}
+	groups = []types.ConsumerGroup{}
+	if len(listed.Groups) == 0 {
+		return groups, nil
+	}
+
+	ids := make([]string, 0, len(listed.Groups))
+	for _, group := range listed.Groups {
+		ids = append(ids, group.GroupID)
+	}
+	described, err := a.client.DescribeGroups(ctx, &kafka.DescribeGroupsRequest{GroupIDs: ids})
+	if err != nil {
+		return nil, utils.NewConnectionError("Failed to describe consumer groups", err)
+	}
 
-	// kafka-go库没有直接的API来列出消费组
-	// 这里返回空列表，实际实现需要使用更高级的API
-	return []types.ConsumerGroup{}, nil
+	for _, group := range described.Groups {
+		if group.Error != nil {
+			return nil, utils.NewConnectionError("Failed to describe consumer group "+group.GroupID, group.Error)
+		}
+		members := make([]string, 0, len(group.Members))
+		topicSet := make(map[string]bool)
+		for _, member := range group.Members {
+			members = append(members, member.MemberID)
+			for _, topic := range member.MemberMetadata.Topics {
+				topicSet[topic] = true
+			}
+		}
+		offsets, err := a.client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{GroupID: group.GroupID})
+		if err != nil {
+			return nil, utils.NewConnectionError("Failed to fetch offsets of consumer group "+group.GroupID, err)
+		}
+		for topic := range offsets.Topics {
+			topicSet[topic] = true
+		}
+		topics := make([]string, 0, len(topicSet))
+		for topic := range topicSet {
+			topics = append(topics, topic)
+		}
+		sort.Strings(members)
+		sort.Strings(topics)
+		groups = append(groups, types.ConsumerGroup{ID: group.GroupID, Members: members, Topics: topics})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].ID < groups[j].ID })
+	return groups, nil
+}
+
+// SeekGroup 将消费组在主题各分区上提交的位移移到第一条时间不早于 t 的消息，没有这样的消息时移到末尾
+//
+// 位移以不属于任何成员的身份提交，broker 只接受没有活动成员的消费组，因此组内有成员时返回校验错误。
+func (a *Admin) SeekGroup(ctx context.Context, group, topic string, t time.Time) (err error) {
+	if !a.connected || a.client == nil {
+		return utils.NewConnectionError("Not connected to Kafka", nil)
+	}
+	if group == "" {
+		return utils.NewValidationError("Consumer group is required", "")
+	}
+	ctx, cancel := context.WithTimeout(ctx, a.config.Timeouts.RequestTimeout())
+	defer cancel()
+	defer func() {
+		err = utils.WrapTimeout(ctx, err, "Moving consumer group position", a.config.Timeouts.RequestTimeout())
+	}()
+
+	described, err := a.client.DescribeGroups(ctx, &kafka.DescribeGroupsRequest{GroupIDs: []string{group}})
+	if err != nil {
+		return utils.NewConnectionError("Failed to describe consumer group "+group, err)
+	}
+	for _, g := range described.Groups {
+		if g.Error != nil {
+			return utils.NewConnectionError("Failed to describe consumer group "+group, g.Error)
+		}
+		if len(g.Members) > 0 {
+			return utils.NewValidationError("Consumer group has active members, stop them before moving its position", group)
+		}
+	}
+
+	metadata, err := a.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		return utils.NewConnectionError("Failed to read topic metadata", err)
+	}
+	if len(metadata.Topics) == 0 || errors.Is(metadata.Topics[0].Error, kafka.UnknownTopicOrPartition) {
+		return utils.NewNotFoundError("Topic", topic)
+	}
+	if metadata.Topics[0].Error != nil {
+		return utils.NewConnectionError("Failed to read topic metadata", metadata.Topics[0].Error)
+	}
+
+	// 时间之后没有消息时 broker 返回 -1，需要另外查询末尾位移
+	byTime := make([]kafka.OffsetRequest, 0, len(metadata.Topics[0].Partitions))
+	last := make([]kafka.OffsetRequest, 0, len(metadata.Topics[0].Partitions))
+	for _, partition := range metadata.Topics[0].Partitions {
+		byTime = append(byTime, kafka.TimeOffsetOf(partition.ID, t))
+		last = append(last, kafka.LastOffsetOf(partition.ID))
+	}
+	timeOffsets, err := a.client.ListOffsets(ctx, &kafka.ListOffsetsRequest{Topics: map[string][]kafka.OffsetRequest{topic: byTime}})
+	if err != nil {
+		return utils.NewConnectionError("Failed to look up offsets by time", err)
+	}
+	lastOffsets, err := a.client.ListOffsets(ctx, &kafka.ListOffsetsRequest{Topics: map[string][]kafka.OffsetRequest{topic: last}})
+	if err != nil {
+		return utils.NewConnectionError("Failed to look up end offsets", err)
+	}
+
+	ends := make(map[int]int64)
+	for _, partition := range lastOffsets.Topics[topic] {
+		if partition.Error != nil {
+			return utils.NewConnectionError(fmt.Sprintf("Failed to look up end offset of partition %d", partition.Partition), partition.Error)
+		}
+		ends[partition.Partition] = partition.LastOffset
+	}
+	commits := make([]kafka.OffsetCommit, 0, len(byTime))
+	for _, partition := range timeOffsets.Topics[topic] {
+		if partition.Error != nil {
+			return utils.NewConnectionError(fmt.Sprintf("Failed to look up offset of partition %d", partition.Partition), partition.Error)
+		}
+		offset := ends[partition.Partition]
+		for o := range partition.Offsets {
+			offset = o
+		}
+		commits = append(commits, kafka.OffsetCommit{Partition: partition.Partition, Offset: offset})
+	}
+
+	committed, err := a.client.OffsetCommit(ctx, &kafka.OffsetCommitRequest{
+		GroupID:      group,
+		GenerationID: -1,
+		Topics:       map[string][]kafka.OffsetCommit{topic: commits},
+	})
+	if err != nil {
+		return utils.NewConnectionError("Failed to commit offsets", err)
+	}
+	for _, partition := range committed.Topics[topic] {
+		if partition.Error != nil {
+			return utils.NewConnectionError(fmt.Sprintf("Failed to commit offset of partition %d", partition.Partition), partition.Error)
+		}
+	}
+	return nil
 }
 
 // Close 关闭连接
 func (a *Admin) Close() error {
+	defer a.closeTunnel()
+	if a.transport != nil {
+		a.transport.CloseIdleConnections()
+		a.transport, a.client = nil, nil
+	}
 	if a.conn != nil {
 		err := a.conn.Close()
 		a.conn = nil
@@ -229,3 +431,10 @@ func (a *Admin) Close() error {
 	}
 	return nil
 }
+
+func (a *Admin) closeTunnel() {
+	if a.tunnel != nil {
+		a.tunnel.Close()
+		a.tunnel = nil
+	}
+}
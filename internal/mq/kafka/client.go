@@ -4,7 +4,7 @@ import (
 	"context"
 	"mq-toolkit/internal/mq"
 	"mq-toolkit/pkg/types"
-	"strings"
+	"time"
 )
 
 // Client Kafka完整客户端实现
@@ -89,7 +89,12 @@ func (c *Client) DeleteTopic(ctx context.Context, topic string) error {
 
 // ListConsumerGroups 列出消费组
 func (c *Client) ListConsumerGroups(ctx context.Context) ([]types.ConsumerGroup, error) {
-	return c.admin.ListConsumerGroups(ctx)
+	return c.admin.(mq.GroupAdmin).ListConsumerGroups(ctx)
+}
+
+// SeekGroup 按时间重置消费组位置
+func (c *Client) SeekGroup(ctx context.Context, group, topic string, t time.Time) error {
+	return c.admin.(mq.OffsetSeeker).SeekGroup(ctx, group, topic, t)
 }
 
 // Close 关闭客户端
@@ -116,18 +121,7 @@ func (c *Client) IsConnected() bool {
 	return c.producer.IsConnected() && c.consumer.IsConnected()
 }
 
-// parseCommaSeparated 解析逗号分隔的字符串
-func parseCommaSeparated(s string) []string {
-	if s == "" {
-		return nil
-	}
-
-	var result []string
-	for _, item := range strings.Split(s, ",") {
-		item = strings.TrimSpace(item)
-		if item != "" {
-			result = append(result, item)
-		}
-	}
-	return result
+// Ping 通过管理连接探测 broker 是否可达
+func (c *Client) Ping(ctx context.Context) error {
+	return c.admin.(mq.Pinger).Ping(ctx)
 }
@@ -2,8 +2,8 @@ package kafka
 
 import (
 	"context"
-	"fmt"
 	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
 	"mq-toolkit/pkg/types"
 	"mq-toolkit/pkg/utils"
 	"strconv"
@@ -17,6 +17,7 @@ type Consumer struct {
 	reader    *kafka.Reader
 	connected bool
 	config    *types.ConnectionConfig
+	tunnel    *tunnel.Tunnel
 }
 
 // NewConsumer 创建Kafka消费者
@@ -29,8 +30,13 @@ func (c *Consumer) Connect(ctx context.Context, config *types.ConnectionConfig)
 	if config.Type != types.MQTypeKafka {
 		return utils.NewValidationError("Invalid MQ type for Kafka consumer", string(config.Type))
 	}
+	t, err := tunnel.Open(config.Tunnel)
+	if err != nil {
+		return utils.NewConnectionError("Failed to open tunnel", err)
+	}
 	// 保存配置以备后用
 	c.config = config
+	c.tunnel = t
 	c.connected = true
 	return nil
 }
@@ -46,14 +52,7 @@ func (c *Consumer) Subscribe(ctx context.Context, req *types.ConsumeRequest) err
 	}
 
 	// 构建broker地址
-	brokers := []string{fmt.Sprintf("%s:%d", c.config.Host, c.config.Port)}
-	if extraBrokers, ok := c.config.Extra["brokers"]; ok {
-		for _, broker := range parseCommaSeparated(extraBrokers) {
-			if broker != "" {
-				brokers = append(brokers, broker)
-			}
-		}
-	}
+	brokers := c.config.Addresses()
 
 	// 确定消费组ID
 	groupID := req.GroupID
@@ -76,6 +75,7 @@ func (c *Consumer) Subscribe(ctx context.Context, req *types.ConsumeRequest) err
 		GroupID:  groupID,
 		Topic:    topic,
 		MaxBytes: 10e6, // 10MB
+		Dialer:   newDialer(c.tunnel, c.config.Timeouts.DialTimeout()),
 	}
 
 	// 配置起始位置
@@ -120,8 +120,8 @@ func (c *Consumer) Consume(ctx context.Context, handler mq.MessageHandler) error
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
-			// 读取消息
-			message, err := c.reader.ReadMessage(ctx)
+			// 读取消息，处理成功后再提交位移，保证至少一次
+			message, err := c.reader.FetchMessage(ctx)
 			if err != nil {
 				// 如果是上下文取消，则正常退出
 				if err == context.Canceled || err == context.DeadlineExceeded {
@@ -155,10 +155,20 @@ func (c *Consumer) Consume(ctx context.Context, handler mq.MessageHandler) error
 				}
 			}
 
-			// 调用处理器
-			if err := handler(msg); err != nil {
-				// 记录错误但继续处理，除非处理器返回特定错误
-				fmt.Printf("message handler error: %v\n", err)
+			// 调用处理器，失败时不提交该消息的位移并继续消费；ctx 已结束时直接退出
+			err = handler(msg)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				continue
+			}
+
+			if err := c.reader.CommitMessages(ctx, message); err != nil {
+				if err == context.Canceled || err == context.DeadlineExceeded {
+					return nil
+				}
+				return utils.NewConnectionError("Failed to commit message", err)
 			}
 		}
 	}
@@ -168,6 +178,10 @@ func (c *Consumer) Consume(ctx context.Context, handler mq.MessageHandler) error
 func (c *Consumer) Close() error {
 	c.connected = false
 	c.config = nil
+	if c.tunnel != nil {
+		defer c.tunnel.Close()
+		c.tunnel = nil
+	}
 	if c.reader != nil {
 		err := c.reader.Close()
 		c.reader = nil
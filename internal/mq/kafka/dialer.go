@@ -0,0 +1,42 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/internal/tunnel"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// newDialer 创建经隧道拨号的 Dialer，broker 公告的地址同样经隧道访问
+func newDialer(t *tunnel.Tunnel, timeout time.Duration) *kafka.Dialer {
+	return &kafka.Dialer{
+		Timeout:   timeout,
+		DualStack: true,
+		DialFunc:  t.DialContext,
+	}
+}
+
+// dialBootstrap 依次尝试 bootstrap 地址，返回第一个连接成功的节点
+func dialBootstrap(ctx context.Context, dialer *kafka.Dialer, addresses []string) (*kafka.Conn, error) {
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("no bootstrap servers configured")
+	}
+	var lastErr error
+	for _, address := range addresses {
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = fmt.Errorf("%s: %w", address, err)
+	}
+	return nil, lastErr
+}
+
+// setDeadline 将 ctx 的截止时间应用到连接，kafka.Conn 的请求方法不接受 context
+func setDeadline(ctx context.Context, conn *kafka.Conn) {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+}
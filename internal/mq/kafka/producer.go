@@ -2,8 +2,8 @@ package kafka
 
 import (
 	"context"
-	"fmt"
 	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
 	"mq-toolkit/pkg/types"
 	"mq-toolkit/pkg/utils"
 	"strconv"
@@ -15,6 +15,8 @@ import (
 // Producer Kafka生产者实现
 type Producer struct {
 	writer    *kafka.Writer
+	tunnel    *tunnel.Tunnel
+	timeouts  *types.TimeoutConfig
 	connected bool
 }
 
@@ -30,24 +32,16 @@ func (p *Producer) Connect(ctx context.Context, config *types.ConnectionConfig)
 	}
 
 	// 构建broker地址
-	brokers := []string{fmt.Sprintf("%s:%d", config.Host, config.Port)}
-
-	// 从Extra配置中获取额外的broker
-	if extraBrokers, ok := config.Extra["brokers"]; ok {
-		for _, broker := range parseCommaSeparated(extraBrokers) {
-			if broker != "" {
-				brokers = append(brokers, broker)
-			}
-		}
-	}
+	brokers := config.Addresses()
 
 	// 创建Writer配置
 	writerConfig := kafka.WriterConfig{
 		Brokers: brokers,
 		// Topic会在发送消息时指定
-		Balancer:     &kafka.LeastBytes{}, // 默认使用最少字节负载均衡
+		Balancer:     &partitionBalancer{fallback: &kafka.LeastBytes{}}, // 未指定分区时使用最少字节负载均衡
 		BatchTimeout: 10 * time.Millisecond,
 		BatchSize:    100,
+		WriteTimeout: config.Timeouts.ProduceTimeout(),
 	}
 
 	// 配置认证
@@ -70,6 +64,22 @@ func (p *Producer) Connect(ctx context.Context, config *types.ConnectionConfig)
 	}
 
 	p.writer = kafka.NewWriter(writerConfig)
+	transport, _ := p.writer.Transport.(*kafka.Transport)
+	if transport != nil {
+		transport.DialTimeout = config.Timeouts.DialTimeout()
+	}
+	if tunnel.Enabled(config.Tunnel) {
+		t, err := tunnel.Open(config.Tunnel)
+		if err != nil {
+			p.writer.Close()
+			return utils.NewConnectionError("Failed to open tunnel", err)
+		}
+		p.tunnel = t
+		if transport != nil {
+			transport.Dial = t.DialContext
+		}
+	}
+	p.timeouts = config.Timeouts
 	p.connected = true
 
 	return nil
@@ -108,10 +118,11 @@ func (p *Producer) Produce(ctx context.Context, req *types.ProduceRequest) error
 	// 指定分区（如果提供）
 	if req.Partition != nil {
 		message.Partition = int(*req.Partition)
+		message.WriterData = explicitPartition{}
 	}
 
 	// 发送消息
-	return p.writer.WriteMessages(ctx, message)
+	return p.write(ctx, message)
 }
 
 // ProduceBatch 批量发送消息
@@ -153,17 +164,46 @@ func (p *Producer) ProduceBatch(ctx context.Context, reqs []*types.ProduceReques
 		// 指定分区（如果提供）
 		if req.Partition != nil {
 			message.Partition = int(*req.Partition)
+			message.WriterData = explicitPartition{}
 		}
 
 		messages = append(messages, message)
 	}
 
 	// 批量发送消息
-	return p.writer.WriteMessages(ctx, messages...)
+	return p.write(ctx, messages...)
+}
+
+// explicitPartition 写入 Message.WriterData，表示 Message.Partition 由调用方指定
+type explicitPartition struct{}
+
+// partitionBalancer kafka-go 的 Writer 忽略 Message.Partition，只按 Balancer 选择分区；
+// 消息指定了分区时直接使用该分区（分区不存在时由 broker 返回错误），否则交给 fallback
+type partitionBalancer struct {
+	fallback kafka.Balancer
+}
+
+// Balance 实现 kafka.Balancer
+func (b *partitionBalancer) Balance(msg kafka.Message, partitions ...int) int {
+	if _, ok := msg.WriterData.(explicitPartition); ok {
+		return msg.Partition
+	}
+	return b.fallback.Balance(msg, partitions...)
+}
+
+// write 在发送超时内写入消息，超时返回超时错误
+func (p *Producer) write(ctx context.Context, messages ...kafka.Message) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeouts.ProduceTimeout())
+	defer cancel()
+	return utils.WrapTimeout(ctx, p.writer.WriteMessages(ctx, messages...), "Producing to Kafka", p.timeouts.ProduceTimeout())
 }
 
 // Close 关闭生产者
 func (p *Producer) Close() error {
+	if p.tunnel != nil {
+		defer p.tunnel.Close()
+		p.tunnel = nil
+	}
 	if p.writer != nil {
 		err := p.writer.Close()
 		p.writer = nil
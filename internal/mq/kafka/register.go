@@ -0,0 +1,27 @@
+package kafka
+
+import (
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+)
+
+// init 注册 Kafka 后端
+func init() {
+	mq.Register(mq.Descriptor{
+		BackendInfo: types.BackendInfo{
+			Type:          types.MQTypeKafka,
+			DisplayName:   "Apache Kafka",
+			Order:         10,
+			DefaultHost:   "localhost",
+			DefaultPort:   9092,
+			EndpointLabel: "其他 Bootstrap 节点",
+			Capabilities: []types.Capability{
+				types.CapabilityTunnel, types.CapabilityEndpoints, types.CapabilityUserPassword, types.CapabilityListTopics, types.CapabilityTopicPartition, types.CapabilityTopicReplicas,
+			},
+		},
+		NewClient:   NewClient,
+		NewProducer: NewProducer,
+		NewConsumer: NewConsumer,
+		NewAdmin:    NewAdmin,
+	})
+}
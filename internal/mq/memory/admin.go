@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"time"
+)
+
+// Admin 内存消息队列管理客户端实现
+type Admin struct {
+	broker *Broker
+}
+
+// NewAdmin 创建内存消息队列管理客户端
+func NewAdmin() mq.Admin {
+	return &Admin{}
+}
+
+// Connect 打开 broker
+func (a *Admin) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	broker, err := openBroker(config, "admin")
+	if err != nil {
+		return err
+	}
+	a.broker = broker
+	return nil
+}
+
+// TestConnection 测试连接，已打开 broker 即成功
+func (a *Admin) TestConnection(ctx context.Context) *types.TestResult {
+	start := time.Now()
+
+	if a.broker == nil {
+		return &types.TestResult{
+			Success: false,
+			Message: "Not connected to memory broker",
+			Latency: time.Since(start).Milliseconds(),
+		}
+	}
+
+	return &types.TestResult{
+		Success: true,
+		Message: fmt.Sprintf("Connected to in-process memory broker %q", a.broker.Name()),
+		Latency: time.Since(start).Milliseconds(),
+	}
+}
+
+// ListTopics 列出主题
+func (a *Admin) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
+	if a.broker == nil {
+		return nil, utils.NewConnectionError("Admin not connected", nil)
+	}
+	return a.broker.Topics(), nil
+}
+
+// CreateTopic 创建主题，副本数被忽略
+func (a *Admin) CreateTopic(ctx context.Context, topic string, partitions int32, replicas int16) error {
+	if a.broker == nil {
+		return utils.NewConnectionError("Admin not connected", nil)
+	}
+	return a.broker.CreateTopic(topic, int(partitions))
+}
+
+// DeleteTopic 删除主题及其中的消息
+func (a *Admin) DeleteTopic(ctx context.Context, topic string) error {
+	if a.broker == nil {
+		return utils.NewConnectionError("Admin not connected", nil)
+	}
+	return a.broker.DeleteTopic(topic)
+}
+
+// ListConsumerGroups 列出消费组
+func (a *Admin) ListConsumerGroups(ctx context.Context) ([]types.ConsumerGroup, error) {
+	if a.broker == nil {
+		return nil, utils.NewConnectionError("Admin not connected", nil)
+	}
+	return a.broker.Groups(), nil
+}
+
+// SeekGroup 将消费组在主题上的位移移到指定时间之后的第一条消息
+func (a *Admin) SeekGroup(ctx context.Context, group, topic string, t time.Time) error {
+	if a.broker == nil {
+		return utils.NewConnectionError("Admin not connected", nil)
+	}
+	return a.broker.Seek(group, topic, t)
+}
+
+// Browse 查看主题中最早的消息
+func (a *Admin) Browse(ctx context.Context, topic string, limit int) ([]types.Message, error) {
+	if a.broker == nil {
+		return nil, utils.NewConnectionError("Admin not connected", nil)
+	}
+	return a.broker.Browse(topic, limit)
+}
+
+// Ping 内存 broker 始终可用
+func (a *Admin) Ping(ctx context.Context) error {
+	if a.broker == nil {
+		return utils.NewConnectionError("Admin not connected", nil)
+	}
+	return nil
+}
+
+// Close 关闭管理客户端，broker 中的消息保留
+func (a *Admin) Close() error {
+	a.broker = nil
+	return nil
+}
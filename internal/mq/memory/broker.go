@@ -0,0 +1,599 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultBrokerName 连接未填写主机时使用的 broker 名称
+	defaultBrokerName = "default"
+	// defaultMaxMessages 每个分区默认保留的消息数
+	defaultMaxMessages = 100000
+	// pollBatchSize 消费者每次取出的最大消息数
+	pollBatchSize = 100
+)
+
+// brokers 进程内的 broker，按名称共享，进程退出前一直保留
+var brokers = struct {
+	sync.Mutex
+	m map[string]*Broker
+}{m: make(map[string]*Broker)}
+
+// Options broker 的设置，在第一次打开时确定
+type Options struct {
+	Partitions  int           // 自动创建的主题的分区数，默认 1
+	MaxMessages int           // 每个分区保留的消息数，默认 100000
+	Retention   time.Duration // 消息保留时间，0 表示不按时间删除
+}
+
+// optionsFromConfig 从连接的 Extra 解析 broker 设置：partitions、max_messages 和 retention（如 30m、24h）
+func optionsFromConfig(config *types.ConnectionConfig) (Options, error) {
+	opts := Options{Partitions: 1, MaxMessages: defaultMaxMessages}
+	if value := config.Extra["partitions"]; value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return opts, utils.NewValidationError("Invalid partitions, expected a positive integer", value)
+		}
+		opts.Partitions = n
+	}
+	if value := config.Extra["max_messages"]; value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return opts, utils.NewValidationError("Invalid max messages, expected a positive integer", value)
+		}
+		opts.MaxMessages = n
+	}
+	if value := config.Extra["retention"]; value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return opts, utils.NewValidationError("Invalid retention, expected a duration such as 30m or 24h", value)
+		}
+		opts.Retention = d
+	}
+	return opts, nil
+}
+
+// Open 返回名称为 name 的 broker，不存在时以 opts 创建
+func Open(name string, opts Options) *Broker {
+	if name == "" {
+		name = defaultBrokerName
+	}
+	brokers.Lock()
+	defer brokers.Unlock()
+
+	if b, ok := brokers.m[name]; ok {
+		return b
+	}
+	if opts.Partitions < 1 {
+		opts.Partitions = 1
+	}
+	if opts.MaxMessages < 1 {
+		opts.MaxMessages = defaultMaxMessages
+	}
+	b := &Broker{
+		name:   name,
+		opts:   opts,
+		topics: make(map[string]*topic),
+		groups: make(map[string]*group),
+		notify: make(chan struct{}),
+	}
+	brokers.m[name] = b
+	return b
+}
+
+// Reset 丢弃所有 broker 及其中的消息，已连接的客户端继续使用原来的 broker，用于测试之间隔离状态
+func Reset() {
+	brokers.Lock()
+	brokers.m = make(map[string]*Broker)
+	brokers.Unlock()
+}
+
+// Broker 进程内的消息 broker
+//
+// 主题由若干分区组成，每个分区是按位移递增的消息日志，超过保留条数或保留时间的消息从头部删除。
+// 消费组记录每个分区已提交的位移，组内成员按轮询分配分区，成员加入或离开时重新分配。
+type Broker struct {
+	name string
+	opts Options
+
+	mu         sync.Mutex
+	topics     map[string]*topic
+	groups     map[string]*group
+	notify     chan struct{} // 有新消息或分区重新分配时关闭并替换，唤醒等待的消费者
+	nextID     int
+	nextMember int
+}
+
+// topic 主题
+type topic struct {
+	name       string
+	partitions []*partition
+	next       int // 没有消息键时轮询选择分区
+}
+
+// partition 分区日志，messages[0] 的位移为 base
+type partition struct {
+	base     int64
+	messages []*types.Message
+}
+
+// end 返回下一条消息的位移
+func (p *partition) end() int64 {
+	return p.base + int64(len(p.messages))
+}
+
+// topicPartition 主题分区
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
+// group 消费组
+type group struct {
+	committed map[topicPartition]int64 // 下一条要消费的位移
+	members   map[string]*member
+}
+
+// member 消费组成员
+type member struct {
+	id       string
+	topics   []string
+	assigned map[topicPartition]bool
+}
+
+// Name 返回 broker 名称
+func (b *Broker) Name() string {
+	return b.name
+}
+
+// wake 唤醒等待的消费者，调用方持有锁
+func (b *Broker) wake() {
+	close(b.notify)
+	b.notify = make(chan struct{})
+}
+
+// createTopic 创建主题，调用方持有锁
+func (b *Broker) createTopic(name string, partitions int) *topic {
+	t := &topic{name: name, partitions: make([]*partition, partitions)}
+	for i := range t.partitions {
+		t.partitions[i] = &partition{}
+	}
+	b.topics[name] = t
+	return t
+}
+
+// CreateTopic 创建主题，partitions 小于 1 时使用默认分区数，已存在时返回校验错误
+func (b *Broker) CreateTopic(name string, partitions int) error {
+	if name == "" {
+		return utils.NewValidationError("Topic name is required", "")
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if partitions < 1 {
+		partitions = b.opts.Partitions
+	}
+	if _, ok := b.topics[name]; ok {
+		return utils.NewValidationError("Topic already exists", name)
+	}
+	b.createTopic(name, partitions)
+	b.rebalance()
+	return nil
+}
+
+// DeleteTopic 删除主题及各消费组在该主题上提交的位移
+func (b *Broker) DeleteTopic(name string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.topics[name]; !ok {
+		return utils.NewNotFoundError("Topic", name)
+	}
+	delete(b.topics, name)
+	for _, g := range b.groups {
+		for tp := range g.committed {
+			if tp.topic == name {
+				delete(g.committed, tp)
+			}
+		}
+	}
+	b.rebalance()
+	return nil
+}
+
+// Topics 返回主题名和分区数，按名称排序
+func (b *Broker) Topics() []types.TopicInfo {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	topics := make([]types.TopicInfo, 0, len(b.topics))
+	for _, t := range b.topics {
+		topics = append(topics, types.TopicInfo{Name: t.name, Partitions: int32(len(t.partitions)), Replicas: 1})
+	}
+	sort.Slice(topics, func(i, j int) bool { return topics[i].Name < topics[j].Name })
+	return topics
+}
+
+// Groups 返回消费组、成员和订阅的主题，按组名排序
+func (b *Broker) Groups() []types.ConsumerGroup {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	groups := make([]types.ConsumerGroup, 0, len(b.groups))
+	for id, g := range b.groups {
+		members := make([]string, 0, len(g.members))
+		topicSet := make(map[string]bool)
+		for _, m := range g.members {
+			members = append(members, m.id)
+			for _, t := range m.topics {
+				topicSet[t] = true
+			}
+		}
+		for tp := range g.committed {
+			topicSet[tp.topic] = true
+		}
+		topics := make([]string, 0, len(topicSet))
+		for t := range topicSet {
+			topics = append(topics, t)
+		}
+		sort.Strings(members)
+		sort.Strings(topics)
+		groups = append(groups, types.ConsumerGroup{ID: id, Members: members, Topics: topics})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].ID < groups[j].ID })
+	return groups
+}
+
+// Publish 追加消息，主题不存在时按默认分区数创建，返回消息的分区和位移
+//
+// 指定分区时写入该分区，否则有消息键时按键的哈希选择分区，没有消息键时轮询。
+func (b *Broker) Publish(req *types.ProduceRequest) (int32, int64, error) {
+	if req.Topic == "" {
+		return 0, 0, utils.NewValidationError("Topic is required", "")
+	}
+	now := time.Now()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t, ok := b.topics[req.Topic]
+	if !ok {
+		t = b.createTopic(req.Topic, b.opts.Partitions)
+		b.rebalance()
+	}
+
+	var index int
+	switch {
+	case req.Partition != nil:
+		index = int(*req.Partition)
+		if index < 0 || index >= len(t.partitions) {
+			return 0, 0, utils.NewValidationError(fmt.Sprintf("Partition out of range, topic %s has %d partitions", req.Topic, len(t.partitions)), strconv.Itoa(index))
+		}
+	case req.Key != "":
+		h := fnv.New32a()
+		h.Write([]byte(req.Key))
+		index = int(h.Sum32() % uint32(len(t.partitions)))
+	default:
+		index = t.next % len(t.partitions)
+		t.next++
+	}
+
+	p := t.partitions[index]
+	offset := p.end()
+	b.nextID++
+	msg := &types.Message{
+		ID:        fmt.Sprintf("%s-%d", b.name, b.nextID),
+		Topic:     req.Topic,
+		Key:       req.Key,
+		Value:     req.Value,
+		Headers:   copyHeaders(req.Headers),
+		Partition: int32(index),
+		Offset:    offset,
+		Timestamp: now,
+	}
+	p.messages = append(p.messages, msg)
+	b.retain(p, now)
+	b.wake()
+	return int32(index), offset, nil
+}
+
+// retain 删除分区中超过保留条数或保留时间的消息，调用方持有锁
+func (b *Broker) retain(p *partition, now time.Time) {
+	drop := len(p.messages) - b.opts.MaxMessages
+	if drop < 0 {
+		drop = 0
+	}
+	if b.opts.Retention > 0 {
+		cutoff := now.Add(-b.opts.Retention)
+		for drop < len(p.messages) && p.messages[drop].Timestamp.Before(cutoff) {
+			drop++
+		}
+	}
+	if drop <= 0 {
+		return
+	}
+	// 复制剩余消息，释放被删除消息占用的底层数组
+	p.messages = append([]*types.Message(nil), p.messages[drop:]...)
+	p.base += int64(drop)
+}
+
+// join 把成员加入消费组并重新分配分区，topics 中不存在的主题按默认分区数创建，调用方持有锁
+func (b *Broker) join(groupID, memberID string, topics []string) *member {
+	for _, name := range topics {
+		if _, ok := b.topics[name]; !ok {
+			b.createTopic(name, b.opts.Partitions)
+		}
+	}
+	g, ok := b.groups[groupID]
+	if !ok {
+		g = &group{committed: make(map[topicPartition]int64), members: make(map[string]*member)}
+		b.groups[groupID] = g
+	}
+	m := &member{id: memberID, topics: topics, assigned: make(map[topicPartition]bool)}
+	g.members[memberID] = m
+	b.rebalance()
+	return m
+}
+
+// leave 把成员移出消费组并重新分配分区，调用方持有锁
+func (b *Broker) leave(groupID, memberID string) {
+	if g, ok := b.groups[groupID]; ok {
+		delete(g.members, memberID)
+		b.rebalance()
+	}
+}
+
+// rebalance 按成员 ID 排序后轮询分配每个主题的分区，调用方持有锁
+func (b *Broker) rebalance() {
+	for _, g := range b.groups {
+		subscribers := make(map[string][]*member)
+		for _, m := range g.members {
+			m.assigned = make(map[topicPartition]bool)
+			for _, name := range m.topics {
+				subscribers[name] = append(subscribers[name], m)
+			}
+		}
+		for name, members := range subscribers {
+			t, ok := b.topics[name]
+			if !ok {
+				continue
+			}
+			sort.Slice(members, func(i, j int) bool { return members[i].id < members[j].id })
+			for i := range t.partitions {
+				members[i%len(members)].assigned[topicPartition{topic: name, partition: int32(i)}] = true
+			}
+		}
+	}
+	b.wake()
+}
+
+// commit 提交消费组在分区上的位移，offset 为下一条要消费的位移
+func (b *Broker) commit(groupID string, tp topicPartition, offset int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if g, ok := b.groups[groupID]; ok && offset > g.committed[tp] {
+		g.committed[tp] = offset
+	}
+}
+
+// Seek 将消费组在主题各分区上的位移移到第一条时间不早于 t 的消息，消费组不存在时创建
+//
+// 组内成员只在分配到分区时读取提交的位移，因此组内有成员时返回校验错误。
+func (b *Broker) Seek(groupID, topicName string, t time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tp, ok := b.topics[topicName]
+	if !ok {
+		return utils.NewNotFoundError("Topic", topicName)
+	}
+	g, ok := b.groups[groupID]
+	if !ok {
+		g = &group{committed: make(map[topicPartition]int64), members: make(map[string]*member)}
+		b.groups[groupID] = g
+	}
+	if len(g.members) > 0 {
+		return utils.NewValidationError("Consumer group has active members, stop them before moving its position", groupID)
+	}
+
+	now := time.Now()
+	for i, p := range tp.partitions {
+		b.retain(p, now)
+		g.committed[topicPartition{topic: topicName, partition: int32(i)}] = p.offsetForTime(t)
+	}
+	return nil
+}
+
+// Browse 按时间顺序返回主题中最早的 limit 条消息，不影响任何消费组
+func (b *Broker) Browse(topicName string, limit int) ([]types.Message, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t, ok := b.topics[topicName]
+	if !ok {
+		return nil, utils.NewNotFoundError("Topic", topicName)
+	}
+
+	now := time.Now()
+	next := make([]int, len(t.partitions)) // 各分区下一条要比较的消息下标
+	for _, p := range t.partitions {
+		b.retain(p, now)
+	}
+	msgs := []types.Message{}
+	for len(msgs) < limit {
+		oldest := -1
+		for i, p := range t.partitions {
+			if next[i] < len(p.messages) && (oldest < 0 ||
+				p.messages[next[i]].Timestamp.Before(t.partitions[oldest].messages[next[oldest]].Timestamp)) {
+				oldest = i
+			}
+		}
+		if oldest < 0 {
+			break
+		}
+		msgs = append(msgs, *copyMessage(t.partitions[oldest].messages[next[oldest]]))
+		next[oldest]++
+	}
+	return msgs, nil
+}
+
+// subscription 消费者的订阅，只由消费者所在的 goroutine 使用
+type subscription struct {
+	group         string
+	member        *member
+	topics        []string
+	fromBeginning bool
+	startTime     *time.Time
+	positions     map[topicPartition]int64 // 下一条要读取的位移
+}
+
+// subscribe 创建订阅，指定消费组时加入该组
+func (b *Broker) subscribe(req *types.ConsumeRequest) *subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := &subscription{
+		group:         req.GroupID,
+		topics:        append([]string(nil), req.Topics...),
+		fromBeginning: req.FromBeginning,
+		startTime:     req.StartTime,
+		positions:     make(map[topicPartition]int64),
+	}
+	if s.group == "" {
+		for _, name := range s.topics {
+			if _, ok := b.topics[name]; !ok {
+				b.createTopic(name, b.opts.Partitions)
+			}
+		}
+		return s
+	}
+	b.nextMember++
+	s.member = b.join(s.group, fmt.Sprintf("member-%d", b.nextMember), s.topics)
+	return s
+}
+
+// unsubscribe 取消订阅，离开消费组
+func (b *Broker) unsubscribe(s *subscription) {
+	if s.member == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.leave(s.group, s.member.id)
+}
+
+// poll 等待并取出订阅的分区中的新消息，ctx 结束时返回 ctx 的错误
+func (b *Broker) poll(ctx context.Context, s *subscription) ([]*types.Message, error) {
+	for {
+		b.mu.Lock()
+		msgs := b.fetch(s)
+		notify := b.notify
+		b.mu.Unlock()
+
+		if len(msgs) > 0 {
+			return msgs, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-notify:
+		}
+	}
+}
+
+// fetch 从订阅的分区取出最多 pollBatchSize 条消息并前移读取位置，调用方持有锁
+//
+// 消费组的成员只读取分配给自己的分区，新分配的分区从组提交的位移开始；没有提交的位移时，
+// 依次按 StartTime、FromBeginning 确定起点，否则从最新位置开始。读取位置已被保留策略删除时
+// 从最早的消息开始。
+func (b *Broker) fetch(s *subscription) []*types.Message {
+	var g *group
+	if s.member != nil {
+		g = b.groups[s.group]
+		for tp := range s.positions {
+			if !s.member.assigned[tp] {
+				delete(s.positions, tp)
+			}
+		}
+	}
+
+	now := time.Now()
+	var msgs []*types.Message
+	for _, name := range s.topics {
+		t, ok := b.topics[name]
+		if !ok {
+			continue
+		}
+		for i, p := range t.partitions {
+			tp := topicPartition{topic: name, partition: int32(i)}
+			if s.member != nil && !s.member.assigned[tp] {
+				continue
+			}
+			b.retain(p, now)
+
+			pos, ok := s.positions[tp]
+			if !ok {
+				committed, hasCommitted := int64(0), false
+				if g != nil {
+					committed, hasCommitted = g.committed[tp]
+				}
+				switch {
+				case hasCommitted:
+					pos = committed
+				case s.startTime != nil:
+					pos = p.offsetForTime(*s.startTime)
+				case s.fromBeginning:
+					pos = p.base
+				default:
+					pos = p.end()
+				}
+			}
+			if pos < p.base {
+				pos = p.base
+			}
+			if pos > p.end() {
+				pos = p.end()
+			}
+			for pos < p.end() && len(msgs) < pollBatchSize {
+				msgs = append(msgs, copyMessage(p.messages[pos-p.base]))
+				pos++
+			}
+			s.positions[tp] = pos
+		}
+	}
+	return msgs
+}
+
+// offsetForTime 返回分区中第一条时间不早于 t 的消息的位移，调用方持有锁
+func (p *partition) offsetForTime(t time.Time) int64 {
+	i := sort.Search(len(p.messages), func(i int) bool { return !p.messages[i].Timestamp.Before(t) })
+	return p.base + int64(i)
+}
+
+// copyHeaders 复制消息头，避免调用方修改已保存的消息
+func copyHeaders(headers map[string]string) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	copied := make(map[string]string, len(headers))
+	for k, v := range headers {
+		copied[k] = v
+	}
+	return copied
+}
+
+// copyMessage 复制消息，消费者拿到的消息与日志中的互不影响
+func copyMessage(m *types.Message) *types.Message {
+	copied := *m
+	copied.Headers = copyHeaders(m.Headers)
+	return &copied
+}
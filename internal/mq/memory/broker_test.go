@@ -0,0 +1,249 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"testing"
+	"time"
+)
+
+// openTestBroker 打开测试用的 broker，测试结束后丢弃所有 broker
+func openTestBroker(t *testing.T, opts Options) *Broker {
+	t.Helper()
+	t.Cleanup(Reset)
+	return Open(t.Name(), opts)
+}
+
+// publish 发送消息，失败时结束测试
+func publish(t *testing.T, b *Broker, req *types.ProduceRequest) (int32, int64) {
+	t.Helper()
+	partition, offset, err := b.Publish(req)
+	if err != nil {
+		t.Fatalf("Publish(%s) failed: %v", req.Topic, err)
+	}
+	return partition, offset
+}
+
+func TestPublishSelectsPartitionByKey(t *testing.T) {
+	b := openTestBroker(t, Options{Partitions: 4})
+
+	partitions := make(map[string]int32)
+	for i := 0; i < 3; i++ {
+		for _, key := range []string{"alpha", "beta", "gamma"} {
+			partition, _ := publish(t, b, &types.ProduceRequest{Topic: "orders", Key: key, Value: "v"})
+			if previous, ok := partitions[key]; ok && previous != partition {
+				t.Fatalf("key %s went to partition %d, then %d", key, previous, partition)
+			}
+			partitions[key] = partition
+		}
+	}
+
+	// 没有消息键时轮询所有分区
+	seen := make(map[int32]bool)
+	for i := 0; i < 4; i++ {
+		partition, _ := publish(t, b, &types.ProduceRequest{Topic: "events", Value: "v"})
+		seen[partition] = true
+	}
+	if len(seen) != 4 {
+		t.Fatalf("messages without key used %d of 4 partitions", len(seen))
+	}
+
+	explicit := int32(2)
+	if partition, _ := publish(t, b, &types.ProduceRequest{Topic: "events", Key: "alpha", Partition: &explicit}); partition != explicit {
+		t.Fatalf("explicit partition: got %d, want %d", partition, explicit)
+	}
+	outOfRange := int32(4)
+	if _, _, err := b.Publish(&types.ProduceRequest{Topic: "events", Partition: &outOfRange}); !utils.IsErrorType(err, utils.ErrorTypeValidation) {
+		t.Fatalf("partition out of range: got %v, want a validation error", err)
+	}
+}
+
+func TestRetentionByCount(t *testing.T) {
+	b := openTestBroker(t, Options{MaxMessages: 3})
+
+	for i := 0; i < 5; i++ {
+		publish(t, b, &types.ProduceRequest{Topic: "orders", Value: fmt.Sprintf("m%d", i)})
+	}
+
+	msgs, err := b.Browse("orders", 10)
+	if err != nil {
+		t.Fatalf("Browse failed: %v", err)
+	}
+	if len(msgs) != 3 {
+		t.Fatalf("got %d messages, want 3", len(msgs))
+	}
+	for i, msg := range msgs {
+		if want := int64(i + 2); msg.Offset != want {
+			t.Errorf("message %d: offset %d, want %d", i, msg.Offset, want)
+		}
+		if want := fmt.Sprintf("m%d", i+2); msg.Value != want {
+			t.Errorf("message %d: value %q, want %q", i, msg.Value, want)
+		}
+	}
+}
+
+func TestRetentionByAge(t *testing.T) {
+	b := openTestBroker(t, Options{Retention: time.Minute})
+
+	publish(t, b, &types.ProduceRequest{Topic: "orders", Value: "old"})
+	publish(t, b, &types.ProduceRequest{Topic: "orders", Value: "recent"})
+
+	b.mu.Lock()
+	b.topics["orders"].partitions[0].messages[0].Timestamp = time.Now().Add(-time.Hour)
+	b.mu.Unlock()
+
+	msgs, err := b.Browse("orders", 10)
+	if err != nil {
+		t.Fatalf("Browse failed: %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].Value != "recent" || msgs[0].Offset != 1 {
+		t.Fatalf("got %+v, want only the recent message at offset 1", msgs)
+	}
+}
+
+func TestGroupRebalance(t *testing.T) {
+	b := openTestBroker(t, Options{Partitions: 4})
+	req := &types.ConsumeRequest{Topics: []string{"orders"}, GroupID: "billing"}
+
+	first := b.subscribe(req)
+	if got := len(first.member.assigned); got != 4 {
+		t.Fatalf("single member has %d partitions, want 4", got)
+	}
+
+	second := b.subscribe(req)
+	if len(first.member.assigned) != 2 || len(second.member.assigned) != 2 {
+		t.Fatalf("two members have %d and %d partitions, want 2 each", len(first.member.assigned), len(second.member.assigned))
+	}
+	for tp := range first.member.assigned {
+		if second.member.assigned[tp] {
+			t.Fatalf("partition %d assigned to both members", tp.partition)
+		}
+	}
+
+	b.unsubscribe(first)
+	if got := len(second.member.assigned); got != 4 {
+		t.Fatalf("remaining member has %d partitions, want 4", got)
+	}
+}
+
+func TestConsumerCommitsHandledMessages(t *testing.T) {
+	b := openTestBroker(t, Options{})
+	config := &types.ConnectionConfig{Type: types.MQTypeMemory, Host: t.Name()}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	consumer := NewConsumer()
+	if err := consumer.Connect(ctx, config); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer consumer.Close()
+	if err := consumer.Subscribe(ctx, &types.ConsumeRequest{Topics: []string{"orders"}, GroupID: "billing", FromBeginning: true}); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	for _, key := range []string{"a", "b", "bad"} {
+		publish(t, b, &types.ProduceRequest{Topic: "orders", Key: key, Value: "v"})
+	}
+
+	received := make(chan string, 3)
+	done := make(chan error, 1)
+	go func() {
+		done <- consumer.Consume(ctx, func(msg *types.Message) error {
+			received <- msg.Key
+			if msg.Key == "bad" {
+				return fmt.Errorf("rejected")
+			}
+			return nil
+		})
+	}()
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-received:
+		case <-time.After(time.Second):
+			t.Fatalf("received %d of 3 messages", i)
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Consume returned %v", err)
+	}
+	consumer.Close()
+
+	b.mu.Lock()
+	committed := b.groups["billing"].committed[topicPartition{topic: "orders"}]
+	b.mu.Unlock()
+	if committed != 2 {
+		t.Fatalf("committed offset %d, want 2 (the rejected message stays uncommitted)", committed)
+	}
+
+	// 原成员离开后，新成员从提交的位移开始，再次收到被拒绝的消息
+	second := b.subscribe(&types.ConsumeRequest{Topics: []string{"orders"}, GroupID: "billing"})
+	b.mu.Lock()
+	msgs := b.fetch(second)
+	b.mu.Unlock()
+	if len(msgs) != 1 || msgs[0].Key != "bad" {
+		t.Fatalf("new member got %+v, want only the rejected message", msgs)
+	}
+}
+
+func TestSeekRejectsGroupWithMembers(t *testing.T) {
+	b := openTestBroker(t, Options{})
+
+	publish(t, b, &types.ProduceRequest{Topic: "orders", Value: "first"})
+	middle := time.Now()
+	publish(t, b, &types.ProduceRequest{Topic: "orders", Value: "second"})
+
+	sub := b.subscribe(&types.ConsumeRequest{Topics: []string{"orders"}, GroupID: "billing"})
+	if err := b.Seek("billing", "orders", middle); !utils.IsErrorType(err, utils.ErrorTypeValidation) {
+		t.Fatalf("Seek with an active member: got %v, want a validation error", err)
+	}
+
+	b.unsubscribe(sub)
+	if err := b.Seek("billing", "orders", middle); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	if committed := b.groups["billing"].committed[topicPartition{topic: "orders"}]; committed != 1 {
+		t.Fatalf("committed offset %d, want 1", committed)
+	}
+
+	if err := b.Seek("billing", "missing", middle); !utils.IsErrorType(err, utils.ErrorTypeNotFound) {
+		t.Fatalf("Seek on a missing topic: got %v, want a not found error", err)
+	}
+}
+
+func TestBrowse(t *testing.T) {
+	b := openTestBroker(t, Options{Partitions: 3})
+
+	for i := 0; i < 6; i++ {
+		publish(t, b, &types.ProduceRequest{Topic: "orders", Value: fmt.Sprintf("m%d", i)})
+	}
+
+	msgs, err := b.Browse("orders", 4)
+	if err != nil {
+		t.Fatalf("Browse failed: %v", err)
+	}
+	if len(msgs) != 4 {
+		t.Fatalf("got %d messages, want 4", len(msgs))
+	}
+	for i, msg := range msgs {
+		if want := fmt.Sprintf("m%d", i); msg.Value != want {
+			t.Errorf("message %d: value %q, want %q", i, msg.Value, want)
+		}
+	}
+
+	// 浏览不影响消费组
+	sub := b.subscribe(&types.ConsumeRequest{Topics: []string{"orders"}, GroupID: "billing", FromBeginning: true})
+	b.mu.Lock()
+	fetched := b.fetch(sub)
+	b.mu.Unlock()
+	if len(fetched) != 6 {
+		t.Fatalf("group fetched %d messages after browsing, want 6", len(fetched))
+	}
+
+	if _, err := b.Browse("missing", 10); !utils.IsErrorType(err, utils.ErrorTypeNotFound) {
+		t.Fatalf("Browse on a missing topic: got %v, want a not found error", err)
+	}
+}
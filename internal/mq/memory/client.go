@@ -0,0 +1,147 @@
+package memory
+
+import (
+	"context"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"time"
+)
+
+// Client 内存消息队列完整客户端实现
+//
+// 消息保存在进程内的 broker 中，不需要外部服务，用于离线体验界面、模板和压测，以及测试。
+// 连接的主机名为 broker 名称，同名的连接共享同一个 broker，应用退出后消息丢失。
+type Client struct {
+	producer mq.Producer
+	consumer mq.Consumer
+	admin    mq.Admin
+	config   *types.ConnectionConfig
+}
+
+// NewClient 创建内存消息队列完整客户端
+func NewClient() mq.Client {
+	return &Client{
+		producer: NewProducer(),
+		consumer: NewConsumer(),
+		admin:    NewAdmin(),
+	}
+}
+
+// Connect 打开 broker
+func (c *Client) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	c.config = config
+
+	if err := c.producer.Connect(ctx, config); err != nil {
+		return err
+	}
+
+	if err := c.consumer.Connect(ctx, config); err != nil {
+		c.producer.Close()
+		return err
+	}
+
+	if err := c.admin.Connect(ctx, config); err != nil {
+		c.producer.Close()
+		c.consumer.Close()
+		return err
+	}
+
+	return nil
+}
+
+// Produce 发送消息
+func (c *Client) Produce(ctx context.Context, req *types.ProduceRequest) error {
+	return c.producer.Produce(ctx, req)
+}
+
+// ProduceBatch 批量发送消息
+func (c *Client) ProduceBatch(ctx context.Context, reqs []*types.ProduceRequest) error {
+	return c.producer.ProduceBatch(ctx, reqs)
+}
+
+// Subscribe 订阅主题
+func (c *Client) Subscribe(ctx context.Context, req *types.ConsumeRequest) error {
+	return c.consumer.Subscribe(ctx, req)
+}
+
+// Consume 消费消息
+func (c *Client) Consume(ctx context.Context, handler mq.MessageHandler) error {
+	return c.consumer.Consume(ctx, handler)
+}
+
+// TestConnection 测试连接
+func (c *Client) TestConnection(ctx context.Context) *types.TestResult {
+	return c.admin.TestConnection(ctx)
+}
+
+// ListTopics 列出主题
+func (c *Client) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
+	return c.admin.ListTopics(ctx)
+}
+
+// CreateTopic 创建主题
+func (c *Client) CreateTopic(ctx context.Context, topic string, partitions int32, replicas int16) error {
+	return c.admin.CreateTopic(ctx, topic, partitions, replicas)
+}
+
+// DeleteTopic 删除主题
+func (c *Client) DeleteTopic(ctx context.Context, topic string) error {
+	return c.admin.DeleteTopic(ctx, topic)
+}
+
+// ListConsumerGroups 列出消费组
+func (c *Client) ListConsumerGroups(ctx context.Context) ([]types.ConsumerGroup, error) {
+	return c.admin.(mq.GroupAdmin).ListConsumerGroups(ctx)
+}
+
+// SeekGroup 移动消费组位置
+func (c *Client) SeekGroup(ctx context.Context, group, topic string, t time.Time) error {
+	return c.admin.(mq.OffsetSeeker).SeekGroup(ctx, group, topic, t)
+}
+
+// Browse 查看主题中最早的消息
+func (c *Client) Browse(ctx context.Context, topic string, limit int) ([]types.Message, error) {
+	return c.admin.(mq.Browser).Browse(ctx, topic, limit)
+}
+
+// Close 关闭客户端
+func (c *Client) Close() error {
+	var lastErr error
+
+	if err := c.producer.Close(); err != nil {
+		lastErr = err
+	}
+
+	if err := c.consumer.Close(); err != nil {
+		lastErr = err
+	}
+
+	if err := c.admin.Close(); err != nil {
+		lastErr = err
+	}
+
+	return lastErr
+}
+
+// IsConnected 检查连接状态
+func (c *Client) IsConnected() bool {
+	return c.producer.IsConnected() && c.consumer.IsConnected()
+}
+
+// Ping 内存 broker 始终可用
+func (c *Client) Ping(ctx context.Context) error {
+	return c.admin.(mq.Pinger).Ping(ctx)
+}
+
+// openBroker 校验连接类型并按连接设置打开 broker
+func openBroker(config *types.ConnectionConfig, role string) (*Broker, error) {
+	if config.Type != types.MQTypeMemory {
+		return nil, utils.NewValidationError("Invalid MQ type for memory "+role, string(config.Type))
+	}
+	opts, err := optionsFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return Open(config.Host, opts), nil
+}
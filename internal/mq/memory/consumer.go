@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+)
+
+// Consumer 内存消息队列消费者实现
+//
+// 指定消费组时加入该组，组内成员分摊主题的分区，处理成功后提交位移；处理失败的消息不提交位移，
+// 继续处理下一条。不指定消费组时单独读取所有分区，不提交位移。
+type Consumer struct {
+	broker *Broker
+	sub    *subscription
+}
+
+// NewConsumer 创建内存消息队列消费者
+func NewConsumer() mq.Consumer {
+	return &Consumer{}
+}
+
+// Connect 打开 broker
+func (c *Consumer) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	broker, err := openBroker(config, "consumer")
+	if err != nil {
+		return err
+	}
+	c.broker = broker
+	return nil
+}
+
+// Subscribe 订阅主题，不存在的主题按默认分区数创建，已有订阅时先取消
+func (c *Consumer) Subscribe(ctx context.Context, req *types.ConsumeRequest) error {
+	if c.broker == nil {
+		return utils.NewConnectionError("Consumer not connected", nil)
+	}
+
+	if len(req.Topics) == 0 {
+		return utils.NewValidationError("No topics specified for subscription", "")
+	}
+	for _, topic := range req.Topics {
+		if topic == "" {
+			return utils.NewValidationError("Topic name is required", "")
+		}
+	}
+
+	if c.sub != nil {
+		c.broker.unsubscribe(c.sub)
+	}
+	c.sub = c.broker.subscribe(req)
+	return nil
+}
+
+// Consume 消费消息，直到 ctx 结束
+func (c *Consumer) Consume(ctx context.Context, handler mq.MessageHandler) error {
+	if c.broker == nil {
+		return utils.NewConnectionError("Consumer not connected", nil)
+	}
+
+	if c.sub == nil {
+		return utils.NewConnectionError("Consumer not subscribed to any topic. Call Subscribe first.", nil)
+	}
+
+	// Close 可能与消费并发调用，使用开始时的 broker 和订阅
+	broker, sub := c.broker, c.sub
+	for {
+		msgs, err := broker.poll(ctx, sub)
+		if err != nil {
+			// ctx 结束，正常退出
+			return nil
+		}
+		for _, msg := range msgs {
+			err := handler(msg)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err == nil && sub.member != nil {
+				broker.commit(sub.group, topicPartition{topic: msg.Topic, partition: msg.Partition}, msg.Offset+1)
+			}
+		}
+	}
+}
+
+// Close 关闭消费者，离开消费组
+func (c *Consumer) Close() error {
+	if c.broker != nil && c.sub != nil {
+		c.broker.unsubscribe(c.sub)
+	}
+	c.broker = nil
+	c.sub = nil
+	return nil
+}
+
+// IsConnected 检查连接状态
+func (c *Consumer) IsConnected() bool {
+	return c.broker != nil
+}
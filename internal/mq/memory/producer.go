@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+)
+
+// Producer 内存消息队列生产者实现
+//
+// 主题不存在时按默认分区数自动创建。
+type Producer struct {
+	broker *Broker
+}
+
+// NewProducer 创建内存消息队列生产者
+func NewProducer() mq.Producer {
+	return &Producer{}
+}
+
+// Connect 打开 broker
+func (p *Producer) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	broker, err := openBroker(config, "producer")
+	if err != nil {
+		return err
+	}
+	p.broker = broker
+	return nil
+}
+
+// Produce 发送单条消息
+func (p *Producer) Produce(ctx context.Context, req *types.ProduceRequest) error {
+	if p.broker == nil {
+		return utils.NewConnectionError("Producer not connected", nil)
+	}
+	_, _, err := p.broker.Publish(req)
+	return err
+}
+
+// ProduceBatch 批量发送消息，遇到失败立即返回，此前的消息已写入
+func (p *Producer) ProduceBatch(ctx context.Context, reqs []*types.ProduceRequest) error {
+	if p.broker == nil {
+		return utils.NewConnectionError("Producer not connected", nil)
+	}
+
+	if len(reqs) == 0 {
+		return utils.NewValidationError("Empty message batch", "")
+	}
+
+	for _, req := range reqs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, _, err := p.broker.Publish(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close 关闭生产者，broker 中的消息保留
+func (p *Producer) Close() error {
+	p.broker = nil
+	return nil
+}
+
+// IsConnected 检查连接状态
+func (p *Producer) IsConnected() bool {
+	return p.broker != nil
+}
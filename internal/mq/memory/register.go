@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+)
+
+// init 注册 内存 后端
+func init() {
+	mq.Register(mq.Descriptor{
+		BackendInfo: types.BackendInfo{
+			Type:          types.MQTypeMemory,
+			DisplayName:   "内存（离线体验，无需服务）",
+			Order:         100,
+			DefaultHost:   "default",
+			DefaultPort:   0,
+			SettingsTitle: "内存 broker 设置",
+			SettingsHelp:  "消息保存在本应用进程内，不需要 Docker 或外部服务，应用退出后消息丢失。主机地址为 broker 名称，同名的连接共享消息；以下设置在该 broker 第一次打开时生效，端口和认证不使用",
+			Fields: []types.ConfigField{
+				{Key: "partitions", Label: "自动创建主题的分区数（默认 1）", Kind: "number"},
+				{Key: "max_messages", Label: "每分区保留条数（默认 100000）", Kind: "number"},
+				{Key: "retention", Label: "保留时间，如 30m、24h", Kind: "text", Mono: true},
+			},
+			ConsumeHint:     "填写消费组时组内成员分摊分区并提交位移，处理失败后重新订阅从已提交位移继续；不填写时单独读取所有分区",
+			CreateTopicHint: "主题保存在应用进程内，退出后丢失；发送或订阅不存在的主题时按连接设置的分区数自动创建",
+			Capabilities: []types.Capability{
+				types.CapabilityListTopics, types.CapabilityTopicPartition, types.CapabilityMultiTopic, types.CapabilityStartTime,
+			},
+		},
+		NewClient:   NewClient,
+		NewProducer: NewProducer,
+		NewConsumer: NewConsumer,
+		NewAdmin:    NewAdmin,
+	})
+}
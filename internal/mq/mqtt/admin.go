@@ -0,0 +1,206 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// discoveryWindow 列出主题时订阅 # 收集主题的时长
+const discoveryWindow = 2 * time.Second
+
+// probeTopic 测试连接时订阅并取消订阅的主题前缀，不会发布消息
+const probeTopic = "mq-toolkit/probe/"
+
+// Admin MQTT管理客户端实现
+//
+// MQTT 主题无需创建，broker 也不提供列出主题的接口：ListTopics 订阅 # 一段时间，
+// 列出收到保留消息或新消息的主题；DeleteTopic 清除主题的保留消息。
+type Admin struct {
+	session session
+	config  *types.ConnectionConfig
+
+	mu     sync.Mutex
+	topics map[string]bool // 主题发现期间收到消息的主题，nil 表示未在发现
+}
+
+// NewAdmin 创建MQTT管理客户端
+func NewAdmin() mq.Admin {
+	return &Admin{}
+}
+
+// Connect 连接到MQTT broker
+func (a *Admin) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	if config.Type != types.MQTypeMQTT {
+		return utils.NewValidationError("Invalid MQ type for MQTT admin", string(config.Type))
+	}
+
+	opts, err := parseOptions(config)
+	if err != nil {
+		return err
+	}
+	// 管理连接不需要遗嘱消息，避免测试连接断开时触发
+	opts.will = nil
+
+	s, err := connect(ctx, config, opts, newClientID(opts.clientID, "admin"), true, a.collect)
+	if err != nil {
+		return err
+	}
+	a.session = s
+	a.config = config
+	return nil
+}
+
+// collect 记录主题发现期间收到的消息主题
+func (a *Admin) collect(msg *inbound) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.topics != nil {
+		a.topics[msg.topic] = true
+	}
+}
+
+// TestConnection 测试连接，订阅并取消订阅一个探测主题以确认 broker 响应
+func (a *Admin) TestConnection(ctx context.Context) *types.TestResult {
+	start := time.Now()
+
+	if a.session == nil || !a.session.isConnected() {
+		return &types.TestResult{
+			Success: false,
+			Message: "Not connected to MQTT broker",
+			Latency: time.Since(start).Milliseconds(),
+		}
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := a.probe(ctx); err != nil {
+		err = utils.WrapTimeout(ctx, err, "Probing MQTT broker", timeout)
+		return &types.TestResult{
+			Success: false,
+			Message: fmt.Sprintf("MQTT broker did not respond: %v", err),
+			Latency: time.Since(start).Milliseconds(),
+		}
+	}
+
+	return &types.TestResult{
+		Success: true,
+		Message: "Connected successfully to MQTT broker",
+		Latency: time.Since(start).Milliseconds(),
+	}
+}
+
+// Ping 订阅并取消订阅探测主题，连接已断开或 broker 无响应时返回错误
+func (a *Admin) Ping(ctx context.Context) error {
+	if a.session == nil || !a.session.isConnected() {
+		return utils.NewConnectionError("Not connected to MQTT broker", nil)
+	}
+	if err := a.probe(ctx); err != nil {
+		return utils.NewConnectionError("MQTT broker is unreachable", err)
+	}
+	return nil
+}
+
+// probe 订阅并取消订阅一个唯一的探测主题
+func (a *Admin) probe(ctx context.Context) error {
+	topic := probeTopic + utils.GenerateID()
+	if err := a.session.subscribe(ctx, []string{topic}, 0); err != nil {
+		return err
+	}
+	return a.session.unsubscribe(ctx, []string{topic})
+}
+
+// ListTopics 订阅 # 一段时间，列出收到保留消息或新消息的主题
+func (a *Admin) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
+	if a.session == nil {
+		return nil, utils.NewConnectionError("Not connected to MQTT broker", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	a.mu.Lock()
+	a.topics = make(map[string]bool)
+	a.mu.Unlock()
+	defer func() {
+		a.mu.Lock()
+		a.topics = nil
+		a.mu.Unlock()
+	}()
+
+	if err := a.session.subscribe(ctx, []string{"#"}, 0); err != nil {
+		return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to discover topics", err), "Discovering topics", timeout)
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(discoveryWindow):
+	}
+	unsubCtx, unsubCancel := context.WithTimeout(context.Background(), timeout)
+	defer unsubCancel()
+	a.session.unsubscribe(unsubCtx, []string{"#"})
+
+	a.mu.Lock()
+	names := make([]string, 0, len(a.topics))
+	for topic := range a.topics {
+		if !strings.HasPrefix(topic, probeTopic) {
+			names = append(names, topic)
+		}
+	}
+	a.mu.Unlock()
+	sort.Strings(names)
+
+	topics := make([]types.TopicInfo, 0, len(names))
+	for _, name := range names {
+		topics = append(topics, types.TopicInfo{
+			Name:       name,
+			Partitions: 1, // MQTT主题没有分区概念
+			Replicas:   1,
+		})
+	}
+	return topics, nil
+}
+
+// CreateTopic MQTT主题在发布时自动创建，无需也无法预先创建
+func (a *Admin) CreateTopic(ctx context.Context, topic string, partitions int32, replicas int16) error {
+	return utils.NewValidationError("MQTT topics are created implicitly on publish", topic)
+}
+
+// DeleteTopic 发布一条空的保留消息以清除主题的保留消息
+func (a *Admin) DeleteTopic(ctx context.Context, topic string) error {
+	if a.session == nil {
+		return utils.NewConnectionError("Not connected to MQTT broker", nil)
+	}
+
+	if !utils.IsValidTopic(topic) || strings.ContainsAny(topic, "+#") {
+		return utils.NewValidationError("Invalid topic", topic)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := a.session.publish(ctx, &outbound{topic: topic, qos: 1, retain: true})
+	if err != nil {
+		return utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to clear retained message", err), "Clearing retained message", timeout)
+	}
+	return nil
+}
+
+// Close 关闭连接
+func (a *Admin) Close() error {
+	if a.session != nil {
+		a.session.close()
+		a.session = nil
+	}
+	a.config = nil
+	return nil
+}
@@ -0,0 +1,402 @@
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 发送时用于指定单条消息 QoS 和保留标志的消息头，发送前移除；消费时同样写入这两个消息头
+const (
+	QoSHeader    = "Mqtt-Qos"
+	RetainHeader = "Mqtt-Retain"
+)
+
+// KeyHeader MQTT 消息没有键，MQTT 5 中以该用户属性保存消息键
+const KeyHeader = "Mqtk-Key"
+
+// 未配置时使用的默认值
+const (
+	defaultQoS           = 1
+	defaultKeepAlive     = 30 * time.Second
+	defaultSessionExpiry = 3600 // 持久会话在断开后保留的秒数（MQTT 5）
+)
+
+// Client MQTT完整客户端实现
+type Client struct {
+	producer mq.Producer
+	consumer mq.Consumer
+	admin    mq.Admin
+	config   *types.ConnectionConfig
+}
+
+// NewClient 创建MQTT完整客户端
+func NewClient() mq.Client {
+	return &Client{
+		producer: NewProducer(),
+		consumer: NewConsumer(),
+		admin:    NewAdmin(),
+	}
+}
+
+// Connect 连接到MQTT broker，消费者在订阅时才建立连接
+func (c *Client) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	c.config = config
+
+	if err := c.producer.Connect(ctx, config); err != nil {
+		return err
+	}
+
+	if err := c.consumer.Connect(ctx, config); err != nil {
+		c.producer.Close()
+		return err
+	}
+
+	if err := c.admin.Connect(ctx, config); err != nil {
+		c.producer.Close()
+		c.consumer.Close()
+		return err
+	}
+
+	return nil
+}
+
+// Produce 发送消息
+func (c *Client) Produce(ctx context.Context, req *types.ProduceRequest) error {
+	return c.producer.Produce(ctx, req)
+}
+
+// ProduceBatch 批量发送消息
+func (c *Client) ProduceBatch(ctx context.Context, reqs []*types.ProduceRequest) error {
+	return c.producer.ProduceBatch(ctx, reqs)
+}
+
+// Subscribe 订阅主题
+func (c *Client) Subscribe(ctx context.Context, req *types.ConsumeRequest) error {
+	return c.consumer.Subscribe(ctx, req)
+}
+
+// Consume 消费消息
+func (c *Client) Consume(ctx context.Context, handler mq.MessageHandler) error {
+	return c.consumer.Consume(ctx, handler)
+}
+
+// TestConnection 测试连接
+func (c *Client) TestConnection(ctx context.Context) *types.TestResult {
+	return c.admin.TestConnection(ctx)
+}
+
+// ListTopics 列出带保留消息的主题
+func (c *Client) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
+	return c.admin.ListTopics(ctx)
+}
+
+// CreateTopic MQTT主题无需创建
+func (c *Client) CreateTopic(ctx context.Context, topic string, partitions int32, replicas int16) error {
+	return c.admin.CreateTopic(ctx, topic, partitions, replicas)
+}
+
+// DeleteTopic 清除主题的保留消息
+func (c *Client) DeleteTopic(ctx context.Context, topic string) error {
+	return c.admin.DeleteTopic(ctx, topic)
+}
+
+// Close 关闭客户端
+func (c *Client) Close() error {
+	var lastErr error
+
+	if err := c.producer.Close(); err != nil {
+		lastErr = err
+	}
+
+	if err := c.consumer.Close(); err != nil {
+		lastErr = err
+	}
+
+	if err := c.admin.Close(); err != nil {
+		lastErr = err
+	}
+
+	return lastErr
+}
+
+// IsConnected 检查连接状态，消费者在订阅前没有连接，因此只检查生产者
+func (c *Client) IsConnected() bool {
+	return c.producer.IsConnected()
+}
+
+// Ping 通过管理连接探测 broker 是否可达
+func (c *Client) Ping(ctx context.Context) error {
+	return c.admin.(mq.Pinger).Ping(ctx)
+}
+
+// will 遗嘱消息
+type will struct {
+	topic   string
+	payload string
+	qos     byte
+	retain  bool
+}
+
+// options 从连接配置的 Extra 中解析的 MQTT 选项
+//
+//   - protocol_version：3.1.1（默认）或 5
+//   - client_id：客户端标识前缀，消费者指定消费组时使用消费组作为客户端标识
+//   - qos、retain：发送和订阅的默认 QoS（默认 1）与保留标志
+//   - clean_session：为 false 时使用持久会话，session_expiry 为 MQTT 5 会话保留秒数
+//   - will_topic、will_payload、will_qos、will_retain：遗嘱消息
+//   - tls、tls_insecure：使用 TLS，以及是否跳过证书校验
+type options struct {
+	version       int
+	clientID      string
+	qos           byte
+	retain        bool
+	cleanSession  bool
+	sessionExpiry uint32
+	will          *will
+	tls           *tls.Config
+}
+
+// parseOptions 解析并校验连接配置中的 MQTT 选项
+func parseOptions(config *types.ConnectionConfig) (*options, error) {
+	extra := config.Extra
+	opts := &options{
+		version:       3,
+		clientID:      extra["client_id"],
+		qos:           defaultQoS,
+		retain:        extra["retain"] == "true",
+		cleanSession:  extra["clean_session"] != "false",
+		sessionExpiry: defaultSessionExpiry,
+	}
+
+	switch extra["protocol_version"] {
+	case "", "3", "3.1.1", "4":
+	case "5", "5.0":
+		opts.version = 5
+	default:
+		return nil, utils.NewValidationError("Unsupported MQTT protocol version", extra["protocol_version"])
+	}
+
+	if value := extra["qos"]; value != "" {
+		qos, err := parseQoS(value)
+		if err != nil {
+			return nil, err
+		}
+		opts.qos = qos
+	}
+
+	if value := extra["session_expiry"]; value != "" {
+		seconds, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, utils.NewValidationError("Invalid session_expiry", value)
+		}
+		opts.sessionExpiry = uint32(seconds)
+	}
+
+	if topic := extra["will_topic"]; topic != "" {
+		if strings.ContainsAny(topic, "+#") {
+			return nil, utils.NewValidationError("Will topic cannot contain wildcards", topic)
+		}
+		opts.will = &will{
+			topic:   topic,
+			payload: extra["will_payload"],
+			retain:  extra["will_retain"] == "true",
+		}
+		if value := extra["will_qos"]; value != "" {
+			qos, err := parseQoS(value)
+			if err != nil {
+				return nil, err
+			}
+			opts.will.qos = qos
+		}
+	}
+
+	if extra["tls"] == "true" {
+		opts.tls = &tls.Config{InsecureSkipVerify: extra["tls_insecure"] == "true"}
+	}
+	return opts, nil
+}
+
+// parseQoS 解析 QoS 0/1/2
+func parseQoS(value string) (byte, error) {
+	qos, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || qos < 0 || qos > 2 {
+		return 0, utils.NewValidationError("Invalid QoS, must be 0, 1 or 2", value)
+	}
+	return byte(qos), nil
+}
+
+// newClientID 生成客户端标识，prefix 为空时使用 mq-toolkit
+func newClientID(prefix, role string) string {
+	if prefix == "" {
+		prefix = "mq-toolkit"
+	}
+	return fmt.Sprintf("%s-%s-%s", prefix, role, utils.GenerateID()[:8])
+}
+
+// dialConn 建立到 address 的连接，t 不为 nil 时经隧道连接，配置了 TLS 时完成 TLS 握手
+func dialConn(ctx context.Context, address string, t *tunnel.Tunnel, tlsConfig *tls.Config) (net.Conn, error) {
+	var conn net.Conn
+	var err error
+	if t != nil {
+		conn, err = t.DialContext(ctx, "tcp", address)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		return conn, nil
+	}
+
+	cfg := tlsConfig.Clone()
+	if cfg.ServerName == "" {
+		host, _, _ := net.SplitHostPort(address)
+		cfg.ServerName = host
+	}
+	tlsConn := tls.Client(conn, cfg)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+// outbound 待发送的消息
+type outbound struct {
+	topic      string
+	payload    []byte
+	qos        byte
+	retain     bool
+	properties map[string]string // MQTT 5 用户属性，3.1.1 忽略
+}
+
+// newOutbound 由发送请求构造消息，QoSHeader 和 RetainHeader 覆盖连接的默认值
+func newOutbound(req *types.ProduceRequest, opts *options) (*outbound, error) {
+	if !utils.IsValidTopic(req.Topic) || strings.ContainsAny(req.Topic, "+#") {
+		return nil, utils.NewValidationError("Invalid topic", req.Topic)
+	}
+	msg := &outbound{
+		topic:   req.Topic,
+		payload: []byte(req.Value),
+		qos:     opts.qos,
+		retain:  opts.retain,
+	}
+	for name, value := range req.Headers {
+		switch {
+		case strings.EqualFold(name, QoSHeader):
+			qos, err := parseQoS(value)
+			if err != nil {
+				return nil, err
+			}
+			msg.qos = qos
+		case strings.EqualFold(name, RetainHeader):
+			msg.retain = value == "true"
+		default:
+			if msg.properties == nil {
+				msg.properties = make(map[string]string)
+			}
+			msg.properties[name] = value
+		}
+	}
+	if req.Key != "" {
+		if msg.properties == nil {
+			msg.properties = make(map[string]string)
+		}
+		msg.properties[KeyHeader] = req.Key
+	}
+	return msg, nil
+}
+
+// inbound 收到的消息
+type inbound struct {
+	topic      string
+	payload    []byte
+	qos        byte
+	retained   bool
+	properties map[string]string
+}
+
+// toMessage 转换为消息结构，KeyHeader 属性作为消息键
+func (m *inbound) toMessage() *types.Message {
+	msg := &types.Message{
+		ID:        utils.GenerateID(),
+		Topic:     m.topic,
+		Value:     string(m.payload),
+		Headers:   map[string]string{QoSHeader: strconv.Itoa(int(m.qos))},
+		Timestamp: time.Now(),
+	}
+	if m.retained {
+		msg.Headers[RetainHeader] = "true"
+	}
+	for name, value := range m.properties {
+		if name == KeyHeader {
+			msg.Key = value
+			continue
+		}
+		msg.Headers[name] = value
+	}
+	return msg
+}
+
+// session 一个 MQTT 连接，屏蔽 3.1.1 和 5 客户端库的差异
+type session interface {
+	publish(ctx context.Context, msg *outbound) error
+	// subscribe 订阅主题过滤器，broker 拒绝任一订阅时返回错误
+	subscribe(ctx context.Context, filters []string, qos byte) error
+	unsubscribe(ctx context.Context, filters []string) error
+	isConnected() bool
+	// lost 连接断开时关闭
+	lost() <-chan struct{}
+	// err 连接断开的原因
+	err() error
+	close()
+}
+
+// connect 建立 MQTT 连接，集群节点依次尝试，收到的消息交给 onMessage
+//
+// 整个过程（包括隧道、TLS 和 MQTT 握手）受连接的拨号超时限制，失败返回连接错误，超时返回超时错误。
+func connect(ctx context.Context, config *types.ConnectionConfig, opts *options, clientID string, cleanSession bool, onMessage func(*inbound)) (session, error) {
+	addresses := config.Addresses()
+	if len(addresses) == 0 {
+		return nil, utils.NewConnectionError("Failed to connect to MQTT broker", fmt.Errorf("no MQTT brokers configured"))
+	}
+
+	t, err := tunnel.OpenIfEnabled(config.Tunnel)
+	if err != nil {
+		return nil, utils.NewConnectionError("Failed to open tunnel", err)
+	}
+
+	timeout := config.Timeouts.DialTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lastErr error
+	for _, address := range addresses {
+		var s session
+		if opts.version == 5 {
+			s, err = connectV5(ctx, config, opts, address, t, clientID, cleanSession, onMessage)
+		} else {
+			s, err = connectV3(ctx, config, opts, address, t, clientID, cleanSession, onMessage)
+		}
+		if err == nil {
+			return s, nil
+		}
+		lastErr = fmt.Errorf("%s: %w", address, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	t.Close()
+	return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to connect to MQTT broker", lastErr), "Connecting to MQTT broker", timeout)
+}
@@ -0,0 +1,150 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"strings"
+)
+
+// consumerBuffer 订阅后、开始消费前到达的消息缓冲
+const consumerBuffer = 1024
+
+// Consumer MQTT消费者实现
+//
+// 订阅时才建立连接：指定消费组时以消费组作为客户端标识，配合 clean_session=false 使用持久会话，
+// 断开期间 QoS 1/2 的消息由 broker 保留，重新订阅后继续投递。
+type Consumer struct {
+	session  session
+	opts     *options
+	config   *types.ConnectionConfig
+	messages chan *inbound
+	stop     chan struct{}
+}
+
+// NewConsumer 创建MQTT消费者
+func NewConsumer() mq.Consumer {
+	return &Consumer{}
+}
+
+// Connect 校验连接配置，连接在 Subscribe 时建立
+func (c *Consumer) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	if config.Type != types.MQTypeMQTT {
+		return utils.NewValidationError("Invalid MQ type for MQTT consumer", string(config.Type))
+	}
+
+	opts, err := parseOptions(config)
+	if err != nil {
+		return err
+	}
+	c.opts = opts
+	c.config = config
+	return nil
+}
+
+// Subscribe 连接 broker 并订阅主题过滤器，支持 + 和 # 通配符以及 $share 共享订阅
+func (c *Consumer) Subscribe(ctx context.Context, req *types.ConsumeRequest) error {
+	if c.opts == nil {
+		return utils.NewConnectionError("Consumer not connected", nil)
+	}
+
+	if len(req.Topics) == 0 {
+		return utils.NewValidationError("No topics specified for subscription", "")
+	}
+	for _, topic := range req.Topics {
+		if !validFilter(topic) {
+			return utils.NewValidationError("Invalid topic filter", topic)
+		}
+	}
+
+	c.Close()
+
+	clientID := req.GroupID
+	if clientID == "" {
+		clientID = c.opts.clientID
+	}
+	if clientID == "" {
+		clientID = newClientID("", "sub")
+	}
+
+	messages := make(chan *inbound, consumerBuffer)
+	stop := make(chan struct{})
+	s, err := connect(ctx, c.config, c.opts, clientID, c.opts.cleanSession, func(msg *inbound) {
+		select {
+		case messages <- msg:
+		case <-stop:
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	timeout := c.config.Timeouts.RequestTimeout()
+	subCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := s.subscribe(subCtx, req.Topics, c.opts.qos); err != nil {
+		close(stop)
+		s.close()
+		return utils.WrapTimeout(subCtx, utils.NewSubscriptionError(fmt.Sprintf("Failed to subscribe to %s", strings.Join(req.Topics, ", ")), err),
+			"Subscribing to MQTT topics", timeout)
+	}
+
+	c.session = s
+	c.messages = messages
+	c.stop = stop
+	return nil
+}
+
+// Consume 消费消息，连接断开时返回连接错误
+func (c *Consumer) Consume(ctx context.Context, handler mq.MessageHandler) error {
+	if c.session == nil {
+		return utils.NewConnectionError("Consumer not subscribed. Call Subscribe first.", nil)
+	}
+
+	s := c.session
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.lost():
+			return utils.NewConnectionError("MQTT connection lost", s.err())
+		case msg := <-c.messages:
+			// 消息在收到时已由客户端确认，处理失败的消息直接跳过
+			handler(msg.toMessage())
+		}
+	}
+}
+
+// Close 关闭消费者，持久会话的订阅保留在 broker 上
+func (c *Consumer) Close() error {
+	if c.session != nil {
+		close(c.stop)
+		c.session.close()
+		c.session = nil
+	}
+	return nil
+}
+
+// IsConnected 检查连接状态
+func (c *Consumer) IsConnected() bool {
+	return c.session != nil && c.session.isConnected()
+}
+
+// validFilter 校验主题过滤器：# 只能作为最后一级，+ 和 # 必须独占一级
+func validFilter(filter string) bool {
+	if !utils.IsValidTopic(filter) {
+		return false
+	}
+	levels := strings.Split(filter, "/")
+	for i, level := range levels {
+		if strings.Contains(level, "#") && (level != "#" || i != len(levels)-1) {
+			return false
+		}
+		if strings.Contains(level, "+") && level != "+" {
+			return false
+		}
+	}
+	return true
+}
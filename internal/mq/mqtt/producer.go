@@ -0,0 +1,101 @@
+package mqtt
+
+import (
+	"context"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+)
+
+// Producer MQTT生产者实现
+type Producer struct {
+	session session
+	opts    *options
+	config  *types.ConnectionConfig
+}
+
+// NewProducer 创建MQTT生产者
+func NewProducer() mq.Producer {
+	return &Producer{}
+}
+
+// Connect 连接到MQTT broker，生产者总是使用干净会话
+func (p *Producer) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	if config.Type != types.MQTypeMQTT {
+		return utils.NewValidationError("Invalid MQ type for MQTT producer", string(config.Type))
+	}
+
+	opts, err := parseOptions(config)
+	if err != nil {
+		return err
+	}
+
+	s, err := connect(ctx, config, opts, newClientID(opts.clientID, "pub"), true, func(*inbound) {})
+	if err != nil {
+		return err
+	}
+	p.session = s
+	p.opts = opts
+	p.config = config
+	return nil
+}
+
+// Produce 发送单条消息，QoS 1/2 等待 broker 确认
+func (p *Producer) Produce(ctx context.Context, req *types.ProduceRequest) error {
+	if p.session == nil {
+		return utils.NewConnectionError("Producer not connected", nil)
+	}
+
+	timeout := p.config.Timeouts.ProduceTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return utils.WrapTimeout(ctx, p.publish(ctx, req), "Producing to MQTT broker", timeout)
+}
+
+// ProduceBatch 批量发送消息，MQTT没有批量发送，整批共用发送超时
+func (p *Producer) ProduceBatch(ctx context.Context, reqs []*types.ProduceRequest) error {
+	if p.session == nil {
+		return utils.NewConnectionError("Producer not connected", nil)
+	}
+
+	if len(reqs) == 0 {
+		return utils.NewValidationError("Empty message batch", "")
+	}
+
+	timeout := p.config.Timeouts.ProduceTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for _, req := range reqs {
+		if err := p.publish(ctx, req); err != nil {
+			return utils.WrapTimeout(ctx, err, "Producing to MQTT broker", timeout)
+		}
+	}
+	return nil
+}
+
+// publish 发布消息
+func (p *Producer) publish(ctx context.Context, req *types.ProduceRequest) error {
+	msg, err := newOutbound(req, p.opts)
+	if err != nil {
+		return err
+	}
+	if err := p.session.publish(ctx, msg); err != nil {
+		return utils.NewConnectionError("Failed to publish message", err)
+	}
+	return nil
+}
+
+// Close 关闭生产者
+func (p *Producer) Close() error {
+	if p.session != nil {
+		p.session.close()
+		p.session = nil
+	}
+	p.config = nil
+	return nil
+}
+
+// IsConnected 检查连接状态
+func (p *Producer) IsConnected() bool {
+	return p.session != nil && p.session.isConnected()
+}
@@ -0,0 +1,42 @@
+package mqtt
+
+import (
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+)
+
+// init 注册 MQTT 后端
+func init() {
+	mq.Register(mq.Descriptor{
+		BackendInfo: types.BackendInfo{
+			Type:          types.MQTypeMQTT,
+			DisplayName:   "MQTT",
+			Order:         50,
+			DefaultHost:   "localhost",
+			DefaultPort:   1883,
+			EndpointLabel: "其他 broker（按顺序尝试）",
+			SettingsTitle: "MQTT 设置",
+			Fields: []types.ConfigField{
+				{Key: "protocol_version", Label: "协议版本", Kind: "text", Options: []types.FieldOption{{Value: "3.1.1", Label: "MQTT 3.1.1"}, {Value: "5", Label: "MQTT 5（支持用户属性）"}}, Default: "3.1.1"},
+				{Key: "qos", Label: "默认 QoS", Kind: "text", Options: []types.FieldOption{{Value: "0", Label: "默认 QoS 0"}, {Value: "1", Label: "默认 QoS 1"}, {Value: "2", Label: "默认 QoS 2"}}, Default: "1"},
+				{Key: "client_id", Label: "客户端 ID（留空自动生成，消费组 ID 优先）", Kind: "text", Mono: true},
+				{Key: "clean_session", Label: "干净会话", Kind: "bool", Default: "true"},
+				{Key: "retain", Label: "默认发送保留消息", Kind: "bool"},
+				{Key: "session_expiry", Label: "会话过期时间（秒，MQTT 5，默认 3600）", Kind: "number", ShowIf: "!clean_session"},
+				{Key: "will_topic", Label: "遗嘱主题（连接异常断开时由 broker 发布）", Kind: "text", Mono: true},
+				{Key: "will_payload", Label: "遗嘱内容", Kind: "text", ShowIf: "will_topic"},
+				{Key: "will_qos", Label: "遗嘱 QoS", Kind: "text", Options: []types.FieldOption{{Value: "0", Label: "遗嘱 QoS 0"}, {Value: "1", Label: "遗嘱 QoS 1"}, {Value: "2", Label: "遗嘱 QoS 2"}}, Default: "0", ShowIf: "will_topic"},
+				{Key: "will_retain", Label: "保留遗嘱消息", Kind: "bool", ShowIf: "will_topic"},
+				{Key: "tls", Label: "使用 TLS", Kind: "bool"},
+				{Key: "tls_insecure", Label: "跳过证书校验", Kind: "bool", ShowIf: "tls"},
+			},
+			Capabilities: []types.Capability{
+				types.CapabilityTunnel, types.CapabilityEndpoints, types.CapabilityUserPassword, types.CapabilityListTopics, types.CapabilityMultiTopic,
+			},
+		},
+		NewClient:   NewClient,
+		NewProducer: NewProducer,
+		NewConsumer: NewConsumer,
+		NewAdmin:    NewAdmin,
+	})
+}
@@ -0,0 +1,125 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	paho3 "github.com/eclipse/paho.mqtt.golang"
+)
+
+// sessionV3 基于 paho.mqtt.golang 的 MQTT 3.1.1 连接，不支持用户属性
+type sessionV3 struct {
+	client  paho3.Client
+	tunnel  *tunnel.Tunnel
+	lostCh  chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	lostErr error
+}
+
+// connectV3 以 MQTT 3.1.1 连接 address，断开后不自动重连，由健康监控重建连接
+func connectV3(ctx context.Context, config *types.ConnectionConfig, opts *options, address string, t *tunnel.Tunnel,
+	clientID string, cleanSession bool, onMessage func(*inbound)) (session, error) {
+	s := &sessionV3{tunnel: t, lostCh: make(chan struct{})}
+
+	o := paho3.NewClientOptions()
+	o.AddBroker("tcp://" + address)
+	o.SetClientID(clientID)
+	o.SetUsername(config.Username)
+	o.SetPassword(config.Password)
+	o.SetProtocolVersion(4)
+	o.SetCleanSession(cleanSession)
+	o.SetKeepAlive(defaultKeepAlive)
+	o.SetAutoReconnect(false)
+	o.SetConnectRetry(false)
+	if deadline, ok := ctx.Deadline(); ok {
+		o.SetConnectTimeout(time.Until(deadline))
+	}
+	o.SetCustomOpenConnectionFn(func(uri *url.URL, _ paho3.ClientOptions) (net.Conn, error) {
+		return dialConn(ctx, uri.Host, t, opts.tls)
+	})
+	if opts.will != nil {
+		o.SetWill(opts.will.topic, opts.will.payload, opts.will.qos, opts.will.retain)
+	}
+	// 持久会话中离线期间的消息在订阅前就会到达，统一由默认处理函数接收
+	o.SetDefaultPublishHandler(func(_ paho3.Client, m paho3.Message) {
+		onMessage(&inbound{topic: m.Topic(), payload: m.Payload(), qos: m.Qos(), retained: m.Retained()})
+	})
+	o.SetConnectionLostHandler(func(_ paho3.Client, err error) {
+		s.setLost(err)
+	})
+
+	s.client = paho3.NewClient(o)
+	if err := wait(ctx, s.client.Connect()); err != nil {
+		s.client.Disconnect(0)
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *sessionV3) publish(ctx context.Context, msg *outbound) error {
+	return wait(ctx, s.client.Publish(msg.topic, msg.qos, msg.retain, msg.payload))
+}
+
+func (s *sessionV3) subscribe(ctx context.Context, filters []string, qos byte) error {
+	subscriptions := make(map[string]byte, len(filters))
+	for _, filter := range filters {
+		subscriptions[filter] = qos
+	}
+	token := s.client.SubscribeMultiple(subscriptions, nil)
+	if err := wait(ctx, token); err != nil {
+		return err
+	}
+	for filter, code := range token.(*paho3.SubscribeToken).Result() {
+		if code >= 0x80 {
+			return fmt.Errorf("subscription to %s rejected by broker, code 0x%02x", filter, code)
+		}
+	}
+	return nil
+}
+
+func (s *sessionV3) unsubscribe(ctx context.Context, filters []string) error {
+	return wait(ctx, s.client.Unsubscribe(filters...))
+}
+
+func (s *sessionV3) isConnected() bool {
+	return s.client.IsConnectionOpen()
+}
+
+func (s *sessionV3) lost() <-chan struct{} {
+	return s.lostCh
+}
+
+func (s *sessionV3) err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lostErr
+}
+
+func (s *sessionV3) close() {
+	s.client.Disconnect(250)
+	s.tunnel.Close()
+}
+
+func (s *sessionV3) setLost(err error) {
+	s.mu.Lock()
+	s.lostErr = err
+	s.mu.Unlock()
+	s.once.Do(func() { close(s.lostCh) })
+}
+
+// wait 等待 paho 操作完成，ctx 结束时返回 ctx 的错误
+func wait(ctx context.Context, token paho3.Token) error {
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,173 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"sync"
+
+	"github.com/eclipse/paho.golang/packets"
+	"github.com/eclipse/paho.golang/paho"
+)
+
+// sessionV5 基于 paho.golang 的 MQTT 5 连接，消息头与用户属性互相映射
+type sessionV5 struct {
+	client  *paho.Client
+	tunnel  *tunnel.Tunnel
+	lostCh  chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	lostErr error
+	closing bool
+}
+
+// connectV5 以 MQTT 5 连接 address，持久会话按 opts.sessionExpiry 保留
+func connectV5(ctx context.Context, config *types.ConnectionConfig, opts *options, address string, t *tunnel.Tunnel,
+	clientID string, cleanSession bool, onMessage func(*inbound)) (session, error) {
+	conn, err := dialConn(ctx, address, t, opts.tls)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &sessionV5{tunnel: t, lostCh: make(chan struct{})}
+	s.client = paho.NewClient(paho.ClientConfig{
+		ClientID: clientID,
+		Conn:     packets.NewThreadSafeConn(conn),
+		OnPublishReceived: []func(paho.PublishReceived) (bool, error){
+			func(received paho.PublishReceived) (bool, error) {
+				p := received.Packet
+				msg := &inbound{topic: p.Topic, payload: p.Payload, qos: p.QoS, retained: p.Retain}
+				if p.Properties != nil && len(p.Properties.User) > 0 {
+					msg.properties = make(map[string]string, len(p.Properties.User))
+					for _, property := range p.Properties.User {
+						msg.properties[property.Key] = property.Value
+					}
+				}
+				onMessage(msg)
+				return true, nil
+			},
+		},
+		OnClientError: func(err error) {
+			s.setLost(err)
+		},
+		OnServerDisconnect: func(d *paho.Disconnect) {
+			s.setLost(fmt.Errorf("disconnected by broker, reason code 0x%02x", d.ReasonCode))
+		},
+	})
+
+	cp := &paho.Connect{
+		ClientID:     clientID,
+		CleanStart:   cleanSession,
+		KeepAlive:    uint16(defaultKeepAlive.Seconds()),
+		Username:     config.Username,
+		UsernameFlag: config.Username != "",
+		Password:     []byte(config.Password),
+		PasswordFlag: config.Password != "",
+	}
+	if !cleanSession {
+		expiry := opts.sessionExpiry
+		cp.Properties = &paho.ConnectProperties{SessionExpiryInterval: &expiry}
+	}
+	if opts.will != nil {
+		cp.WillMessage = &paho.WillMessage{
+			Topic:   opts.will.topic,
+			Payload: []byte(opts.will.payload),
+			QoS:     opts.will.qos,
+			Retain:  opts.will.retain,
+		}
+	}
+
+	if _, err := s.client.Connect(ctx, cp); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	go func() {
+		<-s.client.Done()
+		s.setLost(fmt.Errorf("connection closed"))
+	}()
+	return s, nil
+}
+
+func (s *sessionV5) publish(ctx context.Context, msg *outbound) error {
+	p := &paho.Publish{
+		Topic:   msg.topic,
+		QoS:     msg.qos,
+		Retain:  msg.retain,
+		Payload: msg.payload,
+	}
+	if len(msg.properties) > 0 {
+		p.Properties = &paho.PublishProperties{}
+		for key, value := range msg.properties {
+			p.Properties.User.Add(key, value)
+		}
+	}
+	resp, err := s.client.Publish(ctx, p)
+	if err != nil {
+		return err
+	}
+	if resp != nil && resp.ReasonCode >= 0x80 {
+		return fmt.Errorf("publish rejected by broker, reason code 0x%02x", resp.ReasonCode)
+	}
+	return nil
+}
+
+func (s *sessionV5) subscribe(ctx context.Context, filters []string, qos byte) error {
+	sub := &paho.Subscribe{}
+	for _, filter := range filters {
+		sub.Subscriptions = append(sub.Subscriptions, paho.SubscribeOptions{Topic: filter, QoS: qos})
+	}
+	suback, err := s.client.Subscribe(ctx, sub)
+	if err != nil {
+		return err
+	}
+	for i, code := range suback.Reasons {
+		if code >= 0x80 && i < len(filters) {
+			return fmt.Errorf("subscription to %s rejected by broker, reason code 0x%02x", filters[i], code)
+		}
+	}
+	return nil
+}
+
+func (s *sessionV5) unsubscribe(ctx context.Context, filters []string) error {
+	_, err := s.client.Unsubscribe(ctx, &paho.Unsubscribe{Topics: filters})
+	return err
+}
+
+func (s *sessionV5) isConnected() bool {
+	select {
+	case <-s.lostCh:
+		return false
+	default:
+		return true
+	}
+}
+
+func (s *sessionV5) lost() <-chan struct{} {
+	return s.lostCh
+}
+
+func (s *sessionV5) err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lostErr
+}
+
+func (s *sessionV5) close() {
+	s.mu.Lock()
+	s.closing = true
+	s.mu.Unlock()
+	s.client.Disconnect(&paho.Disconnect{ReasonCode: 0})
+	s.tunnel.Close()
+}
+
+// setLost 记录连接断开，主动关闭时不记录
+func (s *sessionV5) setLost(err error) {
+	s.mu.Lock()
+	if !s.closing && s.lostErr == nil {
+		s.lostErr = err
+	}
+	s.mu.Unlock()
+	s.once.Do(func() { close(s.lostCh) })
+}
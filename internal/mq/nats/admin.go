@@ -0,0 +1,312 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Admin NATS管理客户端实现，主题对应 JetStream 流，消费组对应持久消费者
+type Admin struct {
+	conn      *nats.Conn
+	js        jetstream.JetStream
+	connected bool
+	config    *types.ConnectionConfig
+	tunnel    *tunnel.Tunnel
+}
+
+// NewAdmin 创建NATS管理客户端
+func NewAdmin() mq.Admin {
+	return &Admin{}
+}
+
+// Connect 连接到NATS
+func (a *Admin) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	if config.Type != types.MQTypeNATS {
+		return utils.NewValidationError("Invalid MQ type for NATS admin", string(config.Type))
+	}
+
+	a.config = config
+
+	t, err := tunnel.OpenIfEnabled(config.Tunnel)
+	if err != nil {
+		return utils.NewConnectionError("Failed to open tunnel", err)
+	}
+
+	conn, err := dial(ctx, config, t)
+	if err != nil {
+		t.Close()
+		return err
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		t.Close()
+		return utils.NewConnectionError("Failed to create JetStream context", err)
+	}
+
+	a.tunnel = t
+	a.conn = conn
+	a.js = js
+	a.connected = true
+	return nil
+}
+
+// TestConnection 测试连接，并报告服务器是否启用了 JetStream
+func (a *Admin) TestConnection(ctx context.Context) *types.TestResult {
+	start := time.Now()
+
+	if !a.connected || a.conn == nil || !a.conn.IsConnected() {
+		return &types.TestResult{
+			Success: false,
+			Message: "Not connected to NATS",
+			Latency: time.Since(start).Milliseconds(),
+		}
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := a.conn.FlushWithContext(ctx); err != nil {
+		err = utils.WrapTimeout(ctx, err, "Pinging NATS server", timeout)
+		return &types.TestResult{
+			Success: false,
+			Message: fmt.Sprintf("Failed to ping NATS server: %v", err),
+			Latency: time.Since(start).Milliseconds(),
+		}
+	}
+	latency := time.Since(start).Milliseconds()
+
+	server := a.conn.ConnectedServerName()
+	info, err := a.js.AccountInfo(ctx)
+	switch {
+	case err == nil:
+		return &types.TestResult{
+			Success: true,
+			Message: fmt.Sprintf("Connected successfully to NATS %s (JetStream enabled, %d streams)", server, info.Streams),
+			Latency: latency,
+		}
+	case isNotFound(err):
+		return &types.TestResult{
+			Success: true,
+			Message: fmt.Sprintf("Connected successfully to NATS %s (JetStream not enabled)", server),
+			Latency: latency,
+		}
+	default:
+		err = utils.WrapTimeout(ctx, err, "Getting JetStream account info", timeout)
+		return &types.TestResult{
+			Success: false,
+			Message: fmt.Sprintf("Failed to get JetStream account info: %v", err),
+			Latency: time.Since(start).Milliseconds(),
+		}
+	}
+}
+
+// Ping 刷新连接并等待服务器响应，连接已断开或服务器无响应时返回错误
+func (a *Admin) Ping(ctx context.Context) error {
+	if !a.connected || a.conn == nil || !a.conn.IsConnected() {
+		return utils.NewConnectionError("Not connected to NATS", nil)
+	}
+	if err := a.conn.FlushWithContext(ctx); err != nil {
+		return utils.NewConnectionError("NATS server is unreachable", err)
+	}
+	return nil
+}
+
+// ListTopics 列出所有 JetStream 流，服务器未启用 JetStream 时返回空列表
+func (a *Admin) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
+	if !a.connected || a.js == nil {
+		return nil, utils.NewConnectionError("Not connected to NATS", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	topics := []types.TopicInfo{}
+	lister := a.js.ListStreams(ctx)
+	for info := range lister.Info() {
+		replicas := info.Config.Replicas
+		if replicas < 1 {
+			replicas = 1
+		}
+		topics = append(topics, types.TopicInfo{
+			Name:       info.Config.Name,
+			Partitions: 1, // 流没有分区概念
+			Replicas:   int16(replicas),
+		})
+	}
+	if err := lister.Err(); err != nil && !isNotFound(err) {
+		return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to list streams", err), "Listing streams", timeout)
+	}
+
+	sort.Slice(topics, func(i, j int) bool { return topics[i].Name < topics[j].Name })
+	return topics, nil
+}
+
+// CreateTopic 创建保存 topic 及其子主题（topic.>）的流，流名称中的 '.' 替换为 '_'
+func (a *Admin) CreateTopic(ctx context.Context, topic string, partitions int32, replicas int16) error {
+	if !a.connected || a.js == nil {
+		return utils.NewConnectionError("Not connected to NATS", nil)
+	}
+
+	if !utils.IsValidTopic(topic) || strings.ContainsAny(topic, "*>") {
+		return utils.NewValidationError("Invalid subject", topic)
+	}
+	name := streamName(topic)
+	if !validStreamName(name) {
+		return utils.NewValidationError("Invalid stream name", name)
+	}
+	if replicas < 1 {
+		replicas = 1
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// 流没有分区概念，partitions 参数不适用
+	_, err := a.js.CreateStream(ctx, jetstream.StreamConfig{
+		Name:     name,
+		Subjects: []string{topic, topic + ".>"},
+		Replicas: int(replicas),
+		Storage:  jetstream.FileStorage,
+	})
+	if err != nil {
+		return utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to create stream", err), "Creating stream", timeout)
+	}
+	return nil
+}
+
+// DeleteTopic 删除流，topic 可以是流名称，也可以是流保存的主题
+func (a *Admin) DeleteTopic(ctx context.Context, topic string) error {
+	if !a.connected || a.js == nil {
+		return utils.NewConnectionError("Not connected to NATS", nil)
+	}
+
+	if !utils.IsValidTopic(topic) {
+		return utils.NewValidationError("Invalid stream name", topic)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	name := topic
+	if !validStreamName(name) {
+		stream, err := a.js.StreamNameBySubject(ctx, topic)
+		if err != nil {
+			if isNotFound(err) {
+				return utils.NewNotFoundError("Stream", topic)
+			}
+			return utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to look up stream", err), "Deleting stream", timeout)
+		}
+		name = stream
+	}
+
+	if err := a.js.DeleteStream(ctx, name); err != nil {
+		if errors.Is(err, jetstream.ErrStreamNotFound) {
+			return utils.NewNotFoundError("Stream", topic)
+		}
+		return utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to delete stream", err), "Deleting stream", timeout)
+	}
+	return nil
+}
+
+// ListConsumerGroups 列出所有流上的持久消费者，同名消费者合并为一个消费组
+//
+// 核心 NATS 的队列组只存在于订阅中，服务器不提供查询接口，因此不在列表中。
+func (a *Admin) ListConsumerGroups(ctx context.Context) ([]types.ConsumerGroup, error) {
+	if !a.connected || a.js == nil {
+		return nil, utils.NewConnectionError("Not connected to NATS", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	names := a.js.StreamNames(ctx)
+	var streams []string
+	for stream := range names.Name() {
+		streams = append(streams, stream)
+	}
+	if err := names.Err(); err != nil {
+		if isNotFound(err) {
+			return []types.ConsumerGroup{}, nil
+		}
+		return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to list streams", err), "Listing consumer groups", timeout)
+	}
+
+	groups := make(map[string]*types.ConsumerGroup)
+	var order []string
+	for _, name := range streams {
+		stream, err := a.js.Stream(ctx, name)
+		if err != nil {
+			if errors.Is(err, jetstream.ErrStreamNotFound) {
+				continue // 列出后被删除
+			}
+			return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to get stream "+name, err), "Listing consumer groups", timeout)
+		}
+
+		lister := stream.ListConsumers(ctx)
+		for info := range lister.Info() {
+			if info.Config.Durable == "" {
+				continue
+			}
+			group, ok := groups[info.Name]
+			if !ok {
+				group = &types.ConsumerGroup{ID: info.Name, Members: []string{}}
+				groups[info.Name] = group
+				order = append(order, info.Name)
+			}
+			group.Topics = append(group.Topics, consumerTopics(info)...)
+		}
+		if err := lister.Err(); err != nil {
+			return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to list consumers of stream "+name, err), "Listing consumer groups", timeout)
+		}
+	}
+
+	sort.Strings(order)
+	result := make([]types.ConsumerGroup, 0, len(order))
+	for _, name := range order {
+		result = append(result, *groups[name])
+	}
+	return result, nil
+}
+
+// consumerTopics 返回 JetStream 消费者过滤的主题，未过滤时返回流名称
+func consumerTopics(info *jetstream.ConsumerInfo) []string {
+	if info.Config.FilterSubject != "" {
+		return []string{info.Config.FilterSubject}
+	}
+	if len(info.Config.FilterSubjects) > 0 {
+		return info.Config.FilterSubjects
+	}
+	return []string{info.Stream}
+}
+
+// Close 关闭连接
+func (a *Admin) Close() error {
+	if a.conn != nil {
+		a.conn.Close()
+		a.conn = nil
+	}
+	a.js = nil
+	a.connected = false
+	a.config = nil
+	a.tunnel.Close()
+	a.tunnel = nil
+	return nil
+}
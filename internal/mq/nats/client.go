@@ -0,0 +1,312 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nats-io/nkeys"
+)
+
+// KeyHeader NATS 消息没有键，发送时把消息键写入该消息头，消费时再取出
+const KeyHeader = "Mqtk-Key"
+
+// Client NATS完整客户端实现
+type Client struct {
+	producer mq.Producer
+	consumer mq.Consumer
+	admin    mq.Admin
+	config   *types.ConnectionConfig
+}
+
+// NewClient 创建NATS完整客户端
+func NewClient() mq.Client {
+	return &Client{
+		producer: NewProducer(),
+		consumer: NewConsumer(),
+		admin:    NewAdmin(),
+	}
+}
+
+// Connect 连接到NATS
+func (c *Client) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	c.config = config
+
+	if err := c.producer.Connect(ctx, config); err != nil {
+		return err
+	}
+
+	if err := c.consumer.Connect(ctx, config); err != nil {
+		c.producer.Close()
+		return err
+	}
+
+	if err := c.admin.Connect(ctx, config); err != nil {
+		c.producer.Close()
+		c.consumer.Close()
+		return err
+	}
+
+	return nil
+}
+
+// Produce 发送消息
+func (c *Client) Produce(ctx context.Context, req *types.ProduceRequest) error {
+	return c.producer.Produce(ctx, req)
+}
+
+// ProduceBatch 批量发送消息
+func (c *Client) ProduceBatch(ctx context.Context, reqs []*types.ProduceRequest) error {
+	return c.producer.ProduceBatch(ctx, reqs)
+}
+
+// Subscribe 订阅主题
+func (c *Client) Subscribe(ctx context.Context, req *types.ConsumeRequest) error {
+	return c.consumer.Subscribe(ctx, req)
+}
+
+// Consume 消费消息
+func (c *Client) Consume(ctx context.Context, handler mq.MessageHandler) error {
+	return c.consumer.Consume(ctx, handler)
+}
+
+// TestConnection 测试连接
+func (c *Client) TestConnection(ctx context.Context) *types.TestResult {
+	return c.admin.TestConnection(ctx)
+}
+
+// ListTopics 列出所有流
+func (c *Client) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
+	return c.admin.ListTopics(ctx)
+}
+
+// CreateTopic 创建流
+func (c *Client) CreateTopic(ctx context.Context, topic string, partitions int32, replicas int16) error {
+	return c.admin.CreateTopic(ctx, topic, partitions, replicas)
+}
+
+// DeleteTopic 删除流
+func (c *Client) DeleteTopic(ctx context.Context, topic string) error {
+	return c.admin.DeleteTopic(ctx, topic)
+}
+
+// ListConsumerGroups 列出JetStream持久消费者
+func (c *Client) ListConsumerGroups(ctx context.Context) ([]types.ConsumerGroup, error) {
+	return c.admin.(mq.GroupAdmin).ListConsumerGroups(ctx)
+}
+
+// Close 关闭客户端
+func (c *Client) Close() error {
+	var lastErr error
+
+	if err := c.producer.Close(); err != nil {
+		lastErr = err
+	}
+
+	if err := c.consumer.Close(); err != nil {
+		lastErr = err
+	}
+
+	if err := c.admin.Close(); err != nil {
+		lastErr = err
+	}
+
+	return lastErr
+}
+
+// IsConnected 检查连接状态
+func (c *Client) IsConnected() bool {
+	return c.producer.IsConnected() && c.consumer.IsConnected()
+}
+
+// Ping 通过管理连接探测 NATS 是否可达
+func (c *Client) Ping(ctx context.Context) error {
+	return c.admin.(mq.Pinger).Ping(ctx)
+}
+
+// dial 连接到配置的服务器，t 不为 nil 时经隧道连接
+//
+// 认证方式按以下顺序选择：Extra["creds_file"] 凭据文件、Extra["nkey_seed"] NKey 种子、
+// Extra["token"] 令牌、用户名和密码；只填写密码时密码作为令牌使用。Extra["tls"] 为 true 时使用 TLS。
+// 连接过程受连接的拨号超时限制，失败返回连接错误，超时返回超时错误。
+func dial(ctx context.Context, config *types.ConnectionConfig, t *tunnel.Tunnel, extra ...nats.Option) (*nats.Conn, error) {
+	scheme := "nats"
+	if config.Extra["tls"] == "true" {
+		scheme = "tls"
+	}
+	addresses := config.Addresses()
+	if len(addresses) == 0 {
+		return nil, utils.NewConnectionError("Failed to connect to NATS", fmt.Errorf("no NATS servers configured"))
+	}
+	servers := make([]string, len(addresses))
+	for i, address := range addresses {
+		servers[i] = scheme + "://" + address
+	}
+
+	timeout := config.Timeouts.DialTimeout()
+	opts := []nats.Option{
+		nats.Name("mq-toolkit"),
+		nats.Timeout(timeout),
+		nats.SetCustomDialer(&dialer{tunnel: t, timeout: timeout}),
+	}
+	authOpt, err := authOption(config)
+	if err != nil {
+		return nil, err
+	}
+	if authOpt != nil {
+		opts = append(opts, authOpt)
+	}
+	opts = append(opts, extra...)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		conn *nats.Conn
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		conn, err := nats.Connect(strings.Join(servers, ","), opts...)
+		done <- result{conn, err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to connect to NATS", res.err), "Connecting to NATS", timeout)
+		}
+		return res.conn, nil
+	case <-ctx.Done():
+		// 放弃等待，连接完成后立即关闭
+		go func() {
+			if res := <-done; res.conn != nil {
+				res.conn.Close()
+			}
+		}()
+		return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to connect to NATS", ctx.Err()), "Connecting to NATS", timeout)
+	}
+}
+
+// authOption 按连接配置返回认证选项，未配置认证时返回 nil
+func authOption(config *types.ConnectionConfig) (nats.Option, error) {
+	if creds := config.Extra["creds_file"]; creds != "" {
+		return nats.UserCredentials(creds), nil
+	}
+	if seed := strings.TrimSpace(config.Extra["nkey_seed"]); seed != "" {
+		kp, err := nkeys.FromSeed([]byte(seed))
+		if err != nil {
+			return nil, utils.NewValidationError("Invalid NKey seed", err.Error())
+		}
+		publicKey, err := kp.PublicKey()
+		if err != nil {
+			return nil, utils.NewValidationError("Invalid NKey seed", err.Error())
+		}
+		return nats.Nkey(publicKey, kp.Sign), nil
+	}
+	if token := config.Extra["token"]; token != "" {
+		return nats.Token(token), nil
+	}
+	if config.Username != "" {
+		return nats.UserInfo(config.Username, config.Password), nil
+	}
+	if config.Password != "" {
+		return nats.Token(config.Password), nil
+	}
+	return nil, nil
+}
+
+// dialer 实现 nats.CustomDialer，t 不为 nil 时经隧道拨号
+type dialer struct {
+	tunnel  *tunnel.Tunnel
+	timeout time.Duration
+}
+
+// Dial 建立 TCP 连接，集群中其他服务器的地址同样经隧道改写
+func (d *dialer) Dial(network, address string) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+	if d.tunnel != nil {
+		return d.tunnel.DialContext(ctx, network, address)
+	}
+	var nd net.Dialer
+	return nd.DialContext(ctx, network, address)
+}
+
+// streamForSubject 返回保存该主题的流名称，主题不属于任何流时返回空字符串
+//
+// topic 也可以直接是流名称，此时消费整个流。
+func streamForSubject(ctx context.Context, js jetstream.JetStream, topic string) (stream string, filter string, err error) {
+	name, err := js.StreamNameBySubject(ctx, topic)
+	if err == nil {
+		return name, topic, nil
+	}
+	if !isNotFound(err) {
+		return "", "", err
+	}
+	if validStreamName(topic) {
+		if _, err := js.Stream(ctx, topic); err == nil {
+			return topic, "", nil
+		} else if !isNotFound(err) {
+			return "", "", err
+		}
+	}
+	return "", "", nil
+}
+
+// isNotFound 判断 JetStream 错误是否表示流不存在或服务器、账户未启用 JetStream
+func isNotFound(err error) bool {
+	return errors.Is(err, jetstream.ErrStreamNotFound) || errors.Is(err, nats.ErrNoResponders) ||
+		errors.Is(err, jetstream.ErrJetStreamNotEnabled) || errors.Is(err, jetstream.ErrJetStreamNotEnabledForAccount)
+}
+
+// validStreamName 流名称不能包含空白、'.'、'*'、'>' 以及路径分隔符
+func validStreamName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, " \t\r\n.*>/\\")
+}
+
+// streamName 由主题生成流名称，'.' 替换为 '_'
+func streamName(topic string) string {
+	return strings.ReplaceAll(topic, ".", "_")
+}
+
+// headersToMap 将 NATS 消息头转换为 map，多值用逗号连接，KeyHeader 作为消息键返回
+func headersToMap(header nats.Header) (map[string]string, string) {
+	if len(header) == 0 {
+		return nil, ""
+	}
+	headers := make(map[string]string, len(header))
+	var key string
+	for name, values := range header {
+		if name == KeyHeader {
+			if len(values) > 0 {
+				key = values[0]
+			}
+			continue
+		}
+		headers[name] = strings.Join(values, ",")
+	}
+	return headers, key
+}
+
+// newMsg 由发送请求构造 NATS 消息
+func newMsg(req *types.ProduceRequest) *nats.Msg {
+	msg := nats.NewMsg(req.Topic)
+	msg.Data = []byte(req.Value)
+	for key, value := range req.Headers {
+		msg.Header.Set(key, value)
+	}
+	if req.Key != "" {
+		msg.Header.Set(KeyHeader, req.Key)
+	}
+	return msg
+}
@@ -0,0 +1,346 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// ephemeralInactiveThreshold 未指定消费组时创建的临时消费者在断开后保留的时间
+const ephemeralInactiveThreshold = time.Minute
+
+// Consumer NATS消费者实现
+//
+// 属于 JetStream 流的主题通过拉取消费者消费，消费组对应持久消费者；
+// 其他主题使用核心 NATS 订阅，支持通配符，消费组对应队列组。
+type Consumer struct {
+	conn      *nats.Conn
+	js        jetstream.JetStream
+	connected bool
+	config    *types.ConnectionConfig
+	tunnel    *tunnel.Tunnel
+	closed    chan struct{} // 连接彻底关闭（重连失败）时关闭
+
+	group     string
+	subjects  []string             // 核心 NATS 订阅的主题
+	consumers []jetstream.Consumer // JetStream 消费者，每个流一个
+}
+
+// NewConsumer 创建NATS消费者
+func NewConsumer() mq.Consumer {
+	return &Consumer{}
+}
+
+// Connect 连接到NATS
+func (c *Consumer) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	if config.Type != types.MQTypeNATS {
+		return utils.NewValidationError("Invalid MQ type for NATS consumer", string(config.Type))
+	}
+
+	c.config = config
+
+	t, err := tunnel.OpenIfEnabled(config.Tunnel)
+	if err != nil {
+		return utils.NewConnectionError("Failed to open tunnel", err)
+	}
+
+	closed := make(chan struct{})
+	var once sync.Once
+	conn, err := dial(ctx, config, t, nats.ClosedHandler(func(*nats.Conn) {
+		once.Do(func() { close(closed) })
+	}))
+	if err != nil {
+		t.Close()
+		return err
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		t.Close()
+		return utils.NewConnectionError("Failed to create JetStream context", err)
+	}
+
+	c.tunnel = t
+	c.conn = conn
+	c.js = js
+	c.closed = closed
+	c.connected = true
+	return nil
+}
+
+// Subscribe 订阅主题，为 JetStream 流中的主题创建或更新消费者
+func (c *Consumer) Subscribe(ctx context.Context, req *types.ConsumeRequest) error {
+	if !c.connected || c.conn == nil {
+		return utils.NewConnectionError("Consumer not connected", nil)
+	}
+
+	if len(req.Topics) == 0 {
+		return utils.NewValidationError("No subjects specified for subscription", "")
+	}
+	if req.GroupID != "" && !validStreamName(req.GroupID) {
+		return utils.NewValidationError("Invalid consumer group name", "NATS consumer and queue group names cannot contain whitespace, '.', '*', '>' or path separators")
+	}
+
+	timeout := c.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// 按流归并主题，filters 为 nil 表示消费整个流
+	filters := make(map[string][]string)
+	var subjects []string
+	for _, topic := range req.Topics {
+		if !utils.IsValidTopic(topic) {
+			return utils.NewValidationError("Invalid subject", topic)
+		}
+		stream, filter, err := streamForSubject(ctx, c.js, topic)
+		if err != nil {
+			return utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to look up JetStream stream", err), "Looking up JetStream stream", timeout)
+		}
+		switch {
+		case stream == "":
+			subjects = append(subjects, topic)
+		case filter == "":
+			filters[stream] = nil
+		default:
+			if existing, ok := filters[stream]; !ok || existing != nil {
+				filters[stream] = append(existing, filter)
+			}
+		}
+	}
+
+	streams := make([]string, 0, len(filters))
+	for stream := range filters {
+		streams = append(streams, stream)
+	}
+	sort.Strings(streams)
+
+	var consumers []jetstream.Consumer
+	for _, stream := range streams {
+		consumer, err := c.createConsumer(ctx, stream, filters[stream], req)
+		if err != nil {
+			return utils.WrapTimeout(ctx, utils.NewSubscriptionError(fmt.Sprintf("Failed to create consumer on stream %s", stream), err),
+				fmt.Sprintf("Creating consumer on stream %s", stream), timeout)
+		}
+		consumers = append(consumers, consumer)
+	}
+
+	c.group = req.GroupID
+	c.subjects = subjects
+	c.consumers = consumers
+	return nil
+}
+
+// createConsumer 创建或更新 JetStream 消费者
+//
+// 持久消费者已存在且请求未指定起始位置时保留其原有的投递策略，从上次确认的位置继续消费。
+func (c *Consumer) createConsumer(ctx context.Context, stream string, filters []string, req *types.ConsumeRequest) (jetstream.Consumer, error) {
+	cfg := jetstream.ConsumerConfig{
+		Durable:       req.GroupID,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		DeliverPolicy: jetstream.DeliverNewPolicy,
+	}
+	if len(filters) == 1 {
+		cfg.FilterSubject = filters[0]
+	} else {
+		cfg.FilterSubjects = filters
+	}
+	if req.GroupID == "" {
+		cfg.InactiveThreshold = ephemeralInactiveThreshold
+	}
+
+	switch {
+	case req.StartSequence > 0:
+		cfg.DeliverPolicy = jetstream.DeliverByStartSequencePolicy
+		cfg.OptStartSeq = req.StartSequence
+	case req.StartTime != nil:
+		cfg.DeliverPolicy = jetstream.DeliverByStartTimePolicy
+		cfg.OptStartTime = req.StartTime
+	case req.FromBeginning:
+		cfg.DeliverPolicy = jetstream.DeliverAllPolicy
+	}
+
+	if req.GroupID != "" && req.StartSequence == 0 && req.StartTime == nil {
+		existing, err := c.js.Consumer(ctx, stream, req.GroupID)
+		if err == nil {
+			info := existing.CachedInfo()
+			cfg.DeliverPolicy = info.Config.DeliverPolicy
+			cfg.OptStartSeq = info.Config.OptStartSeq
+			cfg.OptStartTime = info.Config.OptStartTime
+		} else if !errors.Is(err, jetstream.ErrConsumerNotFound) {
+			return nil, err
+		}
+	}
+
+	return c.js.CreateOrUpdateConsumer(ctx, stream, cfg)
+}
+
+// Consume 消费消息，连接关闭时返回连接错误
+func (c *Consumer) Consume(ctx context.Context, handler mq.MessageHandler) error {
+	if !c.connected || c.conn == nil {
+		return utils.NewConnectionError("Consumer not connected", nil)
+	}
+
+	if len(c.subjects) == 0 && len(c.consumers) == 0 {
+		return utils.NewConnectionError("Consumer not subscribed. Call Subscribe first.", nil)
+	}
+
+	conn := c.conn
+	var wg sync.WaitGroup
+	var closed atomic.Bool
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// 确认失败时结束消费，只保留第一个错误
+	var failOnce sync.Once
+	var failure error
+	fail := func(err error) {
+		failOnce.Do(func() { failure = err })
+		cancel()
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-c.closed:
+			closed.Store(true)
+			cancel()
+		}
+	}()
+
+	// 核心 NATS 订阅共用一个通道，按到达顺序处理
+	if len(c.subjects) > 0 {
+		msgs := make(chan *nats.Msg, 256)
+		for _, subject := range c.subjects {
+			var sub *nats.Subscription
+			var err error
+			if c.group != "" {
+				sub, err = conn.ChanQueueSubscribe(subject, c.group, msgs)
+			} else {
+				sub, err = conn.ChanSubscribe(subject, msgs)
+			}
+			if err != nil {
+				cancel()
+				wg.Wait()
+				return utils.NewSubscriptionError(fmt.Sprintf("Failed to subscribe to %s", subject), err)
+			}
+			defer sub.Unsubscribe()
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case m := <-msgs:
+					msg := &types.Message{
+						ID:        utils.GenerateID(),
+						Topic:     m.Subject,
+						Value:     string(m.Data),
+						Timestamp: time.Now(),
+					}
+					msg.Headers, msg.Key = headersToMap(m.Header)
+					// 核心 NATS 没有确认，处理失败的消息直接跳过
+					handler(msg)
+				}
+			}
+		}()
+	}
+
+	for _, consumer := range c.consumers {
+		iter, err := consumer.Messages()
+		if err != nil {
+			cancel()
+			wg.Wait()
+			return utils.NewSubscriptionError(fmt.Sprintf("Failed to consume from stream %s", consumer.CachedInfo().Stream), err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			go func() {
+				<-ctx.Done()
+				iter.Stop()
+			}()
+			for {
+				m, err := iter.Next()
+				if err != nil {
+					if errors.Is(err, jetstream.ErrMsgIteratorClosed) || ctx.Err() != nil {
+						return
+					}
+					// 心跳丢失等临时错误，迭代器会自动重新拉取，稍后重试避免空转
+					select {
+					case <-ctx.Done():
+						return
+					case <-time.After(time.Second):
+					}
+					continue
+				}
+
+				msg := &types.Message{
+					ID:    utils.GenerateID(),
+					Topic: m.Subject(),
+					Value: string(m.Data()),
+				}
+				msg.Headers, msg.Key = headersToMap(m.Headers())
+				if meta, err := m.Metadata(); err == nil {
+					msg.Offset = int64(meta.Sequence.Stream)
+					msg.Timestamp = meta.Timestamp
+				} else {
+					msg.Timestamp = time.Now()
+				}
+
+				// 处理失败时不确认，超过 AckWait 后重新投递；ctx 已结束时同样不确认
+				if err := handler(msg); err != nil || ctx.Err() != nil {
+					continue
+				}
+				if err := m.Ack(); err != nil && ctx.Err() == nil {
+					fail(utils.NewConnectionError(fmt.Sprintf("Failed to ack message from %s", m.Subject()), err))
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	if closed.Load() {
+		return utils.NewConnectionError("NATS connection closed", conn.LastError())
+	}
+	if failure != nil {
+		return failure
+	}
+	return ctx.Err()
+}
+
+// Close 关闭消费者
+func (c *Consumer) Close() error {
+	c.connected = false
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+	c.js = nil
+	c.subjects = nil
+	c.consumers = nil
+	c.tunnel.Close()
+	c.tunnel = nil
+	return nil
+}
+
+// IsConnected 检查连接状态
+func (c *Consumer) IsConnected() bool {
+	return c.connected && c.conn != nil && c.conn.IsConnected()
+}
@@ -0,0 +1,180 @@
+package nats
+
+import (
+	"context"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"strings"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Producer NATS生产者实现
+//
+// 主题属于某个 JetStream 流时等待流的确认，否则使用核心 NATS 发布并刷新缓冲。
+type Producer struct {
+	conn      *nats.Conn
+	js        jetstream.JetStream
+	connected bool
+	config    *types.ConnectionConfig
+	tunnel    *tunnel.Tunnel
+
+	mu      sync.Mutex
+	streams map[string]bool // 主题是否属于 JetStream 流
+}
+
+// NewProducer 创建NATS生产者
+func NewProducer() mq.Producer {
+	return &Producer{}
+}
+
+// Connect 连接到NATS
+func (p *Producer) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	if config.Type != types.MQTypeNATS {
+		return utils.NewValidationError("Invalid MQ type for NATS producer", string(config.Type))
+	}
+
+	p.config = config
+
+	t, err := tunnel.OpenIfEnabled(config.Tunnel)
+	if err != nil {
+		return utils.NewConnectionError("Failed to open tunnel", err)
+	}
+
+	conn, err := dial(ctx, config, t)
+	if err != nil {
+		t.Close()
+		return err
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		t.Close()
+		return utils.NewConnectionError("Failed to create JetStream context", err)
+	}
+
+	p.tunnel = t
+	p.conn = conn
+	p.js = js
+	p.streams = make(map[string]bool)
+	p.connected = true
+	return nil
+}
+
+// Produce 发送单条消息
+func (p *Producer) Produce(ctx context.Context, req *types.ProduceRequest) error {
+	if !p.connected || p.conn == nil {
+		return utils.NewConnectionError("Producer not connected", nil)
+	}
+
+	timeout := p.config.Timeouts.ProduceTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := p.publish(ctx, req); err != nil {
+		return utils.WrapTimeout(ctx, err, "Producing to NATS", timeout)
+	}
+	return utils.WrapTimeout(ctx, p.flush(ctx), "Producing to NATS", timeout)
+}
+
+// ProduceBatch 批量发送消息，核心 NATS 消息在整批发布后统一刷新
+func (p *Producer) ProduceBatch(ctx context.Context, reqs []*types.ProduceRequest) error {
+	if !p.connected || p.conn == nil {
+		return utils.NewConnectionError("Producer not connected", nil)
+	}
+
+	if len(reqs) == 0 {
+		return utils.NewValidationError("Empty message batch", "")
+	}
+
+	timeout := p.config.Timeouts.ProduceTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for _, req := range reqs {
+		if err := p.publish(ctx, req); err != nil {
+			return utils.WrapTimeout(ctx, err, "Producing to NATS", timeout)
+		}
+	}
+	return utils.WrapTimeout(ctx, p.flush(ctx), "Producing to NATS", timeout)
+}
+
+// publish 发布消息，JetStream 流中的主题等待确认
+func (p *Producer) publish(ctx context.Context, req *types.ProduceRequest) error {
+	if !utils.IsValidTopic(req.Topic) {
+		return utils.NewValidationError("Invalid subject", req.Topic)
+	}
+	if strings.ContainsAny(req.Topic, "*>") {
+		return utils.NewValidationError("Cannot publish to a wildcard subject", req.Topic)
+	}
+
+	inStream, err := p.inStream(ctx, req.Topic)
+	if err != nil {
+		return utils.NewConnectionError("Failed to look up JetStream stream", err)
+	}
+
+	msg := newMsg(req)
+	if inStream {
+		if _, err := p.js.PublishMsg(ctx, msg); err != nil {
+			return utils.NewConnectionError("Failed to publish message to JetStream", err)
+		}
+		return nil
+	}
+	if err := p.conn.PublishMsg(msg); err != nil {
+		return utils.NewConnectionError("Failed to publish message", err)
+	}
+	return nil
+}
+
+// flush 等待服务器确认已收到此前发布的核心 NATS 消息
+func (p *Producer) flush(ctx context.Context) error {
+	if err := p.conn.FlushWithContext(ctx); err != nil {
+		return utils.NewConnectionError("Failed to flush messages", err)
+	}
+	return nil
+}
+
+// inStream 查询主题是否属于 JetStream 流，结果在连接期间缓存
+//
+// 缓存后新建的流仍会保存核心 NATS 发布的消息，只是发送时不再等待流的确认。
+func (p *Producer) inStream(ctx context.Context, subject string) (bool, error) {
+	p.mu.Lock()
+	cached, ok := p.streams[subject]
+	p.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	_, err := p.js.StreamNameBySubject(ctx, subject)
+	if err != nil && !isNotFound(err) {
+		return false, err
+	}
+	inStream := err == nil
+
+	p.mu.Lock()
+	p.streams[subject] = inStream
+	p.mu.Unlock()
+	return inStream, nil
+}
+
+// Close 关闭生产者
+func (p *Producer) Close() error {
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+	}
+	p.js = nil
+	p.connected = false
+	p.config = nil
+	p.tunnel.Close()
+	p.tunnel = nil
+	return nil
+}
+
+// IsConnected 检查连接状态
+func (p *Producer) IsConnected() bool {
+	return p.connected && p.conn != nil && p.conn.IsConnected()
+}
@@ -0,0 +1,35 @@
+package nats
+
+import (
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+)
+
+// init 注册 NATS 后端
+func init() {
+	mq.Register(mq.Descriptor{
+		BackendInfo: types.BackendInfo{
+			Type:          types.MQTypeNATS,
+			DisplayName:   "NATS / JetStream",
+			Order:         40,
+			DefaultHost:   "localhost",
+			DefaultPort:   4222,
+			EndpointLabel: "其他服务器",
+			SettingsTitle: "NATS 认证",
+			SettingsHelp:  "按凭据文件、NKey 种子、令牌、用户名密码的顺序选择认证方式，只填写密码时作为令牌使用",
+			Fields: []types.ConfigField{
+				{Key: "token", Label: "令牌（token）", Kind: "password"},
+				{Key: "nkey_seed", Label: "NKey 种子（SU...）", Kind: "password", Mono: true},
+				{Key: "creds_file", Label: "凭据文件路径，如 /etc/nats/user.creds", Kind: "text", Mono: true},
+				{Key: "tls", Label: "使用 TLS", Kind: "bool"},
+			},
+			Capabilities: []types.Capability{
+				types.CapabilityTunnel, types.CapabilityEndpoints, types.CapabilityUserPassword, types.CapabilityListTopics, types.CapabilityTopicReplicas, types.CapabilityMultiTopic, types.CapabilityStartTime, types.CapabilityStartSequence,
+			},
+		},
+		NewClient:   NewClient,
+		NewProducer: NewProducer,
+		NewConsumer: NewConsumer,
+		NewAdmin:    NewAdmin,
+	})
+}
@@ -0,0 +1,630 @@
+package pulsar
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Admin Pulsar管理客户端实现，通过 broker 的管理 REST API（/admin/v2）管理租户、命名空间、主题和订阅
+//
+// 管理 API 地址取 Extra["admin_url"]，默认为第一个 broker 主机的 8080 端口。主题列表只包含持久化主题，
+// 消费组对应主题上的订阅。
+type Admin struct {
+	config  *types.ConnectionConfig
+	baseURL string
+	http    *http.Client
+}
+
+// NewAdmin 创建Pulsar管理客户端
+func NewAdmin() mq.Admin {
+	return &Admin{}
+}
+
+// apiError 管理 API 返回的错误
+type apiError struct {
+	Status int
+	Reason string
+}
+
+func (e *apiError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("admin API returned status %d", e.Status)
+	}
+	return fmt.Sprintf("admin API returned status %d: %s", e.Status, e.Reason)
+}
+
+// Connect 连接到Pulsar管理 API，并确认 API 可用
+func (a *Admin) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	if config.Type != types.MQTypePulsar {
+		return utils.NewValidationError("Invalid MQ type for Pulsar admin", string(config.Type))
+	}
+	if err := checkTunnel(config); err != nil {
+		return err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.Extra["tls_insecure"] == "true" {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	a.config = config
+	a.baseURL = adminURL(config)
+	a.http = &http.Client{Transport: transport}
+
+	timeout := config.Timeouts.DialTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if _, err := a.clusters(ctx); err != nil {
+		a.Close()
+		return utils.WrapTimeout(ctx, a.apiFailure("Failed to connect to Pulsar admin API", err, "", ""), "Connecting to Pulsar", timeout)
+	}
+	return nil
+}
+
+// do 调用管理 API，body 不为 nil 时以 JSON 发送，out 不为 nil 时解析 JSON 响应
+func (a *Admin) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if token := a.config.Extra["token"]; token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else if a.config.Username != "" {
+		req.SetBasicAuth(a.config.Username, a.config.Password)
+	}
+
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		apiErr := &apiError{Status: resp.StatusCode}
+		var reason struct {
+			Reason string `json:"reason"`
+		}
+		if json.Unmarshal(data, &reason) == nil && reason.Reason != "" {
+			apiErr.Reason = reason.Reason
+		} else {
+			apiErr.Reason = strings.TrimSpace(string(data))
+		}
+		return apiErr
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse admin API response: %v", err)
+	}
+	return nil
+}
+
+// apiFailure 把管理 API 的错误转换为应用错误，resource 不为空时 404 转换为未找到错误
+func (a *Admin) apiFailure(message string, err error, resource, id string) error {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		return utils.NewConnectionError(message, err)
+	}
+	switch {
+	case apiErr.Status == http.StatusNotFound && resource != "":
+		return utils.NewNotFoundError(resource, id)
+	case apiErr.Status == http.StatusUnauthorized || apiErr.Status == http.StatusForbidden:
+		return utils.NewAuthError(message + ": " + apiErr.Error())
+	case apiErr.Status == http.StatusConflict || apiErr.Status == http.StatusPreconditionFailed ||
+		apiErr.Status == http.StatusBadRequest || apiErr.Status == http.StatusNotFound:
+		return utils.NewValidationError(message, apiErr.Reason)
+	default:
+		return utils.NewConnectionError(message, err)
+	}
+}
+
+// clusters 返回集群名称
+func (a *Admin) clusters(ctx context.Context) ([]string, error) {
+	var clusters []string
+	err := a.do(ctx, http.MethodGet, "/admin/v2/clusters", nil, &clusters)
+	return clusters, err
+}
+
+// TestConnection 测试连接，并报告集群名称
+func (a *Admin) TestConnection(ctx context.Context) *types.TestResult {
+	start := time.Now()
+
+	if a.http == nil {
+		return &types.TestResult{
+			Success: false,
+			Message: "Not connected to Pulsar",
+			Latency: time.Since(start).Milliseconds(),
+		}
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	clusters, err := a.clusters(ctx)
+	if err != nil {
+		err = utils.WrapTimeout(ctx, err, "Calling Pulsar admin API", timeout)
+		return &types.TestResult{
+			Success: false,
+			Message: fmt.Sprintf("Failed to call Pulsar admin API: %v", err),
+			Latency: time.Since(start).Milliseconds(),
+		}
+	}
+	return &types.TestResult{
+		Success: true,
+		Message: fmt.Sprintf("Connected successfully to Pulsar cluster %s", strings.Join(clusters, ", ")),
+		Latency: time.Since(start).Milliseconds(),
+	}
+}
+
+// Ping 调用管理 API，broker 不可达时返回错误
+func (a *Admin) Ping(ctx context.Context) error {
+	if a.http == nil {
+		return utils.NewConnectionError("Not connected to Pulsar", nil)
+	}
+	if _, err := a.clusters(ctx); err != nil {
+		return utils.NewConnectionError("Pulsar admin API is unreachable", err)
+	}
+	return nil
+}
+
+// ListTenants 列出租户
+func (a *Admin) ListTenants(ctx context.Context) ([]string, error) {
+	if a.http == nil {
+		return nil, utils.NewConnectionError("Not connected to Pulsar", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var tenants []string
+	if err := a.do(ctx, http.MethodGet, "/admin/v2/tenants", nil, &tenants); err != nil {
+		return nil, utils.WrapTimeout(ctx, a.apiFailure("Failed to list tenants", err, "", ""), "Listing tenants", timeout)
+	}
+	sort.Strings(tenants)
+	return tenants, nil
+}
+
+// ListNamespaces 列出租户下的命名空间，返回 租户/命名空间 形式的名称
+func (a *Admin) ListNamespaces(ctx context.Context, tenant string) ([]string, error) {
+	if a.http == nil {
+		return nil, utils.NewConnectionError("Not connected to Pulsar", nil)
+	}
+	if tenant == "" || strings.Contains(tenant, "/") || !utils.IsValidTopic(tenant) {
+		return nil, utils.NewValidationError("Invalid tenant name", tenant)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	namespaces, err := a.namespaces(ctx, tenant)
+	if err != nil {
+		return nil, utils.WrapTimeout(ctx, a.apiFailure("Failed to list namespaces", err, "Tenant", tenant), "Listing namespaces", timeout)
+	}
+	return namespaces, nil
+}
+
+func (a *Admin) namespaces(ctx context.Context, tenant string) ([]string, error) {
+	var namespaces []string
+	if err := a.do(ctx, http.MethodGet, "/admin/v2/namespaces/"+url.PathEscape(tenant), nil, &namespaces); err != nil {
+		return nil, err
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
+// CreateNamespace 以默认策略创建命名空间，租户必须已存在
+func (a *Admin) CreateNamespace(ctx context.Context, namespace string) error {
+	if a.http == nil {
+		return utils.NewConnectionError("Not connected to Pulsar", nil)
+	}
+	path, err := namespacePath(namespace)
+	if err != nil {
+		return err
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := a.do(ctx, http.MethodPut, path, struct{}{}, nil); err != nil {
+		return utils.WrapTimeout(ctx, a.apiFailure("Failed to create namespace "+namespace, err, "", ""), "Creating namespace", timeout)
+	}
+	return nil
+}
+
+// DeleteNamespace 删除命名空间，命名空间中还有主题时 broker 拒绝删除
+func (a *Admin) DeleteNamespace(ctx context.Context, namespace string) error {
+	if a.http == nil {
+		return utils.NewConnectionError("Not connected to Pulsar", nil)
+	}
+	path, err := namespacePath(namespace)
+	if err != nil {
+		return err
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := a.do(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return utils.WrapTimeout(ctx, a.apiFailure("Failed to delete namespace "+namespace, err, "Namespace", namespace), "Deleting namespace", timeout)
+	}
+	return nil
+}
+
+// namespacePath 返回命名空间的管理 API 路径，命名空间必须写作 租户/命名空间
+func namespacePath(namespace string) (string, error) {
+	tenant, name, ok := strings.Cut(namespace, "/")
+	if !ok || tenant == "" || name == "" || strings.Contains(name, "/") || !utils.IsValidTopic(namespace) {
+		return "", utils.NewValidationError("Invalid namespace, expected tenant/namespace", namespace)
+	}
+	return "/admin/v2/namespaces/" + url.PathEscape(tenant) + "/" + url.PathEscape(name), nil
+}
+
+// topicPath 返回完整主题名的管理 API 路径
+func topicPath(name string) string {
+	domain, rest, _ := strings.Cut(name, "://")
+	parts := strings.SplitN(rest, "/", 3)
+	return "/admin/v2/" + domain + "/" + url.PathEscape(parts[0]) + "/" + url.PathEscape(parts[1]) + "/" + url.PathEscape(parts[2])
+}
+
+// partitionBase 分区主题 xxx-partition-N 返回 xxx 和 true
+func partitionBase(name string) (string, bool) {
+	i := strings.LastIndex(name, "-partition-")
+	if i < 0 {
+		return "", false
+	}
+	if _, err := strconv.Atoi(name[i+len("-partition-"):]); err != nil {
+		return "", false
+	}
+	return name[:i], true
+}
+
+// ListTopics 列出持久化主题，分区主题的各分区合并为一个主题
+//
+// 配置了 Extra["namespace"] 时只列出该命名空间，否则列出所有租户下的所有命名空间。
+func (a *Admin) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
+	if a.http == nil {
+		return nil, utils.NewConnectionError("Not connected to Pulsar", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	topics, err := a.topics(ctx)
+	if err != nil {
+		return nil, utils.WrapTimeout(ctx, a.apiFailure("Failed to list topics", err, "", ""), "Listing topics", timeout)
+	}
+	return topics, nil
+}
+
+// topics 返回所有持久化主题，按名称排序
+func (a *Admin) topics(ctx context.Context) ([]types.TopicInfo, error) {
+	var namespaces []string
+	if ns := a.config.Extra["namespace"]; ns != "" {
+		namespaces = []string{ns}
+	} else {
+		var tenants []string
+		if err := a.do(ctx, http.MethodGet, "/admin/v2/tenants", nil, &tenants); err != nil {
+			return nil, err
+		}
+		for _, tenant := range tenants {
+			list, err := a.namespaces(ctx, tenant)
+			if err != nil {
+				return nil, err
+			}
+			namespaces = append(namespaces, list...)
+		}
+	}
+
+	var topics []types.TopicInfo
+	for _, ns := range namespaces {
+		tenant, name, _ := strings.Cut(ns, "/")
+		base := "/admin/v2/persistent/" + url.PathEscape(tenant) + "/" + url.PathEscape(name)
+
+		var partitioned, all []string
+		if err := a.do(ctx, http.MethodGet, base+"/partitioned", nil, &partitioned); err != nil {
+			return nil, err
+		}
+		if err := a.do(ctx, http.MethodGet, base, nil, &all); err != nil {
+			return nil, err
+		}
+
+		counts := make(map[string]int32, len(partitioned))
+		for _, topic := range partitioned {
+			counts[topic] = 0
+		}
+		for _, topic := range all {
+			if b, ok := partitionBase(topic); ok {
+				if _, isPartition := counts[b]; isPartition {
+					counts[b]++
+					continue
+				}
+			}
+			topics = append(topics, types.TopicInfo{Name: topic, Partitions: 1, Replicas: 1})
+		}
+		for topic, count := range counts {
+			if count == 0 {
+				// 分区尚未创建时以元数据中的分区数为准
+				n, err := a.partitions(ctx, topic)
+				if err != nil {
+					return nil, err
+				}
+				count = n
+			}
+			topics = append(topics, types.TopicInfo{Name: topic, Partitions: count, Replicas: 1})
+		}
+	}
+	sort.Slice(topics, func(i, j int) bool { return topics[i].Name < topics[j].Name })
+	return topics, nil
+}
+
+// partitions 返回主题的分区数，非分区主题或主题不存在时返回 0
+func (a *Admin) partitions(ctx context.Context, name string) (int32, error) {
+	var metadata struct {
+		Partitions int32 `json:"partitions"`
+	}
+	if err := a.do(ctx, http.MethodGet, topicPath(name)+"/partitions", nil, &metadata); err != nil {
+		return 0, err
+	}
+	return metadata.Partitions, nil
+}
+
+// CreateTopic 创建持久化主题，partitions 大于 1 时创建分区主题，replicas 不适用
+func (a *Admin) CreateTopic(ctx context.Context, topic string, partitions int32, replicas int16) error {
+	if a.http == nil {
+		return utils.NewConnectionError("Not connected to Pulsar", nil)
+	}
+	name, err := topicName(a.config, topic)
+	if err != nil {
+		return err
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if partitions > 1 {
+		err = a.do(ctx, http.MethodPut, topicPath(name)+"/partitions", partitions, nil)
+	} else {
+		err = a.do(ctx, http.MethodPut, topicPath(name), nil, nil)
+	}
+	if err != nil {
+		return utils.WrapTimeout(ctx, a.apiFailure("Failed to create topic "+name, err, "", ""), "Creating topic", timeout)
+	}
+	return nil
+}
+
+// DeleteTopic 强制删除主题及其订阅，分区主题删除所有分区
+func (a *Admin) DeleteTopic(ctx context.Context, topic string) error {
+	if a.http == nil {
+		return utils.NewConnectionError("Not connected to Pulsar", nil)
+	}
+	name, err := topicName(a.config, topic)
+	if err != nil {
+		return err
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	n, err := a.partitions(ctx, name)
+	if err == nil {
+		path := topicPath(name)
+		if n > 0 {
+			path += "/partitions"
+		}
+		err = a.do(ctx, http.MethodDelete, path+"?force=true", nil, nil)
+	}
+	if err != nil {
+		return utils.WrapTimeout(ctx, a.apiFailure("Failed to delete topic "+name, err, "Topic", name), "Deleting topic", timeout)
+	}
+	return nil
+}
+
+// topicStats 主题统计中的订阅部分，分区主题的统计为各分区汇总
+type topicStats struct {
+	Subscriptions map[string]struct {
+		Type       string `json:"type"`
+		MsgBacklog int64  `json:"msgBacklog"`
+		Consumers  []struct {
+			ConsumerName string `json:"consumerName"`
+		} `json:"consumers"`
+	} `json:"subscriptions"`
+}
+
+// subscriptions 返回主题上的订阅，按名称排序
+func (a *Admin) subscriptions(ctx context.Context, name string, partitioned bool) ([]types.SubscriptionInfo, error) {
+	path := topicPath(name) + "/stats"
+	if partitioned {
+		path = topicPath(name) + "/partitioned-stats"
+	}
+	var stats topicStats
+	if err := a.do(ctx, http.MethodGet, path, nil, &stats); err != nil {
+		return nil, err
+	}
+
+	subs := make([]types.SubscriptionInfo, 0, len(stats.Subscriptions))
+	for subName, sub := range stats.Subscriptions {
+		info := types.SubscriptionInfo{
+			Name:      subName,
+			Type:      sub.Type,
+			Backlog:   sub.MsgBacklog,
+			Consumers: []string{},
+		}
+		for _, consumer := range sub.Consumers {
+			info.Consumers = append(info.Consumers, consumer.ConsumerName)
+		}
+		subs = append(subs, info)
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].Name < subs[j].Name })
+	return subs, nil
+}
+
+// ListConsumerGroups 列出所有主题上的订阅，同名订阅合并为一个消费组
+func (a *Admin) ListConsumerGroups(ctx context.Context) ([]types.ConsumerGroup, error) {
+	if a.http == nil {
+		return nil, utils.NewConnectionError("Not connected to Pulsar", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	topics, err := a.topics(ctx)
+	if err != nil {
+		return nil, utils.WrapTimeout(ctx, a.apiFailure("Failed to list topics", err, "", ""), "Listing consumer groups", timeout)
+	}
+
+	groups := make(map[string]*types.ConsumerGroup)
+	members := make(map[string]map[string]bool)
+	for _, topic := range topics {
+		subs, err := a.subscriptions(ctx, topic.Name, topic.Partitions > 1)
+		if err != nil {
+			var apiErr *apiError
+			if errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound {
+				continue // 列出后被删除
+			}
+			return nil, utils.WrapTimeout(ctx, a.apiFailure("Failed to list subscriptions of "+topic.Name, err, "", ""), "Listing consumer groups", timeout)
+		}
+		for _, sub := range subs {
+			group, ok := groups[sub.Name]
+			if !ok {
+				group = &types.ConsumerGroup{ID: sub.Name, Members: []string{}}
+				groups[sub.Name] = group
+				members[sub.Name] = make(map[string]bool)
+			}
+			group.Topics = append(group.Topics, topic.Name)
+			for _, consumer := range sub.Consumers {
+				if !members[sub.Name][consumer] {
+					members[sub.Name][consumer] = true
+					group.Members = append(group.Members, consumer)
+				}
+			}
+		}
+	}
+
+	result := make([]types.ConsumerGroup, 0, len(groups))
+	for _, group := range groups {
+		sort.Strings(group.Members)
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+// ListSubscriptions 列出主题上的订阅及其类型、积压消息数和消费者
+func (a *Admin) ListSubscriptions(ctx context.Context, topic string) ([]types.SubscriptionInfo, error) {
+	if a.http == nil {
+		return nil, utils.NewConnectionError("Not connected to Pulsar", nil)
+	}
+	name, err := topicName(a.config, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	n, err := a.partitions(ctx, name)
+	if err != nil {
+		return nil, utils.WrapTimeout(ctx, a.apiFailure("Failed to list subscriptions of "+name, err, "Topic", name), "Listing subscriptions", timeout)
+	}
+	subs, err := a.subscriptions(ctx, name, n > 0)
+	if err != nil {
+		return nil, utils.WrapTimeout(ctx, a.apiFailure("Failed to list subscriptions of "+name, err, "Topic", name), "Listing subscriptions", timeout)
+	}
+	return subs, nil
+}
+
+// DeleteSubscription 删除订阅，订阅上还有消费者时一并断开
+func (a *Admin) DeleteSubscription(ctx context.Context, topic, subscription string) error {
+	return a.subscriptionCall(ctx, http.MethodDelete, topic, subscription, "?force=true", "Deleting subscription")
+}
+
+// ClearBacklog 跳过订阅的所有积压消息
+func (a *Admin) ClearBacklog(ctx context.Context, topic, subscription string) error {
+	return a.subscriptionCall(ctx, http.MethodPost, topic, subscription, "/skip_all", "Clearing backlog")
+}
+
+// ResetSubscription 将订阅的游标重置到时间 t，之后发布的消息重新投递
+func (a *Admin) ResetSubscription(ctx context.Context, topic, subscription string, t time.Time) error {
+	return a.subscriptionCall(ctx, http.MethodPost, topic, subscription, "/resetcursor/"+strconv.FormatInt(t.UnixMilli(), 10), "Resetting subscription")
+}
+
+// SeekGroup 将以消费组命名的订阅的游标重置到时间 t
+func (a *Admin) SeekGroup(ctx context.Context, group, topic string, t time.Time) error {
+	return a.ResetSubscription(ctx, topic, group, t)
+}
+
+// subscriptionCall 调用订阅的管理 API，分区主题由 broker 作用到所有分区
+func (a *Admin) subscriptionCall(ctx context.Context, method, topic, subscription, suffix, operation string) error {
+	if a.http == nil {
+		return utils.NewConnectionError("Not connected to Pulsar", nil)
+	}
+	name, err := topicName(a.config, topic)
+	if err != nil {
+		return err
+	}
+	if !utils.IsValidTopic(subscription) {
+		return utils.NewValidationError("Invalid subscription name", subscription)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	path := topicPath(name) + "/subscription/" + url.PathEscape(subscription) + suffix
+	if err := a.do(ctx, method, path, nil, nil); err != nil {
+		return utils.WrapTimeout(ctx, a.apiFailure(operation+" failed", err, "Subscription", name+" "+subscription), operation, timeout)
+	}
+	return nil
+}
+
+// Close 关闭管理客户端
+func (a *Admin) Close() error {
+	if a.http != nil {
+		a.http.CloseIdleConnections()
+		a.http = nil
+	}
+	a.config = nil
+	return nil
+}
@@ -0,0 +1,347 @@
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/apache/pulsar-client-go/pulsar/log"
+)
+
+// 消费时把消息 ID 和排序键放入消息头；发送时 OrderingKeyHeader 消息头作为排序键，不写入消息属性
+const (
+	MessageIDHeader   = "Pulsar-Message-Id"
+	OrderingKeyHeader = "Pulsar-Ordering-Key"
+)
+
+// defaultNamespace 主题名不含租户和命名空间且未配置 Extra["namespace"] 时使用的命名空间
+const defaultNamespace = "public/default"
+
+// Client Pulsar完整客户端实现
+type Client struct {
+	producer mq.Producer
+	consumer mq.Consumer
+	admin    mq.Admin
+	config   *types.ConnectionConfig
+}
+
+// NewClient 创建Pulsar完整客户端
+func NewClient() mq.Client {
+	return &Client{
+		producer: NewProducer(),
+		consumer: NewConsumer(),
+		admin:    NewAdmin(),
+	}
+}
+
+// Connect 连接到Pulsar
+func (c *Client) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	c.config = config
+
+	if err := c.producer.Connect(ctx, config); err != nil {
+		return err
+	}
+
+	if err := c.consumer.Connect(ctx, config); err != nil {
+		c.producer.Close()
+		return err
+	}
+
+	if err := c.admin.Connect(ctx, config); err != nil {
+		c.producer.Close()
+		c.consumer.Close()
+		return err
+	}
+
+	return nil
+}
+
+// Produce 发送消息
+func (c *Client) Produce(ctx context.Context, req *types.ProduceRequest) error {
+	return c.producer.Produce(ctx, req)
+}
+
+// ProduceBatch 批量发送消息
+func (c *Client) ProduceBatch(ctx context.Context, reqs []*types.ProduceRequest) error {
+	return c.producer.ProduceBatch(ctx, reqs)
+}
+
+// Subscribe 订阅主题
+func (c *Client) Subscribe(ctx context.Context, req *types.ConsumeRequest) error {
+	return c.consumer.Subscribe(ctx, req)
+}
+
+// Consume 消费消息
+func (c *Client) Consume(ctx context.Context, handler mq.MessageHandler) error {
+	return c.consumer.Consume(ctx, handler)
+}
+
+// TestConnection 测试连接
+func (c *Client) TestConnection(ctx context.Context) *types.TestResult {
+	return c.admin.TestConnection(ctx)
+}
+
+// ListTopics 列出主题
+func (c *Client) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
+	return c.admin.ListTopics(ctx)
+}
+
+// CreateTopic 创建主题
+func (c *Client) CreateTopic(ctx context.Context, topic string, partitions int32, replicas int16) error {
+	return c.admin.CreateTopic(ctx, topic, partitions, replicas)
+}
+
+// DeleteTopic 删除主题
+func (c *Client) DeleteTopic(ctx context.Context, topic string) error {
+	return c.admin.DeleteTopic(ctx, topic)
+}
+
+// ListConsumerGroups 列出订阅
+func (c *Client) ListConsumerGroups(ctx context.Context) ([]types.ConsumerGroup, error) {
+	return c.admin.(mq.GroupAdmin).ListConsumerGroups(ctx)
+}
+
+// ListTenants 列出租户
+func (c *Client) ListTenants(ctx context.Context) ([]string, error) {
+	return c.admin.(mq.NamespaceAdmin).ListTenants(ctx)
+}
+
+// ListNamespaces 列出租户下的命名空间
+func (c *Client) ListNamespaces(ctx context.Context, tenant string) ([]string, error) {
+	return c.admin.(mq.NamespaceAdmin).ListNamespaces(ctx, tenant)
+}
+
+// CreateNamespace 创建命名空间
+func (c *Client) CreateNamespace(ctx context.Context, namespace string) error {
+	return c.admin.(mq.NamespaceAdmin).CreateNamespace(ctx, namespace)
+}
+
+// DeleteNamespace 删除命名空间
+func (c *Client) DeleteNamespace(ctx context.Context, namespace string) error {
+	return c.admin.(mq.NamespaceAdmin).DeleteNamespace(ctx, namespace)
+}
+
+// ListSubscriptions 列出主题上的订阅
+func (c *Client) ListSubscriptions(ctx context.Context, topic string) ([]types.SubscriptionInfo, error) {
+	return c.admin.(mq.SubscriptionAdmin).ListSubscriptions(ctx, topic)
+}
+
+// DeleteSubscription 删除订阅
+func (c *Client) DeleteSubscription(ctx context.Context, topic, subscription string) error {
+	return c.admin.(mq.SubscriptionAdmin).DeleteSubscription(ctx, topic, subscription)
+}
+
+// ClearBacklog 清空订阅的积压消息
+func (c *Client) ClearBacklog(ctx context.Context, topic, subscription string) error {
+	return c.admin.(mq.SubscriptionAdmin).ClearBacklog(ctx, topic, subscription)
+}
+
+// ResetSubscription 将订阅的游标重置到指定时间
+func (c *Client) ResetSubscription(ctx context.Context, topic, subscription string, t time.Time) error {
+	return c.admin.(mq.SubscriptionAdmin).ResetSubscription(ctx, topic, subscription, t)
+}
+
+// SeekGroup 将以消费组命名的订阅的游标重置到指定时间
+func (c *Client) SeekGroup(ctx context.Context, group, topic string, t time.Time) error {
+	return c.admin.(mq.OffsetSeeker).SeekGroup(ctx, group, topic, t)
+}
+
+// Close 关闭客户端
+func (c *Client) Close() error {
+	var lastErr error
+
+	if err := c.producer.Close(); err != nil {
+		lastErr = err
+	}
+
+	if err := c.consumer.Close(); err != nil {
+		lastErr = err
+	}
+
+	if err := c.admin.Close(); err != nil {
+		lastErr = err
+	}
+
+	return lastErr
+}
+
+// IsConnected 检查连接状态
+func (c *Client) IsConnected() bool {
+	return c.producer.IsConnected() && c.consumer.IsConnected()
+}
+
+// Ping 通过管理 API 探测 Pulsar 是否可达
+func (c *Client) Ping(ctx context.Context) error {
+	return c.admin.(mq.Pinger).Ping(ctx)
+}
+
+// checkTunnel Pulsar 客户端不支持自定义拨号，配置了隧道时返回错误
+func checkTunnel(config *types.ConnectionConfig) error {
+	if tunnel.Enabled(config.Tunnel) {
+		return utils.NewConfigError("Pulsar does not support SSH tunnels or proxies",
+			"the Pulsar client cannot use a custom dialer, forward the broker and admin ports instead")
+	}
+	return nil
+}
+
+// newClient 创建 Pulsar 客户端，客户端在第一次创建生产者、消费者时才连接 broker
+//
+// Extra["tls"] 为 true 时使用 pulsar+ssl://，Extra["tls_insecure"] 为 true 时跳过证书校验，
+// Extra["tls_trust_certs"] 指定 CA 证书文件。Extra["token"] 不为空时使用 JWT 认证，
+// 否则配置了用户名时使用 Basic 认证。
+func newClient(config *types.ConnectionConfig) (pulsar.Client, error) {
+	if err := checkTunnel(config); err != nil {
+		return nil, err
+	}
+
+	addresses := config.Addresses()
+	if len(addresses) == 0 {
+		return nil, utils.NewConnectionError("Failed to connect to Pulsar", fmt.Errorf("no Pulsar address configured"))
+	}
+
+	auth, err := authentication(config)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := "pulsar://"
+	if config.Extra["tls"] == "true" {
+		scheme = "pulsar+ssl://"
+	}
+	client, err := pulsar.NewClient(pulsar.ClientOptions{
+		URL:                        scheme + strings.Join(addresses, ","),
+		ConnectionTimeout:          config.Timeouts.DialTimeout(),
+		OperationTimeout:           config.Timeouts.RequestTimeout(),
+		Authentication:             auth,
+		TLSAllowInsecureConnection: config.Extra["tls_insecure"] == "true",
+		TLSTrustCertsFilePath:      config.Extra["tls_trust_certs"],
+		Logger:                     log.DefaultNopLogger(),
+	})
+	if err != nil {
+		return nil, utils.NewConnectionError("Failed to create Pulsar client", err)
+	}
+	return client, nil
+}
+
+// authentication 由连接配置得到认证方式，未配置认证时返回 nil
+func authentication(config *types.ConnectionConfig) (pulsar.Authentication, error) {
+	if token := config.Extra["token"]; token != "" {
+		return pulsar.NewAuthenticationToken(token), nil
+	}
+	if config.Username == "" {
+		return nil, nil
+	}
+	auth, err := pulsar.NewAuthenticationBasic(config.Username, config.Password)
+	if err != nil {
+		return nil, utils.NewConfigError("Invalid Pulsar basic authentication", err.Error())
+	}
+	return auth, nil
+}
+
+// topicName 返回主题的完整名称，如 persistent://public/default/orders
+//
+// 只写主题名时使用 Extra["namespace"] 命名空间（默认 public/default），
+// 写作 租户/命名空间/主题 时补全 persistent:// 前缀。
+func topicName(config *types.ConnectionConfig, topic string) (string, error) {
+	if !utils.IsValidTopic(topic) {
+		return "", utils.NewValidationError("Invalid topic name", topic)
+	}
+	if domain, rest, ok := strings.Cut(topic, "://"); ok {
+		if (domain != "persistent" && domain != "non-persistent") || strings.Count(rest, "/") < 2 {
+			return "", utils.NewValidationError("Invalid topic name", topic)
+		}
+		return topic, nil
+	}
+	switch strings.Count(topic, "/") {
+	case 0:
+		return "persistent://" + namespace(config) + "/" + topic, nil
+	case 2:
+		return "persistent://" + topic, nil
+	default:
+		return "", utils.NewValidationError("Invalid topic name", topic)
+	}
+}
+
+// namespace 返回连接配置的默认命名空间
+func namespace(config *types.ConnectionConfig) string {
+	if ns := config.Extra["namespace"]; ns != "" {
+		return ns
+	}
+	return defaultNamespace
+}
+
+// formatMessageID 把消息 ID 格式化为 ledger:entry:partition:batch
+func formatMessageID(id pulsar.MessageID) string {
+	return fmt.Sprintf("%d:%d:%d:%d", id.LedgerID(), id.EntryID(), id.PartitionIdx(), id.BatchIdx())
+}
+
+// parseMessageID 解析 formatMessageID 格式的消息 ID，分区和批内序号可以省略，
+// 也可以写作 earliest 或 latest
+func parseMessageID(text string) (pulsar.MessageID, error) {
+	switch text {
+	case "earliest":
+		return pulsar.EarliestMessageID(), nil
+	case "latest":
+		return pulsar.LatestMessageID(), nil
+	}
+	parts := strings.Split(text, ":")
+	if len(parts) < 2 || len(parts) > 4 {
+		return nil, utils.NewValidationError("Invalid message ID, expected ledger:entry[:partition[:batch]]", text)
+	}
+	values := []int64{0, 0, -1, -1}
+	for i, part := range parts {
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, utils.NewValidationError("Invalid message ID, expected ledger:entry[:partition[:batch]]", text)
+		}
+		values[i] = n
+	}
+	return pulsar.NewMessageID(values[0], values[1], int32(values[3]), int32(values[2])), nil
+}
+
+// newMessage 由 Pulsar 消息构造消息，消息属性作为消息头
+func newMessage(m pulsar.Message) *types.Message {
+	msg := &types.Message{
+		ID:        utils.GenerateID(),
+		Topic:     m.Topic(),
+		Key:       m.Key(),
+		Value:     string(m.Payload()),
+		Headers:   make(map[string]string, len(m.Properties())+2),
+		Partition: m.ID().PartitionIdx(),
+		Offset:    m.ID().EntryID(),
+		Timestamp: m.PublishTime(),
+	}
+	for name, value := range m.Properties() {
+		msg.Headers[name] = value
+	}
+	msg.Headers[MessageIDHeader] = formatMessageID(m.ID())
+	if key := m.OrderingKey(); key != "" {
+		msg.Headers[OrderingKeyHeader] = key
+	}
+	return msg
+}
+
+// adminURL 返回管理 REST API 地址，默认为第一个 broker 主机的 8080 端口（TLS 时为 https 的 8443 端口）
+func adminURL(config *types.ConnectionConfig) string {
+	if u := config.Extra["admin_url"]; u != "" {
+		return strings.TrimRight(u, "/")
+	}
+	host := config.Host
+	if addresses := config.Addresses(); len(addresses) > 0 {
+		if h, _, err := net.SplitHostPort(addresses[0]); err == nil {
+			host = h
+		}
+	}
+	if config.Extra["tls"] == "true" {
+		return "https://" + net.JoinHostPort(host, "8443")
+	}
+	return "http://" + net.JoinHostPort(host, "8080")
+}
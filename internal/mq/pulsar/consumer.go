@@ -0,0 +1,271 @@
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+// nackRedeliveryDelay 处理失败的消息重新投递前的等待时间
+const nackRedeliveryDelay = 5 * time.Second
+
+// Consumer Pulsar消费者实现
+//
+// 指定消费组时以消费组名作为订阅名创建消费者，处理成功后确认，处理失败时否认（nack）等待重新投递；
+// 未指定消费组时以 Reader 浏览主题，不创建订阅，也不影响其他订阅的游标。每个主题单独创建消费者或 Reader，
+// 以便按时间重置位置。
+type Consumer struct {
+	client    pulsar.Client
+	config    *types.ConnectionConfig
+	consumers []pulsar.Consumer
+	readers   []pulsar.Reader
+}
+
+// NewConsumer 创建Pulsar消费者
+func NewConsumer() mq.Consumer {
+	return &Consumer{}
+}
+
+// Connect 创建Pulsar客户端，订阅时才连接 broker
+func (c *Consumer) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	if config.Type != types.MQTypePulsar {
+		return utils.NewValidationError("Invalid MQ type for Pulsar consumer", string(config.Type))
+	}
+
+	client, err := newClient(config)
+	if err != nil {
+		return err
+	}
+
+	c.client = client
+	c.config = config
+	return nil
+}
+
+// Subscribe 订阅主题
+//
+// 订阅类型取 Extra["subscription_type"]（exclusive、shared、failover、key_shared，默认 exclusive）。
+// 新建的订阅从最新消息开始，FromBeginning 时从最早的消息开始；指定 StartTime 时把订阅或 Reader
+// 重置到该时间。不指定消费组时还可以用 StartMessageID 从指定消息开始浏览。
+func (c *Consumer) Subscribe(ctx context.Context, req *types.ConsumeRequest) error {
+	if c.client == nil {
+		return utils.NewConnectionError("Consumer not connected", nil)
+	}
+
+	if len(req.Topics) == 0 {
+		return utils.NewValidationError("No topics specified for subscription", "")
+	}
+	names := make([]string, 0, len(req.Topics))
+	for _, topic := range req.Topics {
+		name, err := topicName(c.config, topic)
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+	}
+
+	c.closeHandles()
+	var err error
+	if req.GroupID != "" {
+		err = c.subscribe(names, req)
+	} else {
+		err = c.read(names, req)
+	}
+	if err != nil {
+		c.closeHandles()
+		return err
+	}
+	return nil
+}
+
+// subscribe 为每个主题创建消费者
+func (c *Consumer) subscribe(names []string, req *types.ConsumeRequest) error {
+	if req.StartMessageID != "" {
+		return utils.NewValidationError("Start message ID is only supported without a consumer group", req.StartMessageID)
+	}
+	subType, err := subscriptionType(c.config.Extra["subscription_type"])
+	if err != nil {
+		return err
+	}
+	position := pulsar.SubscriptionPositionLatest
+	if req.FromBeginning {
+		position = pulsar.SubscriptionPositionEarliest
+	}
+
+	for _, name := range names {
+		consumer, err := c.client.Subscribe(pulsar.ConsumerOptions{
+			Topic:                       name,
+			SubscriptionName:            req.GroupID,
+			Type:                        subType,
+			SubscriptionInitialPosition: position,
+			NackRedeliveryDelay:         nackRedeliveryDelay,
+		})
+		if err != nil {
+			return utils.NewSubscriptionError(fmt.Sprintf("Failed to subscribe to %s", name), err)
+		}
+		c.consumers = append(c.consumers, consumer)
+
+		if req.StartTime != nil {
+			if err := consumer.SeekByTime(*req.StartTime); err != nil {
+				return utils.NewSubscriptionError(fmt.Sprintf("Failed to reset subscription %s on %s", req.GroupID, name), err)
+			}
+		}
+	}
+	return nil
+}
+
+// read 为每个主题创建 Reader
+func (c *Consumer) read(names []string, req *types.ConsumeRequest) error {
+	start := pulsar.LatestMessageID()
+	inclusive := false
+	switch {
+	case req.StartMessageID != "":
+		id, err := parseMessageID(req.StartMessageID)
+		if err != nil {
+			return err
+		}
+		start, inclusive = id, true
+	case req.FromBeginning:
+		start = pulsar.EarliestMessageID()
+	}
+
+	for _, name := range names {
+		reader, err := c.client.CreateReader(pulsar.ReaderOptions{
+			Topic:                   name,
+			StartMessageID:          start,
+			StartMessageIDInclusive: inclusive,
+		})
+		if err != nil {
+			return utils.NewSubscriptionError(fmt.Sprintf("Failed to read from %s", name), err)
+		}
+		c.readers = append(c.readers, reader)
+
+		if req.StartTime != nil {
+			if err := reader.SeekByTime(*req.StartTime); err != nil {
+				return utils.NewSubscriptionError(fmt.Sprintf("Failed to seek %s", name), err)
+			}
+		}
+	}
+	return nil
+}
+
+// subscriptionType 解析订阅类型，为空时使用 exclusive
+func subscriptionType(value string) (pulsar.SubscriptionType, error) {
+	switch strings.ToLower(value) {
+	case "", "exclusive":
+		return pulsar.Exclusive, nil
+	case "shared":
+		return pulsar.Shared, nil
+	case "failover":
+		return pulsar.Failover, nil
+	case "key_shared":
+		return pulsar.KeyShared, nil
+	default:
+		return pulsar.Exclusive, utils.NewValidationError("Invalid subscription type, expected exclusive, shared, failover or key_shared", value)
+	}
+}
+
+// received 接收到的消息，consumer 为 nil 表示来自 Reader，不需要确认
+type received struct {
+	msg      pulsar.Message
+	consumer pulsar.Consumer
+}
+
+// Consume 消费消息，所有主题的消息按到达顺序依次处理
+func (c *Consumer) Consume(ctx context.Context, handler mq.MessageHandler) error {
+	if c.client == nil || len(c.consumers)+len(c.readers) == 0 {
+		return utils.NewConnectionError("Consumer not subscribed. Call Subscribe first.", nil)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	msgs := make(chan received)
+	errs := make(chan error, len(c.consumers)+len(c.readers))
+	var wg sync.WaitGroup
+	receive := func(next func(context.Context) (pulsar.Message, error), consumer pulsar.Consumer) {
+		defer wg.Done()
+		for {
+			m, err := next(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					errs <- err
+				}
+				return
+			}
+			select {
+			case msgs <- received{msg: m, consumer: consumer}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+	for _, consumer := range c.consumers {
+		wg.Add(1)
+		go receive(consumer.Receive, consumer)
+	}
+	for _, reader := range c.readers {
+		wg.Add(1)
+		go receive(reader.Next, nil)
+	}
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errs:
+			return utils.NewConnectionError("Failed to receive from Pulsar", err)
+		case r := <-msgs:
+			err := handler(newMessage(r.msg))
+			if ctx.Err() != nil {
+				// 不确认消息，重新订阅时由 broker 再次投递
+				return ctx.Err()
+			}
+			if r.consumer == nil {
+				continue
+			}
+			if err != nil {
+				// 按订阅的否定确认延迟重新投递
+				r.consumer.Nack(r.msg)
+			} else if err := r.consumer.Ack(r.msg); err != nil {
+				return utils.NewConnectionError(fmt.Sprintf("Failed to ack %s on %s", formatMessageID(r.msg.ID()), r.msg.Topic()), err)
+			}
+		}
+	}
+}
+
+// closeHandles 关闭所有消费者和 Reader，订阅保留在 broker 中
+func (c *Consumer) closeHandles() {
+	for _, consumer := range c.consumers {
+		consumer.Close()
+	}
+	for _, reader := range c.readers {
+		reader.Close()
+	}
+	c.consumers = nil
+	c.readers = nil
+}
+
+// Close 关闭消费者
+func (c *Consumer) Close() error {
+	c.closeHandles()
+	if c.client != nil {
+		c.client.Close()
+		c.client = nil
+	}
+	c.config = nil
+	return nil
+}
+
+// IsConnected 检查连接状态
+func (c *Consumer) IsConnected() bool {
+	return c.client != nil
+}
@@ -0,0 +1,155 @@
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"sync"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+// Producer Pulsar生产者实现，每个主题创建一个生产者并复用
+type Producer struct {
+	client    pulsar.Client
+	config    *types.ConnectionConfig
+	mu        sync.Mutex
+	producers map[string]pulsar.Producer
+}
+
+// NewProducer 创建Pulsar生产者
+func NewProducer() mq.Producer {
+	return &Producer{}
+}
+
+// Connect 创建Pulsar客户端，发送第一条消息时才连接 broker
+func (p *Producer) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	if config.Type != types.MQTypePulsar {
+		return utils.NewValidationError("Invalid MQ type for Pulsar producer", string(config.Type))
+	}
+
+	client, err := newClient(config)
+	if err != nil {
+		return err
+	}
+
+	p.client = client
+	p.config = config
+	p.producers = make(map[string]pulsar.Producer)
+	return nil
+}
+
+// Produce 发送单条消息
+//
+// 消息键作为 Pulsar 消息键（决定分区和 Key_Shared 订阅的分配），消息头作为消息属性，
+// OrderingKeyHeader 消息头作为排序键。
+func (p *Producer) Produce(ctx context.Context, req *types.ProduceRequest) error {
+	if p.client == nil {
+		return utils.NewConnectionError("Producer not connected", nil)
+	}
+
+	timeout := p.config.Timeouts.ProduceTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	producer, err := p.producer(req.Topic)
+	if err != nil {
+		return err
+	}
+	if _, err := producer.Send(ctx, producerMessage(req)); err != nil {
+		return utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to send message to "+req.Topic, err), "Producing to Pulsar", timeout)
+	}
+	return nil
+}
+
+// ProduceBatch 批量发送消息，按顺序逐条发送，遇到失败立即返回
+func (p *Producer) ProduceBatch(ctx context.Context, reqs []*types.ProduceRequest) error {
+	if p.client == nil {
+		return utils.NewConnectionError("Producer not connected", nil)
+	}
+
+	if len(reqs) == 0 {
+		return utils.NewValidationError("Empty message batch", "")
+	}
+
+	timeout := p.config.Timeouts.ProduceTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for i, req := range reqs {
+		producer, err := p.producer(req.Topic)
+		if err != nil {
+			return err
+		}
+		if _, err := producer.Send(ctx, producerMessage(req)); err != nil {
+			return utils.WrapTimeout(ctx, utils.NewConnectionError(fmt.Sprintf("Failed to send message %d of %d to %s", i+1, len(reqs), req.Topic), err),
+				"Producing to Pulsar", timeout)
+		}
+	}
+	return nil
+}
+
+// producer 返回主题的生产者，不存在时创建
+func (p *Producer) producer(topic string) (pulsar.Producer, error) {
+	name, err := topicName(p.config, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if producer, ok := p.producers[name]; ok {
+		return producer, nil
+	}
+	producer, err := p.client.CreateProducer(pulsar.ProducerOptions{
+		Topic:       name,
+		SendTimeout: p.config.Timeouts.ProduceTimeout(),
+	})
+	if err != nil {
+		return nil, utils.NewConnectionError("Failed to create producer for "+name, err)
+	}
+	p.producers[name] = producer
+	return producer, nil
+}
+
+// producerMessage 由发送请求构造 Pulsar 消息
+func producerMessage(req *types.ProduceRequest) *pulsar.ProducerMessage {
+	msg := &pulsar.ProducerMessage{
+		Payload:    []byte(req.Value),
+		Key:        req.Key,
+		Properties: make(map[string]string, len(req.Headers)),
+	}
+	for name, value := range req.Headers {
+		if name == OrderingKeyHeader {
+			msg.OrderingKey = value
+			continue
+		}
+		msg.Properties[name] = value
+	}
+	return msg
+}
+
+// Close 关闭所有生产者和客户端
+func (p *Producer) Close() error {
+	p.mu.Lock()
+	for _, producer := range p.producers {
+		producer.Close()
+	}
+	p.producers = nil
+	p.mu.Unlock()
+
+	if p.client != nil {
+		p.client.Close()
+		p.client = nil
+	}
+	p.config = nil
+	return nil
+}
+
+// IsConnected 检查连接状态
+func (p *Producer) IsConnected() bool {
+	return p.client != nil
+}
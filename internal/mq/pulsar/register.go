@@ -0,0 +1,41 @@
+package pulsar
+
+import (
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+)
+
+// init 注册 Pulsar 后端
+func init() {
+	mq.Register(mq.Descriptor{
+		BackendInfo: types.BackendInfo{
+			Type:          types.MQTypePulsar,
+			DisplayName:   "Apache Pulsar",
+			Order:         70,
+			DefaultHost:   "localhost",
+			DefaultPort:   6650,
+			EndpointLabel: "其他 broker",
+			SettingsTitle: "Pulsar 设置",
+			SettingsHelp:  "填写用户名时使用 Basic 认证，填写 Token 时优先使用 JWT 认证",
+			Fields: []types.ConfigField{
+				{Key: "namespace", Label: "默认命名空间（public/default）", Kind: "text", Mono: true},
+				{Key: "subscription_type", Label: "订阅类型", Kind: "text", Options: []types.FieldOption{{Value: "exclusive", Label: "Exclusive 订阅"}, {Value: "shared", Label: "Shared 订阅"}, {Value: "failover", Label: "Failover 订阅"}, {Value: "key_shared", Label: "Key_Shared 订阅"}}, Default: "exclusive"},
+				{Key: "admin_url", Label: "管理 API 地址（默认 http://主机:8080）", Kind: "text", Mono: true},
+				{Key: "token", Label: "JWT Token（可选）", Kind: "password", Mono: true},
+				{Key: "tls", Label: "使用 TLS（pulsar+ssl）", Kind: "bool"},
+				{Key: "tls_insecure", Label: "跳过证书校验", Kind: "bool", ShowIf: "tls"},
+				{Key: "tls_trust_certs", Label: "CA 证书文件路径（可选）", Kind: "text", ShowIf: "tls", Mono: true},
+			},
+			ConsumeHint:     "填写消费组时以该名称订阅并确认消息，不填写时只浏览主题，不创建订阅",
+			CreateTopicHint: "主题名可写作 主题、租户/命名空间/主题 或 persistent://租户/命名空间/主题；分区数为 1 时创建非分区主题",
+			TunnelHelp:      "Pulsar 客户端不支持自定义拨号，无法使用隧道、代理和地址映射。请在本机转发 broker 和管理 API 的端口后直连",
+			Capabilities: []types.Capability{
+				types.CapabilityEndpoints, types.CapabilityUserPassword, types.CapabilityListTopics, types.CapabilityTopicPartition, types.CapabilityMultiTopic, types.CapabilityStartTime, types.CapabilityStartMessageID,
+			},
+		},
+		NewClient:   NewClient,
+		NewProducer: NewProducer,
+		NewConsumer: NewConsumer,
+		NewAdmin:    NewAdmin,
+	})
+}
@@ -3,13 +3,18 @@ package rabbitmq
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
 	"mq-toolkit/pkg/types"
 	"mq-toolkit/pkg/utils"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
@@ -21,6 +26,8 @@ type Admin struct {
 	channel   *amqp.Channel
 	connected bool
 	config    *types.ConnectionConfig
+	tunnel    *tunnel.Tunnel
+	node      string // 当前连接的节点，管理API使用同一节点
 }
 
 // NewAdmin 创建RabbitMQ管理客户端
@@ -36,31 +43,18 @@ func (a *Admin) Connect(ctx context.Context, config *types.ConnectionConfig) err
 
 	a.config = config
 
-	// 构建连接URL
-	vhost := config.VHost
-	if vhost == "" {
-		vhost = "/"
-	}
-
-	var url string
-	// 如果没有提供用户名和密码，使用RabbitMQ默认的guest/guest
-	username := config.Username
-	password := config.Password
-	if username == "" {
-		username = "guest"
-	}
-	if password == "" {
-		password = "guest"
+	t, err := tunnel.OpenIfEnabled(config.Tunnel)
+	if err != nil {
+		return utils.NewConnectionError("Failed to open tunnel", err)
 	}
 
-	url = fmt.Sprintf("amqp://%s:%s@%s:%d%s",
-		username, password, config.Host, config.Port, vhost)
-
-	// 建立连接
-	conn, err := amqp.Dial(url)
+	// 建立连接，集群节点依次尝试
+	conn, endpoint, err := dial(ctx, config, t)
 	if err != nil {
-		return utils.NewConnectionError("Failed to connect to RabbitMQ", err)
+		t.Close()
+		return err
 	}
+	a.tunnel = t
 
 	// 创建通道
 	channel, err := conn.Channel()
@@ -71,6 +65,7 @@ func (a *Admin) Connect(ctx context.Context, config *types.ConnectionConfig) err
 
 	a.conn = conn
 	a.channel = channel
+	a.node = endpoint.Host
 	a.connected = true
 
 	return nil
@@ -97,17 +92,26 @@ func (a *Admin) TestConnection(ctx context.Context) *types.TestResult {
 		}
 	}
 
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	// 尝试声明一个临时队列来测试连接
 	testQueue := fmt.Sprintf("test-queue-%d", time.Now().UnixNano())
-	queue, err := a.channel.QueueDeclare(
-		testQueue, // 队列名称
-		false,     // 不持久化
-		true,      // 自动删除
-		true,      // 排他性
-		false,     // 不等待
-		nil,       // 参数
-	)
+	var queue amqp.Queue
+	err := callWithContext(ctx, func() (err error) {
+		queue, err = a.channel.QueueDeclare(
+			testQueue, // 队列名称
+			false,     // 不持久化
+			true,      // 自动删除
+			true,      // 排他性
+			false,     // 不等待
+			nil,       // 参数
+		)
+		return err
+	})
 	if err != nil {
+		err = utils.WrapTimeout(ctx, err, "Declaring test queue", timeout)
 		return &types.TestResult{
 			Success: false,
 			Message: fmt.Sprintf("Failed to declare test queue: %v", err),
@@ -116,8 +120,12 @@ func (a *Admin) TestConnection(ctx context.Context) *types.TestResult {
 	}
 
 	// 删除测试队列
-	_, err = a.channel.QueueDelete(queue.Name, false, false, false)
+	err = callWithContext(ctx, func() error {
+		_, err := a.channel.QueueDelete(queue.Name, false, false, false)
+		return err
+	})
 	if err != nil {
+		err = utils.WrapTimeout(ctx, err, "Deleting test queue", timeout)
 		return &types.TestResult{
 			Success: false,
 			Message: fmt.Sprintf("Failed to delete test queue: %v", err),
@@ -142,6 +150,25 @@ type QueueInfo struct {
 	Consumers  int    `json:"consumers"`
 }
 
+// Ping 在管理连接上打开并关闭一个通道，连接已断开或 broker 无响应时返回错误
+func (a *Admin) Ping(ctx context.Context) error {
+	if !a.connected || a.conn == nil || a.conn.IsClosed() {
+		return utils.NewConnectionError("Not connected to RabbitMQ", nil)
+	}
+
+	err := callWithContext(ctx, func() error {
+		channel, err := a.conn.Channel()
+		if err == nil {
+			err = channel.Close()
+		}
+		return err
+	})
+	if err != nil {
+		return utils.NewConnectionError("RabbitMQ broker is unreachable", err)
+	}
+	return nil
+}
+
 // ListTopics 列出所有队列（RabbitMQ中的"主题"概念对应队列）
 func (a *Admin) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
 	if !a.connected || a.channel == nil {
@@ -149,10 +176,12 @@ func (a *Admin) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
 	}
 
 	// 使用RabbitMQ HTTP管理API获取队列列表
-	queues, err := a.getQueuesFromAPI()
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	queues, err := a.getQueuesFromAPI(ctx)
 	if err != nil {
-		// 如果HTTP API失败，返回空列表而不是错误，这样不会阻止其他功能
-		return []types.TopicInfo{}, nil
+		return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to list queues through the management API", err), "Listing queues", timeout)
 	}
 
 	// 转换为TopicInfo格式
@@ -169,24 +198,32 @@ func (a *Admin) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
 }
 
 // getQueuesFromAPI 通过HTTP管理API获取队列列表
-func (a *Admin) getQueuesFromAPI() ([]QueueInfo, error) {
-	// 构建管理API URL
-	vhost := a.config.VHost
-	if vhost == "" {
-		vhost = "/"
+func (a *Admin) getQueuesFromAPI(ctx context.Context) ([]QueueInfo, error) {
+	var queues []QueueInfo
+	if err := a.managementGet(ctx, "/queues/"+url.PathEscape(a.vhost()), &queues); err != nil {
+		return nil, err
 	}
+	return queues, nil
+}
 
-	// URL编码vhost
-	encodedVhost := url.QueryEscape(vhost)
+// vhost 返回连接的虚拟主机，未设置时为默认的 "/"
+func (a *Admin) vhost() string {
+	if a.config.VHost == "" {
+		return "/"
+	}
+	return a.config.VHost
+}
 
+// managementGet 调用HTTP管理API的 GET 接口并解析JSON响应，path 为 /api 之后的部分
+func (a *Admin) managementGet(ctx context.Context, path string, out interface{}) error {
 	// 管理API默认端口是15672
 	managementPort := 15672
-	apiURL := fmt.Sprintf("http://%s:%d/api/queues/%s", a.config.Host, managementPort, encodedVhost)
+	apiURL := fmt.Sprintf("http://%s/api%s", net.JoinHostPort(a.node, strconv.Itoa(managementPort)), path)
 
 	// 创建HTTP请求
-	req, err := http.NewRequest("GET", apiURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+		return fmt.Errorf("failed to create request: %v", err)
 	}
 
 	// 设置认证
@@ -201,31 +238,33 @@ func (a *Admin) getQueuesFromAPI() ([]QueueInfo, error) {
 	req.SetBasicAuth(username, password)
 
 	// 发送请求
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := &http.Client{}
+	if a.tunnel != nil {
+		// 管理API与AMQP使用同一隧道
+		client.Transport = &http.Transport{DialContext: a.tunnel.DialContext}
+	}
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call management API: %v", err)
+		return fmt.Errorf("failed to call management API: %v", err)
 	}
 	defer resp.Body.Close()
 
 	// 检查响应状态
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("management API returned status %d", resp.StatusCode)
+		return fmt.Errorf("management API returned status %d", resp.StatusCode)
 	}
 
 	// 读取响应体
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %v", err)
+		return fmt.Errorf("failed to read response: %v", err)
 	}
 
 	// 解析JSON响应
-	var queues []QueueInfo
-	if err := json.Unmarshal(body, &queues); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %v", err)
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %v", err)
 	}
-
-	return queues, nil
+	return nil
 }
 
 // CreateTopic 创建队列
@@ -238,19 +277,26 @@ func (a *Admin) CreateTopic(ctx context.Context, topic string, partitions int32,
 		return utils.NewValidationError("Invalid queue name", topic)
 	}
 
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	// 在RabbitMQ中，partitions和replicas参数不适用
 	// 这里只创建一个持久化队列
-	_, err := a.channel.QueueDeclare(
-		topic, // 队列名称
-		true,  // 持久化
-		false, // 自动删除
-		false, // 排他性
-		false, // 不等待
-		nil,   // 参数
-	)
+	err := callWithContext(ctx, func() error {
+		_, err := a.channel.QueueDeclare(
+			topic, // 队列名称
+			true,  // 持久化
+			false, // 自动删除
+			false, // 排他性
+			false, // 不等待
+			nil,   // 参数
+		)
+		return err
+	})
 
 	if err != nil {
-		return utils.NewConnectionError("Failed to create queue", err)
+		return utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to create queue", err), "Creating queue", timeout)
 	}
 
 	return nil
@@ -266,25 +312,251 @@ func (a *Admin) DeleteTopic(ctx context.Context, topic string) error {
 		return utils.NewValidationError("Invalid queue name", topic)
 	}
 
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	// 删除队列
-	_, err := a.channel.QueueDelete(
-		topic, // 队列名称
-		false, // 如果未使用
-		false, // 如果为空
-		false, // 不等待
-	)
+	err := callWithContext(ctx, func() error {
+		_, err := a.channel.QueueDelete(
+			topic, // 队列名称
+			false, // 如果未使用
+			false, // 如果为空
+			false, // 不等待
+		)
+		return err
+	})
 
 	if err != nil {
-		return utils.NewConnectionError("Failed to delete queue", err)
+		return utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to delete queue", err), "Deleting queue", timeout)
 	}
 
 	return nil
 }
 
-// ListConsumerGroups 列出消费组（RabbitMQ没有消费组概念）
-func (a *Admin) ListConsumerGroups(ctx context.Context) ([]types.ConsumerGroup, error) {
-	// RabbitMQ没有消费组的概念，返回空列表
-	return []types.ConsumerGroup{}, nil
+// exchangeKinds 内置的交换机类型，插件提供的类型以 x- 开头
+var exchangeKinds = map[string]bool{
+	amqp.ExchangeDirect:  true,
+	amqp.ExchangeFanout:  true,
+	amqp.ExchangeTopic:   true,
+	amqp.ExchangeHeaders: true,
+}
+
+// withChannel 在新通道上执行 fn，声明失败时 broker 会关闭所在通道，不影响管理连接的共享通道
+func (a *Admin) withChannel(ctx context.Context, fn func(channel *amqp.Channel) error) error {
+	if !a.connected || a.conn == nil {
+		return utils.NewConnectionError("Not connected to RabbitMQ", nil)
+	}
+	return callWithContext(ctx, func() error {
+		channel, err := a.conn.Channel()
+		if err != nil {
+			return err
+		}
+		defer channel.Close()
+		return fn(channel)
+	})
+}
+
+// channelError 按 broker 关闭通道的原因返回对应类型的错误
+func channelError(message string, err error) error {
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) {
+		switch amqpErr.Code {
+		case amqp.NotFound:
+			return utils.NewErrorWithDetails(utils.ErrorTypeNotFound, "NOT_FOUND_001", message, amqpErr.Reason)
+		case amqp.PreconditionFailed:
+			return utils.NewValidationError(message, amqpErr.Reason)
+		case amqp.AccessRefused:
+			return utils.NewErrorWithDetails(utils.ErrorTypeAuth, "AUTH_001", message, amqpErr.Reason)
+		}
+	}
+	return utils.NewConnectionError(message, err)
+}
+
+// ListExchanges 通过HTTP管理API列出虚拟主机上的交换机，不包括默认交换机
+func (a *Admin) ListExchanges(ctx context.Context) ([]types.ExchangeInfo, error) {
+	if !a.connected || a.conn == nil {
+		return nil, utils.NewConnectionError("Not connected to RabbitMQ", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var exchanges []struct {
+		Name       string `json:"name"`
+		Type       string `json:"type"`
+		Durable    bool   `json:"durable"`
+		AutoDelete bool   `json:"auto_delete"`
+		Internal   bool   `json:"internal"`
+	}
+	if err := a.managementGet(ctx, "/exchanges/"+url.PathEscape(a.vhost()), &exchanges); err != nil {
+		return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to list exchanges through the management API", err), "Listing exchanges", timeout)
+	}
+
+	result := make([]types.ExchangeInfo, 0, len(exchanges))
+	for _, exchange := range exchanges {
+		if exchange.Name == "" {
+			continue // 默认交换机按队列名路由，不能绑定或删除
+		}
+		result = append(result, types.ExchangeInfo{
+			Name:       exchange.Name,
+			Kind:       exchange.Type,
+			Durable:    exchange.Durable,
+			AutoDelete: exchange.AutoDelete,
+			Internal:   exchange.Internal,
+		})
+	}
+	return result, nil
+}
+
+// CreateExchange 声明交换机，已存在且类型或持久化属性不同时返回校验错误
+func (a *Admin) CreateExchange(ctx context.Context, exchange, kind string, durable bool) error {
+	if exchange == "" {
+		return utils.NewValidationError("Exchange name is required", "")
+	}
+	if !exchangeKinds[kind] && !strings.HasPrefix(kind, "x-") {
+		return utils.NewValidationError("Invalid exchange type", kind)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := a.withChannel(ctx, func(channel *amqp.Channel) error {
+		return channel.ExchangeDeclare(exchange, kind, durable, false, false, false, nil)
+	})
+	if err != nil {
+		return utils.WrapTimeout(ctx, channelError("Failed to declare exchange "+exchange, err), "Declaring exchange", timeout)
+	}
+	return nil
+}
+
+// DeleteExchange 删除交换机及其绑定
+func (a *Admin) DeleteExchange(ctx context.Context, exchange string) error {
+	if exchange == "" {
+		return utils.NewValidationError("Exchange name is required", "")
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := a.withChannel(ctx, func(channel *amqp.Channel) error {
+		return channel.ExchangeDelete(exchange, false, false)
+	})
+	if err != nil {
+		return utils.WrapTimeout(ctx, channelError("Failed to delete exchange "+exchange, err), "Deleting exchange", timeout)
+	}
+	return nil
+}
+
+// ListBindings 通过HTTP管理API列出交换机到队列的绑定
+func (a *Admin) ListBindings(ctx context.Context, exchange string) ([]types.BindingInfo, error) {
+	if !a.connected || a.conn == nil {
+		return nil, utils.NewConnectionError("Not connected to RabbitMQ", nil)
+	}
+	if exchange == "" {
+		return nil, utils.NewValidationError("Exchange name is required", "")
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var bindings []struct {
+		Source          string `json:"source"`
+		Destination     string `json:"destination"`
+		DestinationType string `json:"destination_type"`
+		RoutingKey      string `json:"routing_key"`
+	}
+	path := fmt.Sprintf("/exchanges/%s/%s/bindings/source", url.PathEscape(a.vhost()), url.PathEscape(exchange))
+	if err := a.managementGet(ctx, path, &bindings); err != nil {
+		return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to list bindings through the management API", err), "Listing bindings", timeout)
+	}
+
+	result := make([]types.BindingInfo, 0, len(bindings))
+	for _, binding := range bindings {
+		if binding.DestinationType != "queue" {
+			continue
+		}
+		result = append(result, types.BindingInfo{
+			Exchange:   binding.Source,
+			Queue:      binding.Destination,
+			RoutingKey: binding.RoutingKey,
+		})
+	}
+	return result, nil
+}
+
+// BindQueue 将队列按路由键绑定到交换机
+func (a *Admin) BindQueue(ctx context.Context, exchange, queue, routingKey string) error {
+	if exchange == "" || queue == "" {
+		return utils.NewValidationError("Exchange and queue are required", "")
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := a.withChannel(ctx, func(channel *amqp.Channel) error {
+		return channel.QueueBind(queue, routingKey, exchange, false, nil)
+	})
+	if err != nil {
+		return utils.WrapTimeout(ctx, channelError(fmt.Sprintf("Failed to bind queue %s to exchange %s", queue, exchange), err), "Binding queue", timeout)
+	}
+	return nil
+}
+
+// UnbindQueue 解除队列与交换机按路由键的绑定
+func (a *Admin) UnbindQueue(ctx context.Context, exchange, queue, routingKey string) error {
+	if exchange == "" || queue == "" {
+		return utils.NewValidationError("Exchange and queue are required", "")
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := a.withChannel(ctx, func(channel *amqp.Channel) error {
+		return channel.QueueUnbind(queue, routingKey, exchange, nil)
+	})
+	if err != nil {
+		return utils.WrapTimeout(ctx, channelError(fmt.Sprintf("Failed to unbind queue %s from exchange %s", queue, exchange), err), "Unbinding queue", timeout)
+	}
+	return nil
+}
+
+// Browse 取出队列头部最多 limit 条消息后关闭通道，未确认的消息全部放回队列
+//
+// 放回的消息会被标记为重新投递，并可能在此期间被其他消费者收到。
+func (a *Admin) Browse(ctx context.Context, queue string, limit int) ([]types.Message, error) {
+	if !utils.IsValidTopic(queue) {
+		return nil, utils.NewValidationError("Invalid queue name", queue)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	messages := []types.Message{}
+	err := a.withChannel(ctx, func(channel *amqp.Channel) error {
+		for len(messages) < limit {
+			delivery, ok, err := channel.Get(queue, false)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+			messages = append(messages, *newMessage(queue, delivery))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, utils.WrapTimeout(ctx, channelError("Failed to browse queue "+queue, err), "Browsing queue", timeout)
+	}
+	return messages, nil
 }
 
 // Close 关闭连接
@@ -307,5 +579,7 @@ func (a *Admin) Close() error {
 
 	a.connected = false
 	a.config = nil
+	a.tunnel.Close()
+	a.tunnel = nil
 	return lastErr
 }
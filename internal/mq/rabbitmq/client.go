@@ -2,8 +2,16 @@ package rabbitmq
 
 import (
 	"context"
+	"fmt"
 	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
 	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"net"
+	"strings"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 // Client RabbitMQ完整客户端实现
@@ -86,9 +94,39 @@ func (c *Client) DeleteTopic(ctx context.Context, topic string) error {
 	return c.admin.DeleteTopic(ctx, topic)
 }
 
-// ListConsumerGroups 列出消费组
-func (c *Client) ListConsumerGroups(ctx context.Context) ([]types.ConsumerGroup, error) {
-	return c.admin.ListConsumerGroups(ctx)
+// ListExchanges 列出交换机
+func (c *Client) ListExchanges(ctx context.Context) ([]types.ExchangeInfo, error) {
+	return c.admin.(mq.ExchangeAdmin).ListExchanges(ctx)
+}
+
+// CreateExchange 创建交换机
+func (c *Client) CreateExchange(ctx context.Context, exchange, kind string, durable bool) error {
+	return c.admin.(mq.ExchangeAdmin).CreateExchange(ctx, exchange, kind, durable)
+}
+
+// DeleteExchange 删除交换机
+func (c *Client) DeleteExchange(ctx context.Context, exchange string) error {
+	return c.admin.(mq.ExchangeAdmin).DeleteExchange(ctx, exchange)
+}
+
+// ListBindings 列出交换机到队列的绑定
+func (c *Client) ListBindings(ctx context.Context, exchange string) ([]types.BindingInfo, error) {
+	return c.admin.(mq.ExchangeAdmin).ListBindings(ctx, exchange)
+}
+
+// BindQueue 将队列绑定到交换机
+func (c *Client) BindQueue(ctx context.Context, exchange, queue, routingKey string) error {
+	return c.admin.(mq.ExchangeAdmin).BindQueue(ctx, exchange, queue, routingKey)
+}
+
+// UnbindQueue 解除队列与交换机的绑定
+func (c *Client) UnbindQueue(ctx context.Context, exchange, queue, routingKey string) error {
+	return c.admin.(mq.ExchangeAdmin).UnbindQueue(ctx, exchange, queue, routingKey)
+}
+
+// Browse 查看队列头部的消息，消息放回队列
+func (c *Client) Browse(ctx context.Context, queue string, limit int) ([]types.Message, error) {
+	return c.admin.(mq.Browser).Browse(ctx, queue, limit)
 }
 
 // Close 关闭客户端
@@ -114,3 +152,127 @@ func (c *Client) Close() error {
 func (c *Client) IsConnected() bool {
 	return c.producer.IsConnected() && c.consumer.IsConnected()
 }
+
+// Ping 通过管理连接探测 RabbitMQ 是否可达
+func (c *Client) Ping(ctx context.Context) error {
+	return c.admin.(mq.Pinger).Ping(ctx)
+}
+
+// newMessage 将投递的消息转换为 Message，消息头的值格式化为字符串
+func newMessage(queue string, delivery amqp.Delivery) *types.Message {
+	msg := &types.Message{
+		ID:        utils.GenerateID(),
+		Topic:     queue,
+		Key:       delivery.RoutingKey,
+		Value:     string(delivery.Body),
+		Timestamp: delivery.Timestamp,
+	}
+	if delivery.Headers != nil {
+		msg.Headers = make(map[string]string)
+		for key, value := range delivery.Headers {
+			msg.Headers[key] = fmt.Sprintf("%v", value)
+		}
+	}
+	return msg
+}
+
+// dial 按顺序尝试集群节点，返回第一个连接成功的连接及其节点，t 不为 nil 时经隧道连接
+//
+// 未提供用户名和密码时使用RabbitMQ默认的guest/guest，Extra["tls"] 为 true 时使用 amqps。
+// 整个过程（包括 TLS 和 AMQP 握手）受连接的拨号超时限制，失败返回连接错误，超时返回超时错误。
+func dial(ctx context.Context, config *types.ConnectionConfig, t *tunnel.Tunnel) (*amqp.Connection, utils.Endpoint, error) {
+	uri := amqp.URI{
+		Scheme:   "amqp",
+		Username: config.Username,
+		Password: config.Password,
+		Vhost:    config.VHost,
+	}
+	if uri.Username == "" {
+		uri.Username = "guest"
+	}
+	if uri.Password == "" {
+		uri.Password = "guest"
+	}
+	// 与旧版本拼接 URL 的行为保持一致："/orders" 表示虚拟主机 orders
+	if len(uri.Vhost) > 1 {
+		uri.Vhost = strings.TrimPrefix(uri.Vhost, "/")
+	}
+	if uri.Vhost == "" {
+		uri.Vhost = "/"
+	}
+	if config.Extra["tls"] == "true" {
+		uri.Scheme = "amqps"
+	}
+
+	endpoints, err := utils.ParseEndpoints(strings.Join(config.Addresses(), ","), config.Port)
+	if err != nil {
+		return nil, utils.Endpoint{}, utils.NewConnectionError("Failed to connect to RabbitMQ", err)
+	}
+	if len(endpoints) == 0 {
+		return nil, utils.Endpoint{}, utils.NewConnectionError("Failed to connect to RabbitMQ", fmt.Errorf("no RabbitMQ nodes configured"))
+	}
+
+	timeout := config.Timeouts.DialTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lastErr error
+	for _, endpoint := range endpoints {
+		uri.Host, uri.Port = endpoint.Host, endpoint.Port
+		conn, err := amqp.DialConfig(uri.String(), amqp.Config{
+			Heartbeat: 10 * time.Second,
+			Locale:    "en_US",
+			Dial:      dialFunc(ctx, t),
+		})
+		if err == nil {
+			return conn, endpoint, nil
+		}
+		lastErr = fmt.Errorf("%s: %w", endpoint, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, utils.Endpoint{}, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to connect to RabbitMQ", lastErr), "Connecting to RabbitMQ", timeout)
+}
+
+// dialFunc 返回 amqp 拨号函数，t 不为 nil 时经隧道拨号
+//
+// 连接的截止时间取 ctx 的截止时间，握手完成后 amqp 会清除该截止时间。
+func dialFunc(ctx context.Context, t *tunnel.Tunnel) func(network, addr string) (net.Conn, error) {
+	return func(network, addr string) (net.Conn, error) {
+		var conn net.Conn
+		var err error
+		if t != nil {
+			conn, err = t.DialContext(ctx, network, addr)
+		} else {
+			var dialer net.Dialer
+			conn, err = dialer.DialContext(ctx, network, addr)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if deadline, ok := ctx.Deadline(); ok {
+			if err := conn.SetDeadline(deadline); err != nil {
+				conn.Close()
+				return nil, err
+			}
+		}
+		return conn, nil
+	}
+}
+
+// callWithContext 执行不支持 context 的 amqp 操作，ctx 结束时不再等待并返回 ctx 的错误
+//
+// 放弃等待后操作仍在后台执行，连接断开时 amqp 会使其返回。
+func callWithContext(ctx context.Context, fn func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
 	"mq-toolkit/pkg/types"
 	"mq-toolkit/pkg/utils"
 	"sync"
+	"sync/atomic"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
@@ -17,6 +19,7 @@ type Consumer struct {
 	channel          *amqp.Channel
 	connected        bool
 	config           *types.ConnectionConfig
+	tunnel           *tunnel.Tunnel
 	subscribedQueues []string // 保存订阅的队列
 }
 
@@ -32,27 +35,19 @@ func (c *Consumer) Connect(ctx context.Context, config *types.ConnectionConfig)
 	}
 
 	c.config = config
-	vhost := config.VHost
-	if vhost == "" {
-		vhost = "/"
-	}
 
-	// 如果没有提供用户名和密码，使用RabbitMQ默认的guest/guest
-	username := config.Username
-	password := config.Password
-	if username == "" {
-		username = "guest"
-	}
-	if password == "" {
-		password = "guest"
+	t, err := tunnel.OpenIfEnabled(config.Tunnel)
+	if err != nil {
+		return utils.NewConnectionError("Failed to open tunnel", err)
 	}
 
-	url := fmt.Sprintf("amqp://%s:%s@%s:%d%s", username, password, config.Host, config.Port, vhost)
-
-	conn, err := amqp.Dial(url)
+	// 建立连接，集群节点依次尝试
+	conn, _, err := dial(ctx, config, t)
 	if err != nil {
-		return utils.NewConnectionError("Failed to connect to RabbitMQ", err)
+		t.Close()
+		return err
 	}
+	c.tunnel = t
 	c.conn = conn
 	c.connected = true
 	return nil
@@ -79,19 +74,27 @@ func (c *Consumer) Subscribe(ctx context.Context, req *types.ConsumeRequest) err
 	}
 	c.channel = channel
 
+	timeout := c.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	// 声明队列并保存
 	c.subscribedQueues = []string{}
 	for _, queueName := range req.Topics {
-		_, err := c.channel.QueueDeclare(
-			queueName, // name
-			true,      // durable
-			false,     // delete when unused
-			false,     // exclusive
-			false,     // no-wait
-			nil,       // arguments
-		)
+		err := callWithContext(ctx, func() error {
+			_, err := c.channel.QueueDeclare(
+				queueName, // name
+				true,      // durable
+				false,     // delete when unused
+				false,     // exclusive
+				false,     // no-wait
+				nil,       // arguments
+			)
+			return err
+		})
 		if err != nil {
-			return utils.NewConnectionError(fmt.Sprintf("Failed to declare queue %s", queueName), err)
+			return utils.WrapTimeout(ctx, utils.NewConnectionError(fmt.Sprintf("Failed to declare queue %s", queueName), err),
+				fmt.Sprintf("Declaring queue %s", queueName), timeout)
 		}
 		c.subscribedQueues = append(c.subscribedQueues, queueName)
 	}
@@ -113,9 +116,18 @@ func (c *Consumer) Consume(ctx context.Context, handler mq.MessageHandler) error
 	}
 
 	var wg sync.WaitGroup
+	var closed atomic.Bool // broker 关闭了投递通道，通常是连接断开
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	// 开始消费或确认失败时结束消费，只保留第一个错误
+	var failOnce sync.Once
+	var failure error
+	fail := func(err error) {
+		failOnce.Do(func() { failure = err })
+		cancel()
+	}
+
 	for _, queueName := range c.subscribedQueues {
 		wg.Add(1)
 		go func(qName string) {
@@ -125,8 +137,7 @@ func (c *Consumer) Consume(ctx context.Context, handler mq.MessageHandler) error
 				qName, "", false, false, false, false, nil,
 			)
 			if err != nil {
-				fmt.Printf("Failed to start consuming from queue %s: %v\n", qName, err)
-				cancel() // 取消其他 goroutines
+				fail(utils.NewSubscriptionError(fmt.Sprintf("Failed to start consuming from queue %s", qName), err))
 				return
 			}
 
@@ -136,28 +147,25 @@ func (c *Consumer) Consume(ctx context.Context, handler mq.MessageHandler) error
 					return
 				case delivery, ok := <-msgs:
 					if !ok {
+						closed.Store(true)
+						cancel()
 						return
 					}
 
-					msg := &types.Message{
-						ID:        utils.GenerateID(),
-						Topic:     qName,
-						Key:       delivery.RoutingKey,
-						Value:     string(delivery.Body),
-						Timestamp: delivery.Timestamp,
-					}
-					if delivery.Headers != nil {
-						msg.Headers = make(map[string]string)
-						for key, value := range delivery.Headers {
-							msg.Headers[key] = fmt.Sprintf("%v", value)
-						}
+					err := handler(newMessage(qName, delivery))
+					if ctx.Err() != nil {
+						// 不确认，通道关闭后消息回到队列
+						return
 					}
-
-					if err := handler(msg); err != nil {
-						fmt.Printf("Failed to handle message from %s: %v. Nacking.\n", qName, err)
-						delivery.Nack(false, true)
+					if err != nil {
+						// 拒绝且不重新入队，避免在同一条消息上空转；配置了死信交换机时转入死信队列
+						err = delivery.Nack(false, false)
 					} else {
-						delivery.Ack(false)
+						err = delivery.Ack(false)
+					}
+					if err != nil {
+						fail(utils.NewConnectionError(fmt.Sprintf("Failed to acknowledge message from %s", qName), err))
+						return
 					}
 				}
 			}
@@ -165,6 +173,12 @@ func (c *Consumer) Consume(ctx context.Context, handler mq.MessageHandler) error
 	}
 
 	wg.Wait()
+	if closed.Load() {
+		return utils.NewConnectionError("Delivery channel closed by broker", nil)
+	}
+	if failure != nil {
+		return failure
+	}
 	return ctx.Err()
 }
 
@@ -183,6 +197,8 @@ func (c *Consumer) Close() error {
 		}
 	}
 	c.subscribedQueues = nil
+	c.tunnel.Close()
+	c.tunnel = nil
 	return lastErr
 }
 
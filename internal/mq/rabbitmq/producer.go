@@ -2,8 +2,8 @@ package rabbitmq
 
 import (
 	"context"
-	"fmt"
 	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
 	"mq-toolkit/pkg/types"
 	"mq-toolkit/pkg/utils"
 	"time"
@@ -17,6 +17,7 @@ type Producer struct {
 	channel   *amqp.Channel
 	connected bool
 	config    *types.ConnectionConfig
+	tunnel    *tunnel.Tunnel
 }
 
 // NewProducer 创建RabbitMQ生产者
@@ -32,31 +33,18 @@ func (p *Producer) Connect(ctx context.Context, config *types.ConnectionConfig)
 
 	p.config = config
 
-	// 构建连接URL
-	vhost := config.VHost
-	if vhost == "" {
-		vhost = "/"
-	}
-
-	var url string
-	// 如果没有提供用户名和密码，使用RabbitMQ默认的guest/guest
-	username := config.Username
-	password := config.Password
-	if username == "" {
-		username = "guest"
-	}
-	if password == "" {
-		password = "guest"
+	t, err := tunnel.OpenIfEnabled(config.Tunnel)
+	if err != nil {
+		return utils.NewConnectionError("Failed to open tunnel", err)
 	}
 
-	url = fmt.Sprintf("amqp://%s:%s@%s:%d%s",
-		username, password, config.Host, config.Port, vhost)
-
-	// 建立连接
-	conn, err := amqp.Dial(url)
+	// 建立连接，集群节点依次尝试
+	conn, _, err := dial(ctx, config, t)
 	if err != nil {
-		return utils.NewConnectionError("Failed to connect to RabbitMQ", err)
+		t.Close()
+		return err
 	}
+	p.tunnel = t
 
 	// 创建通道
 	channel, err := conn.Channel()
@@ -78,19 +66,30 @@ func (p *Producer) Produce(ctx context.Context, req *types.ProduceRequest) error
 		return utils.NewConnectionError("Producer not connected", nil)
 	}
 
+	timeout := p.config.Timeouts.ProduceTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return utils.WrapTimeout(ctx, p.publish(ctx, req), "Producing to RabbitMQ", timeout)
+}
+
+// publish 声明队列并发布消息
+func (p *Producer) publish(ctx context.Context, req *types.ProduceRequest) error {
 	if !utils.IsValidTopic(req.Topic) {
 		return utils.NewValidationError("Invalid queue name", req.Topic)
 	}
 
 	// 声明队列（确保队列存在）
-	_, err := p.channel.QueueDeclare(
-		req.Topic, // 队列名称
-		true,      // 持久化
-		false,     // 自动删除
-		false,     // 排他性
-		false,     // 不等待
-		nil,       // 参数
-	)
+	err := callWithContext(ctx, func() error {
+		_, err := p.channel.QueueDeclare(
+			req.Topic, // 队列名称
+			true,      // 持久化
+			false,     // 自动删除
+			false,     // 排他性
+			false,     // 不等待
+			nil,       // 参数
+		)
+		return err
+	})
 	if err != nil {
 		return utils.NewConnectionError("Failed to declare queue", err)
 	}
@@ -138,10 +137,13 @@ func (p *Producer) ProduceBatch(ctx context.Context, reqs []*types.ProduceReques
 		return utils.NewValidationError("Empty message batch", "")
 	}
 
-	// 逐个发送消息（RabbitMQ没有原生的批量发送API）
+	// 逐个发送消息（RabbitMQ没有原生的批量发送API），整批共用发送超时
+	timeout := p.config.Timeouts.ProduceTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 	for _, req := range reqs {
-		if err := p.Produce(ctx, req); err != nil {
-			return err
+		if err := p.publish(ctx, req); err != nil {
+			return utils.WrapTimeout(ctx, err, "Producing to RabbitMQ", timeout)
 		}
 	}
 
@@ -168,6 +170,8 @@ func (p *Producer) Close() error {
 
 	p.connected = false
 	p.config = nil
+	p.tunnel.Close()
+	p.tunnel = nil
 	return lastErr
 }
 
@@ -177,6 +181,6 @@ func (p *Producer) IsConnected() bool {
 		return false
 	}
 
-	// 检查连接是否仍然有效
-	return !p.conn.IsClosed()
+	// 检查连接和通道是否仍然有效
+	return !p.conn.IsClosed() && !p.channel.IsClosed()
 }
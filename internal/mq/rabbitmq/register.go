@@ -0,0 +1,28 @@
+package rabbitmq
+
+import (
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+)
+
+// init 注册 RabbitMQ 后端
+func init() {
+	mq.Register(mq.Descriptor{
+		BackendInfo: types.BackendInfo{
+			Type:            types.MQTypeRabbitMQ,
+			DisplayName:     "RabbitMQ",
+			Order:           20,
+			DefaultHost:     "localhost",
+			DefaultPort:     5672,
+			DefaultUsername: "guest",
+			EndpointLabel:   "其他集群节点（按顺序故障转移）",
+			Capabilities: []types.Capability{
+				types.CapabilityTunnel, types.CapabilityEndpoints, types.CapabilityUserPassword, types.CapabilityListTopics, types.CapabilityMultiTopic,
+			},
+		},
+		NewClient:   NewClient,
+		NewProducer: NewProducer,
+		NewConsumer: NewConsumer,
+		NewAdmin:    NewAdmin,
+	})
+}
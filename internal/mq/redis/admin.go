@@ -0,0 +1,536 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// scanCount 每次 SCAN 建议返回的键数
+const scanCount = 1000
+
+// Admin Redis Streams管理客户端实现，主题对应流，消费组对应流上的消费组
+type Admin struct {
+	client redis.UniversalClient
+	config *types.ConnectionConfig
+	tunnel *tunnel.Tunnel
+}
+
+// NewAdmin 创建Redis Streams管理客户端
+func NewAdmin() mq.Admin {
+	return &Admin{}
+}
+
+// Connect 连接到Redis
+func (a *Admin) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	if config.Type != types.MQTypeRedis {
+		return utils.NewValidationError("Invalid MQ type for Redis admin", string(config.Type))
+	}
+
+	t, err := tunnel.OpenIfEnabled(config.Tunnel)
+	if err != nil {
+		return utils.NewConnectionError("Failed to open tunnel", err)
+	}
+
+	client, err := dial(ctx, config, t)
+	if err != nil {
+		t.Close()
+		return err
+	}
+
+	a.client = client
+	a.config = config
+	a.tunnel = t
+	return nil
+}
+
+// TestConnection 测试连接，并报告 Redis 版本
+func (a *Admin) TestConnection(ctx context.Context) *types.TestResult {
+	start := time.Now()
+
+	if a.client == nil {
+		return &types.TestResult{
+			Success: false,
+			Message: "Not connected to Redis",
+			Latency: time.Since(start).Milliseconds(),
+		}
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := a.client.Ping(ctx).Err(); err != nil {
+		err = utils.WrapTimeout(ctx, err, "Pinging Redis", timeout)
+		return &types.TestResult{
+			Success: false,
+			Message: fmt.Sprintf("Failed to ping Redis: %v", err),
+			Latency: time.Since(start).Milliseconds(),
+		}
+	}
+	latency := time.Since(start).Milliseconds()
+
+	message := "Connected successfully to Redis"
+	if info, err := a.client.Info(ctx, "server").Result(); err == nil {
+		for _, line := range strings.Split(info, "\n") {
+			if version, ok := strings.CutPrefix(strings.TrimSpace(line), "redis_version:"); ok {
+				message += " " + version
+				break
+			}
+		}
+	}
+	return &types.TestResult{
+		Success: true,
+		Message: message,
+		Latency: latency,
+	}
+}
+
+// Ping 发送 PING，连接已断开或服务器无响应时返回错误
+func (a *Admin) Ping(ctx context.Context) error {
+	if a.client == nil {
+		return utils.NewConnectionError("Not connected to Redis", nil)
+	}
+	if err := a.client.Ping(ctx).Err(); err != nil {
+		return utils.NewConnectionError("Redis is unreachable", err)
+	}
+	return nil
+}
+
+// ListTopics 用 SCAN TYPE stream 列出所有流，集群模式下扫描每个主节点
+func (a *Admin) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
+	if a.client == nil {
+		return nil, utils.NewConnectionError("Not connected to Redis", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	names, err := a.streams(ctx)
+	if err != nil {
+		return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to list streams", err), "Listing streams", timeout)
+	}
+
+	topics := make([]types.TopicInfo, 0, len(names))
+	for _, name := range names {
+		topics = append(topics, types.TopicInfo{
+			Name:       name,
+			Partitions: 1, // 流没有分区概念
+			Replicas:   1,
+		})
+	}
+	return topics, nil
+}
+
+// streams 返回所有流的名称，按名称排序
+func (a *Admin) streams(ctx context.Context) ([]string, error) {
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	scan := func(ctx context.Context, client redis.UniversalClient) error {
+		iter := client.ScanType(ctx, 0, "*", scanCount, "stream").Iterator()
+		for iter.Next(ctx) {
+			mu.Lock()
+			seen[iter.Val()] = true
+			mu.Unlock()
+		}
+		return iter.Err()
+	}
+
+	var err error
+	if cluster, ok := a.client.(*redis.ClusterClient); ok {
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			return scan(ctx, client)
+		})
+	} else {
+		err = scan(ctx, a.client)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// CreateTopic 创建空流，通过创建再删除一个临时消费组实现，不会写入条目
+func (a *Admin) CreateTopic(ctx context.Context, topic string, partitions int32, replicas int16) error {
+	if a.client == nil {
+		return utils.NewConnectionError("Not connected to Redis", nil)
+	}
+
+	if !utils.IsValidTopic(topic) {
+		return utils.NewValidationError("Invalid stream name", topic)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	exists, err := a.client.Exists(ctx, topic).Result()
+	if err != nil {
+		return utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to create stream", err), "Creating stream", timeout)
+	}
+	if exists > 0 {
+		return utils.NewValidationError("Key already exists", topic)
+	}
+
+	group := "mq-toolkit-create-" + utils.GenerateID()
+	if err := a.client.XGroupCreateMkStream(ctx, topic, group, "$").Err(); err != nil {
+		return utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to create stream", err), "Creating stream", timeout)
+	}
+	if err := a.client.XGroupDestroy(ctx, topic, group).Err(); err != nil {
+		return utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to create stream", err), "Creating stream", timeout)
+	}
+	return nil
+}
+
+// DeleteTopic 删除流，键不存在或不是流时返回错误
+func (a *Admin) DeleteTopic(ctx context.Context, topic string) error {
+	if a.client == nil {
+		return utils.NewConnectionError("Not connected to Redis", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := a.checkStream(ctx, topic); err != nil {
+		return utils.WrapTimeout(ctx, err, "Deleting stream", timeout)
+	}
+	if err := a.client.Del(ctx, topic).Err(); err != nil {
+		return utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to delete stream", err), "Deleting stream", timeout)
+	}
+	return nil
+}
+
+// checkStream 确认键存在且是流，避免误删或误改其他类型的键
+func (a *Admin) checkStream(ctx context.Context, stream string) error {
+	if !utils.IsValidTopic(stream) {
+		return utils.NewValidationError("Invalid stream name", stream)
+	}
+	kind, err := a.client.Type(ctx, stream).Result()
+	if err != nil {
+		return utils.NewConnectionError("Failed to look up stream", err)
+	}
+	switch kind {
+	case "stream":
+		return nil
+	case "none":
+		return utils.NewNotFoundError("Stream", stream)
+	default:
+		return utils.NewValidationError("Key is not a stream", fmt.Sprintf("%s is a %s", stream, kind))
+	}
+}
+
+// ListConsumerGroups 列出所有流上的消费组，同名消费组合并为一个
+func (a *Admin) ListConsumerGroups(ctx context.Context) ([]types.ConsumerGroup, error) {
+	if a.client == nil {
+		return nil, utils.NewConnectionError("Not connected to Redis", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	streams, err := a.streams(ctx)
+	if err != nil {
+		return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to list streams", err), "Listing consumer groups", timeout)
+	}
+
+	groups := make(map[string]*types.ConsumerGroup)
+	members := make(map[string]map[string]bool)
+	for _, stream := range streams {
+		infos, err := a.client.XInfoGroups(ctx, stream).Result()
+		if err != nil {
+			if isNoStream(err) {
+				continue // 列出后被删除
+			}
+			return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to list groups of stream "+stream, err), "Listing consumer groups", timeout)
+		}
+		for _, info := range infos {
+			group, ok := groups[info.Name]
+			if !ok {
+				group = &types.ConsumerGroup{ID: info.Name, Members: []string{}}
+				groups[info.Name] = group
+				members[info.Name] = make(map[string]bool)
+			}
+			group.Topics = append(group.Topics, stream)
+
+			consumers, err := a.client.XInfoConsumers(ctx, stream, info.Name).Result()
+			if err != nil {
+				if isNoStream(err) || isNoGroup(err) {
+					continue
+				}
+				return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to list consumers of group "+info.Name, err), "Listing consumer groups", timeout)
+			}
+			for _, consumer := range consumers {
+				if !members[info.Name][consumer.Name] {
+					members[info.Name][consumer.Name] = true
+					group.Members = append(group.Members, consumer.Name)
+				}
+			}
+		}
+	}
+
+	result := make([]types.ConsumerGroup, 0, len(groups))
+	for _, group := range groups {
+		sort.Strings(group.Members)
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+// Browse 用 XRANGE 查看流中最早的 limit 个条目，不影响消费组
+func (a *Admin) Browse(ctx context.Context, stream string, limit int) ([]types.Message, error) {
+	if a.client == nil {
+		return nil, utils.NewConnectionError("Not connected to Redis", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := a.checkStream(ctx, stream); err != nil {
+		return nil, utils.WrapTimeout(ctx, err, "Browsing stream", timeout)
+	}
+	entries, err := a.client.XRangeN(ctx, stream, "-", "+", int64(limit)).Result()
+	if err != nil {
+		return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to read stream", err), "Browsing stream", timeout)
+	}
+	messages := make([]types.Message, 0, len(entries))
+	for _, entry := range entries {
+		messages = append(messages, *newMessage(stream, entry))
+	}
+	return messages, nil
+}
+
+// StreamInfo 用 XINFO STREAM、XINFO GROUPS 和 XINFO CONSUMERS 查看流详情
+func (a *Admin) StreamInfo(ctx context.Context, stream string) (*types.StreamInfo, error) {
+	if a.client == nil {
+		return nil, utils.NewConnectionError("Not connected to Redis", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := a.checkStream(ctx, stream); err != nil {
+		return nil, utils.WrapTimeout(ctx, err, "Getting stream info", timeout)
+	}
+	info, err := a.client.XInfoStream(ctx, stream).Result()
+	if err != nil {
+		return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to get stream info", err), "Getting stream info", timeout)
+	}
+	result := &types.StreamInfo{
+		Name:            stream,
+		Length:          info.Length,
+		FirstEntryID:    info.FirstEntry.ID,
+		LastEntryID:     info.LastEntry.ID,
+		LastGeneratedID: info.LastGeneratedID,
+		Groups:          []types.StreamGroupInfo{},
+	}
+
+	groups, err := a.client.XInfoGroups(ctx, stream).Result()
+	if err != nil {
+		return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to get stream groups", err), "Getting stream info", timeout)
+	}
+	for _, group := range groups {
+		groupInfo := types.StreamGroupInfo{
+			Name:            group.Name,
+			Pending:         group.Pending,
+			LastDeliveredID: group.LastDeliveredID,
+			Lag:             group.Lag,
+			Consumers:       []types.StreamConsumerInfo{},
+		}
+		consumers, err := a.client.XInfoConsumers(ctx, stream, group.Name).Result()
+		if err != nil {
+			return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to get consumers of group "+group.Name, err), "Getting stream info", timeout)
+		}
+		for _, consumer := range consumers {
+			groupInfo.Consumers = append(groupInfo.Consumers, types.StreamConsumerInfo{
+				Name:    consumer.Name,
+				Pending: consumer.Pending,
+				Idle:    consumer.Idle.Milliseconds(),
+			})
+		}
+		result.Groups = append(result.Groups, groupInfo)
+	}
+	return result, nil
+}
+
+// TrimStream 用 XTRIM MAXLEN 裁剪流，只保留最新的 maxLen 条，返回删除的条目数
+func (a *Admin) TrimStream(ctx context.Context, stream string, maxLen int64) (int64, error) {
+	if a.client == nil {
+		return 0, utils.NewConnectionError("Not connected to Redis", nil)
+	}
+
+	if maxLen < 0 {
+		return 0, utils.NewValidationError("Max length must not be negative", fmt.Sprint(maxLen))
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := a.checkStream(ctx, stream); err != nil {
+		return 0, utils.WrapTimeout(ctx, err, "Trimming stream", timeout)
+	}
+	removed, err := a.client.XTrimMaxLen(ctx, stream, maxLen).Result()
+	if err != nil {
+		return 0, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to trim stream", err), "Trimming stream", timeout)
+	}
+	return removed, nil
+}
+
+// CreateStreamGroup 创建消费组，流不存在时一并创建；startID 为空时只消费新条目
+func (a *Admin) CreateStreamGroup(ctx context.Context, stream, group, startID string) error {
+	if a.client == nil {
+		return utils.NewConnectionError("Not connected to Redis", nil)
+	}
+
+	if !utils.IsValidTopic(stream) {
+		return utils.NewValidationError("Invalid stream name", stream)
+	}
+	if !utils.IsValidTopic(group) {
+		return utils.NewValidationError("Invalid consumer group name", group)
+	}
+	if startID == "" {
+		startID = "$"
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := a.client.XGroupCreateMkStream(ctx, stream, group, startID).Err()
+	if isBusyGroup(err) {
+		return utils.NewValidationError("Consumer group already exists", group)
+	}
+	if err != nil {
+		return utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to create consumer group", err), "Creating consumer group", timeout)
+	}
+	return nil
+}
+
+// DeleteStreamGroup 删除消费组，其未确认的条目一并丢弃
+func (a *Admin) DeleteStreamGroup(ctx context.Context, stream, group string) error {
+	if a.client == nil {
+		return utils.NewConnectionError("Not connected to Redis", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := a.checkStream(ctx, stream); err != nil {
+		return utils.WrapTimeout(ctx, err, "Deleting consumer group", timeout)
+	}
+	deleted, err := a.client.XGroupDestroy(ctx, stream, group).Result()
+	if err != nil {
+		return utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to delete consumer group", err), "Deleting consumer group", timeout)
+	}
+	if deleted == 0 {
+		return utils.NewNotFoundError("Consumer group", group)
+	}
+	return nil
+}
+
+// ListPending 用 XPENDING 列出消费组中最早的 count 条未确认条目
+func (a *Admin) ListPending(ctx context.Context, stream, group string, count int64) ([]types.PendingEntry, error) {
+	if a.client == nil {
+		return nil, utils.NewConnectionError("Not connected to Redis", nil)
+	}
+
+	if count <= 0 {
+		count = 100
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	pending, err := a.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  group,
+		Start:  "-",
+		End:    "+",
+		Count:  count,
+	}).Result()
+	if err != nil {
+		if isNoGroup(err) {
+			return nil, utils.NewNotFoundError("Consumer group", group)
+		}
+		return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to list pending entries", err), "Listing pending entries", timeout)
+	}
+
+	entries := make([]types.PendingEntry, 0, len(pending))
+	for _, p := range pending {
+		entries = append(entries, types.PendingEntry{
+			ID:            p.ID,
+			Consumer:      p.Consumer,
+			Idle:          p.Idle.Milliseconds(),
+			DeliveryCount: p.RetryCount,
+		})
+	}
+	return entries, nil
+}
+
+// ClaimPending 用 XCLAIM 将空闲超过 minIdle 的未确认条目转移给 consumer，返回成功转移的条目 ID
+func (a *Admin) ClaimPending(ctx context.Context, stream, group, consumer string, minIdle time.Duration, ids []string) ([]string, error) {
+	if a.client == nil {
+		return nil, utils.NewConnectionError("Not connected to Redis", nil)
+	}
+
+	if len(ids) == 0 {
+		return nil, utils.NewValidationError("No entry IDs specified", "")
+	}
+	if !utils.IsValidTopic(consumer) {
+		return nil, utils.NewValidationError("Invalid consumer name", consumer)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	claimed, err := a.client.XClaimJustID(ctx, &redis.XClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		if isNoGroup(err) {
+			return nil, utils.NewNotFoundError("Consumer group", group)
+		}
+		return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to claim pending entries", err), "Claiming pending entries", timeout)
+	}
+	return claimed, nil
+}
+
+// Close 关闭连接
+func (a *Admin) Close() error {
+	if a.client != nil {
+		a.client.Close()
+		a.client = nil
+	}
+	a.config = nil
+	a.tunnel.Close()
+	a.tunnel = nil
+	return nil
+}
@@ -0,0 +1,324 @@
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 消息与流条目字段的映射：消息值和键分别写入 value、key 字段，消息头作为其他字段
+const (
+	ValueField = "value"
+	KeyField   = "key"
+)
+
+// EntryIDHeader 消费时把流条目 ID 放入该消息头，可用于查看未确认消息或转移消息
+const EntryIDHeader = "Redis-Stream-Id"
+
+// Client Redis Streams完整客户端实现
+type Client struct {
+	producer mq.Producer
+	consumer mq.Consumer
+	admin    mq.Admin
+	config   *types.ConnectionConfig
+}
+
+// NewClient 创建Redis Streams完整客户端
+func NewClient() mq.Client {
+	return &Client{
+		producer: NewProducer(),
+		consumer: NewConsumer(),
+		admin:    NewAdmin(),
+	}
+}
+
+// Connect 连接到Redis
+func (c *Client) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	c.config = config
+
+	if err := c.producer.Connect(ctx, config); err != nil {
+		return err
+	}
+
+	if err := c.consumer.Connect(ctx, config); err != nil {
+		c.producer.Close()
+		return err
+	}
+
+	if err := c.admin.Connect(ctx, config); err != nil {
+		c.producer.Close()
+		c.consumer.Close()
+		return err
+	}
+
+	return nil
+}
+
+// Produce 发送消息
+func (c *Client) Produce(ctx context.Context, req *types.ProduceRequest) error {
+	return c.producer.Produce(ctx, req)
+}
+
+// ProduceBatch 批量发送消息
+func (c *Client) ProduceBatch(ctx context.Context, reqs []*types.ProduceRequest) error {
+	return c.producer.ProduceBatch(ctx, reqs)
+}
+
+// Subscribe 订阅流
+func (c *Client) Subscribe(ctx context.Context, req *types.ConsumeRequest) error {
+	return c.consumer.Subscribe(ctx, req)
+}
+
+// Consume 消费消息
+func (c *Client) Consume(ctx context.Context, handler mq.MessageHandler) error {
+	return c.consumer.Consume(ctx, handler)
+}
+
+// TestConnection 测试连接
+func (c *Client) TestConnection(ctx context.Context) *types.TestResult {
+	return c.admin.TestConnection(ctx)
+}
+
+// ListTopics 列出所有流
+func (c *Client) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
+	return c.admin.ListTopics(ctx)
+}
+
+// CreateTopic 创建空流
+func (c *Client) CreateTopic(ctx context.Context, topic string, partitions int32, replicas int16) error {
+	return c.admin.CreateTopic(ctx, topic, partitions, replicas)
+}
+
+// DeleteTopic 删除流
+func (c *Client) DeleteTopic(ctx context.Context, topic string) error {
+	return c.admin.DeleteTopic(ctx, topic)
+}
+
+// ListConsumerGroups 列出所有流上的消费组
+func (c *Client) ListConsumerGroups(ctx context.Context) ([]types.ConsumerGroup, error) {
+	return c.admin.(mq.GroupAdmin).ListConsumerGroups(ctx)
+}
+
+// Browse 查看流中最早的条目
+func (c *Client) Browse(ctx context.Context, stream string, limit int) ([]types.Message, error) {
+	return c.admin.(mq.Browser).Browse(ctx, stream, limit)
+}
+
+// StreamInfo 查看流及其消费组、消费者详情
+func (c *Client) StreamInfo(ctx context.Context, stream string) (*types.StreamInfo, error) {
+	return c.admin.(mq.StreamAdmin).StreamInfo(ctx, stream)
+}
+
+// TrimStream 裁剪流
+func (c *Client) TrimStream(ctx context.Context, stream string, maxLen int64) (int64, error) {
+	return c.admin.(mq.StreamAdmin).TrimStream(ctx, stream, maxLen)
+}
+
+// CreateStreamGroup 创建消费组
+func (c *Client) CreateStreamGroup(ctx context.Context, stream, group, startID string) error {
+	return c.admin.(mq.StreamAdmin).CreateStreamGroup(ctx, stream, group, startID)
+}
+
+// DeleteStreamGroup 删除消费组
+func (c *Client) DeleteStreamGroup(ctx context.Context, stream, group string) error {
+	return c.admin.(mq.StreamAdmin).DeleteStreamGroup(ctx, stream, group)
+}
+
+// ListPending 列出消费组中未确认的消息
+func (c *Client) ListPending(ctx context.Context, stream, group string, count int64) ([]types.PendingEntry, error) {
+	return c.admin.(mq.StreamAdmin).ListPending(ctx, stream, group, count)
+}
+
+// ClaimPending 将未确认的消息转移给指定消费者
+func (c *Client) ClaimPending(ctx context.Context, stream, group, consumer string, minIdle time.Duration, ids []string) ([]string, error) {
+	return c.admin.(mq.StreamAdmin).ClaimPending(ctx, stream, group, consumer, minIdle, ids)
+}
+
+// Close 关闭客户端
+func (c *Client) Close() error {
+	var lastErr error
+
+	if err := c.producer.Close(); err != nil {
+		lastErr = err
+	}
+
+	if err := c.consumer.Close(); err != nil {
+		lastErr = err
+	}
+
+	if err := c.admin.Close(); err != nil {
+		lastErr = err
+	}
+
+	return lastErr
+}
+
+// IsConnected 检查连接状态
+func (c *Client) IsConnected() bool {
+	return c.producer.IsConnected() && c.consumer.IsConnected()
+}
+
+// Ping 通过管理连接探测 Redis 是否可达
+func (c *Client) Ping(ctx context.Context) error {
+	return c.admin.(mq.Pinger).Ping(ctx)
+}
+
+// dial 创建 Redis 客户端并发送 PING 确认连接可用，t 不为 nil 时经隧道连接
+//
+// 配置了多个地址时按集群模式连接，Extra["master_name"] 不为空时通过哨兵连接主节点；
+// Extra["db"] 选择数据库（集群模式不支持），Extra["tls"] 为 true 时使用 TLS，
+// Extra["tls_insecure"] 为 true 时跳过证书校验。用户名为空时只用密码认证（requirepass）。
+func dial(ctx context.Context, config *types.ConnectionConfig, t *tunnel.Tunnel) (redis.UniversalClient, error) {
+	addresses := config.Addresses()
+	if len(addresses) == 0 {
+		return nil, utils.NewConnectionError("Failed to connect to Redis", fmt.Errorf("no Redis address configured"))
+	}
+
+	db := 0
+	if value := config.Extra["db"]; value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, utils.NewValidationError("Invalid Redis database", value)
+		}
+		db = n
+	}
+
+	timeout := config.Timeouts.DialTimeout()
+	opts := &redis.UniversalOptions{
+		Addrs:       addresses,
+		MasterName:  config.Extra["master_name"],
+		DB:          db,
+		Username:    config.Username,
+		Password:    config.Password,
+		ClientName:  "mq-toolkit",
+		DialTimeout: timeout,
+		// 连接池中的连接按需创建，阻塞读取的消费连接不占用发送和管理的连接
+		PoolSize: 4,
+	}
+	if config.Extra["tls"] == "true" {
+		opts.TLSConfig = &tls.Config{InsecureSkipVerify: config.Extra["tls_insecure"] == "true"}
+	}
+	if t != nil {
+		opts.Dialer = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := t.DialContext(ctx, network, addr)
+			if err != nil || opts.TLSConfig == nil {
+				return conn, err
+			}
+			cfg := opts.TLSConfig.Clone()
+			if cfg.ServerName == "" {
+				cfg.ServerName, _, _ = net.SplitHostPort(addr)
+			}
+			tlsConn := tls.Client(conn, cfg)
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				conn.Close()
+				return nil, err
+			}
+			return tlsConn, nil
+		}
+	}
+
+	client := redis.NewUniversalClient(opts)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to connect to Redis", err), "Connecting to Redis", timeout)
+	}
+	return client, nil
+}
+
+// isBusyGroup 判断创建消费组的错误是否表示消费组已存在
+func isBusyGroup(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP")
+}
+
+// isNoGroup 判断错误是否表示流或消费组不存在
+func isNoGroup(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "NOGROUP")
+}
+
+// isNoStream 判断 XINFO 的错误是否表示流不存在
+func isNoStream(err error) bool {
+	return errors.Is(err, redis.Nil) || (err != nil && strings.Contains(err.Error(), "no such key"))
+}
+
+// entryArgs 由发送请求构造 XADD 参数，消息头不能与 key、value 字段同名
+func entryArgs(req *types.ProduceRequest) (*redis.XAddArgs, error) {
+	if !utils.IsValidTopic(req.Topic) {
+		return nil, utils.NewValidationError("Invalid stream name", req.Topic)
+	}
+	values := make([]interface{}, 0, 2*len(req.Headers)+4)
+	values = append(values, ValueField, req.Value)
+	if req.Key != "" {
+		values = append(values, KeyField, req.Key)
+	}
+	for name, value := range req.Headers {
+		if name == ValueField || name == KeyField {
+			return nil, utils.NewValidationError("Header name is reserved for the message "+name, name)
+		}
+		values = append(values, name, value)
+	}
+	return &redis.XAddArgs{Stream: req.Topic, Values: values}, nil
+}
+
+// newMessage 由流条目构造消息
+//
+// value、key 字段作为消息值和键，其他字段作为消息头；条目没有 value 字段时（由其他程序写入），
+// 消息值为所有字段组成的 JSON 对象。条目 ID 写入 EntryIDHeader，消息时间取自条目 ID。
+func newMessage(stream string, entry redis.XMessage) *types.Message {
+	msg := &types.Message{
+		ID:        utils.GenerateID(),
+		Topic:     stream,
+		Headers:   make(map[string]string, len(entry.Values)+1),
+		Timestamp: entryTime(entry.ID),
+	}
+	_, hasValue := entry.Values[ValueField]
+	for name, field := range entry.Values {
+		text := fmt.Sprint(field)
+		switch name {
+		case ValueField:
+			msg.Value = text
+		case KeyField:
+			msg.Key = text
+		default:
+			msg.Headers[name] = text
+		}
+	}
+	if !hasValue {
+		data, _ := json.Marshal(entry.Values)
+		msg.Value = string(data)
+	}
+	msg.Headers[EntryIDHeader] = entry.ID
+	return msg
+}
+
+// entryTime 由条目 ID 的毫秒时间戳部分得到消息时间，ID 不是默认格式时返回当前时间
+func entryTime(id string) time.Time {
+	ms, _, _ := strings.Cut(id, "-")
+	n, err := strconv.ParseInt(ms, 10, 64)
+	if err != nil {
+		return time.Now()
+	}
+	return time.UnixMilli(n)
+}
+
+// idBefore 返回时间 t 之前最后一个可能的条目 ID，XREAD 从该 ID 之后开始读取即包含 t 时刻的条目
+func idBefore(t time.Time) string {
+	ms := t.UnixMilli()
+	if ms <= 0 {
+		return "0-0"
+	}
+	return fmt.Sprintf("%d-%d", ms-1, uint64(1<<64-1))
+}
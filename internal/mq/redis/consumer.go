@@ -0,0 +1,241 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 每次读取的最大条目数和阻塞等待时间，阻塞时间决定了取消消费的响应速度
+const (
+	readCount = 100
+	readBlock = time.Second
+)
+
+// Consumer Redis Streams消费者实现
+//
+// 未指定消费组时用 XREAD 读取新条目；指定消费组时用 XREADGROUP 读取，处理成功后 XACK 确认，
+// 订阅后先重新投递该消费者之前未确认的条目，再读取新条目。
+type Consumer struct {
+	client   redis.UniversalClient
+	config   *types.ConnectionConfig
+	tunnel   *tunnel.Tunnel
+	streams  []string
+	ids      map[string]string // 各流下次读取的起始 ID，消费组模式下 ">" 表示读取新条目
+	group    string
+	consumer string
+}
+
+// NewConsumer 创建Redis Streams消费者
+func NewConsumer() mq.Consumer {
+	return &Consumer{}
+}
+
+// Connect 连接到Redis
+func (c *Consumer) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	if config.Type != types.MQTypeRedis {
+		return utils.NewValidationError("Invalid MQ type for Redis consumer", string(config.Type))
+	}
+
+	t, err := tunnel.OpenIfEnabled(config.Tunnel)
+	if err != nil {
+		return utils.NewConnectionError("Failed to open tunnel", err)
+	}
+
+	client, err := dial(ctx, config, t)
+	if err != nil {
+		t.Close()
+		return err
+	}
+
+	c.client = client
+	c.config = config
+	c.tunnel = t
+	return nil
+}
+
+// Subscribe 订阅流，指定消费组时自动创建不存在的流和消费组
+//
+// 新建消费组或不使用消费组时，起始位置按 StartTime、FromBeginning 的顺序选择，默认只读取新条目；
+// 已存在的消费组从上次投递的位置继续。消费者名称取 Extra["consumer_name"]，默认为 mq-toolkit-<主机名>。
+func (c *Consumer) Subscribe(ctx context.Context, req *types.ConsumeRequest) error {
+	if c.client == nil {
+		return utils.NewConnectionError("Consumer not connected", nil)
+	}
+
+	if len(req.Topics) == 0 {
+		return utils.NewValidationError("No topics specified for subscription", "")
+	}
+	for _, topic := range req.Topics {
+		if !utils.IsValidTopic(topic) {
+			return utils.NewValidationError("Invalid stream name", topic)
+		}
+	}
+
+	timeout := c.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ids := make(map[string]string, len(req.Topics))
+	for _, stream := range req.Topics {
+		var start string
+		switch {
+		case req.StartTime != nil:
+			start = idBefore(*req.StartTime)
+		case req.FromBeginning:
+			start = "0-0"
+		case req.GroupID != "":
+			start = "$"
+		default:
+			// XREAD 每次调用时 $ 都表示当时的最后一条，先解析为具体 ID 以免漏读两次调用之间的条目
+			last, err := c.lastID(ctx, stream)
+			if err != nil {
+				return utils.WrapTimeout(ctx, utils.NewSubscriptionError("Failed to subscribe to "+stream, err), "Subscribing to Redis stream", timeout)
+			}
+			start = last
+		}
+
+		if req.GroupID == "" {
+			ids[stream] = start
+			continue
+		}
+		err := c.client.XGroupCreateMkStream(ctx, stream, req.GroupID, start).Err()
+		if err != nil && !isBusyGroup(err) {
+			return utils.WrapTimeout(ctx, utils.NewSubscriptionError(fmt.Sprintf("Failed to create group %s on %s", req.GroupID, stream), err),
+				"Subscribing to Redis stream", timeout)
+		}
+		ids[stream] = "0" // 先读取本消费者未确认的条目
+	}
+
+	c.streams = req.Topics
+	c.ids = ids
+	c.group = req.GroupID
+	c.consumer = c.config.Extra["consumer_name"]
+	if c.consumer == "" {
+		host, _ := os.Hostname()
+		c.consumer = "mq-toolkit-" + host
+	}
+	return nil
+}
+
+// lastID 返回流最后一个条目的 ID，流为空或不存在时返回 0-0
+func (c *Consumer) lastID(ctx context.Context, stream string) (string, error) {
+	entries, err := c.client.XRevRangeN(ctx, stream, "+", "-", 1).Result()
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "0-0", nil
+	}
+	return entries[0].ID, nil
+}
+
+// Consume 消费消息，消费组模式下处理成功的条目立即确认，处理失败的留在未确认列表中
+func (c *Consumer) Consume(ctx context.Context, handler mq.MessageHandler) error {
+	if c.client == nil || len(c.streams) == 0 {
+		return utils.NewConnectionError("Consumer not subscribed. Call Subscribe first.", nil)
+	}
+
+	client, group := c.client, c.group
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		results, err := c.read(ctx, client)
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if isNoGroup(err) {
+				return utils.NewSubscriptionError(fmt.Sprintf("Consumer group %s no longer exists", group), err)
+			}
+			return utils.NewConnectionError("Failed to read from Redis stream", err)
+		}
+
+		for _, result := range results {
+			if group != "" && c.ids[result.Stream] != ">" && len(result.Messages) == 0 {
+				c.ids[result.Stream] = ">" // 未确认的条目已全部重新投递
+				continue
+			}
+			for _, entry := range result.Messages {
+				if group == "" || c.ids[result.Stream] != ">" {
+					c.ids[result.Stream] = entry.ID
+				}
+				// 未确认期间被删除的条目没有字段，直接确认；处理失败的条目留在未确认列表中
+				if entry.Values != nil {
+					err := handler(newMessage(result.Stream, entry))
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					if err != nil {
+						continue
+					}
+				}
+				if group != "" {
+					if err := client.XAck(ctx, result.Stream, group, entry.ID).Err(); err != nil {
+						if ctx.Err() != nil {
+							return ctx.Err()
+						}
+						return utils.NewConnectionError(fmt.Sprintf("Failed to ack %s on %s", entry.ID, result.Stream), err)
+					}
+				}
+			}
+		}
+	}
+}
+
+// read 读取一批条目，只在所有流都读取新条目时阻塞等待
+func (c *Consumer) read(ctx context.Context, client redis.UniversalClient) ([]redis.XStream, error) {
+	args := make([]string, 0, 2*len(c.streams))
+	args = append(args, c.streams...)
+	block := readBlock
+	for _, stream := range c.streams {
+		id := c.ids[stream]
+		args = append(args, id)
+		if c.group != "" && id != ">" {
+			block = -1
+		}
+	}
+
+	if c.group == "" {
+		return client.XRead(ctx, &redis.XReadArgs{Streams: args, Count: readCount, Block: block}).Result()
+	}
+	return client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    c.group,
+		Consumer: c.consumer,
+		Streams:  args,
+		Count:    readCount,
+		Block:    block,
+	}).Result()
+}
+
+// Close 关闭消费者，消费组和未确认的条目保留在 Redis 中
+func (c *Consumer) Close() error {
+	if c.client != nil {
+		c.client.Close()
+		c.client = nil
+	}
+	c.config = nil
+	c.streams = nil
+	c.ids = nil
+	c.tunnel.Close()
+	c.tunnel = nil
+	return nil
+}
+
+// IsConnected 检查连接状态
+func (c *Consumer) IsConnected() bool {
+	return c.client != nil
+}
@@ -0,0 +1,119 @@
+package redis
+
+import (
+	"context"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Producer Redis Streams生产者实现，每条消息以 XADD 追加为一个流条目
+type Producer struct {
+	client redis.UniversalClient
+	config *types.ConnectionConfig
+	tunnel *tunnel.Tunnel
+}
+
+// NewProducer 创建Redis Streams生产者
+func NewProducer() mq.Producer {
+	return &Producer{}
+}
+
+// Connect 连接到Redis
+func (p *Producer) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	if config.Type != types.MQTypeRedis {
+		return utils.NewValidationError("Invalid MQ type for Redis producer", string(config.Type))
+	}
+
+	t, err := tunnel.OpenIfEnabled(config.Tunnel)
+	if err != nil {
+		return utils.NewConnectionError("Failed to open tunnel", err)
+	}
+
+	client, err := dial(ctx, config, t)
+	if err != nil {
+		t.Close()
+		return err
+	}
+
+	p.client = client
+	p.config = config
+	p.tunnel = t
+	return nil
+}
+
+// Produce 发送单条消息，流不存在时自动创建
+func (p *Producer) Produce(ctx context.Context, req *types.ProduceRequest) error {
+	if p.client == nil {
+		return utils.NewConnectionError("Producer not connected", nil)
+	}
+
+	args, err := entryArgs(req)
+	if err != nil {
+		return err
+	}
+
+	timeout := p.config.Timeouts.ProduceTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := p.client.XAdd(ctx, args).Err(); err != nil {
+		return utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to add stream entry", err), "Producing to Redis", timeout)
+	}
+	return nil
+}
+
+// ProduceBatch 批量发送消息，整批通过一个管道发送
+func (p *Producer) ProduceBatch(ctx context.Context, reqs []*types.ProduceRequest) error {
+	if p.client == nil {
+		return utils.NewConnectionError("Producer not connected", nil)
+	}
+
+	if len(reqs) == 0 {
+		return utils.NewValidationError("Empty message batch", "")
+	}
+
+	batch := make([]*redis.XAddArgs, 0, len(reqs))
+	for _, req := range reqs {
+		args, err := entryArgs(req)
+		if err != nil {
+			return err
+		}
+		batch = append(batch, args)
+	}
+
+	timeout := p.config.Timeouts.ProduceTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	_, err := p.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, args := range batch {
+			pipe.XAdd(ctx, args)
+		}
+		return nil
+	})
+	if err != nil {
+		return utils.WrapTimeout(ctx, utils.NewConnectionError("Failed to add stream entries", err), "Producing to Redis", timeout)
+	}
+	return nil
+}
+
+// Close 关闭生产者
+func (p *Producer) Close() error {
+	if p.client != nil {
+		p.client.Close()
+		p.client = nil
+	}
+	p.config = nil
+	p.tunnel.Close()
+	p.tunnel = nil
+	return nil
+}
+
+// IsConnected 检查连接状态
+func (p *Producer) IsConnected() bool {
+	return p.client != nil
+}
@@ -0,0 +1,37 @@
+package redis
+
+import (
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+)
+
+// init 注册 Redis 后端
+func init() {
+	mq.Register(mq.Descriptor{
+		BackendInfo: types.BackendInfo{
+			Type:          types.MQTypeRedis,
+			DisplayName:   "Redis Streams",
+			Order:         60,
+			DefaultHost:   "localhost",
+			DefaultPort:   6379,
+			EndpointLabel: "其他节点（填写后按集群或哨兵模式连接）",
+			SettingsTitle: "Redis 设置",
+			SettingsHelp:  "只填写密码时使用 requirepass 认证，同时填写用户名时使用 ACL 用户",
+			Fields: []types.ConfigField{
+				{Key: "db", Label: "数据库编号（默认 0）", Kind: "number"},
+				{Key: "master_name", Label: "哨兵主节点名称（可选）", Kind: "text", Mono: true},
+				{Key: "consumer_name", Label: "消费组中的消费者名称（默认 mq-toolkit-主机名）", Kind: "text", Mono: true},
+				{Key: "tls", Label: "使用 TLS", Kind: "bool"},
+				{Key: "tls_insecure", Label: "跳过证书校验", Kind: "bool", ShowIf: "tls"},
+			},
+			ConsumeHint: "新建消费组或不使用消费组时可从指定时间重放",
+			Capabilities: []types.Capability{
+				types.CapabilityTunnel, types.CapabilityEndpoints, types.CapabilityUserPassword, types.CapabilityListTopics, types.CapabilityMultiTopic, types.CapabilityStartTime,
+			},
+		},
+		NewClient:   NewClient,
+		NewProducer: NewProducer,
+		NewConsumer: NewConsumer,
+		NewAdmin:    NewAdmin,
+	})
+}
@@ -0,0 +1,86 @@
+package mq
+
+import (
+	"fmt"
+	"mq-toolkit/pkg/types"
+	"sort"
+	"sync"
+)
+
+// Descriptor describes a message queue backend: the metadata the frontend uses
+// to build its connection form, and the constructors for its clients.
+type Descriptor struct {
+	types.BackendInfo
+
+	NewClient   func() Client
+	NewProducer func() Producer
+	NewConsumer func() Consumer
+	NewAdmin    func() Admin
+}
+
+var registry = struct {
+	sync.RWMutex
+	backends map[types.MQType]*Descriptor
+}{backends: make(map[types.MQType]*Descriptor)}
+
+// Register makes a backend available under its type. It is meant to be called
+// from the init function of the backend's package, and panics if the type is
+// empty, a constructor is missing, or the type is already registered.
+//
+// The capabilities of the optional admin interfaces implemented by the
+// backend's client are added to those declared in the descriptor.
+func Register(d Descriptor) {
+	if d.Type == "" {
+		panic("mq: Register called with an empty backend type")
+	}
+	if d.NewClient == nil || d.NewProducer == nil || d.NewConsumer == nil || d.NewAdmin == nil {
+		panic(fmt.Sprintf("mq: Register called without all constructors for backend %s", d.Type))
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, dup := registry.backends[d.Type]; dup {
+		panic(fmt.Sprintf("mq: Register called twice for backend %s", d.Type))
+	}
+	if d.Fields == nil {
+		d.Fields = []types.ConfigField{}
+	}
+	if d.Capabilities == nil {
+		d.Capabilities = []types.Capability{}
+	}
+	for _, c := range discoverCapabilities(d.NewClient()) {
+		if !d.Has(c) {
+			d.Capabilities = append(d.Capabilities, c)
+		}
+	}
+	registry.backends[d.Type] = &d
+}
+
+// Lookup returns the descriptor registered for mqType.
+func Lookup(mqType types.MQType) (*Descriptor, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	d, ok := registry.backends[mqType]
+	return d, ok
+}
+
+// Backends returns the metadata of all registered backends, ordered by Order
+// and then by type.
+func Backends() []types.BackendInfo {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	backends := make([]types.BackendInfo, 0, len(registry.backends))
+	for _, d := range registry.backends {
+		backends = append(backends, d.BackendInfo)
+	}
+	sort.Slice(backends, func(i, j int) bool {
+		if backends[i].Order != backends[j].Order {
+			return backends[i].Order < backends[j].Order
+		}
+		return backends[i].Type < backends[j].Type
+	})
+	return backends
+}
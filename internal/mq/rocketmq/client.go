@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
 	"mq-toolkit/pkg/types"
 	"mq-toolkit/pkg/utils"
+	"strings"
 	"time"
 
 	"github.com/apache/rocketmq-client-go/v2"
@@ -83,10 +85,6 @@ func (c *Client) DeleteTopic(ctx context.Context, topic string) error {
 	return c.admin.DeleteTopic(ctx, topic)
 }
 
-func (c *Client) ListConsumerGroups(ctx context.Context) ([]types.ConsumerGroup, error) {
-	return c.admin.ListConsumerGroups(ctx)
-}
-
 // Close 关闭客户端
 func (c *Client) Close() error {
 	var errs []error
@@ -110,6 +108,14 @@ func (c *Client) IsConnected() bool {
 	return c.producer.IsConnected() && c.consumer.IsConnected()
 }
 
+// Ping 确认生产者和消费者仍在运行，并探测 NameServer 是否可达
+func (c *Client) Ping(ctx context.Context) error {
+	if !c.IsConnected() {
+		return utils.NewConnectionError("RocketMQ producer or consumer is not running", nil)
+	}
+	return c.admin.(mq.Pinger).Ping(ctx)
+}
+
 // Producer RocketMQ生产者实现
 type Producer struct {
 	producer rocketmq.Producer
@@ -126,6 +132,9 @@ func (p *Producer) Connect(ctx context.Context, config *types.ConnectionConfig)
 	if config.Type != types.MQTypeRocketMQ {
 		return utils.NewValidationError("Invalid MQ type for RocketMQ producer", string(config.Type))
 	}
+	if err := checkTunnel(config); err != nil {
+		return err
+	}
 	p.config = config
 
 	// 生成唯一的生产者组名，避免重复创建错误
@@ -133,8 +142,9 @@ func (p *Producer) Connect(ctx context.Context, config *types.ConnectionConfig)
 		config.Host, config.Port, time.Now().UnixNano())
 
 	opts := []producer.Option{
-		producer.WithNameServer([]string{fmt.Sprintf("%s:%d", config.Host, config.Port)}),
+		producer.WithNameServer(config.Addresses()),
 		producer.WithGroupName(groupName), // 设置唯一的生产者组名
+		producer.WithSendMsgTimeout(config.Timeouts.ProduceTimeout()),
 	}
 	if config.Username != "" && config.Password != "" {
 		opts = append(opts, producer.WithCredentials(primitive.Credentials{
@@ -149,7 +159,11 @@ func (p *Producer) Connect(ctx context.Context, config *types.ConnectionConfig)
 		return utils.NewConnectionError("Failed to create RocketMQ producer", err)
 	}
 
-	return p.producer.Start()
+	if err := startWithTimeout(ctx, config.Timeouts.DialTimeout(), "Starting RocketMQ producer", p.producer.Start, p.producer.Shutdown); err != nil {
+		p.producer = nil
+		return err
+	}
+	return nil
 }
 
 // Produce 发送单条消息
@@ -167,8 +181,11 @@ func (p *Producer) Produce(ctx context.Context, req *types.ProduceRequest) error
 		msg.WithProperty(k, v)
 	}
 
+	timeout := p.config.Timeouts.ProduceTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 	_, err := p.producer.SendSync(ctx, msg)
-	return err
+	return utils.WrapTimeout(ctx, err, "Producing to RocketMQ", timeout)
 }
 
 // ProduceBatch 批量发送消息
@@ -190,14 +207,19 @@ func (p *Producer) ProduceBatch(ctx context.Context, reqs []*types.ProduceReques
 		msgs[i] = msg
 	}
 
+	timeout := p.config.Timeouts.ProduceTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 	_, err := p.producer.SendSync(ctx, msgs...)
-	return err
+	return utils.WrapTimeout(ctx, err, "Producing to RocketMQ", timeout)
 }
 
 // Close 关闭生产者
 func (p *Producer) Close() error {
 	if p.producer != nil {
-		return p.producer.Shutdown()
+		err := p.producer.Shutdown()
+		p.producer = nil
+		return err
 	}
 	return nil
 }
@@ -225,6 +247,9 @@ func (c *Consumer) Connect(ctx context.Context, config *types.ConnectionConfig)
 	if config.Type != types.MQTypeRocketMQ {
 		return utils.NewValidationError("Invalid MQ type for RocketMQ consumer", string(config.Type))
 	}
+	if err := checkTunnel(config); err != nil {
+		return err
+	}
 	c.config = config
 	return nil // No connection needed until subscribe
 }
@@ -238,7 +263,7 @@ func (c *Consumer) Subscribe(ctx context.Context, req *types.ConsumeRequest) err
 	}
 
 	opts := []consumer.Option{
-		consumer.WithNameServer([]string{fmt.Sprintf("%s:%d", c.config.Host, c.config.Port)}),
+		consumer.WithNameServer(c.config.Addresses()),
 		consumer.WithGroupName(groupID),
 	}
 	if c.config.Username != "" && c.config.Password != "" {
@@ -312,7 +337,10 @@ func (c *Consumer) Consume(ctx context.Context, handler mq.MessageHandler) error
 	}
 
 	// 启动消费者
-	if err := c.consumer.Start(); err != nil {
+	if err := startWithTimeout(ctx, c.config.Timeouts.DialTimeout(), "Starting RocketMQ consumer", c.consumer.Start, c.consumer.Shutdown); err != nil {
+		if utils.IsErrorType(err, utils.ErrorTypeTimeout) {
+			return err
+		}
 		return utils.NewSubscriptionError("Failed to start consumer", err)
 	}
 
@@ -324,7 +352,9 @@ func (c *Consumer) Consume(ctx context.Context, handler mq.MessageHandler) error
 // Close 关闭消费者
 func (c *Consumer) Close() error {
 	if c.consumer != nil {
-		return c.consumer.Shutdown()
+		err := c.consumer.Shutdown()
+		c.consumer = nil
+		return err
 	}
 	return nil
 }
@@ -350,11 +380,13 @@ func (a *Admin) Connect(ctx context.Context, config *types.ConnectionConfig) err
 	if config.Type != types.MQTypeRocketMQ {
 		return utils.NewValidationError("Invalid MQ type for RocketMQ admin", string(config.Type))
 	}
+	if err := checkTunnel(config); err != nil {
+		return err
+	}
 	a.config = config
 
-	endPoint := []string{fmt.Sprintf("%s:%d", config.Host, config.Port)}
 	var err error
-	a.admin, err = admin.NewAdmin(admin.WithResolver(primitive.NewPassthroughResolver(endPoint)))
+	a.admin, err = admin.NewAdmin(admin.WithResolver(primitive.NewPassthroughResolver(config.Addresses())))
 	if err != nil {
 		return utils.NewConnectionError("Failed to create RocketMQ admin client", err)
 	}
@@ -395,7 +427,7 @@ func (a *Admin) TestConnection(ctx context.Context) *types.TestResult {
 		// 如果admin客户端创建成功，认为连接配置正确
 		return &types.TestResult{
 			Success: true,
-			Message: fmt.Sprintf("RocketMQ admin client created successfully for %s:%d", a.config.Host, a.config.Port),
+			Message: fmt.Sprintf("RocketMQ admin client created successfully for %s", strings.Join(a.config.Addresses(), ",")),
 			Latency: time.Since(start).Milliseconds(),
 		}
 	}
@@ -407,6 +439,17 @@ func (a *Admin) TestConnection(ctx context.Context) *types.TestResult {
 	}
 }
 
+// Ping 向 NameServer 查询主题路由列表，确认 NameServer 可达并能响应请求
+func (a *Admin) Ping(ctx context.Context) error {
+	if a.admin == nil || a.config == nil {
+		return utils.NewConnectionError("Not connected to RocketMQ", nil)
+	}
+	if _, err := a.admin.FetchAllTopicList(ctx); err != nil {
+		return utils.NewConnectionError("RocketMQ NameServer did not answer the route query", err)
+	}
+	return nil
+}
+
 // ListTopics 列出所有主题
 func (a *Admin) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
 	if a.admin == nil {
@@ -414,9 +457,7 @@ func (a *Admin) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
 	}
 
 	// RocketMQ v2 admin API 不支持直接列出所有主题
-	// 这是当前admin API的限制，需要通过其他方式获取主题信息
-	// 作为替代方案，返回空列表而不是错误，这样不会阻止应用程序运行
-	return []types.TopicInfo{}, nil
+	return nil, utils.NewUnsupportedError("Listing topics", string(types.MQTypeRocketMQ))
 }
 
 // CreateTopic 创建主题
@@ -429,10 +470,14 @@ func (a *Admin) CreateTopic(ctx context.Context, topic string, partitions int32,
 	// 使用默认的broker端口10911
 	brokerAddr := fmt.Sprintf("%s:10911", a.config.Host)
 
-	return a.admin.CreateTopic(ctx,
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := a.admin.CreateTopic(ctx,
 		admin.WithTopicCreate(topic),
 		admin.WithBrokerAddrCreate(brokerAddr),
 	)
+	return utils.WrapTimeout(ctx, err, "Creating topic", timeout)
 }
 
 // DeleteTopic 删除主题
@@ -445,18 +490,14 @@ func (a *Admin) DeleteTopic(ctx context.Context, topic string) error {
 	// 使用默认的broker端口10911
 	brokerAddr := fmt.Sprintf("%s:10911", a.config.Host)
 
-	return a.admin.DeleteTopic(ctx,
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := a.admin.DeleteTopic(ctx,
 		admin.WithTopicDelete(topic),
 		admin.WithBrokerAddrDelete(brokerAddr),
 	)
-}
-
-// ListConsumerGroups 列出消费组
-func (a *Admin) ListConsumerGroups(ctx context.Context) ([]types.ConsumerGroup, error) {
-	// RocketMQ admin API 不提供直接列出所有消费组的方法
-	// 这需要更复杂的实现，可能需要通过查询主题来获取
-	// 作为替代方案，返回空列表而不是错误，这样不会阻止应用程序运行
-	return []types.ConsumerGroup{}, nil
+	return utils.WrapTimeout(ctx, err, "Deleting topic", timeout)
 }
 
 // Close 关闭连接
@@ -466,3 +507,36 @@ func (a *Admin) Close() error {
 	}
 	return nil
 }
+
+// checkTunnel RocketMQ 客户端不支持自定义拨号，配置了隧道或地址映射时返回错误
+func checkTunnel(config *types.ConnectionConfig) error {
+	if tunnel.Enabled(config.Tunnel) {
+		return utils.NewConfigError("RocketMQ does not support SSH tunnels or proxies",
+			"the RocketMQ client cannot use a custom dialer, forward the NameServer and broker ports instead")
+	}
+	return nil
+}
+
+// startWithTimeout 在超时内启动生产者或消费者，rocketmq 客户端的 Start 不支持 context，NameServer 不可达时可能长时间阻塞
+//
+// 超时后不再等待，Start 最终成功时在后台调用 shutdown 释放资源。
+func startWithTimeout(ctx context.Context, timeout time.Duration, operation string, start, shutdown func() error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- start()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		go func() {
+			if <-done == nil {
+				shutdown()
+			}
+		}()
+		return utils.WrapTimeout(ctx, ctx.Err(), operation, timeout)
+	}
+}
@@ -0,0 +1,28 @@
+package rocketmq
+
+import (
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+)
+
+// init 注册 RocketMQ 后端
+func init() {
+	mq.Register(mq.Descriptor{
+		BackendInfo: types.BackendInfo{
+			Type:          types.MQTypeRocketMQ,
+			DisplayName:   "Apache RocketMQ",
+			Order:         30,
+			DefaultHost:   "127.0.0.1",
+			DefaultPort:   9876,
+			EndpointLabel: "其他 NameServer",
+			TunnelHelp:    "RocketMQ 客户端不支持自定义拨号，无法使用隧道、代理和地址映射。请在本机将 NameServer 和各 broker 的端口转发到可达地址，并让 broker 公告该地址（brokerIP1）后直连",
+			Capabilities: []types.Capability{
+				types.CapabilityEndpoints, types.CapabilityUserPassword, types.CapabilityMultiTopic,
+			},
+		},
+		NewClient:   NewClient,
+		NewProducer: NewProducer,
+		NewConsumer: NewConsumer,
+		NewAdmin:    NewAdmin,
+	})
+}
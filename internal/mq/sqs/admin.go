@@ -0,0 +1,324 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Admin SQS管理客户端实现
+//
+// 主题对应队列，队列名以 .fifo 结尾时创建 FIFO 队列。队列属性、清空队列和死信策略通过
+// mq.QueueAttributeAdmin 提供。
+type Admin struct {
+	sqs    *sqs.Client
+	queues *queueURLs
+	config *types.ConnectionConfig
+	tunnel *tunnel.Tunnel
+}
+
+// NewAdmin 创建SQS管理客户端
+func NewAdmin() mq.Admin {
+	return &Admin{}
+}
+
+// Connect 创建SQS客户端，请求在调用管理操作时才发出
+func (a *Admin) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	if config.Type != types.MQTypeSQS {
+		return utils.NewValidationError("Invalid MQ type for SQS admin", string(config.Type))
+	}
+
+	t, err := tunnel.OpenIfEnabled(config.Tunnel)
+	if err != nil {
+		return utils.NewConnectionError("Failed to open tunnel", err)
+	}
+
+	client, err := newSQSClient(config, t)
+	if err != nil {
+		t.Close()
+		return err
+	}
+
+	a.sqs = client
+	a.queues = newQueueURLs(client)
+	a.config = config
+	a.tunnel = t
+	return nil
+}
+
+// TestConnection 测试连接，列出一个队列以验证端点和凭证
+func (a *Admin) TestConnection(ctx context.Context) *types.TestResult {
+	start := time.Now()
+
+	if a.sqs == nil {
+		return &types.TestResult{
+			Success: false,
+			Message: "Not connected to SQS",
+			Latency: time.Since(start).Milliseconds(),
+		}
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if _, err := a.sqs.ListQueues(ctx, &sqs.ListQueuesInput{MaxResults: aws.Int32(1)}); err != nil {
+		err = utils.WrapTimeout(ctx, apiFailure("Failed to list queues", err, ""), "Listing queues", timeout)
+		return &types.TestResult{
+			Success: false,
+			Message: fmt.Sprintf("Failed to connect to SQS: %v", err),
+			Latency: time.Since(start).Milliseconds(),
+		}
+	}
+
+	return &types.TestResult{
+		Success: true,
+		Message: fmt.Sprintf("Connected successfully to SQS (%s)", region(a.config)),
+		Latency: time.Since(start).Milliseconds(),
+	}
+}
+
+// Ping 列出一个队列，端点不可达或凭证无效时返回错误
+func (a *Admin) Ping(ctx context.Context) error {
+	if a.sqs == nil {
+		return utils.NewConnectionError("Not connected to SQS", nil)
+	}
+	if _, err := a.sqs.ListQueues(ctx, &sqs.ListQueuesInput{MaxResults: aws.Int32(1)}); err != nil {
+		return apiFailure("SQS is unreachable", err, "")
+	}
+	return nil
+}
+
+// ListTopics 列出队列
+func (a *Admin) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
+	if a.sqs == nil {
+		return nil, utils.NewConnectionError("Not connected to SQS", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var urls []string
+	paginator := sqs.NewListQueuesPaginator(a.sqs, &sqs.ListQueuesInput{MaxResults: aws.Int32(1000)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, utils.WrapTimeout(ctx, apiFailure("Failed to list queues", err, ""), "Listing queues", timeout)
+		}
+		urls = append(urls, page.QueueUrls...)
+	}
+
+	names := sortedQueueNames(urls)
+	topics := make([]types.TopicInfo, 0, len(names))
+	for _, name := range names {
+		topics = append(topics, types.TopicInfo{
+			Name:       name,
+			Partitions: 1, // 队列没有分区概念
+			Replicas:   1,
+		})
+	}
+	return topics, nil
+}
+
+// CreateTopic 创建队列，名称以 .fifo 结尾时创建 FIFO 队列；partitions 和 replicas 不适用
+func (a *Admin) CreateTopic(ctx context.Context, topic string, partitions int32, replicas int16) error {
+	if a.sqs == nil {
+		return utils.NewConnectionError("Not connected to SQS", nil)
+	}
+	if topic == "" {
+		return utils.NewValidationError("Queue name is required", "")
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	input := &sqs.CreateQueueInput{QueueName: aws.String(topic)}
+	if isFIFO(topic) {
+		input.Attributes = map[string]string{string(sqstypes.QueueAttributeNameFifoQueue): "true"}
+	}
+	if _, err := a.sqs.CreateQueue(ctx, input); err != nil {
+		return utils.WrapTimeout(ctx, apiFailure("Failed to create queue "+topic, err, ""), "Creating queue", timeout)
+	}
+	return nil
+}
+
+// DeleteTopic 删除队列
+func (a *Admin) DeleteTopic(ctx context.Context, topic string) error {
+	if a.sqs == nil {
+		return utils.NewConnectionError("Not connected to SQS", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	url, err := a.queues.get(ctx, topic)
+	if err != nil {
+		return utils.WrapTimeout(ctx, err, "Deleting queue", timeout)
+	}
+	if _, err := a.sqs.DeleteQueue(ctx, &sqs.DeleteQueueInput{QueueUrl: aws.String(url)}); err != nil {
+		return utils.WrapTimeout(ctx, apiFailure("Failed to delete queue "+topic, err, topic), "Deleting queue", timeout)
+	}
+	a.queues.forget(topic)
+	return nil
+}
+
+// GetQueueAttributes 查询队列的全部属性，并解析消息数和死信策略
+func (a *Admin) GetQueueAttributes(ctx context.Context, queue string) (*types.QueueAttributes, error) {
+	if a.sqs == nil {
+		return nil, utils.NewConnectionError("Not connected to SQS", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	url, err := a.queues.get(ctx, queue)
+	if err != nil {
+		return nil, utils.WrapTimeout(ctx, err, "Getting queue attributes", timeout)
+	}
+	out, err := a.sqs.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(url),
+		AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNameAll},
+	})
+	if err != nil {
+		return nil, utils.WrapTimeout(ctx, apiFailure("Failed to get attributes of queue "+queue, err, queue), "Getting queue attributes", timeout)
+	}
+	return newQueueAttributes(url, out.Attributes), nil
+}
+
+// newQueueAttributes 由 GetQueueAttributes 返回的属性构造队列属性
+func newQueueAttributes(url string, attrs map[string]string) *types.QueueAttributes {
+	count := func(name sqstypes.QueueAttributeName) int64 {
+		n, _ := strconv.ParseInt(attrs[string(name)], 10, 64)
+		return n
+	}
+	info := &types.QueueAttributes{
+		Name:       queueName(url),
+		URL:        url,
+		ARN:        attrs[string(sqstypes.QueueAttributeNameQueueArn)],
+		FIFO:       attrs[string(sqstypes.QueueAttributeNameFifoQueue)] == "true",
+		Messages:   count(sqstypes.QueueAttributeNameApproximateNumberOfMessages),
+		InFlight:   count(sqstypes.QueueAttributeNameApproximateNumberOfMessagesNotVisible),
+		Delayed:    count(sqstypes.QueueAttributeNameApproximateNumberOfMessagesDelayed),
+		Attributes: attrs,
+	}
+	if policy := attrs[string(sqstypes.QueueAttributeNameRedrivePolicy)]; policy != "" {
+		info.Redrive = parseRedrivePolicy(policy)
+	}
+	return info
+}
+
+// parseRedrivePolicy 解析 RedrivePolicy 属性，maxReceiveCount 可能是数字或字符串
+func parseRedrivePolicy(policy string) *types.RedrivePolicy {
+	var raw struct {
+		DeadLetterTargetARN string          `json:"deadLetterTargetArn"`
+		MaxReceiveCount     json.RawMessage `json:"maxReceiveCount"`
+	}
+	if err := json.Unmarshal([]byte(policy), &raw); err != nil {
+		return nil
+	}
+	redrive := &types.RedrivePolicy{DeadLetterTargetARN: raw.DeadLetterTargetARN}
+	count, err := strconv.Unquote(string(raw.MaxReceiveCount))
+	if err != nil {
+		count = string(raw.MaxReceiveCount)
+	}
+	redrive.MaxReceiveCount, _ = strconv.Atoi(count)
+	return redrive
+}
+
+// SetQueueAttributes 修改队列属性，例如 VisibilityTimeout、MessageRetentionPeriod 和 RedrivePolicy
+func (a *Admin) SetQueueAttributes(ctx context.Context, queue string, attributes map[string]string) error {
+	if a.sqs == nil {
+		return utils.NewConnectionError("Not connected to SQS", nil)
+	}
+	if len(attributes) == 0 {
+		return utils.NewValidationError("No queue attributes specified", queue)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	url, err := a.queues.get(ctx, queue)
+	if err != nil {
+		return utils.WrapTimeout(ctx, err, "Setting queue attributes", timeout)
+	}
+	if _, err := a.sqs.SetQueueAttributes(ctx, &sqs.SetQueueAttributesInput{QueueUrl: aws.String(url), Attributes: attributes}); err != nil {
+		return utils.WrapTimeout(ctx, apiFailure("Failed to set attributes of queue "+queue, err, queue), "Setting queue attributes", timeout)
+	}
+	return nil
+}
+
+// PurgeQueue 删除队列中的全部消息，SQS 每 60 秒只允许清空一次
+func (a *Admin) PurgeQueue(ctx context.Context, queue string) error {
+	if a.sqs == nil {
+		return utils.NewConnectionError("Not connected to SQS", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	url, err := a.queues.get(ctx, queue)
+	if err != nil {
+		return utils.WrapTimeout(ctx, err, "Purging queue", timeout)
+	}
+	if _, err := a.sqs.PurgeQueue(ctx, &sqs.PurgeQueueInput{QueueUrl: aws.String(url)}); err != nil {
+		return utils.WrapTimeout(ctx, apiFailure("Failed to purge queue "+queue, err, queue), "Purging queue", timeout)
+	}
+	return nil
+}
+
+// ListDeadLetterSources 列出 RedrivePolicy 指向该队列的源队列名，按名称排序
+func (a *Admin) ListDeadLetterSources(ctx context.Context, queue string) ([]string, error) {
+	if a.sqs == nil {
+		return nil, utils.NewConnectionError("Not connected to SQS", nil)
+	}
+
+	timeout := a.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	url, err := a.queues.get(ctx, queue)
+	if err != nil {
+		return nil, utils.WrapTimeout(ctx, err, "Listing dead-letter source queues", timeout)
+	}
+
+	var urls []string
+	paginator := sqs.NewListDeadLetterSourceQueuesPaginator(a.sqs, &sqs.ListDeadLetterSourceQueuesInput{
+		QueueUrl:   aws.String(url),
+		MaxResults: aws.Int32(1000),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, utils.WrapTimeout(ctx, apiFailure("Failed to list dead-letter source queues of "+queue, err, queue),
+				"Listing dead-letter source queues", timeout)
+		}
+		urls = append(urls, page.QueueUrls...)
+	}
+	return sortedQueueNames(urls), nil
+}
+
+// Close 关闭管理客户端
+func (a *Admin) Close() error {
+	a.sqs = nil
+	a.queues = nil
+	a.config = nil
+	a.tunnel.Close()
+	a.tunnel = nil
+	return nil
+}
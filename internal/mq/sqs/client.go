@@ -0,0 +1,450 @@
+package sqs
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"net"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/aws/smithy-go"
+)
+
+// 发送参数和系统属性对应的消息头名称。
+// 发送时这些消息头写入对应参数，其余消息头作为 String 类型的消息属性；
+// 消费时系统属性和消息属性都放入消息头，ReceiveCountHeader 和 SequenceNumberHeader 只在消费时出现。
+const (
+	GroupIDHeader         = "Sqs-Message-Group-Id"
+	DeduplicationIDHeader = "Sqs-Message-Deduplication-Id"
+	DelaySecondsHeader    = "Sqs-Delay-Seconds"
+	ReceiveCountHeader    = "Sqs-Approximate-Receive-Count"
+	SequenceNumberHeader  = "Sqs-Sequence-Number"
+	SubjectHeader         = "Sns-Subject" // 只用于发布到 SNS 主题
+)
+
+// defaultRegion 未配置区域时使用的区域，ElasticMQ 和 LocalStack 默认使用该区域
+const defaultRegion = "us-east-1"
+
+// snsARNPrefix 以该前缀开头的主题作为 SNS 主题 ARN 发布
+const snsARNPrefix = "arn:aws:sns:"
+
+// Client SQS完整客户端实现
+type Client struct {
+	producer mq.Producer
+	consumer mq.Consumer
+	admin    mq.Admin
+	config   *types.ConnectionConfig
+}
+
+// NewClient 创建SQS完整客户端
+func NewClient() mq.Client {
+	return &Client{
+		producer: NewProducer(),
+		consumer: NewConsumer(),
+		admin:    NewAdmin(),
+	}
+}
+
+// Connect 连接到SQS
+func (c *Client) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	c.config = config
+
+	if err := c.producer.Connect(ctx, config); err != nil {
+		return err
+	}
+
+	if err := c.consumer.Connect(ctx, config); err != nil {
+		c.producer.Close()
+		return err
+	}
+
+	if err := c.admin.Connect(ctx, config); err != nil {
+		c.producer.Close()
+		c.consumer.Close()
+		return err
+	}
+
+	return nil
+}
+
+// Produce 发送消息
+func (c *Client) Produce(ctx context.Context, req *types.ProduceRequest) error {
+	return c.producer.Produce(ctx, req)
+}
+
+// ProduceBatch 批量发送消息
+func (c *Client) ProduceBatch(ctx context.Context, reqs []*types.ProduceRequest) error {
+	return c.producer.ProduceBatch(ctx, reqs)
+}
+
+// Subscribe 订阅队列
+func (c *Client) Subscribe(ctx context.Context, req *types.ConsumeRequest) error {
+	return c.consumer.Subscribe(ctx, req)
+}
+
+// Consume 消费消息
+func (c *Client) Consume(ctx context.Context, handler mq.MessageHandler) error {
+	return c.consumer.Consume(ctx, handler)
+}
+
+// TestConnection 测试连接
+func (c *Client) TestConnection(ctx context.Context) *types.TestResult {
+	return c.admin.TestConnection(ctx)
+}
+
+// ListTopics 列出队列
+func (c *Client) ListTopics(ctx context.Context) ([]types.TopicInfo, error) {
+	return c.admin.ListTopics(ctx)
+}
+
+// CreateTopic 创建队列
+func (c *Client) CreateTopic(ctx context.Context, topic string, partitions int32, replicas int16) error {
+	return c.admin.CreateTopic(ctx, topic, partitions, replicas)
+}
+
+// DeleteTopic 删除队列
+func (c *Client) DeleteTopic(ctx context.Context, topic string) error {
+	return c.admin.DeleteTopic(ctx, topic)
+}
+
+// GetQueueAttributes 查询队列属性
+func (c *Client) GetQueueAttributes(ctx context.Context, queue string) (*types.QueueAttributes, error) {
+	return c.admin.(mq.QueueAttributeAdmin).GetQueueAttributes(ctx, queue)
+}
+
+// SetQueueAttributes 修改队列属性
+func (c *Client) SetQueueAttributes(ctx context.Context, queue string, attributes map[string]string) error {
+	return c.admin.(mq.QueueAttributeAdmin).SetQueueAttributes(ctx, queue, attributes)
+}
+
+// PurgeQueue 清空队列
+func (c *Client) PurgeQueue(ctx context.Context, queue string) error {
+	return c.admin.(mq.QueueAttributeAdmin).PurgeQueue(ctx, queue)
+}
+
+// ListDeadLetterSources 列出以该队列为死信队列的源队列
+func (c *Client) ListDeadLetterSources(ctx context.Context, queue string) ([]string, error) {
+	return c.admin.(mq.QueueAttributeAdmin).ListDeadLetterSources(ctx, queue)
+}
+
+// Close 关闭客户端
+func (c *Client) Close() error {
+	var lastErr error
+
+	if err := c.producer.Close(); err != nil {
+		lastErr = err
+	}
+
+	if err := c.consumer.Close(); err != nil {
+		lastErr = err
+	}
+
+	if err := c.admin.Close(); err != nil {
+		lastErr = err
+	}
+
+	return lastErr
+}
+
+// IsConnected 检查连接状态
+func (c *Client) IsConnected() bool {
+	return c.producer.IsConnected() && c.consumer.IsConnected()
+}
+
+// Ping 通过管理客户端探测 SQS 端点是否可达
+func (c *Client) Ping(ctx context.Context) error {
+	return c.admin.(mq.Pinger).Ping(ctx)
+}
+
+// endpointURL 返回 SQS 端点地址，取第一个节点，Extra["tls"] 为 true 时使用 https
+func endpointURL(config *types.ConnectionConfig) (string, error) {
+	addresses := config.Addresses()
+	if len(addresses) == 0 {
+		return "", utils.NewValidationError("SQS endpoint is required", config.Host)
+	}
+	scheme := "http"
+	if config.Extra["tls"] == "true" {
+		scheme = "https"
+	}
+	return scheme + "://" + addresses[0], nil
+}
+
+// snsEndpointURL 返回 SNS 端点地址
+//
+// 默认与 SQS 端点相同（LocalStack 在同一端口提供全部服务），主机名以 sqs. 开头时替换为 sns.（AWS 区域端点）；
+// Extra["sns_endpoint"] 可以指定其他地址。
+func snsEndpointURL(config *types.ConnectionConfig, endpoint string) string {
+	if u := config.Extra["sns_endpoint"]; u != "" {
+		return strings.TrimRight(u, "/")
+	}
+	scheme, host, _ := strings.Cut(endpoint, "://")
+	if strings.HasPrefix(host, "sqs.") {
+		host = "sns." + strings.TrimPrefix(host, "sqs.")
+	}
+	return scheme + "://" + host
+}
+
+// httpClient 创建 HTTP 客户端，t 不为 nil 时经隧道连接，Extra["tls_insecure"] 为 true 时跳过证书校验
+//
+// 客户端不设整体超时，长轮询接收依赖调用方的 context 结束。
+func httpClient(config *types.ConnectionConfig, t *tunnel.Tunnel) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.Extra["tls_insecure"] == "true" {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	if t != nil {
+		transport.Proxy = nil
+		transport.DialContext = t.DialContext
+	} else {
+		dialer := &net.Dialer{Timeout: config.Timeouts.DialTimeout(), KeepAlive: 30 * time.Second}
+		transport.DialContext = dialer.DialContext
+	}
+	return &http.Client{Transport: transport}
+}
+
+// credentialsProvider 返回静态凭证，未配置访问密钥时不签名请求（ElasticMQ 不校验签名）
+func credentialsProvider(config *types.ConnectionConfig) aws.CredentialsProvider {
+	if config.AccessKey == "" {
+		return aws.AnonymousCredentials{}
+	}
+	return credentials.NewStaticCredentialsProvider(config.AccessKey, config.SecretKey, config.SessionToken)
+}
+
+// region 返回配置的区域，为空时使用 us-east-1
+func region(config *types.ConnectionConfig) string {
+	if config.Region != "" {
+		return config.Region
+	}
+	return defaultRegion
+}
+
+// newSQSClient 创建 SQS 客户端，端点为配置的主机和端口
+func newSQSClient(config *types.ConnectionConfig, t *tunnel.Tunnel) (*sqs.Client, error) {
+	endpoint, err := endpointURL(config)
+	if err != nil {
+		return nil, err
+	}
+	return sqs.New(sqs.Options{
+		Region:       region(config),
+		BaseEndpoint: aws.String(endpoint),
+		Credentials:  credentialsProvider(config),
+		HTTPClient:   httpClient(config, t),
+	}), nil
+}
+
+// newSNSClient 创建 SNS 客户端，用于发布到主题 ARN
+func newSNSClient(config *types.ConnectionConfig, t *tunnel.Tunnel) (*sns.Client, error) {
+	endpoint, err := endpointURL(config)
+	if err != nil {
+		return nil, err
+	}
+	return sns.New(sns.Options{
+		Region:       region(config),
+		BaseEndpoint: aws.String(snsEndpointURL(config, endpoint)),
+		Credentials:  credentialsProvider(config),
+		HTTPClient:   httpClient(config, t),
+	}), nil
+}
+
+// queueURLs 缓存队列名对应的 URL，队列名已经是 URL 时直接使用
+type queueURLs struct {
+	client *sqs.Client
+	mu     sync.Mutex
+	urls   map[string]string
+}
+
+func newQueueURLs(client *sqs.Client) *queueURLs {
+	return &queueURLs{client: client, urls: make(map[string]string)}
+}
+
+// get 返回队列的 URL，不存在时返回未找到错误
+func (q *queueURLs) get(ctx context.Context, queue string) (string, error) {
+	if queue == "" {
+		return "", utils.NewValidationError("Queue is required", "")
+	}
+	if strings.HasPrefix(queue, "http://") || strings.HasPrefix(queue, "https://") {
+		return queue, nil
+	}
+
+	q.mu.Lock()
+	url, ok := q.urls[queue]
+	q.mu.Unlock()
+	if ok {
+		return url, nil
+	}
+
+	out, err := q.client.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{QueueName: aws.String(queue)})
+	if err != nil {
+		return "", apiFailure("Failed to get URL of queue "+queue, err, queue)
+	}
+	url = aws.ToString(out.QueueUrl)
+
+	q.mu.Lock()
+	q.urls[queue] = url
+	q.mu.Unlock()
+	return url, nil
+}
+
+// forget 删除缓存的队列 URL，队列被删除后调用
+func (q *queueURLs) forget(queue string) {
+	q.mu.Lock()
+	delete(q.urls, queue)
+	q.mu.Unlock()
+}
+
+// queueName 返回队列 URL 的最后一段，即队列名
+func queueName(url string) string {
+	return path.Base(url)
+}
+
+// isFIFO 队列名或主题 ARN 以 .fifo 结尾时为 FIFO 队列或主题
+func isFIFO(queue string) bool {
+	return strings.HasSuffix(queue, ".fifo")
+}
+
+// apiFailure 把 API 错误转换为应用错误，queue 不为空时队列不存在转换为未找到错误
+func apiFailure(message string, err error, queue string) error {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return utils.NewConnectionError(message, err)
+	}
+	code := apiErr.ErrorCode()
+	switch {
+	case queue != "" && (code == "AWS.SimpleQueueService.NonExistentQueue" || code == "QueueDoesNotExist" || code == "NotFound"):
+		return utils.NewNotFoundError("Queue", queue)
+	case strings.Contains(code, "AccessDenied") || strings.Contains(code, "Signature") || strings.Contains(code, "ClientToken") ||
+		strings.Contains(code, "Unrecognized") || strings.Contains(code, "AuthorizationError") || strings.Contains(code, "ExpiredToken"):
+		return utils.NewAuthError(message + ": " + apiErr.ErrorMessage())
+	case apiErr.ErrorFault() == smithy.FaultClient:
+		return utils.NewValidationError(message, fmt.Sprintf("%s: %s", code, apiErr.ErrorMessage()))
+	default:
+		return utils.NewConnectionError(message, err)
+	}
+}
+
+// sendParams 发送参数
+type sendParams struct {
+	groupID         *string
+	deduplicationID *string
+	delaySeconds    int32
+	subject         *string
+	attributes      map[string]string
+}
+
+// newSendParams 由发送请求解析发送参数
+//
+// 消息键作为 FIFO 队列或主题的消息组 ID，GroupIDHeader 优先于消息键；标准队列只有设置了 GroupIDHeader
+// 才发送消息组 ID。FIFO 队列缺少消息组 ID 时返回校验错误。
+func newSendParams(req *types.ProduceRequest) (*sendParams, error) {
+	if req.Topic == "" {
+		return nil, utils.NewValidationError("Queue is required", "")
+	}
+
+	params := &sendParams{attributes: make(map[string]string)}
+	fifo := isFIFO(req.Topic)
+	if req.Key != "" && fifo {
+		params.groupID = aws.String(req.Key)
+	}
+	for name, value := range req.Headers {
+		switch name {
+		case GroupIDHeader:
+			params.groupID = aws.String(value)
+		case DeduplicationIDHeader:
+			params.deduplicationID = aws.String(value)
+		case DelaySecondsHeader:
+			delay, err := strconv.ParseInt(value, 10, 32)
+			if err != nil || delay < 0 || delay > 900 {
+				return nil, utils.NewValidationError("Invalid delay seconds, expected 0-900", value)
+			}
+			params.delaySeconds = int32(delay)
+		case SubjectHeader:
+			params.subject = aws.String(value)
+		default:
+			params.attributes[name] = value
+		}
+	}
+	if fifo && params.groupID == nil {
+		return nil, utils.NewValidationError("Message group ID is required for FIFO queues, set the message key or "+GroupIDHeader, req.Topic)
+	}
+	return params, nil
+}
+
+// sqsAttributes 把消息头转换为 SQS 消息属性
+func (p *sendParams) sqsAttributes() map[string]sqstypes.MessageAttributeValue {
+	if len(p.attributes) == 0 {
+		return nil
+	}
+	attributes := make(map[string]sqstypes.MessageAttributeValue, len(p.attributes))
+	for name, value := range p.attributes {
+		attributes[name] = sqstypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+	}
+	return attributes
+}
+
+// newMessage 把接收到的 SQS 消息转换为通用消息
+//
+// 消息组 ID 作为消息键，发送时间作为时间戳，系统属性和消息属性放入消息头，Binary 类型的属性值以 base64 表示。
+func newMessage(queue string, m sqstypes.Message) *types.Message {
+	msg := &types.Message{
+		ID:        aws.ToString(m.MessageId),
+		Topic:     queue,
+		Value:     aws.ToString(m.Body),
+		Headers:   make(map[string]string),
+		Timestamp: time.Now(),
+	}
+
+	for name, value := range m.MessageAttributes {
+		switch {
+		case value.StringValue != nil:
+			msg.Headers[name] = aws.ToString(value.StringValue)
+		case value.BinaryValue != nil:
+			msg.Headers[name] = base64.StdEncoding.EncodeToString(value.BinaryValue)
+		}
+	}
+
+	attrs := m.Attributes
+	if groupID := attrs[string(sqstypes.MessageSystemAttributeNameMessageGroupId)]; groupID != "" {
+		msg.Key = groupID
+		msg.Headers[GroupIDHeader] = groupID
+	}
+	if id := attrs[string(sqstypes.MessageSystemAttributeNameMessageDeduplicationId)]; id != "" {
+		msg.Headers[DeduplicationIDHeader] = id
+	}
+	if count := attrs[string(sqstypes.MessageSystemAttributeNameApproximateReceiveCount)]; count != "" {
+		msg.Headers[ReceiveCountHeader] = count
+	}
+	if seq := attrs[string(sqstypes.MessageSystemAttributeNameSequenceNumber)]; seq != "" {
+		msg.Headers[SequenceNumberHeader] = seq
+	}
+	if sent, err := strconv.ParseInt(attrs[string(sqstypes.MessageSystemAttributeNameSentTimestamp)], 10, 64); err == nil {
+		msg.Timestamp = time.UnixMilli(sent)
+	}
+	return msg
+}
+
+// sortedQueueNames 返回队列 URL 对应的队列名，按名称排序
+func sortedQueueNames(urls []string) []string {
+	names := make([]string, 0, len(urls))
+	for _, url := range urls {
+		names = append(names, queueName(url))
+	}
+	sort.Strings(names)
+	return names
+}
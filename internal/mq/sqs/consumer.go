@@ -0,0 +1,214 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+const (
+	// defaultWaitTime 长轮询的默认等待时间（秒），也是 SQS 允许的最大值
+	defaultWaitTime = 20
+	// receiveBatchSize 每次接收的最大消息数
+	receiveBatchSize = 10
+)
+
+// Consumer SQS消费者实现
+//
+// 每个队列以长轮询接收消息，处理成功后删除消息；处理失败的消息不删除，可见性超时后重新投递，
+// 接收次数超过死信策略的 maxReceiveCount 后由 SQS 移入死信队列。SQS 队列由所有消费者共享，
+// 没有消费组的概念，GroupID 被忽略。
+type Consumer struct {
+	sqs    *sqs.Client
+	queues *queueURLs
+	config *types.ConnectionConfig
+	tunnel *tunnel.Tunnel
+	urls   []string
+}
+
+// NewConsumer 创建SQS消费者
+func NewConsumer() mq.Consumer {
+	return &Consumer{}
+}
+
+// Connect 创建SQS客户端
+func (c *Consumer) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	if config.Type != types.MQTypeSQS {
+		return utils.NewValidationError("Invalid MQ type for SQS consumer", string(config.Type))
+	}
+
+	t, err := tunnel.OpenIfEnabled(config.Tunnel)
+	if err != nil {
+		return utils.NewConnectionError("Failed to open tunnel", err)
+	}
+
+	client, err := newSQSClient(config, t)
+	if err != nil {
+		t.Close()
+		return err
+	}
+
+	c.sqs = client
+	c.queues = newQueueURLs(client)
+	c.config = config
+	c.tunnel = t
+	return nil
+}
+
+// Subscribe 解析要接收的队列，队列不存在时返回未找到错误
+func (c *Consumer) Subscribe(ctx context.Context, req *types.ConsumeRequest) error {
+	if c.sqs == nil {
+		return utils.NewConnectionError("Consumer not connected", nil)
+	}
+
+	if len(req.Topics) == 0 {
+		return utils.NewValidationError("No topics specified for subscription", "")
+	}
+
+	timeout := c.config.Timeouts.RequestTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	urls := make([]string, 0, len(req.Topics))
+	for _, queue := range req.Topics {
+		url, err := c.queues.get(ctx, queue)
+		if err != nil {
+			return utils.WrapTimeout(ctx, err, "Subscribing to SQS", timeout)
+		}
+		urls = append(urls, url)
+	}
+	c.urls = urls
+	return nil
+}
+
+// receiveOptions 返回接收参数
+//
+// Extra["wait_time"] 为长轮询等待时间（秒，0-20，默认 20），Extra["visibility_timeout"] 为接收后消息
+// 不可见的时间（秒），为空时使用队列的设置。
+func (c *Consumer) receiveOptions() (waitTime int32, visibility int32, err error) {
+	waitTime = defaultWaitTime
+	if value := c.config.Extra["wait_time"]; value != "" {
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || n < 0 || n > 20 {
+			return 0, 0, utils.NewValidationError("Invalid wait time, expected 0-20 seconds", value)
+		}
+		waitTime = int32(n)
+	}
+	if value := c.config.Extra["visibility_timeout"]; value != "" {
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || n < 0 || n > 43200 {
+			return 0, 0, utils.NewValidationError("Invalid visibility timeout, expected 0-43200 seconds", value)
+		}
+		visibility = int32(n)
+	}
+	return waitTime, visibility, nil
+}
+
+// received 接收到的消息
+type received struct {
+	msg sqstypes.Message
+	url string
+}
+
+// Consume 消费消息，所有队列的消息按到达顺序依次处理
+func (c *Consumer) Consume(ctx context.Context, handler mq.MessageHandler) error {
+	if c.sqs == nil || len(c.urls) == 0 {
+		return utils.NewConnectionError("Consumer not subscribed. Call Subscribe first.", nil)
+	}
+
+	waitTime, visibility, err := c.receiveOptions()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	msgs := make(chan received)
+	errs := make(chan error, len(c.urls))
+	var wg sync.WaitGroup
+	for _, url := range c.urls {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			input := &sqs.ReceiveMessageInput{
+				QueueUrl:                    aws.String(url),
+				MaxNumberOfMessages:         receiveBatchSize,
+				WaitTimeSeconds:             waitTime,
+				MessageAttributeNames:       []string{"All"},
+				MessageSystemAttributeNames: []sqstypes.MessageSystemAttributeName{sqstypes.MessageSystemAttributeNameAll},
+			}
+			if visibility > 0 {
+				input.VisibilityTimeout = visibility
+			}
+			for {
+				out, err := c.sqs.ReceiveMessage(ctx, input)
+				if err != nil {
+					if ctx.Err() == nil {
+						errs <- apiFailure("Failed to receive from "+queueName(url), err, queueName(url))
+					}
+					return
+				}
+				for _, m := range out.Messages {
+					select {
+					case msgs <- received{msg: m, url: url}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}(url)
+	}
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errs:
+			return err
+		case r := <-msgs:
+			queue := queueName(r.url)
+			err := handler(newMessage(queue, r.msg))
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				// 不删除消息，可见性超时后重新投递
+				continue
+			}
+			_, err = c.sqs.DeleteMessage(ctx, &sqs.DeleteMessageInput{QueueUrl: aws.String(r.url), ReceiptHandle: r.msg.ReceiptHandle})
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return utils.NewConnectionError(fmt.Sprintf("Failed to delete message from %s", queue), err)
+			}
+		}
+	}
+}
+
+// Close 关闭消费者
+func (c *Consumer) Close() error {
+	c.sqs = nil
+	c.queues = nil
+	c.urls = nil
+	c.config = nil
+	c.tunnel.Close()
+	c.tunnel = nil
+	return nil
+}
+
+// IsConnected 检查连接状态
+func (c *Consumer) IsConnected() bool {
+	return c.sqs != nil
+}
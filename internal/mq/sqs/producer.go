@@ -0,0 +1,228 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/tunnel"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// sendBatchSize SendMessageBatch 每次最多发送的消息数
+const sendBatchSize = 10
+
+// Producer SQS生产者实现
+//
+// 主题为队列名或队列 URL，以 arn:aws:sns: 开头时发布到该 SNS 主题。
+type Producer struct {
+	sqs    *sqs.Client
+	sns    *sns.Client
+	queues *queueURLs
+	config *types.ConnectionConfig
+	tunnel *tunnel.Tunnel
+}
+
+// NewProducer 创建SQS生产者
+func NewProducer() mq.Producer {
+	return &Producer{}
+}
+
+// Connect 创建SQS和SNS客户端，请求在发送时才发出
+func (p *Producer) Connect(ctx context.Context, config *types.ConnectionConfig) error {
+	if config.Type != types.MQTypeSQS {
+		return utils.NewValidationError("Invalid MQ type for SQS producer", string(config.Type))
+	}
+
+	t, err := tunnel.OpenIfEnabled(config.Tunnel)
+	if err != nil {
+		return utils.NewConnectionError("Failed to open tunnel", err)
+	}
+
+	sqsClient, err := newSQSClient(config, t)
+	if err != nil {
+		t.Close()
+		return err
+	}
+	snsClient, err := newSNSClient(config, t)
+	if err != nil {
+		t.Close()
+		return err
+	}
+
+	p.sqs = sqsClient
+	p.sns = snsClient
+	p.queues = newQueueURLs(sqsClient)
+	p.config = config
+	p.tunnel = t
+	return nil
+}
+
+// Produce 发送单条消息
+func (p *Producer) Produce(ctx context.Context, req *types.ProduceRequest) error {
+	if p.sqs == nil {
+		return utils.NewConnectionError("Producer not connected", nil)
+	}
+
+	params, err := newSendParams(req)
+	if err != nil {
+		return err
+	}
+
+	timeout := p.config.Timeouts.ProduceTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if strings.HasPrefix(req.Topic, snsARNPrefix) {
+		return utils.WrapTimeout(ctx, p.publish(ctx, req, params), "Publishing to SNS", timeout)
+	}
+
+	url, err := p.queues.get(ctx, req.Topic)
+	if err != nil {
+		return utils.WrapTimeout(ctx, err, "Producing to SQS", timeout)
+	}
+	_, err = p.sqs.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:               aws.String(url),
+		MessageBody:            aws.String(req.Value),
+		MessageAttributes:      params.sqsAttributes(),
+		MessageGroupId:         params.groupID,
+		MessageDeduplicationId: params.deduplicationID,
+		DelaySeconds:           params.delaySeconds,
+	})
+	if err != nil {
+		return utils.WrapTimeout(ctx, apiFailure("Failed to send message to "+req.Topic, err, req.Topic), "Producing to SQS", timeout)
+	}
+	return nil
+}
+
+// publish 发布消息到 SNS 主题
+func (p *Producer) publish(ctx context.Context, req *types.ProduceRequest, params *sendParams) error {
+	var attributes map[string]snstypes.MessageAttributeValue
+	if len(params.attributes) > 0 {
+		attributes = make(map[string]snstypes.MessageAttributeValue, len(params.attributes))
+		for name, value := range params.attributes {
+			attributes[name] = snstypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+		}
+	}
+	_, err := p.sns.Publish(ctx, &sns.PublishInput{
+		TopicArn:               aws.String(req.Topic),
+		Message:                aws.String(req.Value),
+		Subject:                params.subject,
+		MessageAttributes:      attributes,
+		MessageGroupId:         params.groupID,
+		MessageDeduplicationId: params.deduplicationID,
+	})
+	if err != nil {
+		return apiFailure("Failed to publish message to "+req.Topic, err, "")
+	}
+	return nil
+}
+
+// ProduceBatch 批量发送消息
+//
+// 连续发往同一队列的消息按每批 10 条通过 SendMessageBatch 发送，SNS 主题的消息逐条发布；
+// 遇到失败立即返回，此前的批次已发送。
+func (p *Producer) ProduceBatch(ctx context.Context, reqs []*types.ProduceRequest) error {
+	if p.sqs == nil {
+		return utils.NewConnectionError("Producer not connected", nil)
+	}
+
+	if len(reqs) == 0 {
+		return utils.NewValidationError("Empty message batch", "")
+	}
+
+	params := make([]*sendParams, 0, len(reqs))
+	for _, req := range reqs {
+		param, err := newSendParams(req)
+		if err != nil {
+			return err
+		}
+		params = append(params, param)
+	}
+
+	timeout := p.config.Timeouts.ProduceTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for start := 0; start < len(reqs); {
+		topic := reqs[start].Topic
+		if strings.HasPrefix(topic, snsARNPrefix) {
+			if err := p.publish(ctx, reqs[start], params[start]); err != nil {
+				return utils.WrapTimeout(ctx, err, "Publishing to SNS", timeout)
+			}
+			start++
+			continue
+		}
+
+		end := start + 1
+		for end < len(reqs) && end-start < sendBatchSize && reqs[end].Topic == topic {
+			end++
+		}
+		if err := p.sendBatch(ctx, reqs[start:end], params[start:end], start, len(reqs)); err != nil {
+			return utils.WrapTimeout(ctx, err, "Producing to SQS", timeout)
+		}
+		start = end
+	}
+	return nil
+}
+
+// sendBatch 把发往同一队列的最多 10 条消息作为一批发送，offset 为第一条消息在整个批次中的下标
+func (p *Producer) sendBatch(ctx context.Context, reqs []*types.ProduceRequest, params []*sendParams, offset, total int) error {
+	topic := reqs[0].Topic
+	url, err := p.queues.get(ctx, topic)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]sqstypes.SendMessageBatchRequestEntry, 0, len(reqs))
+	for i, req := range reqs {
+		entries = append(entries, sqstypes.SendMessageBatchRequestEntry{
+			Id:                     aws.String(strconv.Itoa(offset + i)),
+			MessageBody:            aws.String(req.Value),
+			MessageAttributes:      params[i].sqsAttributes(),
+			MessageGroupId:         params[i].groupID,
+			MessageDeduplicationId: params[i].deduplicationID,
+			DelaySeconds:           params[i].delaySeconds,
+		})
+	}
+	out, err := p.sqs.SendMessageBatch(ctx, &sqs.SendMessageBatchInput{QueueUrl: aws.String(url), Entries: entries})
+	if err != nil {
+		return apiFailure(fmt.Sprintf("Failed to send messages %d-%d of %d to %s", offset+1, offset+len(reqs), total, topic), err, topic)
+	}
+	if len(out.Failed) > 0 {
+		failed := out.Failed[0]
+		index, _ := strconv.Atoi(aws.ToString(failed.Id))
+		message := fmt.Sprintf("Failed to send message %d of %d to %s (%d failed in batch)", index+1, total, topic, len(out.Failed))
+		reason := fmt.Sprintf("%s: %s", aws.ToString(failed.Code), aws.ToString(failed.Message))
+		if !failed.SenderFault {
+			return utils.NewConnectionError(message, errors.New(reason))
+		}
+		return utils.NewValidationError(message, reason)
+	}
+	return nil
+}
+
+// Close 关闭生产者
+func (p *Producer) Close() error {
+	p.sqs = nil
+	p.sns = nil
+	p.queues = nil
+	p.config = nil
+	p.tunnel.Close()
+	p.tunnel = nil
+	return nil
+}
+
+// IsConnected 检查连接状态
+func (p *Producer) IsConnected() bool {
+	return p.sqs != nil
+}
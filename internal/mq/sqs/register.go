@@ -0,0 +1,38 @@
+package sqs
+
+import (
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+)
+
+// init 注册 SQS 后端
+func init() {
+	mq.Register(mq.Descriptor{
+		BackendInfo: types.BackendInfo{
+			Type:          types.MQTypeSQS,
+			DisplayName:   "Amazon SQS / SNS",
+			Order:         90,
+			DefaultHost:   "localhost",
+			DefaultPort:   9324,
+			EndpointLabel: "其他端点（只使用第一个）",
+			SettingsTitle: "SQS 设置",
+			SettingsHelp:  "主机和端口为 SQS 端点（ElasticMQ 默认 9324，LocalStack 为 4566，AWS 为 sqs.区域.amazonaws.com:443 并启用 HTTPS）；主题填写 arn:aws:sns: 开头的 ARN 时发布到 SNS 主题",
+			Fields: []types.ConfigField{
+				{Key: "wait_time", Label: "长轮询等待秒数（默认 20）", Kind: "number"},
+				{Key: "visibility_timeout", Label: "可见性超时秒数（默认使用队列设置）", Kind: "number"},
+				{Key: "sns_endpoint", Label: "SNS 端点（默认与 SQS 端点相同）", Kind: "text", Mono: true},
+				{Key: "tls", Label: "使用 HTTPS", Kind: "bool"},
+				{Key: "tls_insecure", Label: "跳过证书校验", Kind: "bool", ShowIf: "tls"},
+			},
+			ConsumeHint:     "长轮询接收，处理成功后删除消息；队列由所有消费者共享，不使用消费组和起始位置，未删除的消息在可见性超时后重新投递",
+			CreateTopicHint: "名称以 .fifo 结尾时创建 FIFO 队列；可见性超时、死信策略等属性可在创建后通过“属性”修改",
+			Capabilities: []types.Capability{
+				types.CapabilityTunnel, types.CapabilityEndpoints, types.CapabilityAWSCredentials, types.CapabilityListTopics, types.CapabilityMultiTopic,
+			},
+		},
+		NewClient:   NewClient,
+		NewProducer: NewProducer,
+		NewConsumer: NewConsumer,
+		NewAdmin:    NewAdmin,
+	})
+}
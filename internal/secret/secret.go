@@ -0,0 +1,363 @@
+// Package secret 对本地存储的敏感信息进行加密
+//
+// 密钥保存在密钥文件中：默认模式下密钥文件直接保存随机生成的密钥；
+// 设置主密码后密钥文件只保存 Argon2id 的盐和校验值，密钥由主密码派生，启动后需先解锁。
+// 密文格式为 "enc:v1:" + base64(nonce || AES-256-GCM 密文)。
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// prefix 加密值的前缀
+	prefix = "enc:v1:"
+
+	keyFileName    = "secret.key"
+	keyFileVersion = 1
+	keySize        = 32
+
+	modeKeyFile    = "keyfile"
+	modePassphrase = "passphrase"
+)
+
+var (
+	// ErrLocked 使用主密码但尚未解锁
+	ErrLocked = errors.New("secret store is locked, unlock it with the master passphrase")
+	// ErrWrongPassphrase 主密码错误
+	ErrWrongPassphrase = errors.New("wrong master passphrase")
+)
+
+// argonParams Argon2id 参数，随密钥文件保存以便日后调整
+type argonParams struct {
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"` // KiB
+	Threads uint8  `json:"threads"`
+}
+
+var defaultArgonParams = argonParams{Time: 3, Memory: 64 * 1024, Threads: 4}
+
+// keyFile 密钥文件内容
+type keyFile struct {
+	Version  int          `json:"version"`
+	Mode     string       `json:"mode"`
+	Key      string       `json:"key,omitempty"`      // keyfile 模式下的密钥
+	Salt     string       `json:"salt,omitempty"`     // passphrase 模式下的盐
+	Params   *argonParams `json:"params,omitempty"`   // passphrase 模式下的派生参数
+	Verifier string       `json:"verifier,omitempty"` // passphrase 模式下用于校验密码的密文
+}
+
+// verifierPlaintext 用于校验主密码的已知明文
+const verifierPlaintext = "mq-toolkit"
+
+// Key 一份可用于加解密的密钥及其密钥文件内容
+type Key struct {
+	file keyFile
+	aead cipher.AEAD
+}
+
+// Store 管理当前密钥，可被多个 goroutine 共享
+type Store struct {
+	mu   sync.RWMutex
+	path string
+	file keyFile
+	key  *Key // 为 nil 表示尚未解锁
+}
+
+// Open 打开 dir 下的密钥文件，不存在时生成随机密钥
+func Open(dir string) (*Store, error) {
+	s := &Store{path: filepath.Join(dir, keyFileName)}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		key, err := NewKey("")
+		if err != nil {
+			return nil, err
+		}
+		if err := s.Commit(key); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &s.file); err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %w", s.path, err)
+	}
+	if s.file.Version > keyFileVersion {
+		return nil, fmt.Errorf("unsupported key file version %d", s.file.Version)
+	}
+	if s.file.Mode == modeKeyFile {
+		raw, err := base64.StdEncoding.DecodeString(s.file.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key in %s: %w", s.path, err)
+		}
+		if s.key, err = newKey(s.file, raw); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// NewKey 生成新密钥，passphrase 为空时使用随机密钥（密钥文件模式），否则由主密码派生
+func NewKey(passphrase string) (*Key, error) {
+	if passphrase == "" {
+		raw := make([]byte, keySize)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, fmt.Errorf("failed to generate key: %w", err)
+		}
+		file := keyFile{Version: keyFileVersion, Mode: modeKeyFile, Key: base64.StdEncoding.EncodeToString(raw)}
+		return newKey(file, raw)
+	}
+
+	key, info, err := NewPassphraseKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	key.file = keyFile{
+		Version:  keyFileVersion,
+		Mode:     modePassphrase,
+		Salt:     info.Salt,
+		Params:   &argonParams{Time: info.Time, Memory: info.Memory, Threads: info.Threads},
+		Verifier: info.Verifier,
+	}
+	return key, nil
+}
+
+// PassphraseInfo 由主密码重新派生密钥所需的参数，不含任何秘密，可随导出文件保存
+type PassphraseInfo struct {
+	Salt     string `json:"salt"`
+	Time     uint32 `json:"time"`
+	Memory   uint32 `json:"memory"`
+	Threads  uint8  `json:"threads"`
+	Verifier string `json:"verifier"`
+}
+
+// NewPassphraseKey 使用随机盐从主密码派生新密钥
+func NewPassphraseKey(passphrase string) (*Key, *PassphraseInfo, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	params := defaultArgonParams
+	key, err := newKey(keyFile{}, deriveKey(passphrase, salt, params))
+	if err != nil {
+		return nil, nil, err
+	}
+	info := &PassphraseInfo{
+		Salt:    base64.StdEncoding.EncodeToString(salt),
+		Time:    params.Time,
+		Memory:  params.Memory,
+		Threads: params.Threads,
+	}
+	if info.Verifier, err = key.Encrypt(verifierPlaintext); err != nil {
+		return nil, nil, err
+	}
+	return key, info, nil
+}
+
+// OpenPassphraseKey 按 info 从主密码重新派生密钥，密码错误时返回 ErrWrongPassphrase
+func OpenPassphraseKey(passphrase string, info *PassphraseInfo) (*Key, error) {
+	salt, err := base64.StdEncoding.DecodeString(info.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt: %w", err)
+	}
+	params := argonParams{Time: info.Time, Memory: info.Memory, Threads: info.Threads}
+	key, err := newKey(keyFile{}, deriveKey(passphrase, salt, params))
+	if err != nil {
+		return nil, err
+	}
+	check, err := key.Decrypt(info.Verifier)
+	if err != nil || subtle.ConstantTimeCompare([]byte(check), []byte(verifierPlaintext)) != 1 {
+		return nil, ErrWrongPassphrase
+	}
+	return key, nil
+}
+
+func newKey(file keyFile, raw []byte) (*Key, error) {
+	if len(raw) != keySize {
+		return nil, fmt.Errorf("invalid key length %d", len(raw))
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{file: file, aead: aead}, nil
+}
+
+func deriveKey(passphrase string, salt []byte, p argonParams) []byte {
+	return argon2.IDKey([]byte(passphrase), salt, p.Time, p.Memory, p.Threads, keySize)
+}
+
+// Encrypt 加密明文，空字符串原样返回
+//
+// 明文即使以密文前缀开头也会加密，避免这样的凭据以明文保存后在解密时失败；
+// 调用方不能把已加密的值再次传入，需要保留已保存的密文时应直接使用原值。
+func (k *Key) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return plaintext, nil
+	}
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := k.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密密文，未加密的值（旧版本保存的明文）原样返回
+func (k *Key) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, prefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %w", err)
+	}
+	nonceSize := k.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", fmt.Errorf("invalid encrypted value: too short")
+	}
+	plaintext, err := k.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// Unlock 使用主密码解锁，密钥文件模式下无需解锁
+func (s *Store) Unlock(passphrase string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file.Mode != modePassphrase {
+		return nil
+	}
+	if s.file.Params == nil {
+		return fmt.Errorf("invalid key file %s", s.path)
+	}
+	key, err := OpenPassphraseKey(passphrase, &PassphraseInfo{
+		Salt:     s.file.Salt,
+		Time:     s.file.Params.Time,
+		Memory:   s.file.Params.Memory,
+		Threads:  s.file.Params.Threads,
+		Verifier: s.file.Verifier,
+	})
+	if err != nil {
+		return err
+	}
+	key.file = s.file
+	s.key = key
+	return nil
+}
+
+// Locked 是否需要主密码解锁
+func (s *Store) Locked() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.key == nil
+}
+
+// UsesPassphrase 是否使用主密码派生密钥
+func (s *Store) UsesPassphrase() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.file.Mode == modePassphrase
+}
+
+// Current 返回当前密钥，未解锁时返回 ErrLocked
+func (s *Store) Current() (*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.key == nil {
+		return nil, ErrLocked
+	}
+	return s.key, nil
+}
+
+// Encrypt 使用当前密钥加密，规则与 Key.Encrypt 相同
+func (s *Store) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return plaintext, nil
+	}
+	key, err := s.Current()
+	if err != nil {
+		return "", err
+	}
+	return key.Encrypt(plaintext)
+}
+
+// Decrypt 使用当前密钥解密
+func (s *Store) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	key, err := s.Current()
+	if err != nil {
+		return "", err
+	}
+	return key.Decrypt(value)
+}
+
+// Commit 将 key 写入密钥文件并作为当前密钥，写入通过临时文件重命名完成
+func (s *Store) Commit(key *Key) error {
+	data, err := json.MarshalIndent(key.file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create key directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace key file: %w", err)
+	}
+
+	s.mu.Lock()
+	s.file = key.file
+	s.key = key
+	s.mu.Unlock()
+	return nil
+}
+
+// IsEncrypted 判断值是否为本包生成的密文
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// sensitiveKeyParts Extra 中名称包含这些片段的项视为凭据
+var sensitiveKeyParts = []string{"password", "passwd", "secret", "token", "credential", "seed", "apikey", "api_key", "access_key", "private_key"}
+
+// IsSensitiveKey 判断 Extra 中的配置项是否为凭据
+func IsSensitiveKey(name string) bool {
+	lower := strings.ToLower(name)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+	return false
+}
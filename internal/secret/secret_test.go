@@ -0,0 +1,150 @@
+package secret
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestKeyRoundTrip(t *testing.T) {
+	key, err := NewKey("")
+	if err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+
+	for _, plaintext := range []string{"s3cret", "密码", prefix + "looks-encrypted", strings.Repeat("x", 4096)} {
+		ciphertext, err := key.Encrypt(plaintext)
+		if err != nil {
+			t.Fatalf("Encrypt(%q) failed: %v", plaintext, err)
+		}
+		if !IsEncrypted(ciphertext) || ciphertext == plaintext {
+			t.Fatalf("Encrypt(%q) returned %q, want a ciphertext", plaintext, ciphertext)
+		}
+		got, err := key.Decrypt(ciphertext)
+		if err != nil || got != plaintext {
+			t.Fatalf("Decrypt returned %q, %v; want %q", got, err, plaintext)
+		}
+	}
+
+	// 同一明文每次使用不同的 nonce
+	first, _ := key.Encrypt("s3cret")
+	second, _ := key.Encrypt("s3cret")
+	if first == second {
+		t.Fatal("encrypting the same plaintext twice returned the same ciphertext")
+	}
+}
+
+func TestKeyPassthrough(t *testing.T) {
+	key, err := NewKey("")
+	if err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+	if got, err := key.Encrypt(""); err != nil || got != "" {
+		t.Fatalf("Encrypt of an empty string returned %q, %v", got, err)
+	}
+	// 旧版本保存的明文原样返回
+	if got, err := key.Decrypt("legacy"); err != nil || got != "legacy" {
+		t.Fatalf("Decrypt of a plaintext value returned %q, %v", got, err)
+	}
+}
+
+func TestDecryptWithWrongKey(t *testing.T) {
+	key, err := NewKey("")
+	if err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+	other, err := NewKey("")
+	if err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+
+	ciphertext, err := key.Encrypt("s3cret")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if _, err := other.Decrypt(ciphertext); err == nil {
+		t.Fatal("Decrypt with another key succeeded")
+	}
+
+	// 篡改或截断的密文
+	tampered := ciphertext[:len(ciphertext)-4] + "AAA="
+	for _, value := range []string{tampered, prefix + "AAAA", prefix + "not base64!"} {
+		if _, err := key.Decrypt(value); err == nil {
+			t.Errorf("Decrypt(%q) succeeded", value)
+		}
+	}
+}
+
+func TestPassphraseKey(t *testing.T) {
+	key, info, err := NewPassphraseKey("correct horse")
+	if err != nil {
+		t.Fatalf("NewPassphraseKey failed: %v", err)
+	}
+	ciphertext, err := key.Encrypt("s3cret")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	reopened, err := OpenPassphraseKey("correct horse", info)
+	if err != nil {
+		t.Fatalf("OpenPassphraseKey failed: %v", err)
+	}
+	if got, err := reopened.Decrypt(ciphertext); err != nil || got != "s3cret" {
+		t.Fatalf("Decrypt with the re-derived key returned %q, %v", got, err)
+	}
+
+	if _, err := OpenPassphraseKey("wrong horse", info); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("OpenPassphraseKey with a wrong passphrase returned %v, want ErrWrongPassphrase", err)
+	}
+}
+
+func TestStorePassphrase(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if store.Locked() || store.UsesPassphrase() {
+		t.Fatal("a new store should use an unlocked key file")
+	}
+
+	key, err := NewKey("correct horse")
+	if err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+	if err := store.Commit(key); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	ciphertext, err := store.Encrypt("s3cret")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	// 重新打开后需要主密码解锁
+	store, err = Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if !store.Locked() || !store.UsesPassphrase() {
+		t.Fatal("a passphrase store should be locked after reopening")
+	}
+	if _, err := store.Decrypt(ciphertext); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Decrypt on a locked store returned %v, want ErrLocked", err)
+	}
+	if _, err := store.Encrypt("s3cret"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Encrypt on a locked store returned %v, want ErrLocked", err)
+	}
+
+	if err := store.Unlock("wrong horse"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("Unlock with a wrong passphrase returned %v, want ErrWrongPassphrase", err)
+	}
+	if !store.Locked() {
+		t.Fatal("store was unlocked by a wrong passphrase")
+	}
+	if err := store.Unlock("correct horse"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if got, err := store.Decrypt(ciphertext); err != nil || got != "s3cret" {
+		t.Fatalf("Decrypt after unlocking returned %q, %v", got, err)
+	}
+}
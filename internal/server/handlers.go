@@ -0,0 +1,585 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// sseKeepAlive SSE 心跳间隔，防止代理关闭空闲连接
+const sseKeepAlive = 15 * time.Second
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/backends", s.listBackends)
+	mux.HandleFunc("GET /api/connections", s.listConnections)
+	mux.HandleFunc("POST /api/connections", s.createConnection)
+	mux.HandleFunc("PUT /api/connections/{id}", s.updateConnection)
+	mux.HandleFunc("DELETE /api/connections/{id}", s.deleteConnection)
+	mux.HandleFunc("POST /api/connections/{id}/test", s.testConnection)
+	mux.HandleFunc("GET /api/connections/{id}/capabilities", s.capabilities)
+	mux.HandleFunc("GET /api/connections/{id}/topics", s.listTopics)
+	mux.HandleFunc("POST /api/connections/{id}/topics", s.createTopic)
+	mux.HandleFunc("DELETE /api/connections/{id}/topics/{topic}", s.deleteTopic)
+	mux.HandleFunc("GET /api/connections/{id}/topics/{topic}/messages", s.browse)
+	mux.HandleFunc("GET /api/connections/{id}/groups", s.listConsumerGroups)
+	mux.HandleFunc("POST /api/connections/{id}/groups/{group}/seek", s.seekGroup)
+	mux.HandleFunc("GET /api/connections/{id}/exchanges", s.listExchanges)
+	mux.HandleFunc("POST /api/connections/{id}/exchanges", s.createExchange)
+	mux.HandleFunc("DELETE /api/connections/{id}/exchanges/{exchange}", s.deleteExchange)
+	mux.HandleFunc("GET /api/connections/{id}/exchanges/{exchange}/bindings", s.listBindings)
+	mux.HandleFunc("POST /api/connections/{id}/exchanges/{exchange}/bindings", s.bindQueue)
+	mux.HandleFunc("DELETE /api/connections/{id}/exchanges/{exchange}/bindings", s.unbindQueue)
+	mux.HandleFunc("GET /api/statuses", s.listStatuses)
+
+	mux.HandleFunc("POST /api/produce", s.produce)
+	mux.HandleFunc("GET /api/consume", s.consume)
+	mux.HandleFunc("GET /api/events", s.events)
+
+	mux.HandleFunc("GET /api/history", s.listHistory)
+	mux.HandleFunc("DELETE /api/history", s.clearHistory)
+
+	mux.HandleFunc("GET /api/templates", s.listTemplates)
+	mux.HandleFunc("POST /api/templates", s.createTemplate)
+	mux.HandleFunc("GET /api/templates/{id}", s.getTemplate)
+	mux.HandleFunc("PUT /api/templates/{id}", s.updateTemplate)
+	mux.HandleFunc("DELETE /api/templates/{id}", s.deleteTemplate)
+	mux.HandleFunc("POST /api/templates/render", s.renderTemplate)
+	mux.HandleFunc("POST /api/templates/produce", s.produceRendered)
+
+	return s.authenticate(mux)
+}
+
+func (s *Server) listBackends(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.app.ListBackends())
+}
+
+func (s *Server) listConnections(w http.ResponseWriter, r *http.Request) {
+	connections, err := s.app.GetConfigService().ListConnections(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, connections)
+}
+
+func (s *Server) createConnection(w http.ResponseWriter, r *http.Request) {
+	var config types.ConnectionConfig
+	if err := readJSON(w, r, &config); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.app.GetConfigService().CreateConnection(r.Context(), &config); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]string{"id": config.ID})
+}
+
+func (s *Server) updateConnection(w http.ResponseWriter, r *http.Request) {
+	var config types.ConnectionConfig
+	if err := readJSON(w, r, &config); err != nil {
+		writeError(w, err)
+		return
+	}
+	config.ID = r.PathValue("id")
+	if err := s.app.GetConfigService().UpdateConnection(r.Context(), &config); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteConnection(w http.ResponseWriter, r *http.Request) {
+	if err := s.app.DeleteConnection(r.Context(), r.PathValue("id")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) testConnection(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.app.TestConnection(r.Context(), r.PathValue("id")))
+}
+
+func (s *Server) capabilities(w http.ResponseWriter, r *http.Request) {
+	capabilities, err := s.app.Capabilities(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, capabilities)
+}
+
+func (s *Server) listTopics(w http.ResponseWriter, r *http.Request) {
+	topics, err := s.app.ListTopics(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, topics)
+}
+
+func (s *Server) createTopic(w http.ResponseWriter, r *http.Request) {
+	var req types.CreateTopicRequest
+	if err := readJSON(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	req.ConnectionID = r.PathValue("id")
+	if req.Partitions <= 0 {
+		req.Partitions = 1
+	}
+	if req.Replicas <= 0 {
+		req.Replicas = 1
+	}
+	if err := s.app.CreateTopic(r.Context(), &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, &req)
+}
+
+func (s *Server) deleteTopic(w http.ResponseWriter, r *http.Request) {
+	err := s.app.DeleteTopic(r.Context(), &types.DeleteTopicRequest{
+		ConnectionID: r.PathValue("id"),
+		Topic:        r.PathValue("topic"),
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listConsumerGroups(w http.ResponseWriter, r *http.Request) {
+	groups, err := s.app.ListConsumerGroups(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, groups)
+}
+
+// browse 浏览主题中的消息，不移动消费位置，limit 查询参数限制条数
+func (s *Server) browse(w http.ResponseWriter, r *http.Request) {
+	req := &types.BrowseRequest{
+		ConnectionID: r.PathValue("id"),
+		Topic:        r.PathValue("topic"),
+	}
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			writeError(w, utils.NewValidationError("Invalid limit", value))
+			return
+		}
+		req.Limit = n
+	}
+	messages, err := s.app.Browse(r.Context(), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, messages)
+}
+
+func (s *Server) seekGroup(w http.ResponseWriter, r *http.Request) {
+	var req types.SeekGroupRequest
+	if err := readJSON(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	req.ConnectionID = r.PathValue("id")
+	req.Group = r.PathValue("group")
+	if err := s.app.SeekGroup(r.Context(), &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listExchanges(w http.ResponseWriter, r *http.Request) {
+	exchanges, err := s.app.ListExchanges(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, exchanges)
+}
+
+func (s *Server) createExchange(w http.ResponseWriter, r *http.Request) {
+	var req types.CreateExchangeRequest
+	if err := readJSON(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	req.ConnectionID = r.PathValue("id")
+	if err := s.app.CreateExchange(r.Context(), &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, &req)
+}
+
+func (s *Server) deleteExchange(w http.ResponseWriter, r *http.Request) {
+	if err := s.app.DeleteExchange(r.Context(), r.PathValue("id"), r.PathValue("exchange")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listBindings(w http.ResponseWriter, r *http.Request) {
+	bindings, err := s.app.ListBindings(r.Context(), r.PathValue("id"), r.PathValue("exchange"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, bindings)
+}
+
+// bindingRequest 读取绑定请求体，连接和交换机取自路径
+func bindingRequest(w http.ResponseWriter, r *http.Request) (*types.BindingRequest, error) {
+	var req types.BindingRequest
+	if err := readJSON(w, r, &req); err != nil {
+		return nil, err
+	}
+	req.ConnectionID = r.PathValue("id")
+	req.Exchange = r.PathValue("exchange")
+	return &req, nil
+}
+
+func (s *Server) bindQueue(w http.ResponseWriter, r *http.Request) {
+	req, err := bindingRequest(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.app.BindQueue(r.Context(), req); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, req)
+}
+
+func (s *Server) unbindQueue(w http.ResponseWriter, r *http.Request) {
+	req, err := bindingRequest(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.app.UnbindQueue(r.Context(), req); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listStatuses(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.app.ListConnectionStatuses())
+}
+
+func (s *Server) produce(w http.ResponseWriter, r *http.Request) {
+	var req types.ProduceRequest
+	if err := readJSON(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.app.ProduceMessage(r.Context(), &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// consume 开始订阅并以 SSE 推送消息，客户端断开或收到 count 条消息后停止订阅
+//
+// 查询参数：connection_id、topic（可重复）、group_id、from_beginning、start_sequence、start_time（RFC 3339）、start_message_id、count。
+func (s *Server) consume(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &types.ConsumeRequest{
+		ConnectionID:   query.Get("connection_id"),
+		Topics:         query["topic"],
+		GroupID:        query.Get("group_id"),
+		AutoCommit:     true,
+		FromBeginning:  query.Get("from_beginning") == "true",
+		StartMessageID: query.Get("start_message_id"),
+	}
+	if req.ConnectionID == "" || len(req.Topics) == 0 {
+		writeError(w, utils.NewValidationError("connection_id and topic are required", ""))
+		return
+	}
+	if value := query.Get("start_sequence"); value != "" {
+		seq, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeError(w, utils.NewValidationError("Invalid start_sequence", value))
+			return
+		}
+		req.StartSequence = seq
+	}
+	if value := query.Get("start_time"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeError(w, utils.NewValidationError("Invalid start_time", value))
+			return
+		}
+		req.StartTime = &t
+	}
+	count := 0
+	if value := query.Get("count"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			writeError(w, utils.NewValidationError("Invalid count", value))
+			return
+		}
+		count = n
+	}
+
+	// 先订阅事件，避免漏掉订阅建立后立即发生的错误
+	events, unsubscribe := s.hub.Subscribe()
+	defer unsubscribe()
+
+	// 消息不经过事件分发器：流写出上一条消息之前消费暂停，消息在交给流之后才被确认，慢客户端不会丢消息
+	messages := make(chan *types.Message)
+	subscriptionID, err := s.app.StartConsumingTo(req, func(ctx context.Context, msg *types.Message) error {
+		select {
+		case messages <- msg:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer s.app.StopConsuming(subscriptionID)
+
+	stream, ok := newEventStream(w)
+	if !ok {
+		return
+	}
+	stream.send("subscribed", map[string]string{"subscription_id": subscriptionID})
+
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+	received := 0
+	for count == 0 || received < count {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			stream.comment("keep-alive")
+		case msg := <-messages:
+			stream.send("message:received", msg)
+			received++
+		case ev := <-events:
+			if detail, ok := ev.Data.(map[string]string); ok && ev.Name == "consumer:error" && detail["subscriptionId"] == subscriptionID {
+				stream.send(ev.Name, detail)
+				return
+			}
+		}
+	}
+}
+
+// events 以 SSE 推送所有服务事件，例如 connection:status、bridge:status、benchmark:progress
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	events, unsubscribe := s.hub.Subscribe()
+	defer unsubscribe()
+
+	stream, ok := newEventStream(w)
+	if !ok {
+		return
+	}
+	s.stream(r, stream, events, func(ev Event) bool {
+		stream.send(ev.Name, ev.Data)
+		return true
+	})
+}
+
+// stream 将事件交给 handle 处理直到客户端断开、服务关闭或 handle 返回 false，空闲时发送心跳
+func (s *Server) stream(r *http.Request, stream *eventStream, events <-chan Event, handle func(Event) bool) {
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			stream.comment("keep-alive")
+		case ev := <-events:
+			if !handle(ev) {
+				return
+			}
+		}
+	}
+}
+
+func (s *Server) listHistory(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := pagination(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	records, err := s.app.GetHistoryService().GetRecords(r.Context(), limit, offset)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, records)
+}
+
+func (s *Server) clearHistory(w http.ResponseWriter, r *http.Request) {
+	if err := s.app.GetHistoryService().ClearRecords(r.Context()); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listTemplates(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	templates, err := s.app.GetTemplateService().FindTemplates(r.Context(), query.Get("collection_id"), query.Get("tag"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, templates)
+}
+
+func (s *Server) getTemplate(w http.ResponseWriter, r *http.Request) {
+	template, err := s.app.GetTemplateService().GetTemplate(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, template)
+}
+
+func (s *Server) createTemplate(w http.ResponseWriter, r *http.Request) {
+	var template types.MessageTemplate
+	if err := readJSON(w, r, &template); err != nil {
+		writeError(w, err)
+		return
+	}
+	created, err := s.app.GetTemplateService().CreateTemplate(r.Context(), &template)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) updateTemplate(w http.ResponseWriter, r *http.Request) {
+	var template types.MessageTemplate
+	if err := readJSON(w, r, &template); err != nil {
+		writeError(w, err)
+		return
+	}
+	template.ID = r.PathValue("id")
+	if err := s.app.GetTemplateService().UpdateTemplate(r.Context(), &template); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteTemplate(w http.ResponseWriter, r *http.Request) {
+	if err := s.app.GetTemplateService().DeleteTemplate(r.Context(), r.PathValue("id")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) renderTemplate(w http.ResponseWriter, r *http.Request) {
+	var req types.RenderRequest
+	if err := readJSON(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	rendered, err := s.app.GetTemplateService().Render(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rendered)
+}
+
+func (s *Server) produceRendered(w http.ResponseWriter, r *http.Request) {
+	var req types.RenderProduceRequest
+	if err := readJSON(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	result, err := s.app.ProduceRendered(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// pagination 解析 limit 和 offset 查询参数，limit 默认 100
+func pagination(r *http.Request) (int, int, error) {
+	limit, offset := 100, 0
+	query := r.URL.Query()
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return 0, 0, utils.NewValidationError("Invalid limit", value)
+		}
+		limit = n
+	}
+	if value := query.Get("offset"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return 0, 0, utils.NewValidationError("Invalid offset", value)
+		}
+		offset = n
+	}
+	return limit, offset, nil
+}
+
+// eventStream SSE 输出
+type eventStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+// newEventStream 写入 SSE 响应头，ResponseWriter 不支持刷新时输出错误并返回 false
+func newEventStream(w http.ResponseWriter) (*eventStream, bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, utils.NewInternalError("Streaming is not supported", nil))
+		return nil, false
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	return &eventStream{w: w, flusher: flusher}, true
+}
+
+// send 发送一个事件，数据编码为单行 JSON
+func (e *eventStream) send(name string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		payload, _ = json.Marshal(map[string]string{"error": err.Error()})
+	}
+	fmt.Fprintf(e.w, "event: %s\ndata: %s\n\n", name, payload)
+	e.flusher.Flush()
+}
+
+// comment 发送注释行，用作心跳
+func (e *eventStream) comment(text string) {
+	fmt.Fprintf(e.w, ": %s\n\n", text)
+	e.flusher.Flush()
+}
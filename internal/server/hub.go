@@ -0,0 +1,49 @@
+package server
+
+import "sync"
+
+// hubBuffer 每个订阅者的事件缓冲，缓冲满时丢弃该订阅者的新事件，避免慢客户端阻塞服务
+const hubBuffer = 256
+
+// Event 服务推送的事件
+type Event struct {
+	Name string
+	Data interface{}
+}
+
+// Hub 将服务事件分发给 SSE 订阅者，Emit 可直接作为 service.EventEmitter 使用
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+}
+
+// NewHub 创建事件分发器
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[chan Event]struct{})}
+}
+
+// Emit 分发事件，不会阻塞
+func (h *Hub) Emit(name string, data interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- Event{Name: name, Data: data}:
+		default:
+		}
+	}
+}
+
+// Subscribe 订阅事件，使用完毕后调用返回的取消函数
+func (h *Hub) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, hubBuffer)
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subscribers, ch)
+		h.mu.Unlock()
+	}
+}
@@ -0,0 +1,182 @@
+// Package server 以本地 HTTP API 暴露 AppService 的操作
+//
+// 接口使用 JSON，消费和服务事件通过 SSE 推送。所有请求都需要携带访问令牌，
+// 默认只监听本机地址，供测试脚本和其他工具在桌面端运行时或无界面的容器中调用。
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mq-toolkit/internal/logger"
+	"mq-toolkit/internal/service"
+	"mq-toolkit/pkg/utils"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// shutdownTimeout 关闭服务时等待进行中请求的时间
+const shutdownTimeout = 5 * time.Second
+
+// Server 本地 HTTP API 服务
+type Server struct {
+	app      *service.AppService
+	logger   *logger.Logger
+	hub      *Hub
+	token    string
+	http     *http.Server
+	listener net.Listener
+	cancel   context.CancelFunc // 结束 SSE 等长连接
+}
+
+// New 创建 HTTP API 服务，token 不能为空
+func New(app *service.AppService, logger *logger.Logger, hub *Hub, token string) *Server {
+	s := &Server{app: app, logger: logger, hub: hub, token: token}
+	s.http = &http.Server{
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// Start 监听 addr 并在后台提供服务
+func (s *Server) Start(addr string) error {
+	if s.token == "" {
+		return utils.NewConfigError("API token is required", "")
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return utils.NewNetworkError("Failed to listen on "+addr, err)
+	}
+	s.listener = listener
+
+	baseCtx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.http.BaseContext = func(net.Listener) context.Context { return baseCtx }
+
+	go func() {
+		if err := s.http.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Server", fmt.Sprintf("HTTP API server stopped: %v", err))
+		}
+	}()
+	s.logger.Info("Server", fmt.Sprintf("HTTP API listening on http://%s", listener.Addr()))
+	return nil
+}
+
+// Addr 返回实际监听的地址
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Close 结束长连接并关闭服务
+func (s *Server) Close() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return s.http.Shutdown(ctx)
+}
+
+// LoadToken 读取令牌文件，不存在时生成随机令牌并写入
+func LoadToken(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read API token: %w", err)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate API token: %w", err)
+	}
+	token := hex.EncodeToString(raw)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create token directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to write API token: %w", err)
+	}
+	return token, nil
+}
+
+// authenticate 校验 Authorization: Bearer 头，EventSource 无法设置请求头，也接受 token 查询参数
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			token = r.URL.Query().Get("token")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mq-toolkit"`)
+			writeError(w, utils.NewAuthError("Invalid or missing API token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// errorResponse 错误响应
+type errorResponse struct {
+	Type    utils.ErrorType `json:"type"`
+	Code    string          `json:"code,omitempty"`
+	Message string          `json:"message"`
+	Details string          `json:"details,omitempty"`
+}
+
+// errorStatus 按错误类型返回 HTTP 状态码
+var errorStatus = map[utils.ErrorType]int{
+	utils.ErrorTypeValidation:   http.StatusBadRequest,
+	utils.ErrorTypeAuth:         http.StatusUnauthorized,
+	utils.ErrorTypeNotFound:     http.StatusNotFound,
+	utils.ErrorTypeTimeout:      http.StatusGatewayTimeout,
+	utils.ErrorTypeConnection:   http.StatusBadGateway,
+	utils.ErrorTypeNetwork:      http.StatusBadGateway,
+	utils.ErrorTypeSubscription: http.StatusBadGateway,
+	utils.ErrorTypeUnsupported:  http.StatusNotImplemented,
+}
+
+// writeError 输出错误，AppError 按类型映射状态码，其他错误返回 500
+func writeError(w http.ResponseWriter, err error) {
+	resp := errorResponse{Type: utils.ErrorTypeInternal, Message: err.Error()}
+	status := http.StatusInternalServerError
+
+	var appErr *utils.AppError
+	if errors.As(err, &appErr) {
+		resp = errorResponse{Type: appErr.Type, Code: appErr.Code, Message: appErr.Message, Details: appErr.Details}
+		if code, ok := errorStatus[appErr.Type]; ok {
+			status = code
+		}
+	}
+	writeJSON(w, status, map[string]errorResponse{"error": resp})
+}
+
+// writeJSON 以 JSON 输出 v
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// readJSON 解析请求体
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<20))
+	if err := decoder.Decode(v); err != nil {
+		return utils.NewValidationError("Invalid request body", err.Error())
+	}
+	return nil
+}
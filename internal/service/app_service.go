@@ -7,11 +7,19 @@ import (
 	"mq-toolkit/internal/factory"
 	"mq-toolkit/internal/logger"
 	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/secret"
+	"mq-toolkit/internal/transfer"
 	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"os"
+	"strings"
 	"sync"
 	"time"
 )
 
+// defaultImportBatchSize 导入消息时每批发送的数量
+const defaultImportBatchSize = 100
+
 // AppService 应用服务
 type AppService struct {
 	ctx             context.Context
@@ -21,16 +29,36 @@ type AppService struct {
 	historyService  *HistoryService
 	consumerService *ConsumerService
 	templateService *TemplateService
+	envService      *EnvironmentService
+	secrets         *secret.Store
+	messageService  *MessageService
+	replayService   *ReplayService
+	bridgeService   *BridgeService
+	benchService    *BenchmarkService
+	bundleService   *BundleService
+	healthService   *HealthService
 	mqFactory       factory.Factory
 	clientsMutex    sync.Mutex
 	activeClients   map[string]mq.Client
+	reconnects      map[string]*reconnectTask // 正在后台重连的客户端 [connectionID -> 任务]
+	stopMonitor     context.CancelFunc
+}
+
+// reconnectTask 后台重连任务
+type reconnectTask struct {
+	cancel context.CancelFunc
 }
 
 // NewAppService creates a new AppService
-func NewAppService(ctx context.Context, db *database.Database, logger *logger.Logger) *AppService {
-	configSvc := NewConfigService(db.GetDB())
+//
+// emit 接收各服务推送的事件，为 nil 时丢弃事件
+func NewAppService(ctx context.Context, db *database.Database, logger *logger.Logger, secrets *secret.Store, emit EventEmitter) *AppService {
+	envSvc := NewEnvironmentService(db.GetDB(), secrets)
+	configSvc := NewConfigService(db.GetDB(), secrets)
+	configSvc.SetResolver(envSvc.ResolveConnection)
 	historySvc := NewHistoryService(db.GetDB())
-	templateSvc := NewTemplateService(db.GetDB())
+	templateSvc := NewTemplateService(db.GetDB(), envSvc)
+	messageSvc := NewMessageService(db.GetDB(), db.FullTextSearch())
 
 	appService := &AppService{
 		ctx:             ctx,
@@ -39,11 +67,28 @@ func NewAppService(ctx context.Context, db *database.Database, logger *logger.Lo
 		configService:   configSvc,
 		historyService:  historySvc,
 		templateService: templateSvc,
+		envService:      envSvc,
+		secrets:         secrets,
+		messageService:  messageSvc,
+		bundleService:   NewBundleService(configSvc, templateSvc, envSvc),
+		healthService:   NewHealthService(logger, emit),
 		activeClients:   make(map[string]mq.Client),
+		reconnects:      make(map[string]*reconnectTask),
 		mqFactory:       factory.NewFactory(),
 	}
 
-	appService.consumerService = NewConsumerService(ctx, logger, appService.mqFactory, configSvc, historySvc)
+	if !secrets.Locked() {
+		appService.encryptPlaintextSecrets()
+	}
+
+	appService.consumerService = NewConsumerService(ctx, logger, emit, appService.mqFactory, configSvc, historySvc, messageSvc, appService.healthService)
+	appService.replayService = NewReplayService(ctx, logger, emit, appService.mqFactory, configSvc, historySvc, messageSvc)
+	appService.bridgeService = NewBridgeService(ctx, logger, emit, appService.mqFactory, configSvc, historySvc)
+	appService.benchService = NewBenchmarkService(ctx, logger, emit, appService.mqFactory, configSvc, historySvc, db.GetDB())
+
+	monitorCtx, stopMonitor := context.WithCancel(ctx)
+	appService.stopMonitor = stopMonitor
+	go appService.monitorClients(monitorCtx)
 
 	return appService
 }
@@ -63,6 +108,60 @@ func (s *AppService) GetTemplateService() *TemplateService {
 	return s.templateService
 }
 
+// GetEnvironmentService 获取环境服务
+func (s *AppService) GetEnvironmentService() *EnvironmentService {
+	return s.envService
+}
+
+// GetMessageService 获取消息存储服务
+func (s *AppService) GetMessageService() *MessageService {
+	return s.messageService
+}
+
+// GetReplayService 获取消息重放服务
+func (s *AppService) GetReplayService() *ReplayService {
+	return s.replayService
+}
+
+// GetBridgeService 获取桥接服务
+func (s *AppService) GetBridgeService() *BridgeService {
+	return s.bridgeService
+}
+
+// GetBenchmarkService 获取压测服务
+func (s *AppService) GetBenchmarkService() *BenchmarkService {
+	return s.benchService
+}
+
+// GetBundleService 获取共享包服务
+func (s *AppService) GetBundleService() *BundleService {
+	return s.bundleService
+}
+
+// GetHealthService 获取健康状态服务
+func (s *AppService) GetHealthService() *HealthService {
+	return s.healthService
+}
+
+// ListBackends 返回已注册的消息队列后端，按显示顺序排列
+func (s *AppService) ListBackends() []types.BackendInfo {
+	return mq.Backends()
+}
+
+// Capabilities 返回连接类型支持的能力，包括注册时声明的能力和客户端实现的管理接口
+func (s *AppService) Capabilities(ctx context.Context, connectionID string) ([]types.Capability, error) {
+	config, err := s.configService.GetConnection(ctx, connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	capabilities, ok := mq.Capabilities(config.Type)
+	if !ok {
+		return nil, utils.NewValidationError("Unsupported connection type", string(config.Type))
+	}
+	return capabilities, nil
+}
+
 // TestConnection 测试连接
 func (s *AppService) TestConnection(ctx context.Context, connectionID string) *types.TestResult {
 	start := time.Now()
@@ -116,6 +215,10 @@ func (s *AppService) TestConnection(ctx context.Context, connectionID string) *t
 func (s *AppService) ProduceMessage(ctx context.Context, req *types.ProduceRequest) error {
 	start := time.Now()
 
+	if err := s.expandProduceRequest(ctx, req); err != nil {
+		return err
+	}
+
 	config, err := s.configService.GetConnection(ctx, req.ConnectionID)
 	if err != nil {
 		s.logger.Error("AppService", fmt.Sprintf("Failed to get connection config: %v", err))
@@ -147,10 +250,106 @@ func (s *AppService) ProduceMessage(ctx context.Context, req *types.ProduceReque
 	return err
 }
 
+// GetSecretStatus 获取凭据加密状态
+func (s *AppService) GetSecretStatus() *types.SecretStatus {
+	return &types.SecretStatus{
+		Locked:         s.secrets.Locked(),
+		UsesPassphrase: s.secrets.UsesPassphrase(),
+	}
+}
+
+// UnlockSecrets 使用主密码解锁凭据
+func (s *AppService) UnlockSecrets(passphrase string) error {
+	if err := s.secrets.Unlock(passphrase); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to unlock secrets: %v", err))
+		return err
+	}
+	s.logger.Info("AppService", "Secrets unlocked")
+	s.encryptPlaintextSecrets()
+	return nil
+}
+
+// RotateSecretKey 更换加密密钥并重新加密所有凭据，passphrase 为空时改用本地密钥文件
+func (s *AppService) RotateSecretKey(ctx context.Context, passphrase string) error {
+	if err := s.configService.RotateKey(ctx, passphrase); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to rotate secret key: %v", err))
+		return err
+	}
+	mode := "key file"
+	if passphrase != "" {
+		mode = "master passphrase"
+	}
+	s.logger.Info("AppService", fmt.Sprintf("Secret key rotated, credentials are now protected by %s", mode))
+	return nil
+}
+
+// encryptPlaintextSecrets 加密旧版本以明文保存的凭据
+func (s *AppService) encryptPlaintextSecrets() {
+	count, err := s.configService.EncryptPlaintextSecrets(s.ctx)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to encrypt stored credentials: %v", err))
+		return
+	}
+	if count > 0 {
+		s.logger.Info("AppService", fmt.Sprintf("Encrypted stored credentials of %d connection(s)", count))
+	}
+
+	count, err = s.envService.EncryptPlaintextSecrets(s.ctx)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to encrypt secret variables: %v", err))
+		return
+	}
+	if count > 0 {
+		s.logger.Info("AppService", fmt.Sprintf("Encrypted secret variables of %d environment(s)", count))
+	}
+}
+
+// expandProduceRequest 使用激活环境的变量展开主题、键和消息头的值
+func (s *AppService) expandProduceRequest(ctx context.Context, req *types.ProduceRequest) error {
+	var err error
+	if req.Topic, err = s.envService.Expand(ctx, req.Topic); err != nil {
+		return fmt.Errorf("topic: %w", err)
+	}
+	if req.Key, err = s.envService.Expand(ctx, req.Key); err != nil {
+		return fmt.Errorf("key: %w", err)
+	}
+	for name, value := range req.Headers {
+		if req.Headers[name], err = s.envService.Expand(ctx, value); err != nil {
+			return fmt.Errorf("header %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ActivateEnvironment 切换激活环境，id 为空时取消激活
+//
+// 连接地址可能引用环境变量，因此切换后关闭已缓存的客户端，下次使用时按新环境重新连接。
+func (s *AppService) ActivateEnvironment(ctx context.Context, id string) error {
+	if err := s.envService.SetActiveEnvironment(ctx, id); err != nil {
+		return err
+	}
+
+	s.closeAllClients()
+	s.logger.Info("AppService", fmt.Sprintf("Activated environment: %s", id))
+	return nil
+}
+
 // StartConsuming 调用 ConsumerService 开始消费
 func (s *AppService) StartConsuming(req *types.ConsumeRequest) (string, error) {
+	return s.StartConsumingTo(req, nil)
+}
+
+// StartConsumingTo 开始消费消息，消息同时交给 sink，sink 阻塞时暂停消费
+func (s *AppService) StartConsumingTo(req *types.ConsumeRequest, sink MessageSink) (string, error) {
+	for i, topic := range req.Topics {
+		expanded, err := s.envService.Expand(s.ctx, topic)
+		if err != nil {
+			return "", err
+		}
+		req.Topics[i] = expanded
+	}
 	s.logger.Info("AppService", fmt.Sprintf("Received request to start consuming from topic(s): %v", req.Topics))
-	subscriptionID, err := s.consumerService.StartConsuming(req)
+	subscriptionID, err := s.consumerService.StartConsumingTo(req, sink)
 	if err != nil {
 		s.logger.Error("AppService", fmt.Sprintf("Failed to start consumer: %v", err))
 		return "", err
@@ -196,12 +395,28 @@ func (s *AppService) CreateTopic(ctx context.Context, req *types.CreateTopicRequ
 		return fmt.Errorf("ConnectionID is required")
 	}
 
+	topic, err := s.envService.Expand(ctx, req.Topic)
+	if err != nil {
+		return err
+	}
+	req.Topic = topic
+
 	config, err := s.configService.GetConnection(ctx, req.ConnectionID)
 	if err != nil {
 		s.logger.Error("AppService", fmt.Sprintf("Failed to get connection config: %v", err))
 		return err
 	}
 
+	// 不适用的分区数和副本数返回错误，而不是被后端忽略
+	if d, ok := mq.Lookup(config.Type); ok {
+		if req.Partitions > 1 && !d.Has(types.CapabilityTopicPartition) {
+			return utils.NewUnsupportedError("Creating partitioned topics", string(config.Type))
+		}
+		if req.Replicas > 1 && !d.Has(types.CapabilityTopicReplicas) {
+			return utils.NewUnsupportedError("Setting the replication factor", string(config.Type))
+		}
+	}
+
 	client, err := s.getOrCreateClient(ctx, req.ConnectionID, config)
 	if err != nil {
 		s.logger.Error("AppService", fmt.Sprintf("Failed to get admin client: %v", err))
@@ -225,6 +440,12 @@ func (s *AppService) DeleteTopic(ctx context.Context, req *types.DeleteTopicRequ
 		return fmt.Errorf("ConnectionID is required")
 	}
 
+	topic, err := s.envService.Expand(ctx, req.Topic)
+	if err != nil {
+		return err
+	}
+	req.Topic = topic
+
 	config, err := s.configService.GetConnection(ctx, req.ConnectionID)
 	if err != nil {
 		s.logger.Error("AppService", fmt.Sprintf("Failed to get connection config: %v", err))
@@ -247,59 +468,976 @@ func (s *AppService) DeleteTopic(ctx context.Context, req *types.DeleteTopicRequ
 	return nil
 }
 
-// getOrCreateClient 获取或创建客户端 (用于生产者/Admin)
-func (s *AppService) getOrCreateClient(ctx context.Context, connectionID string, config *types.ConnectionConfig) (mq.Client, error) {
-	s.clientsMutex.Lock()
-	defer s.clientsMutex.Unlock()
+// ListConsumerGroups 列出消费组
+func (s *AppService) ListConsumerGroups(ctx context.Context, connectionID string) ([]types.ConsumerGroup, error) {
+	admin, err := adminAs[mq.GroupAdmin](s, ctx, connectionID, "Listing consumer groups")
+	if err != nil {
+		return nil, err
+	}
 
-	if client, exists := s.activeClients[connectionID]; exists && client.IsConnected() {
-		return client, nil
+	groups, err := admin.ListConsumerGroups(ctx)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to list consumer groups: %v", err))
+		return nil, err
 	}
 
-	client, err := s.mqFactory.CreateClient(config.Type)
+	s.logger.Info("AppService", fmt.Sprintf("Listed %d consumer groups for connection %s", len(groups), connectionID))
+	return groups, nil
+}
+
+// adminAs 返回连接的客户端并断言为管理能力接口 T，连接类型不支持该能力时返回 operation 的不支持错误
+func adminAs[T any](s *AppService, ctx context.Context, connectionID, operation string) (T, error) {
+	var zero T
+	config, err := s.configService.GetConnection(ctx, connectionID)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to get connection config: %v", err))
+		return zero, err
+	}
+
+	client, err := s.getOrCreateClient(ctx, connectionID, config)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to get admin client: %v", err))
+		return zero, err
+	}
+
+	admin, ok := client.(T)
+	if !ok {
+		return zero, utils.NewUnsupportedError(operation, string(config.Type))
+	}
+	return admin, nil
+}
+
+// SeekGroup 将消费组在主题上的位置移到指定时间之后的第一条消息
+func (s *AppService) SeekGroup(ctx context.Context, req *types.SeekGroupRequest) error {
+	if req.Group == "" {
+		return utils.NewValidationError("Consumer group is required", "")
+	}
+	seeker, err := adminAs[mq.OffsetSeeker](s, ctx, req.ConnectionID, "Moving consumer group positions")
+	if err != nil {
+		return err
+	}
+
+	topic, err := s.envService.Expand(ctx, req.Topic)
+	if err != nil {
+		return err
+	}
+	if err := seeker.SeekGroup(ctx, req.Group, topic, req.Timestamp); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to seek group %s on %s: %v", req.Group, topic, err))
+		return err
+	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Moved group %s on %s to %s", req.Group, topic, req.Timestamp.Format(time.RFC3339)))
+	return nil
+}
+
+// defaultBrowseLimit 查看消息时默认返回的消息数
+const defaultBrowseLimit = 50
+
+// Browse 查看主题或队列中的消息，不确认、不删除消息，也不提交位置
+func (s *AppService) Browse(ctx context.Context, req *types.BrowseRequest) ([]types.Message, error) {
+	browser, err := adminAs[mq.Browser](s, ctx, req.ConnectionID, "Browsing messages")
 	if err != nil {
 		return nil, err
 	}
 
-	if err := client.Connect(ctx, config); err != nil {
-		client.Close()
+	topic, err := s.envService.Expand(ctx, req.Topic)
+	if err != nil {
 		return nil, err
 	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultBrowseLimit
+	}
+	messages, err := browser.Browse(ctx, topic, limit)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to browse %s: %v", topic, err))
+		return nil, err
+	}
+	return messages, nil
+}
 
-	s.activeClients[connectionID] = client
-	return client, nil
+// GetStreamInfo 查看流及其消费组、消费者详情
+func (s *AppService) GetStreamInfo(ctx context.Context, connectionID, stream string) (*types.StreamInfo, error) {
+	admin, err := adminAs[mq.StreamAdmin](s, ctx, connectionID, "Stream administration")
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := admin.StreamInfo(ctx, stream)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to get stream info of %s: %v", stream, err))
+		return nil, err
+	}
+	return info, nil
 }
 
-// CloseConnection 关闭连接
-func (s *AppService) CloseConnection(connectionID string) error {
-	s.clientsMutex.Lock()
-	defer s.clientsMutex.Unlock()
+// TrimStream 裁剪流，返回删除的条目数
+func (s *AppService) TrimStream(ctx context.Context, req *types.TrimStreamRequest) (int64, error) {
+	admin, err := adminAs[mq.StreamAdmin](s, ctx, req.ConnectionID, "Stream administration")
+	if err != nil {
+		return 0, err
+	}
 
-	if client, exists := s.activeClients[connectionID]; exists {
-		err := client.Close()
-		delete(s.activeClients, connectionID)
-		s.logger.Info("AppService", fmt.Sprintf("Closed connection: %s", connectionID))
+	removed, err := admin.TrimStream(ctx, req.Stream, req.MaxLen)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to trim stream %s: %v", req.Stream, err))
+		return 0, err
+	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Trimmed stream %s to %d entries, removed %d", req.Stream, req.MaxLen, removed))
+	return removed, nil
+}
+
+// CreateStreamGroup 在流上创建消费组
+func (s *AppService) CreateStreamGroup(ctx context.Context, req *types.StreamGroupRequest) error {
+	admin, err := adminAs[mq.StreamAdmin](s, ctx, req.ConnectionID, "Stream administration")
+	if err != nil {
+		return err
+	}
+
+	if err := admin.CreateStreamGroup(ctx, req.Stream, req.Group, req.StartID); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to create group %s on stream %s: %v", req.Group, req.Stream, err))
 		return err
 	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Created group %s on stream %s", req.Group, req.Stream))
 	return nil
 }
 
-// Shutdown 关闭应用服务
-func (s *AppService) Shutdown() error {
-	s.logger.Info("AppService", "Shutting down application service...")
-	// 停止所有消费者
-	s.consumerService.StopAllConsumers()
+// DeleteStreamGroup 删除流上的消费组
+func (s *AppService) DeleteStreamGroup(ctx context.Context, req *types.StreamGroupRequest) error {
+	admin, err := adminAs[mq.StreamAdmin](s, ctx, req.ConnectionID, "Stream administration")
+	if err != nil {
+		return err
+	}
 
-	s.clientsMutex.Lock()
-	defer s.clientsMutex.Unlock()
+	if err := admin.DeleteStreamGroup(ctx, req.Stream, req.Group); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to delete group %s on stream %s: %v", req.Group, req.Stream, err))
+		return err
+	}
 
-	for connectionID, client := range s.activeClients {
-		if err := client.Close(); err != nil {
-			s.logger.Error("AppService", fmt.Sprintf("Failed to close connection %s: %v", connectionID, err))
-		}
+	s.logger.Info("AppService", fmt.Sprintf("Deleted group %s on stream %s", req.Group, req.Stream))
+	return nil
+}
+
+// ListPendingEntries 列出流消费组中未确认的条目
+func (s *AppService) ListPendingEntries(ctx context.Context, connectionID, stream, group string, count int64) ([]types.PendingEntry, error) {
+	admin, err := adminAs[mq.StreamAdmin](s, ctx, connectionID, "Stream administration")
+	if err != nil {
+		return nil, err
 	}
 
-	s.activeClients = make(map[string]mq.Client)
+	entries, err := admin.ListPending(ctx, stream, group, count)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to list pending entries of group %s on stream %s: %v", group, stream, err))
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ClaimPendingEntries 将未确认的条目转移给指定消费者，返回成功转移的条目 ID
+func (s *AppService) ClaimPendingEntries(ctx context.Context, req *types.ClaimPendingRequest) ([]string, error) {
+	admin, err := adminAs[mq.StreamAdmin](s, ctx, req.ConnectionID, "Stream administration")
+	if err != nil {
+		return nil, err
+	}
+
+	claimed, err := admin.ClaimPending(ctx, req.Stream, req.Group, req.Consumer, time.Duration(req.MinIdle)*time.Millisecond, req.IDs)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to claim pending entries on stream %s: %v", req.Stream, err))
+		return nil, err
+	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Claimed %d of %d pending entries on stream %s for %s", len(claimed), len(req.IDs), req.Stream, req.Consumer))
+	return claimed, nil
+}
+
+// ListTenants 列出租户
+func (s *AppService) ListTenants(ctx context.Context, connectionID string) ([]string, error) {
+	admin, err := adminAs[mq.NamespaceAdmin](s, ctx, connectionID, "Namespace administration")
+	if err != nil {
+		return nil, err
+	}
+
+	tenants, err := admin.ListTenants(ctx)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to list tenants: %v", err))
+		return nil, err
+	}
+	return tenants, nil
+}
+
+// ListNamespaces 列出租户下的命名空间
+func (s *AppService) ListNamespaces(ctx context.Context, connectionID, tenant string) ([]string, error) {
+	admin, err := adminAs[mq.NamespaceAdmin](s, ctx, connectionID, "Namespace administration")
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces, err := admin.ListNamespaces(ctx, tenant)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to list namespaces of tenant %s: %v", tenant, err))
+		return nil, err
+	}
+	return namespaces, nil
+}
+
+// CreateNamespace 创建命名空间
+func (s *AppService) CreateNamespace(ctx context.Context, req *types.NamespaceRequest) error {
+	admin, err := adminAs[mq.NamespaceAdmin](s, ctx, req.ConnectionID, "Namespace administration")
+	if err != nil {
+		return err
+	}
+
+	if err := admin.CreateNamespace(ctx, req.Namespace); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to create namespace %s: %v", req.Namespace, err))
+		return err
+	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Created namespace %s", req.Namespace))
+	return nil
+}
+
+// DeleteNamespace 删除命名空间
+func (s *AppService) DeleteNamespace(ctx context.Context, req *types.NamespaceRequest) error {
+	admin, err := adminAs[mq.NamespaceAdmin](s, ctx, req.ConnectionID, "Namespace administration")
+	if err != nil {
+		return err
+	}
+
+	if err := admin.DeleteNamespace(ctx, req.Namespace); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to delete namespace %s: %v", req.Namespace, err))
+		return err
+	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Deleted namespace %s", req.Namespace))
+	return nil
+}
+
+// ListSubscriptions 列出主题上的订阅及其积压消息数
+func (s *AppService) ListSubscriptions(ctx context.Context, connectionID, topic string) ([]types.SubscriptionInfo, error) {
+	admin, err := adminAs[mq.SubscriptionAdmin](s, ctx, connectionID, "Subscription administration")
+	if err != nil {
+		return nil, err
+	}
+
+	subs, err := admin.ListSubscriptions(ctx, topic)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to list subscriptions of %s: %v", topic, err))
+		return nil, err
+	}
+	return subs, nil
+}
+
+// DeleteSubscription 删除主题上的订阅
+func (s *AppService) DeleteSubscription(ctx context.Context, req *types.SubscriptionRequest) error {
+	admin, err := adminAs[mq.SubscriptionAdmin](s, ctx, req.ConnectionID, "Subscription administration")
+	if err != nil {
+		return err
+	}
+
+	if err := admin.DeleteSubscription(ctx, req.Topic, req.Subscription); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to delete subscription %s on %s: %v", req.Subscription, req.Topic, err))
+		return err
+	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Deleted subscription %s on %s", req.Subscription, req.Topic))
+	return nil
+}
+
+// ClearBacklog 清空订阅的积压消息
+func (s *AppService) ClearBacklog(ctx context.Context, req *types.SubscriptionRequest) error {
+	admin, err := adminAs[mq.SubscriptionAdmin](s, ctx, req.ConnectionID, "Subscription administration")
+	if err != nil {
+		return err
+	}
+
+	if err := admin.ClearBacklog(ctx, req.Topic, req.Subscription); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to clear backlog of subscription %s on %s: %v", req.Subscription, req.Topic, err))
+		return err
+	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Cleared backlog of subscription %s on %s", req.Subscription, req.Topic))
+	return nil
+}
+
+// ResetSubscription 将订阅的游标重置到指定时间
+func (s *AppService) ResetSubscription(ctx context.Context, req *types.ResetSubscriptionRequest) error {
+	admin, err := adminAs[mq.SubscriptionAdmin](s, ctx, req.ConnectionID, "Subscription administration")
+	if err != nil {
+		return err
+	}
+
+	if err := admin.ResetSubscription(ctx, req.Topic, req.Subscription, req.Timestamp); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to reset subscription %s on %s: %v", req.Subscription, req.Topic, err))
+		return err
+	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Reset subscription %s on %s to %s", req.Subscription, req.Topic, req.Timestamp.Format(time.RFC3339)))
+	return nil
+}
+
+// ListAddresses 列出地址
+func (s *AppService) ListAddresses(ctx context.Context, connectionID string) ([]types.AddressInfo, error) {
+	admin, err := adminAs[mq.AddressAdmin](s, ctx, connectionID, "Address administration")
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, err := admin.ListAddresses(ctx)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to list addresses: %v", err))
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// CreateAddress 创建地址
+func (s *AppService) CreateAddress(ctx context.Context, req *types.CreateAddressRequest) error {
+	admin, err := adminAs[mq.AddressAdmin](s, ctx, req.ConnectionID, "Address administration")
+	if err != nil {
+		return err
+	}
+
+	if err := admin.CreateAddress(ctx, req.Address, req.RoutingType); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to create address %s: %v", req.Address, err))
+		return err
+	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Created address %s", req.Address))
+	return nil
+}
+
+// ListQueues 列出队列，address 为空时列出所有地址上的队列
+func (s *AppService) ListQueues(ctx context.Context, connectionID, address string) ([]types.QueueInfo, error) {
+	admin, err := adminAs[mq.AddressAdmin](s, ctx, connectionID, "Address administration")
+	if err != nil {
+		return nil, err
+	}
+
+	queues, err := admin.ListQueues(ctx, address)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to list queues: %v", err))
+		return nil, err
+	}
+	return queues, nil
+}
+
+// CreateQueue 在地址上创建队列
+func (s *AppService) CreateQueue(ctx context.Context, req *types.CreateQueueRequest) error {
+	admin, err := adminAs[mq.AddressAdmin](s, ctx, req.ConnectionID, "Address administration")
+	if err != nil {
+		return err
+	}
+
+	if err := admin.CreateQueue(ctx, req.Address, req.Queue, req.RoutingType, req.Durable); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to create queue %s on %s: %v", req.Queue, req.Address, err))
+		return err
+	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Created queue %s on %s", req.Queue, req.Address))
+	return nil
+}
+
+// DeleteQueue 删除队列
+func (s *AppService) DeleteQueue(ctx context.Context, connectionID, queue string) error {
+	admin, err := adminAs[mq.AddressAdmin](s, ctx, connectionID, "Address administration")
+	if err != nil {
+		return err
+	}
+
+	if err := admin.DeleteQueue(ctx, queue); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to delete queue %s: %v", queue, err))
+		return err
+	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Deleted queue %s", queue))
+	return nil
+}
+
+// GetQueueAttributes 查询队列属性
+func (s *AppService) GetQueueAttributes(ctx context.Context, connectionID, queue string) (*types.QueueAttributes, error) {
+	admin, err := adminAs[mq.QueueAttributeAdmin](s, ctx, connectionID, "Queue attribute administration")
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := admin.GetQueueAttributes(ctx, queue)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to get attributes of queue %s: %v", queue, err))
+		return nil, err
+	}
+	return attributes, nil
+}
+
+// SetQueueAttributes 修改队列属性
+func (s *AppService) SetQueueAttributes(ctx context.Context, req *types.SetQueueAttributesRequest) error {
+	admin, err := adminAs[mq.QueueAttributeAdmin](s, ctx, req.ConnectionID, "Queue attribute administration")
+	if err != nil {
+		return err
+	}
+
+	if err := admin.SetQueueAttributes(ctx, req.Queue, req.Attributes); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to set attributes of queue %s: %v", req.Queue, err))
+		return err
+	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Updated %d attributes of queue %s", len(req.Attributes), req.Queue))
+	return nil
+}
+
+// PurgeQueue 清空队列
+func (s *AppService) PurgeQueue(ctx context.Context, connectionID, queue string) error {
+	admin, err := adminAs[mq.QueueAttributeAdmin](s, ctx, connectionID, "Queue attribute administration")
+	if err != nil {
+		return err
+	}
+
+	if err := admin.PurgeQueue(ctx, queue); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to purge queue %s: %v", queue, err))
+		return err
+	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Purged queue %s", queue))
+	return nil
+}
+
+// ListDeadLetterSources 列出以该队列为死信队列的源队列
+func (s *AppService) ListDeadLetterSources(ctx context.Context, connectionID, queue string) ([]string, error) {
+	admin, err := adminAs[mq.QueueAttributeAdmin](s, ctx, connectionID, "Queue attribute administration")
+	if err != nil {
+		return nil, err
+	}
+
+	sources, err := admin.ListDeadLetterSources(ctx, queue)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to list dead-letter source queues of %s: %v", queue, err))
+		return nil, err
+	}
+	return sources, nil
+}
+
+// ListExchanges 列出交换机
+func (s *AppService) ListExchanges(ctx context.Context, connectionID string) ([]types.ExchangeInfo, error) {
+	admin, err := adminAs[mq.ExchangeAdmin](s, ctx, connectionID, "Exchange administration")
+	if err != nil {
+		return nil, err
+	}
+
+	exchanges, err := admin.ListExchanges(ctx)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to list exchanges: %v", err))
+		return nil, err
+	}
+	return exchanges, nil
+}
+
+// CreateExchange 创建交换机
+func (s *AppService) CreateExchange(ctx context.Context, req *types.CreateExchangeRequest) error {
+	admin, err := adminAs[mq.ExchangeAdmin](s, ctx, req.ConnectionID, "Exchange administration")
+	if err != nil {
+		return err
+	}
+
+	if err := admin.CreateExchange(ctx, req.Exchange, req.Kind, req.Durable); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to create exchange %s: %v", req.Exchange, err))
+		return err
+	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Created %s exchange %s", req.Kind, req.Exchange))
+	return nil
+}
+
+// DeleteExchange 删除交换机
+func (s *AppService) DeleteExchange(ctx context.Context, connectionID, exchange string) error {
+	admin, err := adminAs[mq.ExchangeAdmin](s, ctx, connectionID, "Exchange administration")
+	if err != nil {
+		return err
+	}
+
+	if err := admin.DeleteExchange(ctx, exchange); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to delete exchange %s: %v", exchange, err))
+		return err
+	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Deleted exchange %s", exchange))
+	return nil
+}
+
+// ListBindings 列出交换机到队列的绑定
+func (s *AppService) ListBindings(ctx context.Context, connectionID, exchange string) ([]types.BindingInfo, error) {
+	admin, err := adminAs[mq.ExchangeAdmin](s, ctx, connectionID, "Exchange administration")
+	if err != nil {
+		return nil, err
+	}
+
+	bindings, err := admin.ListBindings(ctx, exchange)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to list bindings of %s: %v", exchange, err))
+		return nil, err
+	}
+	return bindings, nil
+}
+
+// BindQueue 将队列按路由键绑定到交换机
+func (s *AppService) BindQueue(ctx context.Context, req *types.BindingRequest) error {
+	admin, err := adminAs[mq.ExchangeAdmin](s, ctx, req.ConnectionID, "Exchange administration")
+	if err != nil {
+		return err
+	}
+
+	if err := admin.BindQueue(ctx, req.Exchange, req.Queue, req.RoutingKey); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to bind %s to %s: %v", req.Queue, req.Exchange, err))
+		return err
+	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Bound %s to %s with key %q", req.Queue, req.Exchange, req.RoutingKey))
+	return nil
+}
+
+// UnbindQueue 解除队列与交换机的绑定
+func (s *AppService) UnbindQueue(ctx context.Context, req *types.BindingRequest) error {
+	admin, err := adminAs[mq.ExchangeAdmin](s, ctx, req.ConnectionID, "Exchange administration")
+	if err != nil {
+		return err
+	}
+
+	if err := admin.UnbindQueue(ctx, req.Exchange, req.Queue, req.RoutingKey); err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to unbind %s from %s: %v", req.Queue, req.Exchange, err))
+		return err
+	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Unbound %s from %s with key %q", req.Queue, req.Exchange, req.RoutingKey))
+	return nil
+}
+
+// ExportMessages 将已存储的消息按指定格式导出到文件，返回导出的消息数
+func (s *AppService) ExportMessages(ctx context.Context, req *types.ExportRequest, path string) (int, error) {
+	format, err := transfer.ParseFormat(string(req.Format))
+	if err != nil {
+		return 0, err
+	}
+
+	msgs, err := s.messageService.ListMessages(ctx, &req.Query)
+	if err != nil {
+		return 0, err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer file.Close()
+
+	if err := transfer.Write(file, format, msgs, transfer.Options{Delimiter: req.Delimiter}); err != nil {
+		return 0, fmt.Errorf("failed to export messages: %w", err)
+	}
+
+	s.logger.Info("AppService", fmt.Sprintf("Exported %d messages to %s", len(msgs), path))
+	return len(msgs), nil
+}
+
+// ImportMessages 读取消息文件并通过 ProduceBatch 批量发送到目标连接
+func (s *AppService) ImportMessages(ctx context.Context, req *types.ImportRequest) (*types.ImportResult, error) {
+	start := time.Now()
+
+	if req.ConnectionID == "" {
+		return nil, fmt.Errorf("ConnectionID is required")
+	}
+
+	format := transfer.FormatFromPath(req.FilePath)
+	if req.Format != "" {
+		var err error
+		if format, err = transfer.ParseFormat(string(req.Format)); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := os.Open(req.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer file.Close()
+
+	msgs, err := transfer.Read(file, format, transfer.Options{Delimiter: req.Delimiter})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import file: %w", err)
+	}
+
+	reqs := make([]*types.ProduceRequest, 0, len(msgs))
+	for i, msg := range msgs {
+		produceReq := importProduceRequest(req, msg)
+		if produceReq.Topic == "" {
+			return nil, fmt.Errorf("message %d has no topic and no default topic was given", i+1)
+		}
+		reqs = append(reqs, produceReq)
+	}
+
+	config, err := s.configService.GetConnection(ctx, req.ConnectionID)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to get connection config: %v", err))
+		return nil, err
+	}
+
+	client, err := s.getOrCreateClient(ctx, req.ConnectionID, config)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to get producer client: %v", err))
+		return nil, err
+	}
+
+	result := &types.ImportResult{FilePath: req.FilePath, Total: len(reqs)}
+	result.Produced, err = produceInBatches(ctx, client, reqs, req.BatchSize)
+	result.Latency = time.Since(start).Milliseconds()
+
+	message := fmt.Sprintf("Imported %d/%d messages from %s", result.Produced, result.Total, req.FilePath)
+	if err != nil {
+		message = fmt.Sprintf("%s: %v", message, err)
+		s.logger.Error("AppService", message)
+	} else {
+		s.logger.Info("AppService", message)
+	}
+	s.historyService.AddProduceRecord(ctx, req.ConnectionID, requestTopics(reqs), err == nil, message, result.Latency)
+
+	return result, err
+}
+
+// ProduceRendered 按模板渲染 Count 条消息并通过 ProduceBatch 批量发送
+func (s *AppService) ProduceRendered(ctx context.Context, req *types.RenderProduceRequest) (*types.RenderProduceResult, error) {
+	start := time.Now()
+
+	req, err := s.templateService.ResolveProduceRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if req.ConnectionID == "" {
+		return nil, fmt.Errorf("ConnectionID is required")
+	}
+
+	reqs, err := s.templateService.RenderProduceRequests(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := s.configService.GetConnection(ctx, req.ConnectionID)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to get connection config: %v", err))
+		return nil, err
+	}
+
+	client, err := s.getOrCreateClient(ctx, req.ConnectionID, config)
+	if err != nil {
+		s.logger.Error("AppService", fmt.Sprintf("Failed to get producer client: %v", err))
+		return nil, err
+	}
+
+	result := &types.RenderProduceResult{Total: len(reqs)}
+	result.Produced, err = produceInBatches(ctx, client, reqs, req.BatchSize)
+	result.Latency = time.Since(start).Milliseconds()
+
+	message := fmt.Sprintf("Sent %d/%d rendered messages", result.Produced, result.Total)
+	if err != nil {
+		message = fmt.Sprintf("%s: %v", message, err)
+		s.logger.Error("AppService", message)
+	} else {
+		s.logger.Info("AppService", message)
+	}
+	s.historyService.AddProduceRecord(ctx, req.ConnectionID, requestTopics(reqs), err == nil, message, result.Latency)
+
+	return result, err
+}
+
+// produceInBatches 每次取 batchSize 条请求通过 ProduceBatch 发送，遇到错误即停止，返回已发送的数量
+func produceInBatches(ctx context.Context, producer mq.Producer, reqs []*types.ProduceRequest, batchSize int) (int, error) {
+	if batchSize <= 0 {
+		batchSize = defaultImportBatchSize
+	}
+
+	produced := 0
+	for produced < len(reqs) {
+		end := produced + batchSize
+		if end > len(reqs) {
+			end = len(reqs)
+		}
+		if err := producer.ProduceBatch(ctx, reqs[produced:end]); err != nil {
+			return produced, err
+		}
+		produced = end
+	}
+	return produced, nil
+}
+
+// requestTopics 返回请求中出现的主题，多个主题按首次出现的顺序以逗号分隔，用于历史记录
+func requestTopics(reqs []*types.ProduceRequest) string {
+	var topics []string
+	seen := make(map[string]bool)
+	for _, req := range reqs {
+		if !seen[req.Topic] {
+			seen[req.Topic] = true
+			topics = append(topics, req.Topic)
+		}
+	}
+	return strings.Join(topics, ", ")
+}
+
+// importProduceRequest 根据导入选项将文件中的消息转换为发送请求
+func importProduceRequest(req *types.ImportRequest, msg *types.Message) *types.ProduceRequest {
+	topic := msg.Topic
+	if mapped, ok := req.TopicMap[topic]; ok {
+		topic = mapped
+	} else if req.Topic != "" {
+		topic = req.Topic
+	}
+
+	key := msg.Key
+	if mapped, ok := req.KeyMap[key]; ok {
+		key = mapped
+	}
+
+	produceReq := &types.ProduceRequest{
+		ConnectionID: req.ConnectionID,
+		Topic:        topic,
+		Key:          key,
+		Value:        msg.Value,
+		Headers:      msg.Headers,
+	}
+	if req.PreservePartition {
+		partition := msg.Partition
+		produceReq.Partition = &partition
+	}
+	return produceReq
+}
+
+// getOrCreateClient 获取或创建客户端 (用于生产者/Admin)
+func (s *AppService) getOrCreateClient(ctx context.Context, connectionID string, config *types.ConnectionConfig) (mq.Client, error) {
+	s.clientsMutex.Lock()
+	defer s.clientsMutex.Unlock()
+
+	if client, exists := s.activeClients[connectionID]; exists {
+		if client.IsConnected() {
+			return client, nil
+		}
+		client.Close()
+		delete(s.activeClients, connectionID)
+	}
+
+	client, err := s.mqFactory.CreateClient(config.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.Connect(ctx, config); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	s.activeClients[connectionID] = client
+	s.healthService.Report(&types.ConnectionStatus{ConnectionID: connectionID, State: types.ConnectionStateConnected})
+	return client, nil
+}
+
+// DeleteConnection 删除连接配置，同时关闭该连接缓存的客户端
+func (s *AppService) DeleteConnection(ctx context.Context, connectionID string) error {
+	if err := s.configService.DeleteConnection(ctx, connectionID); err != nil {
+		return err
+	}
+	return s.CloseConnection(connectionID)
+}
+
+// CloseConnection 关闭连接
+func (s *AppService) CloseConnection(connectionID string) error {
+	s.clientsMutex.Lock()
+	defer s.clientsMutex.Unlock()
+
+	s.cancelReconnect(connectionID)
+	defer s.healthService.Forget(connectionID, "")
+	if client, exists := s.activeClients[connectionID]; exists {
+		err := client.Close()
+		delete(s.activeClients, connectionID)
+		s.logger.Info("AppService", fmt.Sprintf("Closed connection: %s", connectionID))
+		return err
+	}
+	return nil
+}
+
+// ListConnectionStatuses 返回缓存客户端和订阅的健康状态
+func (s *AppService) ListConnectionStatuses() []*types.ConnectionStatus {
+	return s.healthService.ListStatuses()
+}
+
+// monitorClients 定期探测缓存的客户端，探测失败时关闭客户端并在后台重连
+func (s *AppService) monitorClients(ctx context.Context) {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkClients(ctx)
+		}
+	}
+}
+
+// checkClients 并发探测所有缓存的客户端
+func (s *AppService) checkClients(ctx context.Context) {
+	s.clientsMutex.Lock()
+	clients := make(map[string]mq.Client, len(s.activeClients))
+	for connectionID, client := range s.activeClients {
+		clients[connectionID] = client
+	}
+	s.clientsMutex.Unlock()
+
+	var wg sync.WaitGroup
+	for connectionID, client := range clients {
+		wg.Add(1)
+		go func(connectionID string, client mq.Client) {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			if err := probeClient(probeCtx, client); err != nil {
+				if ctx.Err() == nil {
+					s.startReconnect(ctx, connectionID, client, err)
+				}
+				return
+			}
+			s.healthService.Report(&types.ConnectionStatus{
+				ConnectionID: connectionID,
+				State:        types.ConnectionStateConnected,
+				Latency:      time.Since(start).Milliseconds(),
+			})
+		}(connectionID, client)
+	}
+	wg.Wait()
+}
+
+// startReconnect 移除失效的客户端并启动后台重连，客户端已被替换或已在重连时不做任何事
+func (s *AppService) startReconnect(ctx context.Context, connectionID string, broken mq.Client, cause error) {
+	s.clientsMutex.Lock()
+	if s.activeClients[connectionID] != broken || s.reconnects[connectionID] != nil {
+		s.clientsMutex.Unlock()
+		return
+	}
+	delete(s.activeClients, connectionID)
+	taskCtx, cancel := context.WithCancel(ctx)
+	task := &reconnectTask{cancel: cancel}
+	s.reconnects[connectionID] = task
+	s.clientsMutex.Unlock()
+
+	broken.Close()
+	go s.reconnect(taskCtx, task, connectionID, cause)
+}
+
+// reconnect 按指数退避重建客户端，直到成功、连接被关闭或在此期间已有请求重新创建客户端
+func (s *AppService) reconnect(ctx context.Context, task *reconnectTask, connectionID string, cause error) {
+	defer func() {
+		s.clientsMutex.Lock()
+		if s.reconnects[connectionID] == task {
+			delete(s.reconnects, connectionID)
+		}
+		s.clientsMutex.Unlock()
+		task.cancel()
+	}()
+
+	for attempt := 1; ; attempt++ {
+		if !s.healthService.waitBackoff(ctx, connectionID, "", attempt, cause) {
+			return
+		}
+
+		s.clientsMutex.Lock()
+		_, recreated := s.activeClients[connectionID]
+		s.clientsMutex.Unlock()
+		if recreated {
+			return
+		}
+
+		client, err := s.connectClient(ctx, connectionID)
+		if utils.IsErrorType(err, utils.ErrorTypeNotFound) {
+			// 连接配置已被删除
+			s.healthService.Forget(connectionID, "")
+			return
+		}
+		if isFatalConnectError(err) {
+			// 凭据或配置有误，重试也无法恢复
+			s.healthService.Fail(connectionID, err)
+			return
+		}
+		if err != nil {
+			cause = err
+			continue
+		}
+
+		s.clientsMutex.Lock()
+		if _, exists := s.activeClients[connectionID]; exists || ctx.Err() != nil {
+			s.clientsMutex.Unlock()
+			client.Close()
+			return
+		}
+		s.activeClients[connectionID] = client
+		s.clientsMutex.Unlock()
+
+		s.logger.Info("AppService", fmt.Sprintf("Reconnected connection %s after %d attempt(s)", connectionID, attempt))
+		s.healthService.Report(&types.ConnectionStatus{ConnectionID: connectionID, State: types.ConnectionStateConnected})
+		return
+	}
+}
+
+// connectClient 读取最新的连接配置并建立新的客户端，连接受配置的拨号超时限制
+func (s *AppService) connectClient(ctx context.Context, connectionID string) (mq.Client, error) {
+	config, err := s.configService.GetConnection(ctx, connectionID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.mqFactory.CreateClient(config.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.Connect(ctx, config); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// cancelReconnect 停止连接的后台重连，调用方需持有 clientsMutex
+func (s *AppService) cancelReconnect(connectionID string) {
+	if task, ok := s.reconnects[connectionID]; ok {
+		task.cancel()
+		delete(s.reconnects, connectionID)
+	}
+}
+
+// closeAllClients 停止所有后台重连并关闭缓存的客户端
+func (s *AppService) closeAllClients() {
+	s.clientsMutex.Lock()
+	defer s.clientsMutex.Unlock()
+
+	for connectionID := range s.reconnects {
+		s.cancelReconnect(connectionID)
+		s.healthService.Forget(connectionID, "")
+	}
+	for connectionID, client := range s.activeClients {
+		if err := client.Close(); err != nil {
+			s.logger.Error("AppService", fmt.Sprintf("Failed to close connection %s: %v", connectionID, err))
+		}
+		s.healthService.Forget(connectionID, "")
+	}
+	s.activeClients = make(map[string]mq.Client)
+}
+
+// Shutdown 关闭应用服务
+func (s *AppService) Shutdown() error {
+	s.logger.Info("AppService", "Shutting down application service...")
+	// 停止所有消费者
+	s.consumerService.StopAllConsumers()
+	s.replayService.StopAllReplays()
+	s.bridgeService.StopAllBridges()
+	s.benchService.StopAllBenchmarks()
+
+	s.stopMonitor()
+	s.closeAllClients()
 	s.logger.Info("AppService", "Application service shutdown completed")
 	return nil
 }
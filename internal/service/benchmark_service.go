@@ -0,0 +1,489 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"mq-toolkit/internal/factory"
+	"mq-toolkit/internal/logger"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/templating"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// maxBenchmarkConcurrency 压测并发数上限
+	maxBenchmarkConcurrency = 64
+	// latencySampleSize 延迟统计保留的样本数，超出后使用蓄水池抽样
+	latencySampleSize = 100000
+	// defaultBenchmarkPayloadSize 未指定消息体时的默认大小
+	defaultBenchmarkPayloadSize = 256
+)
+
+// BenchmarkService 负责生产/消费压测
+type BenchmarkService struct {
+	ctx        context.Context
+	db         *gorm.DB
+	logger     *logger.Logger
+	emit       EventEmitter
+	mqFactory  factory.Factory
+	configSvc  *ConfigService
+	historySvc *HistoryService
+	runs       sync.Map // 存储运行中的压测 [benchmarkID -> *benchmarkRun]
+}
+
+// benchmarkRun 代表一次运行中的压测
+type benchmarkRun struct {
+	report   *types.BenchmarkReport
+	template *templating.Template // 设置了消息体模板时按消息序号渲染
+	cancel   context.CancelFunc
+	start    time.Time
+	claimed  atomic.Int64 // 已分配的消息序号
+	count    atomic.Int64
+	errors   atomic.Int64
+	bytes    atomic.Int64
+	latency  *latencyRecorder
+}
+
+// NewBenchmarkService 创建压测服务
+func NewBenchmarkService(ctx context.Context, logger *logger.Logger, emit EventEmitter, factory factory.Factory, configSvc *ConfigService, historySvc *HistoryService, db *gorm.DB) *BenchmarkService {
+	return &BenchmarkService{
+		ctx:        ctx,
+		db:         db,
+		logger:     logger,
+		emit:       emitter(emit),
+		mqFactory:  factory,
+		configSvc:  configSvc,
+		historySvc: historySvc,
+	}
+}
+
+// StartBenchmark 校验请求并在后台开始压测，返回压测ID
+func (s *BenchmarkService) StartBenchmark(req *types.BenchmarkRequest) (string, error) {
+	if req.ConnectionID == "" {
+		return "", utils.NewValidationError("ConnectionID is required", "")
+	}
+	if !utils.IsValidTopic(req.Topic) {
+		return "", utils.NewValidationError("Invalid topic name", req.Topic)
+	}
+	if req.MessageCount <= 0 && req.DurationSeconds <= 0 {
+		return "", utils.NewValidationError("Either message count or duration is required", "")
+	}
+	if req.Mode == "" {
+		req.Mode = types.BenchmarkModeProduce
+	}
+	if req.Mode != types.BenchmarkModeProduce && req.Mode != types.BenchmarkModeConsume {
+		return "", utils.NewValidationError("Invalid benchmark mode", string(req.Mode))
+	}
+	if req.Concurrency <= 0 {
+		req.Concurrency = 1
+	}
+	if req.Concurrency > maxBenchmarkConcurrency {
+		req.Concurrency = maxBenchmarkConcurrency
+	}
+
+	var tmpl *templating.Template
+	if req.Mode == types.BenchmarkModeProduce && req.Template != "" {
+		var err error
+		if tmpl, err = templating.Parse(req.Template, req.Variables); err != nil {
+			return "", utils.NewValidationError("Invalid payload template", err.Error())
+		}
+	}
+
+	connConfig, err := s.configSvc.GetConnection(s.ctx, req.ConnectionID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get connection config: %w", err)
+	}
+
+	var runCtx context.Context
+	var cancel context.CancelFunc
+	if req.DurationSeconds > 0 {
+		runCtx, cancel = context.WithTimeout(s.ctx, time.Duration(req.DurationSeconds)*time.Second)
+	} else {
+		runCtx, cancel = context.WithCancel(s.ctx)
+	}
+
+	run := &benchmarkRun{
+		report: &types.BenchmarkReport{
+			ID:           utils.GenerateID(),
+			Mode:         req.Mode,
+			ConnectionID: req.ConnectionID,
+			Topic:        req.Topic,
+			State:        types.BenchmarkStateRunning,
+			Request:      *req,
+			StartedAt:    time.Now(),
+		},
+		template: tmpl,
+		cancel:   cancel,
+		latency:  newLatencyRecorder(latencySampleSize),
+	}
+
+	var runner func(ctx context.Context, run *benchmarkRun, config *types.ConnectionConfig) error
+	if req.Mode == types.BenchmarkModeConsume {
+		runner = s.runConsume
+	} else {
+		runner = s.runProduce
+	}
+
+	s.runs.Store(run.report.ID, run)
+	go s.execute(runCtx, run, connConfig, runner)
+
+	s.logger.Info("BenchmarkService", fmt.Sprintf("Started %s benchmark %s on topic %s", req.Mode, run.report.ID, req.Topic))
+	return run.report.ID, nil
+}
+
+// StopBenchmark 提前结束压测，已有结果仍会保存
+func (s *BenchmarkService) StopBenchmark(benchmarkID string) {
+	if value, ok := s.runs.Load(benchmarkID); ok {
+		value.(*benchmarkRun).cancel()
+	}
+}
+
+// StopAllBenchmarks 结束所有压测
+func (s *BenchmarkService) StopAllBenchmarks() {
+	s.runs.Range(func(key, value interface{}) bool {
+		value.(*benchmarkRun).cancel()
+		return true
+	})
+}
+
+// ListReports 列出已保存的压测报告
+func (s *BenchmarkService) ListReports(ctx context.Context, limit, offset int) ([]*types.BenchmarkReport, error) {
+	var reports []*types.BenchmarkReport
+	query := s.db.Order("started_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+	if err := query.Find(&reports).Error; err != nil {
+		return nil, fmt.Errorf("failed to list benchmark reports: %w", err)
+	}
+	return reports, nil
+}
+
+// DeleteReport 删除压测报告
+func (s *BenchmarkService) DeleteReport(ctx context.Context, id string) error {
+	result := s.db.Delete(&types.BenchmarkReport{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete benchmark report: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("benchmark report not found: %s", id)
+	}
+	return nil
+}
+
+// execute 运行压测、定期推送进度，并在结束后保存报告
+func (s *BenchmarkService) execute(ctx context.Context, run *benchmarkRun, config *types.ConnectionConfig, runner func(context.Context, *benchmarkRun, *types.ConnectionConfig) error) {
+	defer s.runs.Delete(run.report.ID)
+	defer run.cancel()
+
+	done := make(chan error, 1)
+	run.start = time.Now()
+	go func() { done <- runner(ctx, run, config) }()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var err error
+loop:
+	for {
+		select {
+		case err = <-done:
+			break loop
+		case <-ticker.C:
+			s.emit("benchmark:progress", map[string]interface{}{
+				"id":    run.report.ID,
+				"mode":  run.report.Mode,
+				"stats": run.stats(),
+			})
+		}
+	}
+
+	report := run.report
+	report.Stats = run.stats()
+	finished := time.Now()
+	report.FinishedAt = &finished
+	switch {
+	case err != nil:
+		report.State = types.BenchmarkStateFailed
+		report.Error = err.Error()
+	case ctx.Err() == context.Canceled:
+		report.State = types.BenchmarkStateCancelled
+	default:
+		report.State = types.BenchmarkStateCompleted
+	}
+
+	if dbErr := s.db.Create(report).Error; dbErr != nil {
+		s.logger.Error("BenchmarkService", fmt.Sprintf("Failed to save benchmark report: %v", dbErr))
+	}
+
+	message := fmt.Sprintf("Benchmark %s %s: %d messages, %.1f msg/s, %.2f MB/s, p99 %.2fms",
+		report.Mode, report.State, report.Stats.Messages, report.Stats.MsgsPerSec, report.Stats.MBPerSec, report.Stats.LatencyP99)
+	if report.Error != "" {
+		message = fmt.Sprintf("%s, error: %s", message, report.Error)
+	}
+	s.historySvc.AddBenchmarkRecord(s.ctx, report.ConnectionID, report.Topic, report.State != types.BenchmarkStateFailed, message, report.Stats.ElapsedMs)
+	s.logger.Info("BenchmarkService", message)
+
+	s.emit("benchmark:finished", report)
+}
+
+// runProduce 多个并发生产者按速率发送消息，记录每次发送的延迟
+//
+// 设置了模板时每条消息以序号 seq 单独渲染，否则所有消息使用同一个固定或随机消息体。
+func (s *BenchmarkService) runProduce(ctx context.Context, run *benchmarkRun, config *types.ConnectionConfig) error {
+	req := &run.report.Request
+	limiter := utils.NewRateLimiter(req.RatePerSecond)
+
+	payload := req.Payload
+	if payload == "" && run.template == nil {
+		size := req.PayloadSize
+		if size <= 0 {
+			size = defaultBenchmarkPayloadSize
+		}
+		payload = randomPayload(size)
+	}
+
+	producers := make([]mq.Producer, 0, req.Concurrency)
+	defer func() {
+		for _, producer := range producers {
+			producer.Close()
+		}
+	}()
+	for i := 0; i < req.Concurrency; i++ {
+		producer, err := s.mqFactory.CreateProducer(config.Type)
+		if err != nil {
+			return fmt.Errorf("failed to create producer: %w", err)
+		}
+		if err := producer.Connect(ctx, config); err != nil {
+			producer.Close()
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		producers = append(producers, producer)
+	}
+
+	// 每个生产者使用各自的模板副本，渲染时互不等待
+	templates := make([]*templating.Template, len(producers))
+	if run.template != nil {
+		for i := range templates {
+			var err error
+			if templates[i], err = run.template.Clone(); err != nil {
+				return fmt.Errorf("failed to clone template: %w", err)
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, producer := range producers {
+		wg.Add(1)
+		go func(producer mq.Producer, tmpl *templating.Template) {
+			defer wg.Done()
+			for {
+				seq := run.claimed.Add(1)
+				if req.MessageCount > 0 && seq > int64(req.MessageCount) {
+					return
+				}
+				if err := limiter.Wait(ctx); err != nil {
+					return
+				}
+
+				headers := make(map[string]string, len(req.Headers)+1)
+				for k, v := range req.Headers {
+					headers[k] = v
+				}
+				value := payload
+				if tmpl != nil {
+					var err error
+					if value, err = tmpl.Execute(seq); err != nil {
+						run.errors.Add(1)
+						continue
+					}
+				}
+
+				sentAt := time.Now()
+				headers[types.BenchmarkSentAtHeader] = strconv.FormatInt(sentAt.UnixNano(), 10)
+
+				err := producer.Produce(ctx, &types.ProduceRequest{
+					ConnectionID: req.ConnectionID,
+					Topic:        req.Topic,
+					Key:          benchmarkKey(req, seq),
+					Value:        value,
+					Headers:      headers,
+				})
+				if ctx.Err() != nil {
+					return
+				}
+				if err != nil {
+					run.errors.Add(1)
+					continue
+				}
+				run.latency.record(time.Since(sentAt))
+				run.count.Add(1)
+				run.bytes.Add(int64(len(value)))
+			}
+		}(producer, templates[i])
+	}
+	wg.Wait()
+	return nil
+}
+
+// runConsume 消费压测消息，依据发送时间戳头计算端到端延迟
+func (s *BenchmarkService) runConsume(ctx context.Context, run *benchmarkRun, config *types.ConnectionConfig) error {
+	req := &run.report.Request
+
+	consumer, err := s.mqFactory.CreateConsumer(config.Type)
+	if err != nil {
+		return fmt.Errorf("failed to create consumer: %w", err)
+	}
+	defer consumer.Close()
+
+	if err := consumer.Connect(ctx, config); err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+
+	groupID := req.GroupID
+	if groupID == "" {
+		groupID = "mq-toolkit-benchmark-" + utils.GenerateShortID()
+	}
+	if err := consumer.Subscribe(ctx, &types.ConsumeRequest{
+		ConnectionID: req.ConnectionID,
+		Topics:       []string{req.Topic},
+		GroupID:      groupID,
+	}); err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	consumeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	err = consumer.Consume(consumeCtx, func(msg *types.Message) error {
+		received := time.Now()
+		if sentAt, err := strconv.ParseInt(msg.Headers[types.BenchmarkSentAtHeader], 10, 64); err == nil {
+			run.latency.record(received.Sub(time.Unix(0, sentAt)))
+		}
+		run.bytes.Add(int64(len(msg.Value)))
+		if n := run.count.Add(1); req.MessageCount > 0 && n >= int64(req.MessageCount) {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil && consumeCtx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// stats 计算当前统计
+func (r *benchmarkRun) stats() types.BenchmarkStats {
+	elapsed := time.Since(r.start)
+	stats := types.BenchmarkStats{
+		Messages:  r.count.Load(),
+		Errors:    r.errors.Load(),
+		Bytes:     r.bytes.Load(),
+		ElapsedMs: elapsed.Milliseconds(),
+	}
+	if seconds := elapsed.Seconds(); seconds > 0 {
+		stats.MsgsPerSec = float64(stats.Messages) / seconds
+		stats.MBPerSec = float64(stats.Bytes) / (1024 * 1024) / seconds
+	}
+	stats.LatencyP50, stats.LatencyP95, stats.LatencyP99, stats.LatencyMax = r.latency.percentiles()
+	return stats
+}
+
+// benchmarkKey 按键分布生成第 seq 条消息的键
+func benchmarkKey(req *types.BenchmarkRequest, seq int64) string {
+	keyCount := int64(req.KeyCount)
+	if keyCount <= 0 {
+		keyCount = 100
+	}
+	switch req.KeyDistribution {
+	case "fixed":
+		return req.Key
+	case "sequential":
+		return fmt.Sprintf("key-%d", (seq-1)%keyCount)
+	case "random":
+		return fmt.Sprintf("key-%d", rand.Int63n(keyCount))
+	default:
+		return ""
+	}
+}
+
+// randomPayload 生成指定长度的随机可打印字符串
+func randomPayload(size int) string {
+	const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	buf := make([]byte, size)
+	for i := range buf {
+		buf[i] = alphabet[rand.Intn(len(alphabet))]
+	}
+	return string(buf)
+}
+
+// latencyRecorder 记录延迟样本，样本数超过上限后使用蓄水池抽样，最大值始终精确
+type latencyRecorder struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	seen    int64
+	max     time.Duration
+	limit   int
+}
+
+func newLatencyRecorder(limit int) *latencyRecorder {
+	return &latencyRecorder{samples: make([]time.Duration, 0, 1024), limit: limit}
+}
+
+func (l *latencyRecorder) record(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.seen++
+	if d > l.max {
+		l.max = d
+	}
+	if len(l.samples) < l.limit {
+		l.samples = append(l.samples, d)
+		return
+	}
+	if i := rand.Int63n(l.seen); i < int64(l.limit) {
+		l.samples[i] = d
+	}
+}
+
+// percentiles 返回 p50/p95/p99/max，单位毫秒
+func (l *latencyRecorder) percentiles() (p50, p95, p99, max float64) {
+	l.mu.Lock()
+	sorted := make([]time.Duration, len(l.samples))
+	copy(sorted, l.samples)
+	maxLatency := l.max
+	l.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return 0, 0, 0, 0
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	at := func(p float64) float64 {
+		idx := int(p*float64(len(sorted))+0.5) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		if idx >= len(sorted) {
+			idx = len(sorted) - 1
+		}
+		return durationMs(sorted[idx])
+	}
+	return at(0.50), at(0.95), at(0.99), durationMs(maxLatency)
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"encoding/json"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"testing"
+)
+
+func TestBenchmarkRendersTemplatePerMessage(t *testing.T) {
+	env := newTestEnv(t)
+	connectionID := env.createMemoryConnection("memory", t.Name())
+	svc := NewBenchmarkService(env.ctx, env.logger, env.emit, env.factory, env.configSvc, env.historySvc, env.db.GetDB())
+
+	id, err := svc.StartBenchmark(&types.BenchmarkRequest{
+		ConnectionID: connectionID,
+		Topic:        "bench",
+		MessageCount: 20,
+		Concurrency:  4,
+		Template:     `{"seq": {{seq}}, "env": "{{.env}}"}`,
+		Variables:    map[string]string{"env": "test"},
+	})
+	if err != nil {
+		t.Fatalf("StartBenchmark failed: %v", err)
+	}
+
+	report := env.waitEvent("benchmark:finished", nil).(*types.BenchmarkReport)
+	if report.ID != id || report.State != types.BenchmarkStateCompleted {
+		t.Fatalf("report %s finished in state %s, want %s completed", report.ID, report.State, id)
+	}
+	if report.Stats.Messages != 20 || report.Stats.Errors != 0 {
+		t.Fatalf("stats %+v, want 20 messages without errors", report.Stats)
+	}
+
+	seqs := make(map[int64]bool)
+	for _, msg := range env.browse(t.Name(), "bench") {
+		var payload struct {
+			Seq int64  `json:"seq"`
+			Env string `json:"env"`
+		}
+		if err := json.Unmarshal([]byte(msg.Value), &payload); err != nil {
+			t.Fatalf("message %q is not the rendered template: %v", msg.Value, err)
+		}
+		if payload.Env != "test" {
+			t.Fatalf("message %q: env %q, want test", msg.Value, payload.Env)
+		}
+		seqs[payload.Seq] = true
+	}
+	if len(seqs) != 20 {
+		t.Fatalf("got %d distinct sequence numbers, want 20", len(seqs))
+	}
+
+	env.waitFor("the finished run to be removed", func() bool {
+		_, ok := svc.runs.Load(id)
+		return !ok
+	})
+	reports, err := svc.ListReports(env.ctx, 10, 0)
+	if err != nil || len(reports) != 1 || reports[0].ID != id {
+		t.Fatalf("ListReports returned %v, %v; want the saved report %s", reports, err, id)
+	}
+}
+
+func TestBenchmarkRejectsInvalidTemplate(t *testing.T) {
+	env := newTestEnv(t)
+	connectionID := env.createMemoryConnection("memory", t.Name())
+	svc := NewBenchmarkService(env.ctx, env.logger, env.emit, env.factory, env.configSvc, env.historySvc, env.db.GetDB())
+
+	_, err := svc.StartBenchmark(&types.BenchmarkRequest{
+		ConnectionID: connectionID,
+		Topic:        "bench",
+		MessageCount: 1,
+		Template:     `{{seq`,
+	})
+	if !utils.IsErrorType(err, utils.ErrorTypeValidation) {
+		t.Fatalf("StartBenchmark returned %v, want a validation error", err)
+	}
+}
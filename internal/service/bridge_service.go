@@ -0,0 +1,435 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/internal/factory"
+	"mq-toolkit/internal/filter"
+	"mq-toolkit/internal/logger"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// defaultBridgeRetries 目标发送失败时的默认重试次数
+	defaultBridgeRetries = 3
+	// bridgeRetryBackoff 首次重试前的等待时间，之后逐次翻倍
+	bridgeRetryBackoff = 200 * time.Millisecond
+)
+
+// BridgeService 管理跨连接的消息桥接任务
+type BridgeService struct {
+	ctx        context.Context
+	logger     *logger.Logger
+	emit       EventEmitter
+	mqFactory  factory.Factory
+	configSvc  *ConfigService
+	historySvc *HistoryService
+	bridges    sync.Map // 存储桥接任务 [bridgeID -> *bridgeJob]
+}
+
+// bridgeJob 代表一个运行中的桥接任务
+type bridgeJob struct {
+	id       string
+	req      *types.BridgeRequest
+	consumer mq.Consumer
+	producer mq.Producer
+	filter   *filter.Filter
+	limiter  *utils.RateLimiter
+	cancel   context.CancelFunc
+
+	consumed atomic.Int64
+	produced atomic.Int64
+	filtered atomic.Int64
+	failed   atomic.Int64
+
+	mu        sync.Mutex
+	state     types.BridgeState
+	rate      float64
+	lastError string
+	resume    chan struct{} // 暂停时非空，恢复时关闭
+	startedAt time.Time
+}
+
+// NewBridgeService 创建桥接服务
+func NewBridgeService(ctx context.Context, logger *logger.Logger, emit EventEmitter, factory factory.Factory, configSvc *ConfigService, historySvc *HistoryService) *BridgeService {
+	return &BridgeService{
+		ctx:        ctx,
+		logger:     logger,
+		emit:       emitter(emit),
+		mqFactory:  factory,
+		configSvc:  configSvc,
+		historySvc: historySvc,
+	}
+}
+
+// StartBridge 创建并启动桥接任务，返回任务ID
+func (s *BridgeService) StartBridge(req *types.BridgeRequest) (string, error) {
+	if req.SourceConnectionID == "" || req.TargetConnectionID == "" {
+		return "", utils.NewValidationError("Source and target connections are required", "")
+	}
+	if len(req.SourceTopics) == 0 {
+		return "", utils.NewValidationError("No source topics specified", "")
+	}
+
+	compiled, err := filter.Compile(req.Filter)
+	if err != nil {
+		return "", utils.NewValidationError("Invalid filter expression", err.Error())
+	}
+
+	sourceConfig, err := s.configSvc.GetConnection(s.ctx, req.SourceConnectionID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get source connection config: %w", err)
+	}
+	targetConfig, err := s.configSvc.GetConnection(s.ctx, req.TargetConnectionID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get target connection config: %w", err)
+	}
+
+	// 先连接目标，避免源消息在目标不可用时被消费
+	producer, err := s.mqFactory.CreateProducer(targetConfig.Type)
+	if err != nil {
+		return "", fmt.Errorf("failed to create target producer: %w", err)
+	}
+	if err := producer.Connect(s.ctx, targetConfig); err != nil {
+		producer.Close()
+		return "", fmt.Errorf("failed to connect to target: %w", err)
+	}
+
+	consumer, err := s.mqFactory.CreateConsumer(sourceConfig.Type)
+	if err != nil {
+		producer.Close()
+		return "", fmt.Errorf("failed to create source consumer: %w", err)
+	}
+	if err := consumer.Connect(s.ctx, sourceConfig); err != nil {
+		consumer.Close()
+		producer.Close()
+		return "", fmt.Errorf("failed to connect to source: %w", err)
+	}
+
+	groupID := req.GroupID
+	if groupID == "" {
+		groupID = "mq-toolkit-bridge-" + utils.GenerateShortID()
+	}
+	consumeReq := &types.ConsumeRequest{
+		ConnectionID:  req.SourceConnectionID,
+		Topics:        req.SourceTopics,
+		GroupID:       groupID,
+		FromBeginning: req.FromBeginning,
+	}
+	if err := consumer.Subscribe(s.ctx, consumeReq); err != nil {
+		consumer.Close()
+		producer.Close()
+		return "", fmt.Errorf("failed to subscribe to source: %w", err)
+	}
+
+	bridgeCtx, cancel := context.WithCancel(s.ctx)
+	job := &bridgeJob{
+		id:        utils.GenerateID(),
+		req:       req,
+		consumer:  consumer,
+		producer:  producer,
+		filter:    compiled,
+		limiter:   utils.NewRateLimiter(req.RatePerSecond),
+		cancel:    cancel,
+		state:     types.BridgeStateRunning,
+		startedAt: time.Now(),
+	}
+	if job.req.Name == "" {
+		job.req.Name = fmt.Sprintf("%s -> %s", sourceConfig.Name, targetConfig.Name)
+	}
+	s.bridges.Store(job.id, job)
+
+	go s.monitor(bridgeCtx, job)
+	go s.run(bridgeCtx, job)
+
+	s.logger.Info("BridgeService", fmt.Sprintf("Started bridge %s (%s)", job.id, job.req.Name))
+	return job.id, nil
+}
+
+// PauseBridge 暂停桥接，暂停期间不再从源消费
+func (s *BridgeService) PauseBridge(bridgeID string) error {
+	job, err := s.getJob(bridgeID)
+	if err != nil {
+		return err
+	}
+
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	if job.state != types.BridgeStateRunning {
+		return utils.NewValidationError("Bridge is not running", string(job.state))
+	}
+	job.state = types.BridgeStatePaused
+	job.resume = make(chan struct{})
+	s.logger.Info("BridgeService", fmt.Sprintf("Paused bridge %s", bridgeID))
+	return nil
+}
+
+// ResumeBridge 恢复已暂停的桥接
+func (s *BridgeService) ResumeBridge(bridgeID string) error {
+	job, err := s.getJob(bridgeID)
+	if err != nil {
+		return err
+	}
+
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	if job.state != types.BridgeStatePaused {
+		return utils.NewValidationError("Bridge is not paused", string(job.state))
+	}
+	job.state = types.BridgeStateRunning
+	close(job.resume)
+	job.resume = nil
+	s.logger.Info("BridgeService", fmt.Sprintf("Resumed bridge %s", bridgeID))
+	return nil
+}
+
+// StopBridge 停止并移除桥接任务
+func (s *BridgeService) StopBridge(bridgeID string) {
+	if value, ok := s.bridges.Load(bridgeID); ok {
+		value.(*bridgeJob).cancel()
+	}
+}
+
+// StopAllBridges 停止所有桥接任务
+func (s *BridgeService) StopAllBridges() {
+	s.bridges.Range(func(key, value interface{}) bool {
+		value.(*bridgeJob).cancel()
+		return true
+	})
+}
+
+// ListBridges 列出所有桥接任务
+func (s *BridgeService) ListBridges() []types.BridgeStatus {
+	var statuses []types.BridgeStatus
+	s.bridges.Range(func(key, value interface{}) bool {
+		statuses = append(statuses, value.(*bridgeJob).status())
+		return true
+	})
+	return statuses
+}
+
+// getJob 按ID查找桥接任务
+func (s *BridgeService) getJob(bridgeID string) (*bridgeJob, error) {
+	value, ok := s.bridges.Load(bridgeID)
+	if !ok {
+		return nil, utils.NewNotFoundError("bridge", bridgeID)
+	}
+	return value.(*bridgeJob), nil
+}
+
+// run 消费源消息直到任务停止、源端出错或转发失败
+func (s *BridgeService) run(ctx context.Context, job *bridgeJob) {
+	err := job.consumer.Consume(ctx, func(msg *types.Message) error {
+		// 任务已停止，后续消息不再转发，也不被确认
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		err := s.forward(ctx, job, msg)
+		if err != nil && ctx.Err() == nil {
+			// 先停止任务再返回错误，源端不会确认该消息，重新启动桥接后再次收到
+			job.fail(err)
+		}
+		return err
+	})
+
+	job.mu.Lock()
+	if err != nil && ctx.Err() == nil {
+		job.state = types.BridgeStateFailed
+		job.lastError = err.Error()
+	} else if job.state != types.BridgeStateFailed {
+		job.state = types.BridgeStateStopped
+	}
+	if job.resume != nil {
+		close(job.resume)
+		job.resume = nil
+	}
+	job.mu.Unlock()
+	job.cancel()
+
+	job.consumer.Close()
+	job.producer.Close()
+
+	status := job.status()
+	message := fmt.Sprintf("Bridge %s %s: consumed %d, produced %d, filtered %d, failed %d",
+		status.Name, status.State, status.Consumed, status.Produced, status.Filtered, status.Failed)
+	if status.Error != "" {
+		message = fmt.Sprintf("%s, last error: %s", message, status.Error)
+		s.logger.Error("BridgeService", message)
+	} else {
+		s.logger.Info("BridgeService", message)
+	}
+	topic := job.req.TargetTopic
+	if topic == "" {
+		topic = strings.Join(job.req.SourceTopics, ", ")
+	}
+	s.historySvc.AddBridgeRecord(s.ctx, job.req.TargetConnectionID, topic, status.State != types.BridgeStateFailed, message, time.Since(job.startedAt).Milliseconds())
+
+	s.emit("bridge:status", status)
+	s.bridges.Delete(job.id)
+}
+
+// forward 处理一条源消息：过滤、限速、映射后发送到目标
+func (s *BridgeService) forward(ctx context.Context, job *bridgeJob, msg *types.Message) error {
+	// 暂停期间收到的消息在恢复后才计入，停止时未处理的消息不计入
+	if err := job.waitIfPaused(ctx); err != nil {
+		return err
+	}
+	job.consumed.Add(1)
+
+	if !job.filter.Match(msg) {
+		job.filtered.Add(1)
+		return nil
+	}
+
+	if err := job.limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	req := bridgeProduceRequest(job.req, msg)
+
+	retries := job.req.MaxRetries
+	if retries <= 0 {
+		retries = defaultBridgeRetries
+	}
+	backoff := bridgeRetryBackoff
+
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+			backoff *= 2
+		}
+		if err = job.producer.Produce(ctx, req); err == nil {
+			job.produced.Add(1)
+			return nil
+		}
+	}
+
+	job.failed.Add(1)
+	job.mu.Lock()
+	job.lastError = err.Error()
+	job.mu.Unlock()
+	return fmt.Errorf("failed to forward message to %s after %d retries: %w", req.Topic, retries, err)
+}
+
+// monitor 每秒计算吞吐速率并推送状态到前端
+func (s *BridgeService) monitor(ctx context.Context, job *bridgeJob) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	last := job.produced.Load()
+	lastTime := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			produced := job.produced.Load()
+			job.mu.Lock()
+			job.rate = float64(produced-last) / now.Sub(lastTime).Seconds()
+			job.mu.Unlock()
+			last, lastTime = produced, now
+			s.emit("bridge:status", job.status())
+		}
+	}
+}
+
+// fail 将任务标记为失败并停止，只记录第一个错误
+func (j *bridgeJob) fail(err error) {
+	j.mu.Lock()
+	if j.state != types.BridgeStateFailed {
+		j.state = types.BridgeStateFailed
+		j.lastError = err.Error()
+	}
+	j.mu.Unlock()
+	j.cancel()
+}
+
+// waitIfPaused 暂停期间阻塞，直到恢复或停止
+func (j *bridgeJob) waitIfPaused(ctx context.Context) error {
+	j.mu.Lock()
+	resume := j.resume
+	j.mu.Unlock()
+	if resume == nil {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-resume:
+		return nil
+	}
+}
+
+// status 返回当前状态快照
+func (j *bridgeJob) status() types.BridgeStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return types.BridgeStatus{
+		ID:                 j.id,
+		Name:               j.req.Name,
+		SourceConnectionID: j.req.SourceConnectionID,
+		SourceTopics:       j.req.SourceTopics,
+		TargetConnectionID: j.req.TargetConnectionID,
+		TargetTopic:        j.req.TargetTopic,
+		State:              j.state,
+		Consumed:           j.consumed.Load(),
+		Produced:           j.produced.Load(),
+		Filtered:           j.filtered.Load(),
+		Failed:             j.failed.Load(),
+		Rate:               j.rate,
+		Error:              j.lastError,
+		StartedAt:          j.startedAt,
+	}
+}
+
+// bridgeProduceRequest 按映射规则将源消息转换为目标发送请求
+func bridgeProduceRequest(req *types.BridgeRequest, msg *types.Message) *types.ProduceRequest {
+	topic := req.TargetTopic
+	if topic == "" {
+		topic = req.TopicTransform.Apply(msg.Topic)
+	}
+
+	key := msg.Key
+	switch {
+	case req.KeyFrom == "none":
+		key = ""
+	case strings.HasPrefix(req.KeyFrom, "header:"):
+		key = msg.Headers[strings.TrimPrefix(req.KeyFrom, "header:")]
+	}
+
+	headers := make(map[string]string, len(msg.Headers)+len(req.SetHeaders))
+	for name, value := range msg.Headers {
+		if mapped, ok := req.HeaderMap[name]; ok {
+			if mapped == "" {
+				continue
+			}
+			name = mapped
+		}
+		headers[name] = value
+	}
+	for name, value := range req.SetHeaders {
+		headers[name] = value
+	}
+
+	return &types.ProduceRequest{
+		ConnectionID: req.TargetConnectionID,
+		Topic:        topic,
+		Key:          key,
+		Value:        msg.Value,
+		Headers:      headers,
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"mq-toolkit/pkg/types"
+	"testing"
+)
+
+func TestBridgeForwardsMessages(t *testing.T) {
+	env := newTestEnv(t)
+	sourceID := env.createMemoryConnection("source", "bridge-source")
+	targetID := env.createMemoryConnection("target", "bridge-target")
+	svc := NewBridgeService(env.ctx, env.logger, env.emit, env.factory, env.configSvc, env.historySvc)
+
+	env.publish("bridge-source", "orders", "first", "second", "third")
+
+	id, err := svc.StartBridge(&types.BridgeRequest{
+		SourceConnectionID: sourceID,
+		SourceTopics:       []string{"orders"},
+		FromBeginning:      true,
+		TargetConnectionID: targetID,
+		TargetTopic:        "copied",
+		SetHeaders:         map[string]string{"bridged": "yes"},
+	})
+	if err != nil {
+		t.Fatalf("StartBridge failed: %v", err)
+	}
+
+	env.waitFor("forwarded messages", func() bool {
+		msgs, _ := env.brokerMessages("bridge-target", "copied")
+		return len(msgs) == 3
+	})
+	for i, msg := range env.browse("bridge-target", "copied") {
+		if want := []string{"first", "second", "third"}[i]; msg.Value != want || msg.Headers["bridged"] != "yes" {
+			t.Errorf("message %d: got %q with headers %v, want %q with bridged=yes", i, msg.Value, msg.Headers, want)
+		}
+	}
+
+	svc.StopBridge(id)
+	status := env.waitEvent("bridge:status", func(data interface{}) bool {
+		return data.(types.BridgeStatus).State == types.BridgeStateStopped
+	}).(types.BridgeStatus)
+	if status.ID != id || status.Consumed != 3 || status.Produced != 3 {
+		t.Fatalf("bridge stopped with %+v, want %s with 3 consumed and produced", status, id)
+	}
+	env.waitFor("the stopped bridge to be removed", func() bool {
+		return len(svc.ListBridges()) == 0
+	})
+}
+
+func TestBridgeRejectsInvalidFilter(t *testing.T) {
+	env := newTestEnv(t)
+	sourceID := env.createMemoryConnection("source", "bridge-source")
+	targetID := env.createMemoryConnection("target", "bridge-target")
+	svc := NewBridgeService(env.ctx, env.logger, env.emit, env.factory, env.configSvc, env.historySvc)
+
+	_, err := svc.StartBridge(&types.BridgeRequest{
+		SourceConnectionID: sourceID,
+		SourceTopics:       []string{"orders"},
+		TargetConnectionID: targetID,
+		Filter:             "value ==",
+	})
+	if err == nil {
+		t.Fatal("StartBridge accepted an invalid filter")
+	}
+}
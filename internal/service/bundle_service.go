@@ -0,0 +1,417 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mq-toolkit/internal/secret"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BundleService 导出和导入连接、模板、环境的共享包
+type BundleService struct {
+	configSvc   *ConfigService
+	templateSvc *TemplateService
+	envSvc      *EnvironmentService
+}
+
+// NewBundleService 创建共享包服务
+func NewBundleService(configSvc *ConfigService, templateSvc *TemplateService, envSvc *EnvironmentService) *BundleService {
+	return &BundleService{configSvc: configSvc, templateSvc: templateSvc, envSvc: envSvc}
+}
+
+// Export 按请求导出共享包，凭据默认移除，也可用导出口令加密
+func (s *BundleService) Export(ctx context.Context, req *types.BundleExportRequest) (*types.Bundle, error) {
+	mode := req.SecretMode
+	if mode == "" {
+		mode = types.BundleSecretStrip
+	}
+	if mode != types.BundleSecretStrip && mode != types.BundleSecretEncrypt {
+		return nil, utils.NewValidationError("Invalid secret mode", string(mode))
+	}
+	if mode == types.BundleSecretEncrypt && req.Passphrase == "" {
+		return nil, utils.NewValidationError("Passphrase is required to encrypt secrets", "")
+	}
+
+	bundle := &types.Bundle{
+		Version:    types.BundleVersion,
+		ExportedAt: time.Now(),
+		SecretMode: mode,
+	}
+
+	var err error
+	if len(req.ConnectionIDs) > 0 {
+		if bundle.Connections, err = s.configSvc.ExportConnections(ctx, req.ConnectionIDs); err != nil {
+			return nil, err
+		}
+	}
+	if len(req.EnvironmentIDs) > 0 {
+		if bundle.Environments, err = s.envSvc.ExportEnvironments(ctx, req.EnvironmentIDs); err != nil {
+			return nil, err
+		}
+		for _, env := range bundle.Environments {
+			env.Active = false
+		}
+	}
+	if err := s.exportTemplates(ctx, bundle, req); err != nil {
+		return nil, err
+	}
+
+	transform := func(string) (string, error) { return "", nil }
+	if mode == types.BundleSecretEncrypt {
+		key, info, err := secret.NewPassphraseKey(req.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Encryption = &types.BundleEncryption{
+			Salt:     info.Salt,
+			Time:     info.Time,
+			Memory:   info.Memory,
+			Threads:  info.Threads,
+			Verifier: info.Verifier,
+		}
+		transform = key.Encrypt
+	}
+	if err := transformBundleSecrets(bundle, transform); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// exportTemplates 导出选中的集合（含子文件夹和模板）以及单独选中的模板
+func (s *BundleService) exportTemplates(ctx context.Context, bundle *types.Bundle, req *types.BundleExportRequest) error {
+	seenCollections := make(map[string]bool)
+	seenTemplates := make(map[string]bool)
+	for _, id := range req.CollectionIDs {
+		export, err := s.templateSvc.ExportCollection(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, collection := range export.Collections {
+			if !seenCollections[collection.ID] {
+				seenCollections[collection.ID] = true
+				bundle.Collections = append(bundle.Collections, collection)
+			}
+		}
+		for _, template := range export.Templates {
+			if !seenTemplates[template.ID] {
+				seenTemplates[template.ID] = true
+				bundle.Templates = append(bundle.Templates, template)
+			}
+		}
+	}
+	for _, id := range req.TemplateIDs {
+		if seenTemplates[id] {
+			continue
+		}
+		template, err := s.templateSvc.GetTemplate(ctx, id)
+		if err != nil {
+			return err
+		}
+		seenTemplates[id] = true
+		bundle.Templates = append(bundle.Templates, template)
+	}
+	return nil
+}
+
+// Import 导入共享包，先导入连接以便模板引用导入后的连接
+func (s *BundleService) Import(ctx context.Context, bundle *types.Bundle, req *types.BundleImportRequest) (*types.BundleImportResult, error) {
+	if bundle.Version > types.BundleVersion {
+		return nil, utils.NewValidationError("Unsupported bundle version", fmt.Sprint(bundle.Version))
+	}
+	mode := req.Conflict
+	if mode == "" {
+		mode = types.ImportConflictSkip
+	}
+	switch mode {
+	case types.ImportConflictSkip, types.ImportConflictRename, types.ImportConflictOverwrite:
+	default:
+		return nil, utils.NewValidationError("Invalid conflict mode", string(mode))
+	}
+
+	if bundle.SecretMode == types.BundleSecretEncrypt {
+		if bundle.Encryption == nil {
+			return nil, utils.NewValidationError("Bundle is missing encryption parameters", "")
+		}
+		if req.Passphrase == "" {
+			return nil, utils.NewAuthError("Passphrase is required to import encrypted secrets")
+		}
+		key, err := secret.OpenPassphraseKey(req.Passphrase, &secret.PassphraseInfo{
+			Salt:     bundle.Encryption.Salt,
+			Time:     bundle.Encryption.Time,
+			Memory:   bundle.Encryption.Memory,
+			Threads:  bundle.Encryption.Threads,
+			Verifier: bundle.Encryption.Verifier,
+		})
+		if errors.Is(err, secret.ErrWrongPassphrase) {
+			return nil, utils.NewAuthError("Wrong bundle passphrase")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := transformBundleSecrets(bundle, key.Decrypt); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &types.BundleImportResult{FilePath: req.FilePath}
+	connectionIDs := make(map[string]string, len(bundle.Connections))
+	for _, config := range bundle.Connections {
+		item, id, err := s.configSvc.ImportConnection(ctx, config, mode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import connection %s: %w", config.Name, err)
+		}
+		connectionIDs[config.ID] = id
+		result.Items = append(result.Items, item)
+	}
+
+	for _, env := range bundle.Environments {
+		item, err := s.envSvc.ImportEnvironment(ctx, env, mode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import environment %s: %w", env.Name, err)
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	for _, template := range bundle.Templates {
+		if template.ConnectionID == "" {
+			continue
+		}
+		if id, ok := connectionIDs[template.ConnectionID]; ok {
+			template.ConnectionID = id
+		} else if _, err := s.configSvc.GetConnection(ctx, template.ConnectionID); err != nil {
+			// 引用的连接既不在共享包中也不在本地
+			template.ConnectionID = ""
+		}
+	}
+	items, err := s.templateSvc.ImportTemplates(ctx, bundle.Collections, bundle.Templates, mode)
+	if err != nil {
+		return nil, err
+	}
+	result.Items = append(result.Items, items...)
+	return result, nil
+}
+
+// transformBundleSecrets 对共享包中的连接密码、隧道凭据、凭据类 Extra 和密文环境变量应用 fn
+func transformBundleSecrets(bundle *types.Bundle, fn func(string) (string, error)) error {
+	var err error
+	for _, config := range bundle.Connections {
+		for _, field := range connectionSecretFields(config) {
+			if *field != "" {
+				if *field, err = fn(*field); err != nil {
+					return err
+				}
+			}
+		}
+		for name, value := range config.Extra {
+			if value != "" && secret.IsSensitiveKey(name) {
+				if config.Extra[name], err = fn(value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, env := range bundle.Environments {
+		for i, v := range env.Variables {
+			if v.Secret && v.Value != "" {
+				if env.Variables[i].Value, err = fn(v.Value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// EncodeBundle 将共享包编码为 json 或 yaml
+func EncodeBundle(bundle *types.Bundle, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(format) {
+	case "", "json":
+		return data, nil
+	case "yaml", "yml":
+		// 经由 JSON 转换，使 yaml 的字段名与 json 标签一致
+		var doc interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(doc)
+	default:
+		return nil, utils.NewValidationError("Unsupported bundle format", format)
+	}
+}
+
+// DecodeBundle 解析 json 或 yaml 格式的共享包
+func DecodeBundle(data []byte) (*types.Bundle, error) {
+	trimmed := bytes.TrimSpace(data)
+	if !bytes.HasPrefix(trimmed, []byte("{")) {
+		var doc interface{}
+		if err := yaml.Unmarshal(trimmed, &doc); err != nil {
+			return nil, utils.NewValidationError("Invalid bundle file", err.Error())
+		}
+		converted, err := json.Marshal(doc)
+		if err != nil {
+			return nil, utils.NewValidationError("Invalid bundle file", err.Error())
+		}
+		trimmed = converted
+	}
+
+	var bundle types.Bundle
+	if err := json.Unmarshal(trimmed, &bundle); err != nil {
+		return nil, utils.NewValidationError("Invalid bundle file", err.Error())
+	}
+	if bundle.Version == 0 {
+		return nil, utils.NewValidationError("Invalid bundle file", "missing version")
+	}
+	return &bundle, nil
+}
+
+// 各类型 URI 的默认端口
+var uriDefaultPorts = map[string]int{
+	"amqp":       5672,
+	"amqps":      5671,
+	"kafka":      9092,
+	"rocketmq":   9876,
+	"nats":       4222,
+	"mqtt":       1883,
+	"mqtts":      8883,
+	"redis":      6379,
+	"rediss":     6379,
+	"pulsar":     6650,
+	"pulsar+ssl": 6651,
+	"amqp10":     5672,
+	"amqp10s":    5671,
+	"sqs":        9324,
+	"sqss":       443,
+}
+
+// ParseConnectionURI 解析连接 URI，支持 amqp://、amqps://、kafka://、rocketmq://、nats://、mqtt://、mqtts://、redis://、rediss://、pulsar://、pulsar+ssl://、amqp10://、amqp10s://、sqs:// 和 sqss://
+//
+// amqp:// 和 amqps:// 对应 RabbitMQ（AMQP 0-9-1），AMQP 1.0 broker 使用 amqp10:// 和 amqp10s://。
+// sqs:// 的用户名和密码为 AWS 访问密钥，查询参数 region 写入区域，sqss:// 使用 https。
+// 可用逗号分隔多个节点，第一个作为主地址，其余写入 Endpoints；
+// 查询参数 name 指定连接名称，kafka 的 group_id 写入消费组，其余参数写入 Extra。
+func ParseConnectionURI(uri string) (*types.ConnectionConfig, error) {
+	scheme, _, found := strings.Cut(strings.TrimSpace(uri), "://")
+	if !found {
+		return nil, utils.NewValidationError("Invalid connection URI", uri)
+	}
+	defaultPort, ok := uriDefaultPorts[strings.ToLower(scheme)]
+	if !ok {
+		return nil, utils.NewValidationError("Unsupported URI scheme", scheme)
+	}
+	cs, err := utils.ParseConnectionString(uri, defaultPort)
+	if err != nil {
+		return nil, utils.NewValidationError("Invalid connection URI", err.Error())
+	}
+
+	config := &types.ConnectionConfig{
+		Host:     cs.Endpoints[0].Host,
+		Port:     cs.Endpoints[0].Port,
+		Username: cs.Username,
+		Password: cs.Password,
+		Extra:    map[string]string{},
+	}
+	for _, endpoint := range cs.Endpoints[1:] {
+		config.Endpoints = append(config.Endpoints, endpoint.String())
+	}
+
+	switch cs.Scheme {
+	case "amqp", "amqps":
+		config.Type = types.MQTypeRabbitMQ
+		if cs.Scheme == "amqps" {
+			config.Extra["tls"] = "true"
+		}
+		config.VHost = cs.Path
+		if config.VHost == "" {
+			config.VHost = "/"
+		}
+	case "kafka":
+		config.Type = types.MQTypeKafka
+	case "rocketmq":
+		config.Type = types.MQTypeRocketMQ
+	case "nats":
+		config.Type = types.MQTypeNATS
+		// 与 NATS 客户端一致，只有用户名时作为令牌
+		if config.Username != "" && config.Password == "" {
+			config.Extra["token"] = config.Username
+			config.Username = ""
+		}
+	case "mqtt", "mqtts":
+		config.Type = types.MQTypeMQTT
+		if cs.Scheme == "mqtts" {
+			config.Extra["tls"] = "true"
+		}
+	case "redis", "rediss":
+		config.Type = types.MQTypeRedis
+		if cs.Scheme == "rediss" {
+			config.Extra["tls"] = "true"
+		}
+		// 路径为数据库编号，如 redis://host:6379/2
+		if cs.Path != "" {
+			config.Extra["db"] = cs.Path
+		}
+	case "pulsar", "pulsar+ssl":
+		config.Type = types.MQTypePulsar
+		if cs.Scheme == "pulsar+ssl" {
+			config.Extra["tls"] = "true"
+		}
+	case "amqp10", "amqp10s":
+		config.Type = types.MQTypeAMQP10
+		if cs.Scheme == "amqp10s" {
+			config.Extra["tls"] = "true"
+		}
+		// 路径为 open 帧中的 hostname
+		config.VHost = cs.Path
+	case "sqs", "sqss":
+		config.Type = types.MQTypeSQS
+		if cs.Scheme == "sqss" {
+			config.Extra["tls"] = "true"
+		}
+		config.AccessKey, config.SecretKey = config.Username, config.Password
+		config.Username, config.Password = "", ""
+	}
+
+	for name, value := range cs.Params {
+		switch {
+		case name == "name":
+			config.Name = value
+		case name == "group_id" && config.Type == types.MQTypeKafka:
+			config.GroupID = value
+		case name == "region" && config.Type == types.MQTypeSQS:
+			config.Region = value
+		default:
+			config.Extra[name] = value
+		}
+	}
+	if config.Name == "" {
+		config.Name = fmt.Sprintf("%s-%s", config.Type, config.Host)
+	}
+	return config, nil
+}
+
+// ImportConnectionURI 解析连接 URI 并导入，name 不为空时覆盖 URI 中的名称
+func (s *BundleService) ImportConnectionURI(ctx context.Context, uri, name string, mode types.ImportConflictMode) (*types.ImportItemResult, error) {
+	config, err := ParseConnectionURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		config.Name = name
+	}
+	if mode == "" {
+		mode = types.ImportConflictSkip
+	}
+	result, _, err := s.configSvc.ImportConnection(ctx, config, mode)
+	return result, err
+}
@@ -3,20 +3,41 @@ package service
 import (
 	"context"
 	"fmt"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/secret"
+	"mq-toolkit/internal/tunnel"
 	"mq-toolkit/pkg/types"
 	"mq-toolkit/pkg/utils"
 
 	"gorm.io/gorm"
 )
 
+// ConnectionResolver 在连接配置返回给调用方前对其进行改写，例如展开环境变量
+type ConnectionResolver func(ctx context.Context, config *types.ConnectionConfig) error
+
 // ConfigService 配置管理服务
+//
+// 密码和 Extra 中的凭据加密存储，返回给前端时以 types.SecretMask 代替。
 type ConfigService struct {
-	db *gorm.DB
+	db       *gorm.DB
+	secrets  *secret.Store
+	resolver ConnectionResolver
+	timeouts types.TimeoutConfig // 全局默认超时，连接未设置的字段使用该值
 }
 
 // NewConfigService 创建配置服务
-func NewConfigService(db *gorm.DB) *ConfigService {
-	return &ConfigService{db: db}
+func NewConfigService(db *gorm.DB, secrets *secret.Store) *ConfigService {
+	return &ConfigService{db: db, secrets: secrets}
+}
+
+// SetResolver 设置连接配置解析器，GetConnection 返回前调用
+func (s *ConfigService) SetResolver(resolver ConnectionResolver) {
+	s.resolver = resolver
+}
+
+// SetDefaultTimeouts 设置全局默认超时，GetConnection 用它补全连接未设置的超时
+func (s *ConfigService) SetDefaultTimeouts(timeouts types.TimeoutConfig) {
+	s.timeouts = timeouts
 }
 
 // CreateConnection 创建连接配置
@@ -24,6 +45,9 @@ func (s *ConfigService) CreateConnection(ctx context.Context, config *types.Conn
 	if config.ID == "" {
 		config.ID = utils.GenerateID()
 	}
+	if err := checkTunnelSupported(config); err != nil {
+		return err
+	}
 	
 	// 检查名称是否重复
 	var count int64
@@ -33,8 +57,12 @@ func (s *ConfigService) CreateConnection(ctx context.Context, config *types.Conn
 	if count > 0 {
 		return fmt.Errorf("connection name '%s' already exists", config.Name)
 	}
-	
-	return s.db.Create(config).Error
+
+	stored := *config
+	if err := s.encryptSecrets(&stored, nil); err != nil {
+		return err
+	}
+	return s.db.Create(&stored).Error
 }
 
 // UpdateConnection 更新连接配置
@@ -47,6 +75,9 @@ func (s *ConfigService) UpdateConnection(ctx context.Context, config *types.Conn
 		}
 		return fmt.Errorf("failed to find connection: %w", err)
 	}
+	if err := checkTunnelSupported(config); err != nil {
+		return err
+	}
 	
 	// 检查名称是否与其他连接重复
 	var count int64
@@ -56,8 +87,13 @@ func (s *ConfigService) UpdateConnection(ctx context.Context, config *types.Conn
 	if count > 0 {
 		return fmt.Errorf("connection name '%s' already exists", config.Name)
 	}
-	
-	return s.db.Save(config).Error
+
+	// 前端传回掩码时保留已保存的值
+	stored := *config
+	if err := s.encryptSecrets(&stored, &existing); err != nil {
+		return err
+	}
+	return s.db.Save(&stored).Error
 }
 
 // DeleteConnection 删除连接配置
@@ -72,24 +108,36 @@ func (s *ConfigService) DeleteConnection(ctx context.Context, id string) error {
 	return nil
 }
 
-// GetConnection 获取连接配置
+// GetConnection 获取用于建立连接的配置，已经过解析器处理并补全了默认超时
 func (s *ConfigService) GetConnection(ctx context.Context, id string) (*types.ConnectionConfig, error) {
 	var config types.ConnectionConfig
 	if err := s.db.First(&config, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("connection not found: %s", id)
+			return nil, utils.NewNotFoundError("connection", id)
 		}
 		return nil, fmt.Errorf("failed to get connection: %w", err)
 	}
+	if err := s.decryptSecrets(&config); err != nil {
+		return nil, err
+	}
+	if s.resolver != nil {
+		if err := s.resolver(ctx, &config); err != nil {
+			return nil, err
+		}
+	}
+	config.Timeouts = config.Timeouts.WithDefaults(s.timeouts)
 	return &config, nil
 }
 
-// ListConnections 列出所有连接配置
+// ListConnections 列出所有连接配置，凭据以掩码返回
 func (s *ConfigService) ListConnections(ctx context.Context) ([]*types.ConnectionConfig, error) {
 	var configs []*types.ConnectionConfig
 	if err := s.db.Order("created DESC").Find(&configs).Error; err != nil {
 		return nil, fmt.Errorf("failed to list connections: %w", err)
 	}
+	for _, config := range configs {
+		maskConnectionSecrets(config)
+	}
 	return configs, nil
 }
 
@@ -99,5 +147,309 @@ func (s *ConfigService) ListConnectionsByType(ctx context.Context, mqType types.
 	if err := s.db.Where("type = ?", mqType).Order("created DESC").Find(&configs).Error; err != nil {
 		return nil, fmt.Errorf("failed to list connections by type: %w", err)
 	}
+	for _, config := range configs {
+		maskConnectionSecrets(config)
+	}
+	return configs, nil
+}
+
+// EncryptPlaintextSecrets 加密旧版本以明文保存的凭据，返回处理的连接数
+func (s *ConfigService) EncryptPlaintextSecrets(ctx context.Context) (int, error) {
+	key, err := s.secrets.Current()
+	if err != nil {
+		return 0, err
+	}
+	return s.reencrypt(key, key, func(config *types.ConnectionConfig) bool {
+		for _, field := range connectionSecretFields(config) {
+			if *field != "" && !secret.IsEncrypted(*field) {
+				return true
+			}
+		}
+		for name, value := range config.Extra {
+			if secret.IsSensitiveKey(name) && value != "" && !secret.IsEncrypted(value) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// RotateKey 生成新密钥并重新加密所有凭据和环境的密文变量，passphrase 为空时改用本地密钥文件
+func (s *ConfigService) RotateKey(ctx context.Context, passphrase string) error {
+	current, err := s.secrets.Current()
+	if err != nil {
+		return err
+	}
+	next, err := secret.NewKey(passphrase)
+	if err != nil {
+		return err
+	}
+
+	// 先在事务中重新加密，成功后再替换密钥文件
+	tx := s.db.Begin()
+	if _, err := s.reencryptTx(tx, current, next, nil); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := reencryptEnvironmentsTx(tx, current, next, nil); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := s.secrets.Commit(next); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		// 密钥文件已替换，恢复旧密钥以保持与数据库一致
+		if restoreErr := s.secrets.Commit(current); restoreErr != nil {
+			return fmt.Errorf("failed to commit re-encrypted secrets: %w (restoring previous key also failed: %v)", err, restoreErr)
+		}
+		return fmt.Errorf("failed to commit re-encrypted secrets: %w", err)
+	}
+	return nil
+}
+
+// reencrypt 用 from 解密并用 to 重新加密满足 filter 的连接（filter 为 nil 表示全部）
+func (s *ConfigService) reencrypt(from, to *secret.Key, filter func(*types.ConnectionConfig) bool) (int, error) {
+	var count int
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		count, err = s.reencryptTx(tx, from, to, filter)
+		return err
+	})
+	return count, err
+}
+
+func (s *ConfigService) reencryptTx(tx *gorm.DB, from, to *secret.Key, filter func(*types.ConnectionConfig) bool) (int, error) {
+	var configs []*types.ConnectionConfig
+	if err := tx.Find(&configs).Error; err != nil {
+		return 0, fmt.Errorf("failed to list connections: %w", err)
+	}
+
+	count := 0
+	for _, config := range configs {
+		if filter != nil && !filter(config) {
+			continue
+		}
+		for _, field := range connectionSecretFields(config) {
+			value, err := recrypt(from, to, *field)
+			if err != nil {
+				return 0, fmt.Errorf("connection %s: %w", config.Name, err)
+			}
+			*field = value
+		}
+
+		if len(config.Extra) > 0 {
+			extra := make(map[string]string, len(config.Extra))
+			for name, value := range config.Extra {
+				if secret.IsSensitiveKey(name) {
+					var err error
+					if value, err = recrypt(from, to, value); err != nil {
+						return 0, fmt.Errorf("connection %s: %w", config.Name, err)
+					}
+				}
+				extra[name] = value
+			}
+			config.Extra = extra
+		}
+
+		if err := tx.Model(config).Select("password", "secret_key", "session_token", "extra", "tunnel").Updates(&types.ConnectionConfig{
+			Password: config.Password, SecretKey: config.SecretKey, SessionToken: config.SessionToken, Extra: config.Extra, Tunnel: config.Tunnel,
+		}).Error; err != nil {
+			return 0, fmt.Errorf("failed to update connection %s: %w", config.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+func recrypt(from, to *secret.Key, value string) (string, error) {
+	plaintext, err := from.Decrypt(value)
+	if err != nil {
+		return "", err
+	}
+	return to.Encrypt(plaintext)
+}
+
+// encryptSecrets 加密待保存的凭据，existing 非空时掩码值替换为已保存的值
+func (s *ConfigService) encryptSecrets(config *types.ConnectionConfig, existing *types.ConnectionConfig) error {
+	if config.Tunnel != nil {
+		// 复制隧道配置，避免修改调用方的数据
+		tunnel := *config.Tunnel
+		config.Tunnel = &tunnel
+	}
+	var previous []*string
+	if existing != nil {
+		previous = connectionSecretFields(existing)
+	}
+	var err error
+	for i, field := range connectionSecretFields(config) {
+		if *field == types.SecretMask && i < len(previous) {
+			// 已保存的值本身是密文，直接保留
+			*field = *previous[i]
+			continue
+		}
+		if *field, err = s.secrets.Encrypt(*field); err != nil {
+			return err
+		}
+	}
+
+	if len(config.Extra) == 0 {
+		return nil
+	}
+	extra := make(map[string]string, len(config.Extra))
+	for name, value := range config.Extra {
+		if secret.IsSensitiveKey(name) {
+			if existing != nil && value == types.SecretMask {
+				value = existing.Extra[name]
+			} else if value, err = s.secrets.Encrypt(value); err != nil {
+				return err
+			}
+		}
+		extra[name] = value
+	}
+	config.Extra = extra
+	return nil
+}
+
+// decryptSecrets 解密连接中的凭据
+func (s *ConfigService) decryptSecrets(config *types.ConnectionConfig) error {
+	var err error
+	for _, field := range connectionSecretFields(config) {
+		if *field, err = s.secrets.Decrypt(*field); err != nil {
+			return utils.NewAuthError(fmt.Sprintf("failed to decrypt credentials of connection %s: %v", config.Name, err))
+		}
+	}
+	for name, value := range config.Extra {
+		if secret.IsSensitiveKey(name) {
+			if config.Extra[name], err = s.secrets.Decrypt(value); err != nil {
+				return utils.NewAuthError(fmt.Sprintf("failed to decrypt %s of connection %s: %v", name, config.Name, err))
+			}
+		}
+	}
+	return nil
+}
+
+// checkTunnelSupported 后端不支持隧道时拒绝配置了隧道或地址映射的连接，避免保存后才在连接时失败
+func checkTunnelSupported(config *types.ConnectionConfig) error {
+	if !tunnel.Enabled(config.Tunnel) {
+		return nil
+	}
+	desc, ok := mq.Lookup(config.Type)
+	if !ok || desc.Has(types.CapabilityTunnel) {
+		return nil
+	}
+	return utils.NewValidationError(fmt.Sprintf("%s does not support tunnels or proxies", desc.DisplayName), desc.TunnelHelp)
+}
+
+// connectionSecretFields 返回连接中需要加密的字段：密码、AWS 秘密访问密钥和会话令牌以及隧道的密码、私钥和私钥口令
+//
+// 字段顺序固定，更新时按下标与已保存的连接对应。
+func connectionSecretFields(config *types.ConnectionConfig) []*string {
+	fields := []*string{&config.Password, &config.SecretKey, &config.SessionToken}
+	if config.Tunnel != nil {
+		fields = append(fields, &config.Tunnel.Password, &config.Tunnel.PrivateKey, &config.Tunnel.Passphrase)
+	}
+	return fields
+}
+
+// maskConnectionSecrets 将凭据替换为掩码
+func maskConnectionSecrets(config *types.ConnectionConfig) {
+	for _, field := range connectionSecretFields(config) {
+		if *field != "" {
+			*field = types.SecretMask
+		}
+	}
+	for name, value := range config.Extra {
+		if secret.IsSensitiveKey(name) && value != "" {
+			config.Extra[name] = types.SecretMask
+		}
+	}
+}
+
+// ExportConnections 返回解密后的连接配置（不经过解析器），用于导出共享包
+func (s *ConfigService) ExportConnections(ctx context.Context, ids []string) ([]*types.ConnectionConfig, error) {
+	var configs []*types.ConnectionConfig
+	if err := s.db.Where("id IN ?", ids).Order("created ASC").Find(&configs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list connections: %w", err)
+	}
+	for _, config := range configs {
+		if err := s.decryptSecrets(config); err != nil {
+			return nil, err
+		}
+	}
 	return configs, nil
 }
+
+// ImportConnection 导入连接配置，按名称处理冲突，返回导入结果和导入后的连接ID
+//
+// 覆盖已有连接时，空的密码、隧道凭据和 Extra 凭据保留已保存的值，便于导入移除了凭据的共享包。
+func (s *ConfigService) ImportConnection(ctx context.Context, config *types.ConnectionConfig, mode types.ImportConflictMode) (*types.ImportItemResult, string, error) {
+	result := &types.ImportItemResult{Kind: "connection", Name: config.Name, Action: "created"}
+	imported := *config
+	imported.ID = ""
+
+	var existing []*types.ConnectionConfig
+	if err := s.db.Where("name = ?", config.Name).Limit(1).Find(&existing).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to check connection name: %w", err)
+	}
+	if len(existing) > 0 {
+		switch mode {
+		case types.ImportConflictOverwrite:
+			imported.ID = existing[0].ID
+			imported.Created = existing[0].Created
+			if imported.Tunnel != nil {
+				tunnel := *imported.Tunnel
+				imported.Tunnel = &tunnel
+			}
+			for _, field := range connectionSecretFields(&imported) {
+				if *field == "" {
+					*field = types.SecretMask
+				}
+			}
+			for name, value := range imported.Extra {
+				if secret.IsSensitiveKey(name) && value == "" {
+					imported.Extra[name] = types.SecretMask
+				}
+			}
+			if err := s.UpdateConnection(ctx, &imported); err != nil {
+				return nil, "", err
+			}
+			result.Action = "overwritten"
+			return result, imported.ID, nil
+		case types.ImportConflictRename:
+			name, err := uniqueName(config.Name, func(name string) (bool, error) {
+				var count int64
+				err := s.db.Model(&types.ConnectionConfig{}).Where("name = ?", name).Count(&count).Error
+				return count > 0, err
+			})
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to check connection name: %w", err)
+			}
+			imported.Name = name
+			result.Action, result.NewName = "renamed", name
+		default:
+			result.Action = "skipped"
+			return result, existing[0].ID, nil
+		}
+	}
+
+	if err := s.CreateConnection(ctx, &imported); err != nil {
+		return nil, "", err
+	}
+	return result, imported.ID, nil
+}
+
+// uniqueName 在 base 后追加序号直到 exists 返回 false
+func uniqueName(base string, exists func(name string) (bool, error)) (string, error) {
+	for i := 2; ; i++ {
+		name := fmt.Sprintf("%s (%d)", base, i)
+		taken, err := exists(name)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return name, nil
+		}
+	}
+}
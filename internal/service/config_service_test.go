@@ -0,0 +1,46 @@
+package service
+
+import (
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"testing"
+)
+
+func TestCreateConnectionRejectsUnsupportedTunnel(t *testing.T) {
+	env := newTestEnv(t)
+	tunnel := &types.TunnelConfig{Type: types.TunnelSSH, Host: "bastion", Port: 22}
+
+	err := env.configSvc.CreateConnection(env.ctx, &types.ConnectionConfig{Name: "rocketmq", Type: types.MQTypeRocketMQ, Host: "127.0.0.1", Port: 9876, Tunnel: tunnel})
+	if !utils.IsErrorType(err, utils.ErrorTypeValidation) {
+		t.Fatalf("CreateConnection returned %v, want a validation error for a RocketMQ tunnel", err)
+	}
+
+	if err := env.configSvc.CreateConnection(env.ctx, &types.ConnectionConfig{Name: "kafka", Type: types.MQTypeKafka, Host: "broker", Port: 9092, Tunnel: tunnel}); err != nil {
+		t.Fatalf("CreateConnection rejected a Kafka tunnel: %v", err)
+	}
+}
+
+func TestConnectionPasswordRoundTrip(t *testing.T) {
+	env := newTestEnv(t)
+
+	// 以密文前缀开头的明文同样加密保存
+	password := "enc:v1:not-really-encrypted"
+	config := &types.ConnectionConfig{Name: "memory", Type: types.MQTypeMemory, Host: t.Name(), Password: password}
+	if err := env.configSvc.CreateConnection(env.ctx, config); err != nil {
+		t.Fatalf("CreateConnection failed: %v", err)
+	}
+	got, err := env.configSvc.GetConnection(env.ctx, config.ID)
+	if err != nil || got.Password != password {
+		t.Fatalf("GetConnection returned %+v, %v; want password %q", got, err, password)
+	}
+
+	// 掩码保留已保存的密码，不会被再次加密
+	config.Password = types.SecretMask
+	if err := env.configSvc.UpdateConnection(env.ctx, config); err != nil {
+		t.Fatalf("UpdateConnection failed: %v", err)
+	}
+	got, err = env.configSvc.GetConnection(env.ctx, config.ID)
+	if err != nil || got.Password != password {
+		t.Fatalf("GetConnection after a masked update returned %+v, %v; want password %q", got, err, password)
+	}
+}
@@ -2,125 +2,244 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"mq-toolkit/internal/factory"
 	"mq-toolkit/internal/logger"
 	"mq-toolkit/internal/mq"
 	"mq-toolkit/pkg/types"
 	"mq-toolkit/pkg/utils"
-	"strings"
 	"sync"
-
-	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // ConsumerService 负责管理消息消费
 type ConsumerService struct {
 	ctx        context.Context
 	logger     *logger.Logger
+	emit       EventEmitter
 	mqFactory  factory.Factory
 	configSvc  *ConfigService
 	historySvc *HistoryService
+	messageSvc *MessageService
+	healthSvc  *HealthService
 	activeSubs sync.Map // 存储活跃的订阅 [subscriptionID -> *activeSubscription]
 }
 
 // activeSubscription 代表一个活跃的订阅
 type activeSubscription struct {
-	consumer     mq.Consumer
+	mu           sync.Mutex
+	consumer     mq.Consumer // 重连期间为 nil
+	stopped      bool
 	cancel       context.CancelFunc
 	connectionID string
 	topics       []string
 }
 
+// replace 换上新的消费者并返回旧的消费者，订阅已停止时返回 false
+func (sub *activeSubscription) replace(consumer mq.Consumer) (mq.Consumer, bool) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if sub.stopped {
+		return nil, false
+	}
+	previous := sub.consumer
+	sub.consumer = consumer
+	return previous, true
+}
+
+// stop 取消消费并关闭当前的消费者
+func (sub *activeSubscription) stop() {
+	sub.mu.Lock()
+	sub.stopped = true
+	consumer := sub.consumer
+	sub.consumer = nil
+	sub.mu.Unlock()
+
+	sub.cancel() // 取消上下文，这将导致 Consume 循环退出
+	if consumer != nil {
+		consumer.Close() // 关闭底层连接
+	}
+}
+
 // NewConsumerService 创建一个新的 ConsumerService
-func NewConsumerService(ctx context.Context, logger *logger.Logger, factory factory.Factory, configSvc *ConfigService, historySvc *HistoryService) *ConsumerService {
+func NewConsumerService(ctx context.Context, logger *logger.Logger, emit EventEmitter, factory factory.Factory, configSvc *ConfigService, historySvc *HistoryService, messageSvc *MessageService, healthSvc *HealthService) *ConsumerService {
 	return &ConsumerService{
 		ctx:        ctx,
 		logger:     logger,
+		emit:       emitter(emit),
 		mqFactory:  factory,
 		configSvc:  configSvc,
 		historySvc: historySvc,
+		messageSvc: messageSvc,
+		healthSvc:  healthSvc,
 	}
 }
 
+// MessageSink 接收订阅消费到的消息，返回前消费暂停；ctx 结束时应返回 ctx 的错误，消息不会被确认
+type MessageSink func(ctx context.Context, msg *types.Message) error
+
 // StartConsuming 开始消费消息
 func (s *ConsumerService) StartConsuming(req *types.ConsumeRequest) (string, error) {
+	return s.StartConsumingTo(req, nil)
+}
+
+// StartConsumingTo 开始消费消息，并在消息保存、推送事件后交给 sink，sink 处理完成后才确认消息
+func (s *ConsumerService) StartConsumingTo(req *types.ConsumeRequest, sink MessageSink) (string, error) {
+	consumer, err := s.connect(s.ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	// 创建一个可取消的上下文来控制消费 goroutine
+	consumeCtx, cancel := context.WithCancel(s.ctx)
+	subscriptionID := utils.GenerateID()
+
+	// 保存订阅信息
+	sub := &activeSubscription{
+		consumer:     consumer,
+		cancel:       cancel,
+		connectionID: req.ConnectionID,
+		topics:       req.Topics,
+	}
+	s.activeSubs.Store(subscriptionID, sub)
+	s.healthSvc.Report(&types.ConnectionStatus{
+		ConnectionID:   req.ConnectionID,
+		SubscriptionID: subscriptionID,
+		State:          types.ConnectionStateConnected,
+	})
+
+	// 在一个新的 goroutine 中开始消费
+	go s.consume(consumeCtx, subscriptionID, sub, consumer, req, sink)
+
+	return subscriptionID, nil
+}
+
+// connect 按最新的连接配置创建消费者、连接并订阅
+func (s *ConsumerService) connect(ctx context.Context, req *types.ConsumeRequest) (mq.Consumer, error) {
 	// 获取连接配置
-	connConfig, err := s.configSvc.GetConnection(s.ctx, req.ConnectionID)
+	connConfig, err := s.configSvc.GetConnection(ctx, req.ConnectionID)
 	if err != nil {
-		return "", fmt.Errorf("failed to get connection config: %w", err)
+		return nil, fmt.Errorf("failed to get connection config: %w", err)
 	}
 
 	// 创建消费者
 	consumer, err := s.mqFactory.CreateConsumer(connConfig.Type)
 	if err != nil {
-		return "", fmt.Errorf("failed to create consumer: %w", err)
+		return nil, fmt.Errorf("failed to create consumer: %w", err)
 	}
 
 	// 连接
-	if err := consumer.Connect(s.ctx, connConfig); err != nil {
-		return "", fmt.Errorf("failed to connect: %w", err)
+	if err := consumer.Connect(ctx, connConfig); err != nil {
+		consumer.Close()
+		return nil, fmt.Errorf("failed to connect: %w", err)
 	}
 
 	// 订阅
-	if err := consumer.Subscribe(s.ctx, req); err != nil {
+	if err := consumer.Subscribe(ctx, req); err != nil {
 		consumer.Close()
-		return "", fmt.Errorf("failed to subscribe: %w", err)
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
 	}
+	return consumer, nil
+}
 
-	// 创建一个可取消的上下文来控制消费 goroutine
-	consumeCtx, cancel := context.WithCancel(s.ctx)
-	subscriptionID := utils.GenerateID()
+// consume 运行消费循环，消费因连接错误中断时按指数退避重新连接并订阅
+func (s *ConsumerService) consume(ctx context.Context, subscriptionID string, sub *activeSubscription, consumer mq.Consumer, req *types.ConsumeRequest, sink MessageSink) {
+	s.logger.Info("ConsumerService", fmt.Sprintf("Starting consumer for subscription %s", subscriptionID))
 
-	// 保存订阅信息
-	s.activeSubs.Store(subscriptionID, &activeSubscription{
-		consumer:     consumer,
-		cancel:       cancel,
-		connectionID: req.ConnectionID,
-		topics:       req.Topics,
-	})
+	handler := func(msg *types.Message) error {
+		msg.SubscriptionID = subscriptionID
+		// 记录收到的消息
+		s.logger.Info("ConsumerService", fmt.Sprintf("Received message from topic %s: %s", msg.Topic, msg.Value))
 
-	// 在一个新的 goroutine 中开始消费
-	go func() {
-		s.logger.Info("ConsumerService", fmt.Sprintf("Starting consumer for subscription %s", subscriptionID))
+		// 持久化完整消息，便于之后检索
+		if err := s.messageSvc.SaveMessage(s.ctx, subscriptionID, req.ConnectionID, msg); err != nil {
+			s.logger.Error("ConsumerService", fmt.Sprintf("Failed to store message %s: %v", msg.ID, err))
+		}
 
-		err := consumer.Consume(consumeCtx, func(msg *types.Message) error {
-			// 记录收到的消息
-			s.logger.Info("ConsumerService", fmt.Sprintf("Received message from topic %s: %s", msg.Topic, msg.Value))
+		// 记录消费历史
+		s.historySvc.AddConsumeRecord(s.ctx, req.ConnectionID, msg.Topic, true, fmt.Sprintf("Consumed message: %s", msg.Value), 0)
 
-			// 记录消费历史
-			s.historySvc.AddConsumeRecord(s.ctx, req.ConnectionID, msg.Topic, true, fmt.Sprintf("Consumed message: %s", msg.Value), 0)
+		// 将消息发送到前端
+		s.emit("message:received", msg)
+		s.logger.Info("ConsumerService", "Message event emitted")
+		if sink != nil {
+			return sink(ctx, msg)
+		}
+		return nil
+	}
+
+	for consumer != nil {
+		err := consumer.Consume(ctx, handler)
+
+		// 订阅已被停止，属于正常结束
+		if ctx.Err() != nil {
+			s.logger.Info("ConsumerService", fmt.Sprintf("Consumer %s stopped normally", subscriptionID))
+			break
+		}
 
-			// 将消息发送到前端
-			runtime.EventsEmit(s.ctx, "message:received", msg)
-			s.logger.Info("ConsumerService", "Message event emitted to frontend")
+		// 连接被 broker 关闭时部分实现返回 nil，同样需要重连
+		if err == nil {
+			err = utils.NewConnectionError("Consumer stopped unexpectedly", nil)
+		}
+		s.logger.Error("ConsumerService", fmt.Sprintf("Consumption error for %s: %v", subscriptionID, err))
+		consumer = s.reconnect(ctx, subscriptionID, sub, req, err)
+	}
+
+	s.logger.Info("ConsumerService", fmt.Sprintf("Stopping consumer for subscription %s", subscriptionID))
+	s.stopAndRemove(subscriptionID)
+}
+
+// reconnect 关闭失效的消费者并按指数退避重建，订阅停止或连接被删除时返回 nil
+func (s *ConsumerService) reconnect(ctx context.Context, subscriptionID string, sub *activeSubscription, req *types.ConsumeRequest, cause error) mq.Consumer {
+	if previous, ok := sub.replace(nil); ok && previous != nil {
+		previous.Close()
+	}
+
+	for attempt := 1; ; attempt++ {
+		if !s.healthSvc.waitBackoff(ctx, req.ConnectionID, subscriptionID, attempt, cause) {
 			return nil
-		})
+		}
 
-		// 消费结束后（可能因为错误或取消）
-		if err != nil && err != context.Canceled {
-			// 检查是否是正常的连接关闭错误
-			errMsg := err.Error()
-			isNormalShutdown := strings.Contains(errMsg, "connection closed") ||
-				strings.Contains(errMsg, "use of closed network connection") ||
-				strings.Contains(errMsg, "CONN_")
-
-			if !isNormalShutdown {
-				s.logger.Error("ConsumerService", fmt.Sprintf("Consumption error for %s: %v", subscriptionID, err))
-				runtime.EventsEmit(s.ctx, "consumer:error", map[string]string{
-					"subscriptionId": subscriptionID,
-					"error":          err.Error(),
-				})
-			} else {
-				s.logger.Info("ConsumerService", fmt.Sprintf("Consumer %s stopped normally", subscriptionID))
-			}
+		consumer, err := s.connect(ctx, req)
+		if isFatalConnectError(err) {
+			// 连接配置已被删除或配置、认证有误，重试也无法恢复
+			s.logger.Error("ConsumerService", fmt.Sprintf("Subscription %s cannot reconnect: %v", subscriptionID, err))
+			s.emit("consumer:error", map[string]string{
+				"subscriptionId": subscriptionID,
+				"error":          err.Error(),
+			})
+			return nil
+		}
+		if err != nil {
+			cause = err
+			continue
 		}
 
-		s.logger.Info("ConsumerService", fmt.Sprintf("Stopping consumer for subscription %s", subscriptionID))
-		s.stopAndRemove(subscriptionID)
-	}()
+		if _, ok := sub.replace(consumer); !ok {
+			consumer.Close()
+			return nil
+		}
+		s.logger.Info("ConsumerService", fmt.Sprintf("Subscription %s reconnected after %d attempt(s)", subscriptionID, attempt))
+		s.healthSvc.Report(&types.ConnectionStatus{
+			ConnectionID:   req.ConnectionID,
+			SubscriptionID: subscriptionID,
+			State:          types.ConnectionStateConnected,
+		})
+		return consumer
+	}
+}
 
-	return subscriptionID, nil
+// isFatalConnectError 判断重建消费者失败的原因是否无法通过重试恢复
+func isFatalConnectError(err error) bool {
+	var appErr *utils.AppError
+	if !errors.As(err, &appErr) {
+		return false
+	}
+	switch appErr.Type {
+	case utils.ErrorTypeNotFound, utils.ErrorTypeAuth, utils.ErrorTypeConfig, utils.ErrorTypeValidation:
+		return true
+	}
+	return false
 }
 
 // StopConsuming 停止消费消息
@@ -130,11 +249,10 @@ func (s *ConsumerService) StopConsuming(subscriptionID string) {
 
 // stopAndRemove 停止并移除一个订阅
 func (s *ConsumerService) stopAndRemove(subscriptionID string) {
-	if sub, ok := s.activeSubs.Load(subscriptionID); ok {
+	if sub, ok := s.activeSubs.LoadAndDelete(subscriptionID); ok {
 		activeSub := sub.(*activeSubscription)
-		activeSub.cancel()         // 取消上下文，这将导致 Consume 循环退出
-		activeSub.consumer.Close() // 关闭底层连接
-		s.activeSubs.Delete(subscriptionID)
+		activeSub.stop()
+		s.healthSvc.Forget(activeSub.connectionID, subscriptionID)
 		s.logger.Info("ConsumerService", fmt.Sprintf("Successfully stopped and removed subscription %s", subscriptionID))
 	}
 }
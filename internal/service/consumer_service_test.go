@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"mq-toolkit/pkg/types"
+	"testing"
+	"time"
+)
+
+func TestConsumerServiceReceivesAndStops(t *testing.T) {
+	env := newTestEnv(t)
+	connectionID := env.createMemoryConnection("memory", t.Name())
+	svc := NewConsumerService(env.ctx, env.logger, env.emit, env.factory, env.configSvc, env.historySvc, env.messageSvc, env.healthSvc)
+
+	subscriptionID, err := svc.StartConsuming(&types.ConsumeRequest{
+		ConnectionID:  connectionID,
+		Topics:        []string{"orders"},
+		FromBeginning: true,
+	})
+	if err != nil {
+		t.Fatalf("StartConsuming failed: %v", err)
+	}
+
+	env.publish(t.Name(), "orders", "hello")
+	msg := env.waitEvent("message:received", nil).(*types.Message)
+	if msg.Value != "hello" || msg.SubscriptionID != subscriptionID {
+		t.Fatalf("received %+v, want hello for subscription %s", msg, subscriptionID)
+	}
+	result, err := env.messageSvc.SearchMessages(env.ctx, &types.MessageQuery{SubscriptionID: subscriptionID})
+	if err != nil || len(result.Messages) != 1 {
+		t.Fatalf("SearchMessages returned %+v, %v; want the received message", result, err)
+	}
+	if stored := result.Messages[0]; stored.MessageID != msg.ID || stored.ConnectionID != connectionID || stored.Value != "hello" {
+		t.Fatalf("stored %+v, want message %s from connection %s", stored, msg.ID, connectionID)
+	}
+
+	svc.StopConsuming(subscriptionID)
+	if _, ok := svc.activeSubs.Load(subscriptionID); ok {
+		t.Fatalf("subscription %s is still active after StopConsuming", subscriptionID)
+	}
+}
+
+func TestConsumerServiceRejectsMissingConnection(t *testing.T) {
+	env := newTestEnv(t)
+	svc := NewConsumerService(env.ctx, env.logger, env.emit, env.factory, env.configSvc, env.historySvc, env.messageSvc, env.healthSvc)
+
+	if _, err := svc.StartConsuming(&types.ConsumeRequest{ConnectionID: "missing", Topics: []string{"orders"}}); err == nil {
+		t.Fatal("StartConsuming succeeded for a missing connection")
+	}
+}
+
+func TestConsumerServiceSinkHoldsBackMessages(t *testing.T) {
+	env := newTestEnv(t)
+	connectionID := env.createMemoryConnection("memory", t.Name())
+	svc := NewConsumerService(env.ctx, env.logger, env.emit, env.factory, env.configSvc, env.historySvc, env.messageSvc, env.healthSvc)
+	req := &types.ConsumeRequest{ConnectionID: connectionID, Topics: []string{"orders"}, GroupID: "api", FromBeginning: true}
+
+	messages := make(chan *types.Message)
+	sink := func(ctx context.Context, msg *types.Message) error {
+		select {
+		case messages <- msg:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	receive := func() *types.Message {
+		t.Helper()
+		select {
+		case msg := <-messages:
+			return msg
+		case <-time.After(testTimeout):
+			t.Fatal("timed out waiting for the sink")
+			return nil
+		}
+	}
+
+	subscriptionID, err := svc.StartConsumingTo(req, sink)
+	if err != nil {
+		t.Fatalf("StartConsumingTo failed: %v", err)
+	}
+	env.publish(t.Name(), "orders", "first", "second")
+	if msg := receive(); msg.Value != "first" {
+		t.Fatalf("sink got %q, want first", msg.Value)
+	}
+
+	// 第二条消息停在 sink 中，停止订阅后不被确认，由消费组的下一个订阅收到
+	svc.StopConsuming(subscriptionID)
+	subscriptionID, err = svc.StartConsumingTo(req, sink)
+	if err != nil {
+		t.Fatalf("StartConsumingTo failed: %v", err)
+	}
+	defer svc.StopConsuming(subscriptionID)
+	if msg := receive(); msg.Value != "second" {
+		t.Fatalf("sink got %q after resubscribing, want second", msg.Value)
+	}
+}
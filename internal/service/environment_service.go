@@ -0,0 +1,401 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/internal/secret"
+	"mq-toolkit/internal/templating"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"net"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// envNameVariable 始终可用的内置变量，值为当前激活环境的名称
+const envNameVariable = "env"
+
+// EnvironmentService 环境管理服务，密文变量加密后保存
+type EnvironmentService struct {
+	db      *gorm.DB
+	secrets *secret.Store
+}
+
+// NewEnvironmentService 创建环境服务
+func NewEnvironmentService(db *gorm.DB, secrets *secret.Store) *EnvironmentService {
+	return &EnvironmentService{db: db, secrets: secrets}
+}
+
+// ListEnvironments 列出所有环境，密文变量以 SecretMask 返回
+func (s *EnvironmentService) ListEnvironments(ctx context.Context) ([]*types.Environment, error) {
+	var envs []*types.Environment
+	if err := s.db.Order("name asc").Find(&envs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+	for _, env := range envs {
+		maskSecrets(env)
+	}
+	return envs, nil
+}
+
+// GetEnvironment 获取环境（包含密文变量的真实值）
+func (s *EnvironmentService) GetEnvironment(ctx context.Context, id string) (*types.Environment, error) {
+	var env types.Environment
+	if err := s.db.First(&env, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("environment not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get environment: %w", err)
+	}
+	if err := s.decryptVariables(&env); err != nil {
+		return nil, err
+	}
+	return &env, nil
+}
+
+// CreateEnvironment 创建环境
+func (s *EnvironmentService) CreateEnvironment(ctx context.Context, env *types.Environment) error {
+	if err := s.validate(env); err != nil {
+		return err
+	}
+	variables, err := s.encryptVariables(env.Variables, nil)
+	if err != nil {
+		return err
+	}
+	env.ID = utils.GenerateID()
+	env.Active = false
+	stored := *env
+	stored.Variables = variables
+	if err := s.db.Create(&stored).Error; err != nil {
+		return err
+	}
+	env.CreatedAt, env.UpdatedAt = stored.CreatedAt, stored.UpdatedAt
+	return nil
+}
+
+// UpdateEnvironment 更新环境名称、描述和变量，值为 SecretMask 的密文变量保留原值
+func (s *EnvironmentService) UpdateEnvironment(ctx context.Context, env *types.Environment) error {
+	var existing types.Environment
+	if err := s.db.First(&existing, "id = ?", env.ID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("environment not found: %s", env.ID)
+		}
+		return fmt.Errorf("failed to get environment: %w", err)
+	}
+	if err := s.validate(env); err != nil {
+		return err
+	}
+
+	previous := make(map[string]string, len(existing.Variables))
+	for _, v := range existing.Variables {
+		if v.Secret {
+			previous[v.Key] = v.Value
+		}
+	}
+	variables, err := s.encryptVariables(env.Variables, previous)
+	if err != nil {
+		return err
+	}
+
+	stored := *env
+	stored.Variables = variables
+	return s.db.Model(&existing).Select("name", "description", "variables").Updates(&stored).Error
+}
+
+// DeleteEnvironment 删除环境
+func (s *EnvironmentService) DeleteEnvironment(ctx context.Context, id string) error {
+	result := s.db.Delete(&types.Environment{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete environment: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("environment not found: %s", id)
+	}
+	return nil
+}
+
+// SetActiveEnvironment 激活环境，id 为空时取消激活
+func (s *EnvironmentService) SetActiveEnvironment(ctx context.Context, id string) error {
+	if id != "" {
+		if _, err := s.GetEnvironment(ctx, id); err != nil {
+			return err
+		}
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&types.Environment{}).Where("active = ?", true).Update("active", false).Error; err != nil {
+			return fmt.Errorf("failed to deactivate environment: %w", err)
+		}
+		if id == "" {
+			return nil
+		}
+		if err := tx.Model(&types.Environment{}).Where("id = ?", id).Update("active", true).Error; err != nil {
+			return fmt.Errorf("failed to activate environment: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetActiveEnvironment 获取当前激活的环境，没有时返回 nil
+func (s *EnvironmentService) GetActiveEnvironment(ctx context.Context) (*types.Environment, error) {
+	var envs []*types.Environment
+	if err := s.db.Where("active = ?", true).Limit(1).Find(&envs).Error; err != nil {
+		return nil, fmt.Errorf("failed to get active environment: %w", err)
+	}
+	if len(envs) == 0 {
+		return nil, nil
+	}
+	if err := s.decryptVariables(envs[0]); err != nil {
+		return nil, err
+	}
+	return envs[0], nil
+}
+
+// Variables 返回当前激活环境的变量，并提供内置变量 env（环境名称）
+func (s *EnvironmentService) Variables(ctx context.Context) (map[string]string, error) {
+	env, err := s.GetActiveEnvironment(ctx)
+	if err != nil || env == nil {
+		return map[string]string{}, err
+	}
+	vars := make(map[string]string, len(env.Variables)+1)
+	vars[envNameVariable] = env.Name
+	for _, v := range env.Variables {
+		vars[v.Key] = v.Value
+	}
+	return vars, nil
+}
+
+// Expand 使用激活环境的变量渲染字符串，不含 {{ 的字符串原样返回
+func (s *EnvironmentService) Expand(ctx context.Context, text string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	vars, err := s.Variables(ctx)
+	if err != nil {
+		return "", err
+	}
+	return templating.Render(text, vars, 0)
+}
+
+// ResolveConnection 对开启了 ResolveVariables 的连接，用激活环境的变量展开主机、端口、其他节点和隧道主机
+//
+// 主机渲染结果为 host:port 形式时同时覆盖端口。认证信息可能本身含有 {{，只在开启 ResolveCredentials 时展开。
+func (s *EnvironmentService) ResolveConnection(ctx context.Context, config *types.ConnectionConfig) error {
+	if !config.ResolveVariables {
+		return nil
+	}
+
+	fields := []*string{&config.Host}
+	for i := range config.Endpoints {
+		fields = append(fields, &config.Endpoints[i])
+	}
+	if config.Tunnel != nil {
+		fields = append(fields, &config.Tunnel.Host)
+	}
+	if config.ResolveCredentials {
+		fields = append(fields, &config.Username, &config.Password, &config.VHost,
+			&config.Region, &config.AccessKey, &config.SecretKey, &config.SessionToken)
+		if config.Tunnel != nil {
+			fields = append(fields, &config.Tunnel.Username)
+		}
+	}
+	for _, field := range fields {
+		value, err := s.Expand(ctx, *field)
+		if err != nil {
+			return fmt.Errorf("failed to resolve connection %s: %w", config.Name, err)
+		}
+		*field = value
+	}
+
+	if host, port, err := net.SplitHostPort(config.Host); err == nil {
+		portInt, err := strconv.Atoi(port)
+		if err != nil {
+			return utils.NewValidationError("Invalid port", port)
+		}
+		config.Host, config.Port = host, portInt
+	}
+	return nil
+}
+
+func (s *EnvironmentService) validate(env *types.Environment) error {
+	if strings.TrimSpace(env.Name) == "" {
+		return utils.NewValidationError("Environment name is required", "")
+	}
+
+	var count int64
+	if err := s.db.Model(&types.Environment{}).Where("name = ? AND id != ?", env.Name, env.ID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check environment name: %w", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("environment name '%s' already exists", env.Name)
+	}
+
+	seen := make(map[string]bool, len(env.Variables))
+	for _, v := range env.Variables {
+		if v.Key == "" {
+			return utils.NewValidationError("Variable name is required", "")
+		}
+		if seen[v.Key] {
+			return utils.NewValidationError("Duplicate variable", v.Key)
+		}
+		seen[v.Key] = true
+	}
+	return nil
+}
+
+// maskSecrets 将密文变量的值替换为 SecretMask
+func maskSecrets(env *types.Environment) {
+	for i, v := range env.Variables {
+		if v.Secret && v.Value != "" {
+			env.Variables[i].Value = types.SecretMask
+		}
+	}
+}
+
+// ImportEnvironment 导入环境，按名称处理冲突；覆盖时空的密文变量保留已保存的值
+func (s *EnvironmentService) ImportEnvironment(ctx context.Context, env *types.Environment, mode types.ImportConflictMode) (*types.ImportItemResult, error) {
+	result := &types.ImportItemResult{Kind: "environment", Name: env.Name, Action: "created"}
+	imported := *env
+	imported.ID = ""
+	imported.Variables = append([]types.EnvironmentVariable(nil), env.Variables...)
+
+	var existing []*types.Environment
+	if err := s.db.Where("name = ?", env.Name).Limit(1).Find(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to check environment name: %w", err)
+	}
+	if len(existing) > 0 {
+		switch mode {
+		case types.ImportConflictOverwrite:
+			imported.ID = existing[0].ID
+			for i, v := range imported.Variables {
+				if v.Secret && v.Value == "" {
+					imported.Variables[i].Value = types.SecretMask
+				}
+			}
+			if err := s.UpdateEnvironment(ctx, &imported); err != nil {
+				return nil, err
+			}
+			result.Action = "overwritten"
+			return result, nil
+		case types.ImportConflictRename:
+			name, err := uniqueName(env.Name, func(name string) (bool, error) {
+				var count int64
+				err := s.db.Model(&types.Environment{}).Where("name = ?", name).Count(&count).Error
+				return count > 0, err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to check environment name: %w", err)
+			}
+			imported.Name = name
+			result.Action, result.NewName = "renamed", name
+		default:
+			result.Action = "skipped"
+			return result, nil
+		}
+	}
+
+	if err := s.CreateEnvironment(ctx, &imported); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ExportEnvironments 返回包含密文变量真实值的环境，用于导出共享包
+func (s *EnvironmentService) ExportEnvironments(ctx context.Context, ids []string) ([]*types.Environment, error) {
+	var envs []*types.Environment
+	if err := s.db.Where("id IN ?", ids).Order("name asc").Find(&envs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+	for _, env := range envs {
+		if err := s.decryptVariables(env); err != nil {
+			return nil, err
+		}
+	}
+	return envs, nil
+}
+
+// EncryptPlaintextSecrets 加密旧版本以明文保存的密文变量，返回处理的环境数
+func (s *EnvironmentService) EncryptPlaintextSecrets(ctx context.Context) (int, error) {
+	key, err := s.secrets.Current()
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		count, err = reencryptEnvironmentsTx(tx, key, key, func(env *types.Environment) bool {
+			for _, v := range env.Variables {
+				if v.Secret && v.Value != "" && !secret.IsEncrypted(v.Value) {
+					return true
+				}
+			}
+			return false
+		})
+		return err
+	})
+	return count, err
+}
+
+// encryptVariables 返回密文变量加密后的副本，值为 SecretMask 时使用 previous 中已保存的密文
+func (s *EnvironmentService) encryptVariables(vars []types.EnvironmentVariable, previous map[string]string) ([]types.EnvironmentVariable, error) {
+	encrypted := make([]types.EnvironmentVariable, len(vars))
+	for i, v := range vars {
+		if v.Secret {
+			if v.Value == types.SecretMask {
+				v.Value = previous[v.Key]
+			} else {
+				var err error
+				if v.Value, err = s.secrets.Encrypt(v.Value); err != nil {
+					return nil, err
+				}
+			}
+		}
+		encrypted[i] = v
+	}
+	return encrypted, nil
+}
+
+// decryptVariables 解密环境中的密文变量
+func (s *EnvironmentService) decryptVariables(env *types.Environment) error {
+	for i, v := range env.Variables {
+		if !v.Secret {
+			continue
+		}
+		value, err := s.secrets.Decrypt(v.Value)
+		if err != nil {
+			return utils.NewAuthError(fmt.Sprintf("failed to decrypt variable %s of environment %s: %v", v.Key, env.Name, err))
+		}
+		env.Variables[i].Value = value
+	}
+	return nil
+}
+
+// reencryptEnvironmentsTx 用 from 解密并用 to 重新加密满足 filter 的环境中的密文变量（filter 为 nil 表示全部）
+func reencryptEnvironmentsTx(tx *gorm.DB, from, to *secret.Key, filter func(*types.Environment) bool) (int, error) {
+	var envs []*types.Environment
+	if err := tx.Find(&envs).Error; err != nil {
+		return 0, fmt.Errorf("failed to list environments: %w", err)
+	}
+
+	count := 0
+	for _, env := range envs {
+		if filter != nil && !filter(env) {
+			continue
+		}
+		for i, v := range env.Variables {
+			if !v.Secret {
+				continue
+			}
+			value, err := recrypt(from, to, v.Value)
+			if err != nil {
+				return 0, fmt.Errorf("environment %s: %w", env.Name, err)
+			}
+			env.Variables[i].Value = value
+		}
+		if err := tx.Model(env).Select("variables").Updates(&types.Environment{Variables: env.Variables}).Error; err != nil {
+			return 0, fmt.Errorf("failed to update environment %s: %w", env.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
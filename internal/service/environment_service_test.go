@@ -0,0 +1,94 @@
+package service
+
+import (
+	"mq-toolkit/internal/secret"
+	"mq-toolkit/pkg/types"
+	"testing"
+)
+
+func TestEnvironmentSecretsEncryptedAtRest(t *testing.T) {
+	env := newTestEnv(t)
+	svc := NewEnvironmentService(env.db.GetDB(), env.secrets)
+
+	created := &types.Environment{Name: "prod", Variables: []types.EnvironmentVariable{
+		{Key: "host", Value: "broker:9092"},
+		{Key: "token", Value: "s3cret", Secret: true},
+	}}
+	if err := svc.CreateEnvironment(env.ctx, created); err != nil {
+		t.Fatalf("CreateEnvironment failed: %v", err)
+	}
+	if created.Variables[1].Value != "s3cret" {
+		t.Fatalf("CreateEnvironment changed the caller's variable to %q", created.Variables[1].Value)
+	}
+
+	stored := func() map[string]string {
+		t.Helper()
+		var row types.Environment
+		if err := env.db.GetDB().First(&row, "id = ?", created.ID).Error; err != nil {
+			t.Fatalf("failed to read environment: %v", err)
+		}
+		values := make(map[string]string)
+		for _, v := range row.Variables {
+			values[v.Key] = v.Value
+		}
+		return values
+	}
+	if values := stored(); values["host"] != "broker:9092" || !secret.IsEncrypted(values["token"]) {
+		t.Fatalf("stored variables %v, want host in plaintext and token encrypted", values)
+	}
+
+	// 掩码值保留已保存的密文
+	ciphertext := stored()["token"]
+	created.Variables[1].Value = types.SecretMask
+	if err := svc.UpdateEnvironment(env.ctx, created); err != nil {
+		t.Fatalf("UpdateEnvironment failed: %v", err)
+	}
+	if values := stored(); values["token"] != ciphertext {
+		t.Fatalf("masked update stored %q, want the previous ciphertext", values["token"])
+	}
+
+	if err := svc.SetActiveEnvironment(env.ctx, created.ID); err != nil {
+		t.Fatalf("SetActiveEnvironment failed: %v", err)
+	}
+	vars, err := svc.Variables(env.ctx)
+	if err != nil {
+		t.Fatalf("Variables failed: %v", err)
+	}
+	if vars["token"] != "s3cret" || vars["host"] != "broker:9092" {
+		t.Fatalf("Variables returned %v, want the decrypted token", vars)
+	}
+
+	exported, err := svc.ExportEnvironments(env.ctx, []string{created.ID})
+	if err != nil || len(exported) != 1 || exported[0].Variables[1].Value != "s3cret" {
+		t.Fatalf("ExportEnvironments returned %v, %v; want the decrypted token", exported, err)
+	}
+}
+
+func TestEnvironmentEncryptPlaintextSecrets(t *testing.T) {
+	env := newTestEnv(t)
+	svc := NewEnvironmentService(env.db.GetDB(), env.secrets)
+
+	// 旧版本以明文保存的密文变量
+	legacy := &types.Environment{ID: "legacy", Name: "legacy", Variables: []types.EnvironmentVariable{
+		{Key: "token", Value: "s3cret", Secret: true},
+	}}
+	if err := env.db.GetDB().Create(legacy).Error; err != nil {
+		t.Fatalf("failed to save environment: %v", err)
+	}
+
+	count, err := svc.EncryptPlaintextSecrets(env.ctx)
+	if err != nil || count != 1 {
+		t.Fatalf("EncryptPlaintextSecrets returned %d, %v; want 1 environment", count, err)
+	}
+	var row types.Environment
+	if err := env.db.GetDB().First(&row, "id = ?", "legacy").Error; err != nil {
+		t.Fatalf("failed to read environment: %v", err)
+	}
+	if !secret.IsEncrypted(row.Variables[0].Value) {
+		t.Fatalf("token is still stored as %q", row.Variables[0].Value)
+	}
+	got, err := svc.GetEnvironment(env.ctx, "legacy")
+	if err != nil || got.Variables[0].Value != "s3cret" {
+		t.Fatalf("GetEnvironment returned %+v, %v; want the decrypted token", got, err)
+	}
+}
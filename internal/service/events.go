@@ -0,0 +1,12 @@
+package service
+
+// EventEmitter 推送服务事件，桌面端转发给前端，命令行模式按需处理
+type EventEmitter func(name string, data interface{})
+
+// emitter 返回 emit，为 nil 时返回丢弃事件的空实现
+func emitter(emit EventEmitter) EventEmitter {
+	if emit == nil {
+		return func(string, interface{}) {}
+	}
+	return emit
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"mq-toolkit/internal/logger"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// healthCheckInterval 探测缓存客户端的间隔
+	healthCheckInterval = 15 * time.Second
+	// healthCheckTimeout 单次探测的超时
+	healthCheckTimeout = 5 * time.Second
+	// reconnectBaseDelay 首次重连前的等待时间，之后逐次翻倍
+	reconnectBaseDelay = time.Second
+	// reconnectMaxDelay 重连等待时间的上限
+	reconnectMaxDelay = 30 * time.Second
+)
+
+// HealthService 记录连接和订阅的健康状态，状态变化时推送 connection:status 事件
+type HealthService struct {
+	logger   *logger.Logger
+	emit     EventEmitter
+	mu       sync.Mutex
+	statuses map[string]*types.ConnectionStatus // [connectionID 或 connectionID/subscriptionID -> 状态]
+}
+
+// NewHealthService 创建健康状态服务
+func NewHealthService(logger *logger.Logger, emit EventEmitter) *HealthService {
+	return &HealthService{
+		logger:   logger,
+		emit:     emitter(emit),
+		statuses: make(map[string]*types.ConnectionStatus),
+	}
+}
+
+// Report 更新状态，状态、错误或重连次数变化时推送事件
+func (s *HealthService) Report(status *types.ConnectionStatus) {
+	status.CheckedAt = time.Now()
+	key := statusKey(status.ConnectionID, status.SubscriptionID)
+
+	s.mu.Lock()
+	previous := s.statuses[key]
+	s.statuses[key] = status
+	s.mu.Unlock()
+
+	if previous != nil && previous.State == status.State &&
+		previous.Error == status.Error && previous.Attempt == status.Attempt {
+		return
+	}
+	if status.State == types.ConnectionStateReconnecting {
+		s.logger.Warn("HealthService", fmt.Sprintf("Connection %s unhealthy, reconnect attempt %d: %s",
+			statusKey(status.ConnectionID, status.SubscriptionID), status.Attempt, status.Error))
+	}
+	s.emit("connection:status", status)
+}
+
+// Forget 移除状态并推送 disconnected，用于主动关闭的连接或停止的订阅
+func (s *HealthService) Forget(connectionID, subscriptionID string) {
+	key := statusKey(connectionID, subscriptionID)
+	s.mu.Lock()
+	_, existed := s.statuses[key]
+	delete(s.statuses, key)
+	s.mu.Unlock()
+
+	if existed {
+		s.emit("connection:status", &types.ConnectionStatus{
+			ConnectionID:   connectionID,
+			SubscriptionID: subscriptionID,
+			State:          types.ConnectionStateDisconnected,
+			CheckedAt:      time.Now(),
+		})
+	}
+}
+
+// Fail 移除状态并推送 connection:error，用于重连也无法恢复的错误（如认证失败、配置错误）
+func (s *HealthService) Fail(connectionID string, err error) {
+	s.logger.Error("HealthService", fmt.Sprintf("Connection %s cannot reconnect: %v", connectionID, err))
+	s.Forget(connectionID, "")
+	s.emit("connection:error", map[string]string{
+		"connectionId": connectionID,
+		"error":        err.Error(),
+	})
+}
+
+// ListStatuses 返回所有已知的健康状态
+func (s *HealthService) ListStatuses() []*types.ConnectionStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make([]*types.ConnectionStatus, 0, len(s.statuses))
+	for _, status := range s.statuses {
+		copied := *status
+		statuses = append(statuses, &copied)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].ConnectionID != statuses[j].ConnectionID {
+			return statuses[i].ConnectionID < statuses[j].ConnectionID
+		}
+		return statuses[i].SubscriptionID < statuses[j].SubscriptionID
+	})
+	return statuses
+}
+
+func statusKey(connectionID, subscriptionID string) string {
+	if subscriptionID == "" {
+		return connectionID
+	}
+	return connectionID + "/" + subscriptionID
+}
+
+// probeClient 探测客户端是否存活，实现了 mq.Pinger 时主动探测，否则只检查本地状态
+func probeClient(ctx context.Context, client mq.Client) error {
+	if pinger, ok := client.(mq.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	if !client.IsConnected() {
+		return utils.NewConnectionError("Client is not connected", nil)
+	}
+	return nil
+}
+
+// backoffDelay 第 attempt 次重连前的等待时间，指数增长并加入最多 20% 的随机抖动
+func backoffDelay(attempt int) time.Duration {
+	delay := reconnectMaxDelay
+	if attempt < 16 {
+		if d := reconnectBaseDelay << (attempt - 1); d < reconnectMaxDelay {
+			delay = d
+		}
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
+
+// waitBackoff 等待重连间隔并报告重连状态，ctx 取消时返回 false
+func (s *HealthService) waitBackoff(ctx context.Context, connectionID, subscriptionID string, attempt int, cause error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	delay := backoffDelay(attempt)
+	next := time.Now().Add(delay)
+	s.Report(&types.ConnectionStatus{
+		ConnectionID:   connectionID,
+		SubscriptionID: subscriptionID,
+		State:          types.ConnectionStateReconnecting,
+		Error:          cause.Error(),
+		Attempt:        attempt,
+		NextRetry:      &next,
+	})
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -70,6 +70,48 @@ func (s *HistoryService) AddTestRecord(ctx context.Context, connectionID string,
 	return s.AddRecord(ctx, record)
 }
 
+// AddReplayRecord 添加重放记录
+func (s *HistoryService) AddReplayRecord(ctx context.Context, connectionID, topic string, success bool, message string, latency int64) error {
+	record := &types.HistoryRecord{
+		ID:           utils.GenerateID(),
+		ConnectionID: connectionID,
+		Type:         "replay",
+		Topic:        topic,
+		Success:      success,
+		Message:      message,
+		Latency:      latency,
+	}
+	return s.AddRecord(ctx, record)
+}
+
+// AddBridgeRecord 添加桥接记录，connectionID 和 topic 为目标连接和主题
+func (s *HistoryService) AddBridgeRecord(ctx context.Context, connectionID, topic string, success bool, message string, latency int64) error {
+	record := &types.HistoryRecord{
+		ID:           utils.GenerateID(),
+		ConnectionID: connectionID,
+		Type:         "bridge",
+		Topic:        topic,
+		Success:      success,
+		Message:      message,
+		Latency:      latency,
+	}
+	return s.AddRecord(ctx, record)
+}
+
+// AddBenchmarkRecord 添加压测记录
+func (s *HistoryService) AddBenchmarkRecord(ctx context.Context, connectionID, topic string, success bool, message string, latency int64) error {
+	record := &types.HistoryRecord{
+		ID:           utils.GenerateID(),
+		ConnectionID: connectionID,
+		Type:         "benchmark",
+		Topic:        topic,
+		Success:      success,
+		Message:      message,
+		Latency:      latency,
+	}
+	return s.AddRecord(ctx, record)
+}
+
 // GetRecords 获取历史记录
 func (s *HistoryService) GetRecords(ctx context.Context, limit, offset int) ([]*types.HistoryRecord, error) {
 	var records []*types.HistoryRecord
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MessageService 消费消息持久化与检索服务
+type MessageService struct {
+	db             *gorm.DB
+	fullTextSearch bool
+}
+
+// NewMessageService 创建消息存储服务，fullTextSearch 表示数据库是否建立了 FTS5 索引
+func NewMessageService(db *gorm.DB, fullTextSearch bool) *MessageService {
+	return &MessageService{db: db, fullTextSearch: fullTextSearch}
+}
+
+// SaveMessage 保存一条消费到的消息，每次保存都生成新的记录ID
+func (s *MessageService) SaveMessage(ctx context.Context, subscriptionID, connectionID string, msg *types.Message) error {
+	stored := &types.StoredMessage{
+		ID:             utils.GenerateID(),
+		MessageID:      msg.ID,
+		SubscriptionID: subscriptionID,
+		ConnectionID:   connectionID,
+		Topic:          msg.Topic,
+		Key:            msg.Key,
+		Value:          msg.Value,
+		Headers:        msg.Headers,
+		Partition:      msg.Partition,
+		Offset:         msg.Offset,
+		Timestamp:      msg.Timestamp,
+	}
+	if stored.Timestamp.IsZero() {
+		stored.Timestamp = time.Now()
+	}
+	if err := s.db.Create(stored).Error; err != nil {
+		return fmt.Errorf("failed to save message: %w", err)
+	}
+	return nil
+}
+
+// GetMessage 按ID获取已存储的消息
+func (s *MessageService) GetMessage(ctx context.Context, id string) (*types.StoredMessage, error) {
+	var msg types.StoredMessage
+	if err := s.db.First(&msg, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("message not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+	return &msg, nil
+}
+
+// GetMessages 按ID批量获取已存储的消息，结果按消息时间升序
+func (s *MessageService) GetMessages(ctx context.Context, ids []string) ([]*types.StoredMessage, error) {
+	var messages []*types.StoredMessage
+	if len(ids) == 0 {
+		return messages, nil
+	}
+	if err := s.db.Where("id IN ?", ids).Order("timestamp ASC").Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+	return messages, nil
+}
+
+// SearchMessages 按条件检索已存储的消息，结果按消息时间倒序
+func (s *MessageService) SearchMessages(ctx context.Context, query *types.MessageQuery) (*types.MessageSearchResult, error) {
+	if query == nil {
+		query = &types.MessageQuery{}
+	}
+
+	tx := s.applyQuery(s.db.Model(&types.StoredMessage{}), query)
+
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count messages: %w", err)
+	}
+
+	tx = tx.Order("timestamp DESC")
+	if query.Limit > 0 {
+		tx = tx.Limit(query.Limit)
+	}
+	if query.Offset > 0 {
+		tx = tx.Offset(query.Offset)
+	}
+
+	var messages []*types.StoredMessage
+	if err := tx.Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to search messages: %w", err)
+	}
+
+	return &types.MessageSearchResult{Total: total, Messages: messages}, nil
+}
+
+// ListMessages 按条件获取消息并按消费顺序（时间升序）返回，用于导出和重放
+func (s *MessageService) ListMessages(ctx context.Context, query *types.MessageQuery) ([]*types.Message, error) {
+	result, err := s.SearchMessages(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]*types.Message, len(result.Messages))
+	for i, stored := range result.Messages {
+		msgs[len(msgs)-1-i] = stored.ToMessage()
+	}
+	return msgs, nil
+}
+
+// DeleteMessages 删除符合条件的消息，条件为空时删除全部
+func (s *MessageService) DeleteMessages(ctx context.Context, query *types.MessageQuery) (int64, error) {
+	if query == nil {
+		query = &types.MessageQuery{}
+	}
+
+	tx := s.applyQuery(s.db.Where("1 = 1"), query)
+	result := tx.Delete(&types.StoredMessage{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete messages: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// ClearOldMessages 清理旧消息（保留最近N天的消息）
+func (s *MessageService) ClearOldMessages(ctx context.Context, days int) error {
+	cutoff := time.Now().AddDate(0, 0, -days)
+	return s.db.Where("created_at < ?", cutoff).Delete(&types.StoredMessage{}).Error
+}
+
+// applyQuery 将查询条件应用到 gorm 查询
+func (s *MessageService) applyQuery(tx *gorm.DB, query *types.MessageQuery) *gorm.DB {
+	if query.ConnectionID != "" {
+		tx = tx.Where("connection_id = ?", query.ConnectionID)
+	}
+	if query.SubscriptionID != "" {
+		tx = tx.Where("subscription_id = ?", query.SubscriptionID)
+	}
+	if query.Topic != "" {
+		tx = tx.Where("topic = ?", query.Topic)
+	}
+	if query.Key != "" {
+		tx = tx.Where("key = ?", query.Key)
+	}
+	if query.HeaderKey != "" {
+		path := headerPath(query.HeaderKey)
+		if query.HeaderValue != "" {
+			tx = tx.Where("json_extract(headers, ?) = ?", path, query.HeaderValue)
+		} else {
+			tx = tx.Where("json_extract(headers, ?) IS NOT NULL", path)
+		}
+	}
+	if query.Start != nil {
+		tx = tx.Where("timestamp >= ?", *query.Start)
+	}
+	if query.End != nil {
+		tx = tx.Where("timestamp <= ?", *query.End)
+	}
+	if search := strings.TrimSpace(query.Search); search != "" {
+		if s.fullTextSearch {
+			tx = tx.Where("rowid IN (SELECT rowid FROM consumed_messages_fts WHERE consumed_messages_fts MATCH ?)", ftsQuery(search))
+		} else {
+			pattern := "%" + search + "%"
+			tx = tx.Where("(value LIKE ? OR key LIKE ? OR headers LIKE ?)", pattern, pattern, pattern)
+		}
+	}
+	return tx
+}
+
+// headerPath 构造 headers JSON 中指定键的路径
+func headerPath(key string) string {
+	return `$."` + strings.ReplaceAll(key, `"`, `\"`) + `"`
+}
+
+// ftsQuery 将用户输入转换为 FTS5 查询，每个词按前缀匹配并取交集，避免语法字符导致查询出错
+func ftsQuery(search string) string {
+	terms := strings.Fields(search)
+	for i, term := range terms {
+		terms[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"*`
+	}
+	return strings.Join(terms, " ")
+}
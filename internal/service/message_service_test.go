@@ -0,0 +1,63 @@
+package service
+
+import (
+	"mq-toolkit/pkg/types"
+	"testing"
+	"time"
+)
+
+func TestSaveMessageTwice(t *testing.T) {
+	env := newTestEnv(t)
+	msg := &types.Message{ID: "broker-1", Topic: "orders", Value: "hello", Timestamp: time.Now()}
+
+	// 两个订阅各消费一次，同一订阅又收到一次重复投递
+	for _, subscriptionID := range []string{"first", "second", "second"} {
+		if err := env.messageSvc.SaveMessage(env.ctx, subscriptionID, "connection", msg); err != nil {
+			t.Fatalf("SaveMessage for %s failed: %v", subscriptionID, err)
+		}
+	}
+
+	result, err := env.messageSvc.SearchMessages(env.ctx, &types.MessageQuery{Topic: "orders"})
+	if err != nil {
+		t.Fatalf("SearchMessages failed: %v", err)
+	}
+	if result.Total != 3 {
+		t.Fatalf("stored %d messages, want 3", result.Total)
+	}
+	ids := make(map[string]bool)
+	for _, stored := range result.Messages {
+		if stored.MessageID != msg.ID {
+			t.Errorf("stored message ID %q, want %q", stored.MessageID, msg.ID)
+		}
+		ids[stored.ID] = true
+	}
+	if len(ids) != 3 {
+		t.Fatalf("got %d distinct record IDs, want 3", len(ids))
+	}
+
+	exported, err := env.messageSvc.ListMessages(env.ctx, &types.MessageQuery{SubscriptionID: "first"})
+	if err != nil || len(exported) != 1 || exported[0].ID != msg.ID {
+		t.Fatalf("ListMessages returned %+v, %v; want the message with its broker ID", exported, err)
+	}
+}
+
+func TestClearOldMessages(t *testing.T) {
+	env := newTestEnv(t)
+	for _, id := range []string{"old", "recent"} {
+		if err := env.messageSvc.SaveMessage(env.ctx, "subscription", "connection", &types.Message{ID: id, Topic: "orders"}); err != nil {
+			t.Fatalf("SaveMessage failed: %v", err)
+		}
+	}
+	if err := env.db.GetDB().Model(&types.StoredMessage{}).Where("message_id = ?", "old").
+		Update("created_at", time.Now().AddDate(0, 0, -10)).Error; err != nil {
+		t.Fatalf("failed to age message: %v", err)
+	}
+
+	if err := env.messageSvc.ClearOldMessages(env.ctx, 7); err != nil {
+		t.Fatalf("ClearOldMessages failed: %v", err)
+	}
+	result, err := env.messageSvc.SearchMessages(env.ctx, &types.MessageQuery{})
+	if err != nil || result.Total != 1 || result.Messages[0].MessageID != "recent" {
+		t.Fatalf("SearchMessages returned %+v, %v; want only the recent message", result, err)
+	}
+}
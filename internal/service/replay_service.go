@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"mq-toolkit/internal/factory"
+	"mq-toolkit/internal/logger"
+	"mq-toolkit/internal/mq"
+	"mq-toolkit/internal/transfer"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"os"
+	"sync"
+	"time"
+)
+
+// replayProgressInterval 两次进度事件之间的最小间隔
+const replayProgressInterval = 500 * time.Millisecond
+
+// ReplayService 负责将已存储或导出的消息重放到指定连接
+type ReplayService struct {
+	ctx        context.Context
+	logger     *logger.Logger
+	emit       EventEmitter
+	mqFactory  factory.Factory
+	configSvc  *ConfigService
+	historySvc *HistoryService
+	messageSvc *MessageService
+	replays    sync.Map // 存储重放任务 [replayID -> *replayJob]
+}
+
+// replayJob 代表一个重放任务
+type replayJob struct {
+	mu     sync.Mutex
+	status types.ReplayStatus
+	cancel context.CancelFunc
+}
+
+// snapshot 返回当前状态的副本
+func (j *replayJob) snapshot() types.ReplayStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+// NewReplayService 创建重放服务
+func NewReplayService(ctx context.Context, logger *logger.Logger, emit EventEmitter, factory factory.Factory, configSvc *ConfigService, historySvc *HistoryService, messageSvc *MessageService) *ReplayService {
+	return &ReplayService{
+		ctx:        ctx,
+		logger:     logger,
+		emit:       emitter(emit),
+		mqFactory:  factory,
+		configSvc:  configSvc,
+		historySvc: historySvc,
+		messageSvc: messageSvc,
+	}
+}
+
+// StartReplay 加载消息并在后台开始重放，返回任务ID
+func (s *ReplayService) StartReplay(req *types.ReplayRequest) (string, error) {
+	if req.ConnectionID == "" {
+		return "", utils.NewValidationError("ConnectionID is required", "")
+	}
+
+	msgs, err := s.loadMessages(req)
+	if err != nil {
+		return "", err
+	}
+	if len(msgs) == 0 {
+		return "", utils.NewValidationError("No messages to replay", "")
+	}
+	// 从文件加载的消息（例如 kcat 输出）可能没有主题，未设置目标主题时在开始前拒绝
+	for i, msg := range msgs {
+		if replayProduceRequest(req, msg).Topic == "" {
+			return "", utils.NewValidationError("Target topic is required",
+				fmt.Sprintf("message %d has no topic, set a target topic for the replay", i+1))
+		}
+	}
+
+	connConfig, err := s.configSvc.GetConnection(s.ctx, req.ConnectionID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get connection config: %w", err)
+	}
+
+	producer, err := s.mqFactory.CreateProducer(connConfig.Type)
+	if err != nil {
+		return "", fmt.Errorf("failed to create producer: %w", err)
+	}
+	if err := producer.Connect(s.ctx, connConfig); err != nil {
+		producer.Close()
+		return "", fmt.Errorf("failed to connect: %w", err)
+	}
+
+	replayCtx, cancel := context.WithCancel(s.ctx)
+	replayID := utils.GenerateID()
+	job := &replayJob{
+		status: types.ReplayStatus{
+			ID:           replayID,
+			ConnectionID: req.ConnectionID,
+			State:        types.ReplayStateRunning,
+			Total:        len(msgs),
+			StartedAt:    time.Now(),
+		},
+		cancel: cancel,
+	}
+	s.replays.Store(replayID, job)
+
+	go s.run(replayCtx, job, producer, req, msgs)
+
+	s.logger.Info("ReplayService", fmt.Sprintf("Started replay %s of %d messages to connection %s", replayID, len(msgs), connConfig.Name))
+	return replayID, nil
+}
+
+// StopReplay 取消一个正在运行的重放任务
+func (s *ReplayService) StopReplay(replayID string) {
+	if value, ok := s.replays.Load(replayID); ok {
+		value.(*replayJob).cancel()
+	}
+}
+
+// ListReplays 列出所有重放任务的状态
+func (s *ReplayService) ListReplays() []types.ReplayStatus {
+	var statuses []types.ReplayStatus
+	s.replays.Range(func(key, value interface{}) bool {
+		statuses = append(statuses, value.(*replayJob).snapshot())
+		return true
+	})
+	return statuses
+}
+
+// StopAllReplays 取消所有重放任务
+func (s *ReplayService) StopAllReplays() {
+	s.replays.Range(func(key, value interface{}) bool {
+		value.(*replayJob).cancel()
+		return true
+	})
+}
+
+// loadMessages 根据请求选取要重放的消息，按原始顺序返回
+func (s *ReplayService) loadMessages(req *types.ReplayRequest) ([]*types.Message, error) {
+	switch {
+	case len(req.MessageIDs) > 0:
+		stored, err := s.messageSvc.GetMessages(s.ctx, req.MessageIDs)
+		if err != nil {
+			return nil, err
+		}
+		msgs := make([]*types.Message, len(stored))
+		for i, m := range stored {
+			msgs[i] = m.ToMessage()
+		}
+		return msgs, nil
+	case req.Query != nil:
+		return s.messageSvc.ListMessages(s.ctx, req.Query)
+	case req.CaptureFile != "":
+		format := transfer.FormatFromPath(req.CaptureFile)
+		if req.CaptureFormat != "" {
+			var err error
+			if format, err = transfer.ParseFormat(string(req.CaptureFormat)); err != nil {
+				return nil, err
+			}
+		}
+		file, err := os.Open(req.CaptureFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open capture file: %w", err)
+		}
+		defer file.Close()
+		return transfer.Read(file, format, transfer.Options{})
+	default:
+		return nil, utils.NewValidationError("No replay source specified", "message_ids, query or capture_file is required")
+	}
+}
+
+// run 按请求的节奏依次发送消息，直到完成或被取消
+func (s *ReplayService) run(ctx context.Context, job *replayJob, producer mq.Producer, req *types.ReplayRequest, msgs []*types.Message) {
+	defer producer.Close()
+
+	start := time.Now()
+	lastEmit := time.Time{}
+	var lastErr error
+
+	var interval time.Duration
+	if !req.PreserveTiming && req.RatePerSecond > 0 {
+		interval = time.Duration(float64(time.Second) / req.RatePerSecond)
+	}
+
+	for i, msg := range msgs {
+		if wait := replayDelay(req, msgs, i, interval); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+			case <-timer.C:
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		err := producer.Produce(ctx, replayProduceRequest(req, msg))
+
+		job.mu.Lock()
+		if err != nil {
+			job.status.Failed++
+			job.status.Error = err.Error()
+			lastErr = err
+		} else {
+			job.status.Sent++
+		}
+		job.mu.Unlock()
+
+		if time.Since(lastEmit) >= replayProgressInterval {
+			s.emit("replay:progress", job.snapshot())
+			lastEmit = time.Now()
+		}
+	}
+
+	job.mu.Lock()
+	finished := time.Now()
+	job.status.FinishedAt = &finished
+	switch {
+	case ctx.Err() != nil:
+		job.status.State = types.ReplayStateCancelled
+	case job.status.Sent == 0 && lastErr != nil:
+		job.status.State = types.ReplayStateFailed
+	default:
+		job.status.State = types.ReplayStateCompleted
+	}
+	status := job.status
+	job.mu.Unlock()
+	job.cancel()
+
+	message := fmt.Sprintf("Replay %s %s: %d/%d sent, %d failed", status.ID, status.State, status.Sent, status.Total, status.Failed)
+	if lastErr != nil {
+		message = fmt.Sprintf("%s, last error: %v", message, lastErr)
+	}
+	s.historySvc.AddReplayRecord(s.ctx, req.ConnectionID, req.Topic, status.State == types.ReplayStateCompleted && status.Failed == 0, message, time.Since(start).Milliseconds())
+	s.logger.Info("ReplayService", message)
+
+	s.emit("replay:finished", status)
+	s.replays.Delete(status.ID)
+}
+
+// replayDelay 计算发送第 i 条消息前需要等待的时间
+func replayDelay(req *types.ReplayRequest, msgs []*types.Message, i int, interval time.Duration) time.Duration {
+	if i == 0 {
+		return 0
+	}
+	if req.PreserveTiming {
+		prev, cur := msgs[i-1].Timestamp, msgs[i].Timestamp
+		if prev.IsZero() || cur.IsZero() || !cur.After(prev) {
+			return 0
+		}
+		return cur.Sub(prev)
+	}
+	return interval
+}
+
+// replayProduceRequest 根据重放选项构造发送请求
+func replayProduceRequest(req *types.ReplayRequest, msg *types.Message) *types.ProduceRequest {
+	topic := req.Topic
+	if topic == "" {
+		topic = req.TopicTransform.Apply(msg.Topic)
+	}
+
+	produceReq := &types.ProduceRequest{
+		ConnectionID: req.ConnectionID,
+		Topic:        topic,
+		Value:        msg.Value,
+	}
+	if req.PreserveKeys {
+		produceReq.Key = msg.Key
+	}
+	if req.PreserveHeaders && len(msg.Headers) > 0 {
+		produceReq.Headers = make(map[string]string, len(msg.Headers))
+		for k, v := range msg.Headers {
+			produceReq.Headers[k] = v
+		}
+	}
+	return produceReq
+}
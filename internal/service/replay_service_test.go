@@ -0,0 +1,107 @@
+package service
+
+import (
+	"fmt"
+	"mq-toolkit/pkg/types"
+	"mq-toolkit/pkg/utils"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReplayStoredMessages(t *testing.T) {
+	env := newTestEnv(t)
+	connectionID := env.createMemoryConnection("memory", t.Name())
+	svc := NewReplayService(env.ctx, env.logger, env.emit, env.factory, env.configSvc, env.historySvc, env.messageSvc)
+
+	start := time.Now().Add(-time.Minute)
+	for i := 0; i < 3; i++ {
+		msg := &types.Message{
+			ID:        fmt.Sprintf("stored-%d", i),
+			Topic:     "orders",
+			Key:       fmt.Sprintf("key-%d", i),
+			Value:     fmt.Sprintf("value-%d", i),
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+		}
+		if err := env.messageSvc.SaveMessage(env.ctx, "subscription", connectionID, msg); err != nil {
+			t.Fatalf("SaveMessage failed: %v", err)
+		}
+	}
+	result, err := env.messageSvc.SearchMessages(env.ctx, &types.MessageQuery{ConnectionID: connectionID})
+	if err != nil {
+		t.Fatalf("SearchMessages failed: %v", err)
+	}
+	var ids []string
+	for _, stored := range result.Messages {
+		ids = append(ids, stored.ID)
+	}
+
+	id, err := svc.StartReplay(&types.ReplayRequest{
+		MessageIDs:   ids,
+		ConnectionID: connectionID,
+		Topic:        "replayed",
+		PreserveKeys: true,
+	})
+	if err != nil {
+		t.Fatalf("StartReplay failed: %v", err)
+	}
+
+	status := env.waitEvent("replay:finished", nil).(types.ReplayStatus)
+	if status.ID != id || status.State != types.ReplayStateCompleted || status.Sent != 3 || status.Failed != 0 {
+		t.Fatalf("replay finished with %+v, want %s completed with 3 sent", status, id)
+	}
+
+	msgs := env.browse(t.Name(), "replayed")
+	if len(msgs) != 3 {
+		t.Fatalf("target topic has %d messages, want 3", len(msgs))
+	}
+	for i, msg := range msgs {
+		if msg.Key != fmt.Sprintf("key-%d", i) || msg.Value != fmt.Sprintf("value-%d", i) {
+			t.Errorf("message %d: got %s=%s, want key-%d=value-%d", i, msg.Key, msg.Value, i, i)
+		}
+	}
+
+	if replays := svc.ListReplays(); len(replays) != 0 {
+		t.Fatalf("finished replay is still listed: %+v", replays)
+	}
+}
+
+func TestReplayWithoutMessages(t *testing.T) {
+	env := newTestEnv(t)
+	connectionID := env.createMemoryConnection("memory", t.Name())
+	svc := NewReplayService(env.ctx, env.logger, env.emit, env.factory, env.configSvc, env.historySvc, env.messageSvc)
+
+	if _, err := svc.StartReplay(&types.ReplayRequest{ConnectionID: connectionID}); err == nil {
+		t.Fatal("StartReplay succeeded without a message source")
+	}
+}
+
+func TestReplayCaptureWithoutTopic(t *testing.T) {
+	env := newTestEnv(t)
+	connectionID := env.createMemoryConnection("memory", t.Name())
+	svc := NewReplayService(env.ctx, env.logger, env.emit, env.factory, env.configSvc, env.historySvc, env.messageSvc)
+
+	// kcat 格式的文件只有键和值，没有主题
+	capture := filepath.Join(t.TempDir(), "capture.txt")
+	if err := os.WriteFile(capture, []byte("k1\tfirst\nk2\tsecond\n"), 0600); err != nil {
+		t.Fatalf("failed to write capture file: %v", err)
+	}
+	req := &types.ReplayRequest{CaptureFile: capture, CaptureFormat: types.MessageFormatKcat, ConnectionID: connectionID}
+
+	if _, err := svc.StartReplay(req); !utils.IsErrorType(err, utils.ErrorTypeValidation) {
+		t.Fatalf("StartReplay returned %v, want a validation error for messages without a topic", err)
+	}
+	if replays := svc.ListReplays(); len(replays) != 0 {
+		t.Fatalf("rejected replay was started: %+v", replays)
+	}
+
+	req.Topic = "replayed"
+	if _, err := svc.StartReplay(req); err != nil {
+		t.Fatalf("StartReplay with a target topic failed: %v", err)
+	}
+	status := env.waitEvent("replay:finished", nil).(types.ReplayStatus)
+	if status.Sent != 2 || status.Failed != 0 {
+		t.Fatalf("replay finished with %+v, want 2 sent", status)
+	}
+}
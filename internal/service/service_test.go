@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"io"
+	"mq-toolkit/internal/database"
+	"mq-toolkit/internal/factory"
+	"mq-toolkit/internal/logger"
+	"mq-toolkit/internal/mq/memory"
+	"mq-toolkit/internal/secret"
+	"mq-toolkit/pkg/types"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testTimeout 等待后台任务事件的最长时间
+const testTimeout = 5 * time.Second
+
+// testEvent 服务推送的事件
+type testEvent struct {
+	name string
+	data interface{}
+}
+
+// testEnv 使用临时数据库和内存 broker 的服务依赖
+type testEnv struct {
+	t          *testing.T
+	ctx        context.Context
+	logger     *logger.Logger
+	factory    factory.Factory
+	configSvc  *ConfigService
+	historySvc *HistoryService
+	messageSvc *MessageService
+	healthSvc  *HealthService
+	db         *database.Database
+	secrets    *secret.Store
+	events     chan testEvent
+}
+
+// newTestEnv 创建测试依赖，测试结束后关闭数据库并丢弃内存 broker
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	db, err := database.New(filepath.Join(t.TempDir(), "mq-toolkit.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	secrets, err := secret.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to open secret store: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		db.Close()
+		memory.Reset()
+	})
+
+	env := &testEnv{
+		t:       t,
+		ctx:     ctx,
+		logger:  logger.New(logger.LevelError, io.Discard),
+		factory: factory.NewFactory(),
+		db:      db,
+		secrets: secrets,
+		events:  make(chan testEvent, 1024),
+	}
+	env.configSvc = NewConfigService(db.GetDB(), secrets)
+	env.historySvc = NewHistoryService(db.GetDB())
+	env.messageSvc = NewMessageService(db.GetDB(), db.FullTextSearch())
+	env.healthSvc = NewHealthService(env.logger, env.emit)
+	return env
+}
+
+// emit 记录事件，缓冲区已满时丢弃
+func (e *testEnv) emit(name string, data interface{}) {
+	select {
+	case e.events <- testEvent{name: name, data: data}:
+	default:
+	}
+}
+
+// waitEvent 等待名称为 name 且满足 match 的事件，超时结束测试
+func (e *testEnv) waitEvent(name string, match func(data interface{}) bool) interface{} {
+	e.t.Helper()
+	timeout := time.After(testTimeout)
+	for {
+		select {
+		case event := <-e.events:
+			if event.name == name && (match == nil || match(event.data)) {
+				return event.data
+			}
+		case <-timeout:
+			e.t.Fatalf("timed out waiting for event %s", name)
+			return nil
+		}
+	}
+}
+
+// createMemoryConnection 创建使用名为 broker 的内存 broker 的连接，返回连接ID
+func (e *testEnv) createMemoryConnection(name, broker string) string {
+	e.t.Helper()
+	config := &types.ConnectionConfig{Name: name, Type: types.MQTypeMemory, Host: broker}
+	if err := e.configSvc.CreateConnection(e.ctx, config); err != nil {
+		e.t.Fatalf("failed to create connection %s: %v", name, err)
+	}
+	return config.ID
+}
+
+// publish 直接向内存 broker 发送消息
+func (e *testEnv) publish(broker, topic string, values ...string) {
+	e.t.Helper()
+	b := memory.Open(broker, memory.Options{})
+	for _, value := range values {
+		if _, _, err := b.Publish(&types.ProduceRequest{Topic: topic, Value: value}); err != nil {
+			e.t.Fatalf("failed to publish to %s: %v", topic, err)
+		}
+	}
+}
+
+// brokerMessages 读取内存 broker 中主题的消息，主题不存在时返回错误
+func (e *testEnv) brokerMessages(broker, topic string) ([]types.Message, error) {
+	return memory.Open(broker, memory.Options{}).Browse(topic, 1000)
+}
+
+// browse 读取内存 broker 中主题的消息，失败时结束测试
+func (e *testEnv) browse(broker, topic string) []types.Message {
+	e.t.Helper()
+	msgs, err := e.brokerMessages(broker, topic)
+	if err != nil {
+		e.t.Fatalf("failed to browse %s: %v", topic, err)
+	}
+	return msgs
+}
+
+// waitFor 轮询直到 cond 成立，超时结束测试
+func (e *testEnv) waitFor(what string, cond func() bool) {
+	e.t.Helper()
+	deadline := time.Now().Add(testTimeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			e.t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
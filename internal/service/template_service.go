@@ -3,29 +3,56 @@ package service
 import (
 	"context"
 	"fmt"
+	"mq-toolkit/internal/templating"
 	"mq-toolkit/pkg/types"
 	"mq-toolkit/pkg/utils"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// maxRenderCount 单次渲染的最大消息条数
+const maxRenderCount = 100000
+
 // TemplateService manages message templates
 type TemplateService struct {
-	db *gorm.DB
+	db     *gorm.DB
+	envSvc *EnvironmentService
 }
 
-// NewTemplateService creates a new TemplateService
-func NewTemplateService(db *gorm.DB) *TemplateService {
-	return &TemplateService{db: db}
+// NewTemplateService creates a new TemplateService, variables of the active environment are applied when rendering
+func NewTemplateService(db *gorm.DB, envSvc *EnvironmentService) *TemplateService {
+	return &TemplateService{db: db, envSvc: envSvc}
 }
 
 // ListTemplates returns all message templates
 func (s *TemplateService) ListTemplates(ctx context.Context) ([]*types.MessageTemplate, error) {
+	return s.FindTemplates(ctx, "", "")
+}
+
+// FindTemplates returns message templates in a collection and/or with a tag, empty arguments match everything
+func (s *TemplateService) FindTemplates(ctx context.Context, collectionID, tag string) ([]*types.MessageTemplate, error) {
 	var templates []*types.MessageTemplate
-	if err := s.db.Order("created_at desc").Find(&templates).Error; err != nil {
+	query := s.db.Order("created_at desc")
+	if collectionID != "" {
+		query = query.Where("collection_id = ?", collectionID)
+	}
+	if err := query.Find(&templates).Error; err != nil {
 		return nil, fmt.Errorf("failed to list templates: %w", err)
 	}
-	return templates, nil
+	if tag == "" {
+		return templates, nil
+	}
+
+	// 标签以 JSON 数组存储，在内存中过滤
+	filtered := templates[:0]
+	for _, template := range templates {
+		if utils.Contains(template.Tags, tag) {
+			filtered = append(filtered, template)
+		}
+	}
+	return filtered, nil
 }
 
 // GetTemplate returns a single message template by ID
@@ -38,29 +65,571 @@ func (s *TemplateService) GetTemplate(ctx context.Context, id string) (*types.Me
 }
 
 // CreateTemplate creates a new message template
-func (s *TemplateService) CreateTemplate(ctx context.Context, name, content string) (*types.MessageTemplate, error) {
-	template := &types.MessageTemplate{
-		ID:      utils.GenerateID(),
-		Name:    name,
-		Content: content,
+func (s *TemplateService) CreateTemplate(ctx context.Context, template *types.MessageTemplate) (*types.MessageTemplate, error) {
+	if err := s.validateTemplate(ctx, template); err != nil {
+		return nil, err
 	}
+	template.ID = utils.GenerateID()
 	if err := s.db.Create(template).Error; err != nil {
 		return nil, fmt.Errorf("failed to create template: %w", err)
 	}
 	return template, nil
 }
 
-// UpdateTemplate updates an existing message template
-func (s *TemplateService) UpdateTemplate(ctx context.Context, id, name, content string) error {
-	template := &types.MessageTemplate{
-		ID:      id,
-		Name:    name,
-		Content: content,
+// UpdateTemplate updates an existing message template, all fields are replaced
+func (s *TemplateService) UpdateTemplate(ctx context.Context, template *types.MessageTemplate) error {
+	if err := s.validateTemplate(ctx, template); err != nil {
+		return err
+	}
+	result := s.db.Model(template).Select("*").Omit("id", "created_at").Updates(template)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update template: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return utils.NewNotFoundError("template", template.ID)
 	}
-	return s.db.Model(&template).Updates(template).Error
+	return nil
+}
+
+// DuplicateTemplate copies a template into the same collection
+func (s *TemplateService) DuplicateTemplate(ctx context.Context, id string) (*types.MessageTemplate, error) {
+	template, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	template.ID = utils.GenerateID()
+	template.Name = template.Name + " (copy)"
+	template.CreatedAt = time.Time{}
+	template.UpdatedAt = time.Time{}
+	if err := s.db.Create(template).Error; err != nil {
+		return nil, fmt.Errorf("failed to duplicate template: %w", err)
+	}
+	return template, nil
 }
 
 // DeleteTemplate deletes a message template
 func (s *TemplateService) DeleteTemplate(ctx context.Context, id string) error {
 	return s.db.Delete(&types.MessageTemplate{}, "id = ?", id).Error
-}
\ No newline at end of file
+}
+
+func (s *TemplateService) validateTemplate(ctx context.Context, template *types.MessageTemplate) error {
+	if strings.TrimSpace(template.Name) == "" {
+		return utils.NewValidationError("Template name is required", "")
+	}
+	if template.Topic != "" && !strings.Contains(template.Topic, "{{") && !utils.IsValidTopic(template.Topic) {
+		return utils.NewValidationError("Invalid topic name", template.Topic)
+	}
+	if template.CollectionID != "" {
+		if _, err := s.GetCollection(ctx, template.CollectionID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListCollections returns all template collections, nested folders reference their parent by ParentID
+func (s *TemplateService) ListCollections(ctx context.Context) ([]*types.TemplateCollection, error) {
+	var collections []*types.TemplateCollection
+	if err := s.db.Order("name asc").Find(&collections).Error; err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	return collections, nil
+}
+
+// GetCollection returns a single collection by ID
+func (s *TemplateService) GetCollection(ctx context.Context, id string) (*types.TemplateCollection, error) {
+	var collection types.TemplateCollection
+	if err := s.db.First(&collection, "id = ?", id).Error; err != nil {
+		return nil, fmt.Errorf("failed to get collection: %w", err)
+	}
+	return &collection, nil
+}
+
+// CreateCollection creates a collection, or a folder when ParentID is set
+func (s *TemplateService) CreateCollection(ctx context.Context, collection *types.TemplateCollection) (*types.TemplateCollection, error) {
+	if strings.TrimSpace(collection.Name) == "" {
+		return nil, utils.NewValidationError("Collection name is required", "")
+	}
+	if collection.ParentID != "" {
+		if _, err := s.GetCollection(ctx, collection.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	collection.ID = utils.GenerateID()
+	if err := s.db.Create(collection).Error; err != nil {
+		return nil, fmt.Errorf("failed to create collection: %w", err)
+	}
+	return collection, nil
+}
+
+// UpdateCollection renames or moves a collection
+func (s *TemplateService) UpdateCollection(ctx context.Context, collection *types.TemplateCollection) error {
+	if strings.TrimSpace(collection.Name) == "" {
+		return utils.NewValidationError("Collection name is required", "")
+	}
+	if collection.ParentID != "" {
+		// 不允许移动到自身或其子文件夹下
+		ids, err := s.collectionTree(collection.ID)
+		if err != nil {
+			return err
+		}
+		if utils.Contains(ids, collection.ParentID) {
+			return utils.NewValidationError("A collection cannot be moved into itself", collection.ParentID)
+		}
+		if _, err := s.GetCollection(ctx, collection.ParentID); err != nil {
+			return err
+		}
+	}
+	result := s.db.Model(collection).Select("name", "description", "parent_id").Updates(collection)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update collection: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return utils.NewNotFoundError("collection", collection.ID)
+	}
+	return nil
+}
+
+// DeleteCollection deletes a collection with its folders and templates
+func (s *TemplateService) DeleteCollection(ctx context.Context, id string) error {
+	ids, err := s.collectionTree(id)
+	if err != nil {
+		return err
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id IN ?", ids).Delete(&types.MessageTemplate{}).Error; err != nil {
+			return fmt.Errorf("failed to delete templates: %w", err)
+		}
+		if err := tx.Where("id IN ?", ids).Delete(&types.TemplateCollection{}).Error; err != nil {
+			return fmt.Errorf("failed to delete collection: %w", err)
+		}
+		return nil
+	})
+}
+
+// DuplicateCollection deep copies a collection with its folders and templates under the same parent
+func (s *TemplateService) DuplicateCollection(ctx context.Context, id string) (*types.TemplateCollection, error) {
+	export, err := s.ExportCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	export.Collections[0].Name = export.Collections[0].Name + " (copy)"
+
+	root, err := s.GetCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	idMap, err := s.importCollections(export, root.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCollection(ctx, idMap[id])
+}
+
+// ExportCollection exports a collection with its folders and templates, an empty ID exports everything
+func (s *TemplateService) ExportCollection(ctx context.Context, id string) (*types.CollectionExport, error) {
+	export := &types.CollectionExport{
+		Version:    types.CollectionExportVersion,
+		ExportedAt: time.Now(),
+	}
+
+	if id == "" {
+		if err := s.db.Order("created_at asc").Find(&export.Collections).Error; err != nil {
+			return nil, fmt.Errorf("failed to export collections: %w", err)
+		}
+		if err := s.db.Order("created_at asc").Find(&export.Templates).Error; err != nil {
+			return nil, fmt.Errorf("failed to export templates: %w", err)
+		}
+		return export, nil
+	}
+
+	ids, err := s.collectionTree(id)
+	if err != nil {
+		return nil, err
+	}
+	// 保证父集合在子文件夹之前，导入时可按顺序重建
+	for _, collectionID := range ids {
+		collection, err := s.GetCollection(ctx, collectionID)
+		if err != nil {
+			return nil, err
+		}
+		export.Collections = append(export.Collections, collection)
+	}
+	if err := s.db.Where("collection_id IN ?", ids).Order("created_at asc").Find(&export.Templates).Error; err != nil {
+		return nil, fmt.Errorf("failed to export templates: %w", err)
+	}
+	return export, nil
+}
+
+// ImportCollection imports an exported collection file under parentID with fresh IDs
+func (s *TemplateService) ImportCollection(ctx context.Context, export *types.CollectionExport, parentID string) (*types.CollectionImportResult, error) {
+	if export.Version > types.CollectionExportVersion {
+		return nil, utils.NewValidationError("Unsupported collection file version", fmt.Sprint(export.Version))
+	}
+	if parentID != "" {
+		if _, err := s.GetCollection(ctx, parentID); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := s.importCollections(export, parentID); err != nil {
+		return nil, err
+	}
+	return &types.CollectionImportResult{
+		Collections: len(export.Collections),
+		Templates:   len(export.Templates),
+	}, nil
+}
+
+// importCollections 以新ID写入集合和模板，返回旧ID到新ID的映射
+func (s *TemplateService) importCollections(export *types.CollectionExport, parentID string) (map[string]string, error) {
+	idMap := make(map[string]string, len(export.Collections))
+	for _, collection := range export.Collections {
+		idMap[collection.ID] = utils.GenerateID()
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, collection := range export.Collections {
+			copied := *collection
+			copied.ID = idMap[collection.ID]
+			if mapped, ok := idMap[collection.ParentID]; ok {
+				copied.ParentID = mapped
+			} else {
+				// 父集合不在导入内容中，挂到指定位置
+				copied.ParentID = parentID
+			}
+			copied.CreatedAt, copied.UpdatedAt = time.Time{}, time.Time{}
+			if err := tx.Create(&copied).Error; err != nil {
+				return fmt.Errorf("failed to import collection %s: %w", collection.Name, err)
+			}
+		}
+		for _, template := range export.Templates {
+			copied := *template
+			copied.ID = utils.GenerateID()
+			if mapped, ok := idMap[template.CollectionID]; ok {
+				copied.CollectionID = mapped
+			} else {
+				copied.CollectionID = parentID
+			}
+			copied.CreatedAt, copied.UpdatedAt = time.Time{}, time.Time{}
+			if err := tx.Create(&copied).Error; err != nil {
+				return fmt.Errorf("failed to import template %s: %w", template.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return idMap, nil
+}
+
+// ImportTemplates imports collections and templates from a bundle, keeping their IDs unless they conflict.
+// Collections must be ordered parents first; references to collections missing from both the bundle and
+// the database are cleared.
+func (s *TemplateService) ImportTemplates(ctx context.Context, collections []*types.TemplateCollection, templates []*types.MessageTemplate, mode types.ImportConflictMode) ([]*types.ImportItemResult, error) {
+	var results []*types.ImportItemResult
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		idMap := make(map[string]string, len(collections))
+		resolveCollection := func(id string) (string, error) {
+			if id == "" {
+				return "", nil
+			}
+			if mapped, ok := idMap[id]; ok {
+				return mapped, nil
+			}
+			exists, err := recordExists(tx, &types.TemplateCollection{}, "id = ?", id)
+			if err != nil || !exists {
+				return "", err
+			}
+			return id, nil
+		}
+
+		for _, collection := range collections {
+			copied := *collection
+			copied.CreatedAt, copied.UpdatedAt = time.Time{}, time.Time{}
+			parentID, err := resolveCollection(collection.ParentID)
+			if err != nil {
+				return fmt.Errorf("failed to import collection %s: %w", collection.Name, err)
+			}
+			copied.ParentID = parentID
+
+			result, err := importRecord(tx, mode, "collection", &copied, &copied.ID, &copied.Name, &types.TemplateCollection{},
+				func() error { return tx.Model(&copied).Select("name", "description", "parent_id").Updates(&copied).Error })
+			if err != nil {
+				return fmt.Errorf("failed to import collection %s: %w", collection.Name, err)
+			}
+			idMap[collection.ID] = copied.ID
+			results = append(results, result)
+		}
+
+		for _, template := range templates {
+			copied := *template
+			copied.CreatedAt, copied.UpdatedAt = time.Time{}, time.Time{}
+			collectionID, err := resolveCollection(template.CollectionID)
+			if err != nil {
+				return fmt.Errorf("failed to import template %s: %w", template.Name, err)
+			}
+			copied.CollectionID = collectionID
+
+			result, err := importRecord(tx, mode, "template", &copied, &copied.ID, &copied.Name, &types.MessageTemplate{},
+				func() error { return tx.Model(&copied).Select("*").Omit("id", "created_at").Updates(&copied).Error })
+			if err != nil {
+				return fmt.Errorf("failed to import template %s: %w", template.Name, err)
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// importRecord creates record, resolving an ID conflict according to mode.
+// On rename the record gets a new ID and a unique name, on skip *id is left pointing at the existing record.
+func importRecord(tx *gorm.DB, mode types.ImportConflictMode, kind string, record interface{}, id, name *string, model interface{}, overwrite func() error) (*types.ImportItemResult, error) {
+	result := &types.ImportItemResult{Kind: kind, Name: *name, Action: "created"}
+	if *id == "" {
+		*id = utils.GenerateID()
+	}
+
+	exists, err := recordExists(tx, model, "id = ?", *id)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		switch mode {
+		case types.ImportConflictOverwrite:
+			if err := overwrite(); err != nil {
+				return nil, err
+			}
+			result.Action = "overwritten"
+			return result, nil
+		case types.ImportConflictRename:
+			*id = utils.GenerateID()
+			unique, err := uniqueName(*name, func(candidate string) (bool, error) {
+				return recordExists(tx, model, "name = ?", candidate)
+			})
+			if err != nil {
+				return nil, err
+			}
+			*name = unique
+			result.Action, result.NewName = "renamed", unique
+		default:
+			result.Action = "skipped"
+			return result, nil
+		}
+	}
+
+	if err := tx.Create(record).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func recordExists(tx *gorm.DB, model interface{}, query string, args ...interface{}) (bool, error) {
+	var count int64
+	if err := tx.Model(model).Where(query, args...).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// collectionTree 返回集合及其所有子文件夹的ID，父级在前
+func (s *TemplateService) collectionTree(id string) ([]string, error) {
+	var collections []*types.TemplateCollection
+	if err := s.db.Select("id", "parent_id").Find(&collections).Error; err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	children := make(map[string][]string)
+	found := false
+	for _, collection := range collections {
+		children[collection.ParentID] = append(children[collection.ParentID], collection.ID)
+		if collection.ID == id {
+			found = true
+		}
+	}
+	if !found {
+		return nil, utils.NewNotFoundError("collection", id)
+	}
+
+	ids := []string{id}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+	return ids, nil
+}
+
+// Render renders a template count times for preview
+func (s *TemplateService) Render(ctx context.Context, req *types.RenderRequest) ([]string, error) {
+	content, defaults := req.Content, map[string]string(nil)
+	if req.TemplateID != "" {
+		template, err := s.GetTemplate(ctx, req.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+		if content == "" {
+			content = template.Content
+		}
+		defaults = template.Variables
+	}
+	variables, err := s.variables(ctx, defaults, req.Variables)
+	if err != nil {
+		return nil, err
+	}
+	count, err := renderCount(req.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := templating.Parse(content, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	seq := renderSeqStart(req.SeqStart)
+	values := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		value, err := tmpl.Execute(seq + int64(i))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// ResolveProduceRequest fills empty fields of req from the saved template and the active environment,
+// request headers and variables take precedence
+func (s *TemplateService) ResolveProduceRequest(ctx context.Context, req *types.RenderProduceRequest) (*types.RenderProduceRequest, error) {
+	resolved := *req
+	if req.TemplateID == "" {
+		variables, err := s.variables(ctx, nil, req.Variables)
+		if err != nil {
+			return nil, err
+		}
+		resolved.Variables = variables
+		return &resolved, nil
+	}
+	template, err := s.GetTemplate(ctx, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if resolved.ConnectionID == "" {
+		resolved.ConnectionID = template.ConnectionID
+	}
+	if resolved.Content == "" {
+		resolved.Content = template.Content
+	}
+	if resolved.Topic == "" {
+		resolved.Topic = template.Topic
+	}
+	if resolved.Key == "" {
+		resolved.Key = template.Key
+	}
+	if resolved.Partition == nil {
+		resolved.Partition = template.Partition
+	}
+	resolved.Headers = mergeVariables(template.Headers, req.Headers)
+	if resolved.Variables, err = s.variables(ctx, template.Variables, req.Variables); err != nil {
+		return nil, err
+	}
+	return &resolved, nil
+}
+
+// variables 按 模板默认值 < 激活环境 < 请求变量 的优先级合并渲染变量
+func (s *TemplateService) variables(ctx context.Context, defaults, overrides map[string]string) (map[string]string, error) {
+	envVars, err := s.envSvc.Variables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mergeVariables(mergeVariables(defaults, envVars), overrides), nil
+}
+
+// RenderProduceRequests renders a resolved request into produce requests, topic, key and header values are templates too
+func (s *TemplateService) RenderProduceRequests(ctx context.Context, req *types.RenderProduceRequest) ([]*types.ProduceRequest, error) {
+	count, err := renderCount(req.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := templating.Parse(req.Content, req.Variables)
+	if err != nil {
+		return nil, err
+	}
+	topic, err := templating.Parse(req.Topic, req.Variables)
+	if err != nil {
+		return nil, fmt.Errorf("topic: %w", err)
+	}
+	key, err := templating.Parse(req.Key, req.Variables)
+	if err != nil {
+		return nil, fmt.Errorf("key: %w", err)
+	}
+	headers := make(map[string]*templating.Template, len(req.Headers))
+	for name, header := range req.Headers {
+		if headers[name], err = templating.Parse(header, req.Variables); err != nil {
+			return nil, fmt.Errorf("header %s: %w", name, err)
+		}
+	}
+
+	seqStart := renderSeqStart(req.SeqStart)
+	reqs := make([]*types.ProduceRequest, 0, count)
+	for i := 0; i < count; i++ {
+		seq := seqStart + int64(i)
+		produceReq := &types.ProduceRequest{
+			ConnectionID: req.ConnectionID,
+			Partition:    req.Partition,
+			Headers:      make(map[string]string, len(headers)),
+		}
+		if produceReq.Value, err = value.Execute(seq); err != nil {
+			return nil, err
+		}
+		if produceReq.Topic, err = topic.Execute(seq); err != nil {
+			return nil, fmt.Errorf("topic: %w", err)
+		}
+		if !utils.IsValidTopic(produceReq.Topic) {
+			return nil, utils.NewValidationError("Invalid topic name", produceReq.Topic)
+		}
+		if produceReq.Key, err = key.Execute(seq); err != nil {
+			return nil, fmt.Errorf("key: %w", err)
+		}
+		for name, header := range headers {
+			if produceReq.Headers[name], err = header.Execute(seq); err != nil {
+				return nil, fmt.Errorf("header %s: %w", name, err)
+			}
+		}
+		reqs = append(reqs, produceReq)
+	}
+	return reqs, nil
+}
+
+// mergeVariables 合并两组键值，overrides 中的值优先
+func mergeVariables(base, overrides map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(overrides))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
+
+func renderCount(count int) (int, error) {
+	if count <= 0 {
+		return 1, nil
+	}
+	if count > maxRenderCount {
+		return 0, utils.NewValidationError(fmt.Sprintf("Count must not exceed %d", maxRenderCount), fmt.Sprint(count))
+	}
+	return count, nil
+}
+
+func renderSeqStart(seq int64) int64 {
+	if seq <= 0 {
+		return 1
+	}
+	return seq
+}
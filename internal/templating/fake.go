@@ -0,0 +1,69 @@
+package templating
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+var firstNames = []string{
+	"James", "Mary", "John", "Patricia", "Robert", "Jennifer", "Michael", "Linda",
+	"William", "Elizabeth", "David", "Barbara", "Richard", "Susan", "Joseph", "Jessica",
+	"Thomas", "Sarah", "Charles", "Karen", "Wei", "Li", "Hiroshi", "Yuki", "Carlos", "Sofia",
+}
+
+var lastNames = []string{
+	"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis",
+	"Rodriguez", "Martinez", "Hernandez", "Lopez", "Wilson", "Anderson", "Taylor", "Thomas",
+	"Moore", "Jackson", "Martin", "Lee", "Wang", "Zhang", "Tanaka", "Sato", "Silva", "Rossi",
+}
+
+var streetNames = []string{
+	"Main", "Oak", "Pine", "Maple", "Cedar", "Elm", "Lake", "Hill", "Park", "Washington",
+	"Sunset", "River", "Church", "Mill", "Spring", "Highland",
+}
+
+var streetSuffixes = []string{"St", "Ave", "Rd", "Blvd", "Ln", "Dr", "Way", "Ct"}
+
+var cities = []string{
+	"New York", "London", "Paris", "Berlin", "Tokyo", "Shanghai", "Beijing", "Singapore",
+	"Sydney", "Toronto", "Amsterdam", "Madrid", "Seoul", "San Francisco", "Chicago", "Austin",
+}
+
+var countries = []string{
+	"United States", "United Kingdom", "France", "Germany", "Japan", "China", "Singapore",
+	"Australia", "Canada", "Netherlands", "Spain", "South Korea", "Brazil", "India",
+}
+
+var companies = []string{
+	"Acme Corp", "Globex", "Initech", "Umbrella", "Hooli", "Stark Industries", "Wayne Enterprises",
+	"Wonka Industries", "Cyberdyne", "Soylent", "Tyrell", "Vandelay Industries",
+}
+
+var emailDomains = []string{"example.com", "example.org", "example.net", "test.local"}
+
+// fullName 随机生成全名
+func fullName() string {
+	return pick(firstNames...) + " " + pick(lastNames...)
+}
+
+// email 随机生成邮箱地址，域名均为保留的示例域名
+func email() string {
+	user := strings.ToLower(pick(firstNames...) + "." + pick(lastNames...))
+	return fmt.Sprintf("%s%d@%s", user, rand.Intn(1000), pick(emailDomains...))
+}
+
+// phone 随机生成电话号码
+func phone() string {
+	return fmt.Sprintf("+1-%03d-%03d-%04d", 200+rand.Intn(800), rand.Intn(1000), rand.Intn(10000))
+}
+
+// street 随机生成街道地址
+func street() string {
+	return fmt.Sprintf("%d %s %s", 1+rand.Intn(9999), pick(streetNames...), pick(streetSuffixes...))
+}
+
+// address 随机生成完整地址
+func address() string {
+	return fmt.Sprintf("%s, %s %05d, %s", street(), pick(cities...), rand.Intn(100000), pick(countries...))
+}
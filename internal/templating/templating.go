@@ -0,0 +1,185 @@
+// Package templating 渲染消息模板，支持变量和随机测试数据生成
+//
+// 模板使用 text/template 语法，例如：
+//
+//	{"id": "{{uuid}}", "seq": {{seq}}, "at": "{{now "RFC3339"}}",
+//	 "user": "{{name}}", "email": "{{email}}", "level": "{{pick "info" "warn" "error"}}",
+//	 "score": {{randInt 1 100}}, "env": "{{.env}}", "region": "{{region}}"}
+//
+// 用户变量既可以通过 {{.名称}} 访问，也可以作为无参函数 {{名称}} 调用（名称需是合法标识符，
+// 且不能与内置函数重名），{{var "名称" "默认值"}} 可在变量缺失时提供默认值。
+package templating
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// identPattern 可以注册为模板函数的变量名
+var identPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Template 编译后的模板，可被多个 goroutine 共享；Execute 之间互斥，并发渲染时各 goroutine 应使用 Clone 得到的副本
+type Template struct {
+	mu   sync.Mutex
+	tmpl *template.Template
+	vars map[string]string
+	seq  int64
+}
+
+// Parse 编译模板，vars 中的变量同时注册为无参函数
+func Parse(content string, vars map[string]string) (*Template, error) {
+	t := &Template{vars: vars}
+
+	funcs := t.funcs()
+	for name, value := range vars {
+		if _, builtin := funcs[name]; builtin || !identPattern.MatchString(name) {
+			continue
+		}
+		value := value
+		funcs[name] = func() string { return value }
+	}
+
+	tmpl, err := template.New("message").Option("missingkey=zero").Funcs(funcs).Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	t.tmpl = tmpl
+	return t, nil
+}
+
+// Execute 以序号 seq 渲染一条消息，{{seq}} 返回该序号
+func (t *Template) Execute(seq int64) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.seq = seq
+	var sb strings.Builder
+	if err := t.tmpl.Execute(&sb, t.vars); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// Clone 返回共享编译结果但序号独立的副本，副本与原模板可同时渲染
+func (t *Template) Clone() (*Template, error) {
+	c := &Template{vars: t.vars}
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	c.tmpl = tmpl.Funcs(template.FuncMap{"seq": func() int64 { return c.seq }})
+	return c, nil
+}
+
+// Render 编译并渲染一次模板
+func Render(content string, vars map[string]string, seq int64) (string, error) {
+	t, err := Parse(content, vars)
+	if err != nil {
+		return "", err
+	}
+	return t.Execute(seq)
+}
+
+// funcs 内置模板函数
+func (t *Template) funcs() template.FuncMap {
+	return template.FuncMap{
+		"uuid": func() string { return uuid.New().String() },
+		"seq":  func() int64 { return t.seq },
+		"now":  now,
+		"var": func(name string, fallback ...string) string {
+			if value, ok := t.vars[name]; ok {
+				return value
+			}
+			if len(fallback) > 0 {
+				return fallback[0]
+			}
+			return ""
+		},
+		"randInt":    randInt,
+		"randFloat":  randFloat,
+		"randBool":   func() bool { return rand.Intn(2) == 1 },
+		"randString": randString,
+		"pick":       pick,
+		"firstName":  func() string { return pick(firstNames...) },
+		"lastName":   func() string { return pick(lastNames...) },
+		"name":       fullName,
+		"email":      email,
+		"phone":      phone,
+		"street":     street,
+		"city":       func() string { return pick(cities...) },
+		"country":    func() string { return pick(countries...) },
+		"zip":        func() string { return fmt.Sprintf("%05d", rand.Intn(100000)) },
+		"address":    address,
+		"company":    func() string { return pick(companies...) },
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+	}
+}
+
+// now 按布局返回当前时间，支持 RFC3339、RFC3339Nano、Unix、UnixMilli 等名称或 Go 时间布局
+func now(layout ...string) string {
+	t := time.Now()
+	if len(layout) == 0 {
+		return t.Format(time.RFC3339)
+	}
+	switch layout[0] {
+	case "RFC3339":
+		return t.Format(time.RFC3339)
+	case "RFC3339Nano":
+		return t.Format(time.RFC3339Nano)
+	case "RFC1123":
+		return t.Format(time.RFC1123)
+	case "DateTime":
+		return t.Format(time.DateTime)
+	case "Date":
+		return t.Format(time.DateOnly)
+	case "Unix":
+		return fmt.Sprint(t.Unix())
+	case "UnixMilli":
+		return fmt.Sprint(t.UnixMilli())
+	case "UnixNano":
+		return fmt.Sprint(t.UnixNano())
+	}
+	return t.Format(layout[0])
+}
+
+// randInt 返回 [min, max] 之间的随机整数
+func randInt(min, max int) int {
+	if max <= min {
+		return min
+	}
+	return min + rand.Intn(max-min+1)
+}
+
+// randFloat 返回 [min, max) 之间的随机浮点数
+func randFloat(min, max float64) float64 {
+	if max <= min {
+		return min
+	}
+	return min + rand.Float64()*(max-min)
+}
+
+// randString 返回长度为 n 的随机字母数字字符串
+func randString(n int) string {
+	const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	buf := make([]byte, n)
+	for i := range buf {
+		buf[i] = alphabet[rand.Intn(len(alphabet))]
+	}
+	return string(buf)
+}
+
+// pick 从候选值中随机选择一个
+func pick(values ...string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[rand.Intn(len(values))]
+}
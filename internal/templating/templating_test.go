@@ -0,0 +1,164 @@
+package templating
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRenderVariables(t *testing.T) {
+	vars := map[string]string{"env": "prod", "region": "eu-west-1", "my-var": "dashed"}
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{`{{.env}}`, "prod"},
+		{`{{env}}`, "prod"},                           // 变量同时注册为无参函数
+		{`{{var "region" "us-east-1"}}`, "eu-west-1"}, // 变量存在时忽略默认值
+		{`{{var "zone" "a"}}`, "a"},                   // 变量缺失时使用默认值
+		{`{{var "zone"}}`, ""},                        // 没有默认值时为空
+		{`{{index . "my-var"}}`, "dashed"},            // 非标识符名称只能通过 . 访问
+		{`{{upper .env}}-{{lower "EU"}}`, "PROD-eu"},  // 内置字符串函数
+		{`seq={{seq}}`, "seq=42"},                     // {{seq}} 返回渲染序号
+		{`{"env": "{{.env}}", "n": {{seq}}}`, `{"env": "prod", "n": 42}`},
+	}
+	for _, tt := range tests {
+		got, err := Render(tt.content, vars, 42)
+		if err != nil {
+			t.Errorf("Render(%s) failed: %v", tt.content, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Render(%s) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestRenderMissingKey(t *testing.T) {
+	// missingkey=zero：缺失的变量渲染为空字符串而不是 <no value>
+	got, err := Render(`[{{.missing}}]`, map[string]string{"env": "prod"}, 0)
+	if err != nil || got != "[]" {
+		t.Fatalf("Render returned %q, %v; want []", got, err)
+	}
+	got, err = Render(`[{{.missing}}]`, nil, 0)
+	if err != nil || got != "[]" {
+		t.Fatalf("Render without variables returned %q, %v; want []", got, err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, content := range []string{`{{seq`, `{{unknownFunc}}`, `{{if .env}}`} {
+		if _, err := Parse(content, nil); err == nil {
+			t.Errorf("Parse(%s) succeeded, want an error", content)
+		}
+	}
+	// 函数参数错误在渲染时才发现
+	if _, err := Render(`{{randInt "a" 2}}`, nil, 0); err == nil {
+		t.Error("Render with a bad randInt argument succeeded, want an error")
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	tests := []struct {
+		content string
+		pattern string
+	}{
+		{`{{uuid}}`, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{`{{now}}`, `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(Z|[+-]\d{2}:\d{2})$`},
+		{`{{now "RFC3339Nano"}}`, `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})$`},
+		{`{{now "DateTime"}}`, `^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}$`},
+		{`{{now "Date"}}`, `^\d{4}-\d{2}-\d{2}$`},
+		{`{{now "Unix"}}`, `^\d{10}$`},
+		{`{{now "UnixMilli"}}`, `^\d{13}$`},
+		{`{{now "UnixNano"}}`, `^\d{19}$`},
+		{`{{now "2006/01/02"}}`, `^\d{4}/\d{2}/\d{2}$`},
+		{`{{randInt 5 7}}`, `^[567]$`},
+		{`{{randInt 9 3}}`, `^9$`}, // max <= min 时返回 min
+		{`{{randFloat 1.0 2.0}}`, `^1(\.\d+)?$`},
+		{`{{randBool}}`, `^(true|false)$`},
+		{`{{randString 12}}`, `^[A-Za-z0-9]{12}$`},
+		{`{{pick "a" "b"}}`, `^(a|b)$`},
+		{`{{firstName}}`, `^[A-Z][a-z]+$`},
+		{`{{lastName}}`, `^[A-Z][a-z]+$`},
+		{`{{name}}`, `^[A-Z][a-z]+ [A-Z][a-z]+$`},
+		{`{{email}}`, `^[a-z]+\.[a-z]+\d{1,3}@(example\.com|example\.org|example\.net|test\.local)$`},
+		{`{{phone}}`, `^\+1-\d{3}-\d{3}-\d{4}$`},
+		{`{{street}}`, `^\d{1,4} [A-Z][a-z]+ [A-Z][a-z]+$`},
+		{`{{city}}`, `^[A-Z][A-Za-z ]+$`},
+		{`{{country}}`, `^[A-Z][A-Za-z ]+$`},
+		{`{{zip}}`, `^\d{5}$`},
+		{`{{address}}`, `^\d{1,4} [A-Z][a-z]+ [A-Z][a-z]+, [A-Z][A-Za-z ]+ \d{5}, [A-Z][A-Za-z ]+$`},
+		{`{{company}}`, `^[A-Z][A-Za-z ]+$`},
+	}
+	for _, tt := range tests {
+		pattern := regexp.MustCompile(tt.pattern)
+		// 随机生成器多渲染几次，覆盖不同的取值
+		for i := 0; i < 20; i++ {
+			got, err := Render(tt.content, nil, 0)
+			if err != nil {
+				t.Fatalf("Render(%s) failed: %v", tt.content, err)
+			}
+			if !pattern.MatchString(got) {
+				t.Errorf("Render(%s) = %q, want a match for %s", tt.content, got, tt.pattern)
+				break
+			}
+		}
+	}
+}
+
+func TestNowUsesCurrentTime(t *testing.T) {
+	before := time.Now().Add(-time.Second).Unix()
+	got, err := Render(`{{now "Unix"}}`, nil, 0)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	unix, err := strconv.ParseInt(strings.TrimSpace(got), 10, 64)
+	if err != nil || unix < before || unix > time.Now().Unix() {
+		t.Fatalf("now \"Unix\" rendered %q, want the current time", got)
+	}
+}
+
+func TestCloneRendersIndependently(t *testing.T) {
+	tmpl, err := Parse(`{{.env}}-{{seq}}`, map[string]string{"env": "test"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for w := 0; w < 8; w++ {
+		clone, err := tmpl.Clone()
+		if err != nil {
+			t.Fatalf("Clone failed: %v", err)
+		}
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				seq := int64(w*1000 + i)
+				got, err := clone.Execute(seq)
+				if err == nil && got != "test-"+strconv.FormatInt(seq, 10) {
+					err = fmt.Errorf("rendered %q for seq %d", got, seq)
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	// 副本不影响原模板的序号
+	if got, err := tmpl.Execute(7); err != nil || got != "test-7" {
+		t.Fatalf("original template rendered %q, %v; want test-7", got, err)
+	}
+}
@@ -0,0 +1,132 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mq-toolkit/pkg/types"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvColumns CSV 文件的列，headers 列为 JSON 对象
+var csvColumns = []string{"id", "topic", "key", "value", "headers", "partition", "offset", "timestamp"}
+
+// writeCSV 写入带表头的 CSV
+func writeCSV(w io.Writer, msgs []*types.Message) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvColumns); err != nil {
+		return err
+	}
+
+	for _, msg := range msgs {
+		headers := ""
+		if len(msg.Headers) > 0 {
+			data, err := json.Marshal(msg.Headers)
+			if err != nil {
+				return fmt.Errorf("failed to encode headers of message %s: %w", msg.ID, err)
+			}
+			headers = string(data)
+		}
+
+		timestamp := ""
+		if !msg.Timestamp.IsZero() {
+			timestamp = msg.Timestamp.Format(time.RFC3339Nano)
+		}
+
+		record := []string{
+			msg.ID,
+			msg.Topic,
+			msg.Key,
+			msg.Value,
+			headers,
+			strconv.FormatInt(int64(msg.Partition), 10),
+			strconv.FormatInt(msg.Offset, 10),
+			timestamp,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// readCSV 读取 CSV，按表头定位列，缺失的列保持零值
+func readCSV(r io.Reader) ([]*types.Message, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["value"]; !ok {
+		return nil, fmt.Errorf("CSV header must contain a value column")
+	}
+
+	var msgs []*types.Message
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV record: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+
+		msg := &types.Message{
+			ID:    field("id"),
+			Topic: field("topic"),
+			Key:   field("key"),
+			Value: field("value"),
+		}
+		if headers := field("headers"); headers != "" {
+			if err := json.Unmarshal([]byte(headers), &msg.Headers); err != nil {
+				return nil, fmt.Errorf("invalid headers on line %d: %w", line, err)
+			}
+		}
+		if partition := field("partition"); partition != "" {
+			value, err := strconv.ParseInt(partition, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid partition on line %d: %w", line, err)
+			}
+			msg.Partition = int32(value)
+		}
+		if offset := field("offset"); offset != "" {
+			value, err := strconv.ParseInt(offset, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid offset on line %d: %w", line, err)
+			}
+			msg.Offset = value
+		}
+		if timestamp := field("timestamp"); timestamp != "" {
+			value, err := time.Parse(time.RFC3339Nano, timestamp)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timestamp on line %d: %w", line, err)
+			}
+			msg.Timestamp = value
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
@@ -0,0 +1,91 @@
+// Package transfer 负责消息文件的导入导出格式编解码
+package transfer
+
+import (
+	"fmt"
+	"io"
+	"mq-toolkit/pkg/types"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultDelimiter kcat 格式默认的键值分隔符，与 kafka-console-producer 的 key.separator 一致
+const DefaultDelimiter = "\t"
+
+// Options 编解码选项
+type Options struct {
+	Delimiter string
+}
+
+func (o Options) delimiter() string {
+	if o.Delimiter == "" {
+		return DefaultDelimiter
+	}
+	return o.Delimiter
+}
+
+// ParseFormat 解析格式名称，空值默认为 NDJSON
+func ParseFormat(format string) (types.MessageFormat, error) {
+	switch types.MessageFormat(strings.ToLower(strings.TrimSpace(format))) {
+	case "", types.MessageFormatNDJSON, "jsonl":
+		return types.MessageFormatNDJSON, nil
+	case types.MessageFormatCSV:
+		return types.MessageFormatCSV, nil
+	case types.MessageFormatKcat:
+		return types.MessageFormatKcat, nil
+	default:
+		return "", fmt.Errorf("unsupported message format: %s", format)
+	}
+}
+
+// FileExtension 返回格式对应的文件扩展名
+func FileExtension(format types.MessageFormat) string {
+	switch format {
+	case types.MessageFormatCSV:
+		return ".csv"
+	case types.MessageFormatKcat:
+		return ".txt"
+	default:
+		return ".ndjson"
+	}
+}
+
+// FormatFromPath 根据文件扩展名推断格式
+func FormatFromPath(path string) types.MessageFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return types.MessageFormatCSV
+	case ".txt":
+		return types.MessageFormatKcat
+	default:
+		return types.MessageFormatNDJSON
+	}
+}
+
+// Write 将消息按指定格式写入
+func Write(w io.Writer, format types.MessageFormat, msgs []*types.Message, opts Options) error {
+	switch format {
+	case types.MessageFormatNDJSON, "":
+		return writeNDJSON(w, msgs)
+	case types.MessageFormatCSV:
+		return writeCSV(w, msgs)
+	case types.MessageFormatKcat:
+		return writeKcat(w, msgs, opts.delimiter())
+	default:
+		return fmt.Errorf("unsupported message format: %s", format)
+	}
+}
+
+// Read 按指定格式读取消息
+func Read(r io.Reader, format types.MessageFormat, opts Options) ([]*types.Message, error) {
+	switch format {
+	case types.MessageFormatNDJSON, "":
+		return readNDJSON(r)
+	case types.MessageFormatCSV:
+		return readCSV(r)
+	case types.MessageFormatKcat:
+		return readKcat(r, opts.delimiter())
+	default:
+		return nil, fmt.Errorf("unsupported message format: %s", format)
+	}
+}
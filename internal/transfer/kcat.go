@@ -0,0 +1,51 @@
+package transfer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"mq-toolkit/pkg/types"
+	"strings"
+)
+
+// writeKcat 每行写入 key<分隔符>value，可直接用于 kcat -P -K 或 kafka-console-producer --property parse.key=true
+// 该格式是按行切分的，不保留 headers 等元数据
+func writeKcat(w io.Writer, msgs []*types.Message, delimiter string) error {
+	writer := bufio.NewWriter(w)
+	for _, msg := range msgs {
+		if strings.Contains(msg.Key, delimiter) {
+			return fmt.Errorf("key of message %s contains the delimiter %q", msg.ID, delimiter)
+		}
+		if strings.ContainsAny(msg.Key, "\r\n") || strings.ContainsAny(msg.Value, "\r\n") {
+			return fmt.Errorf("message %s contains line breaks, use ndjson or csv instead", msg.ID)
+		}
+		if _, err := writer.WriteString(msg.Key + delimiter + msg.Value + "\n"); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// readKcat 读取 key<分隔符>value 格式，没有分隔符的行视为无键消息
+func readKcat(r io.Reader, delimiter string) ([]*types.Message, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var msgs []*types.Message
+	for scanner.Scan() {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if text == "" {
+			continue
+		}
+		msg := &types.Message{Value: text}
+		if key, value, found := strings.Cut(text, delimiter); found {
+			msg.Key = key
+			msg.Value = value
+		}
+		msgs = append(msgs, msg)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return msgs, nil
+}
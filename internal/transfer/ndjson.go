@@ -0,0 +1,50 @@
+package transfer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mq-toolkit/pkg/types"
+	"strings"
+)
+
+// maxLineSize 单行消息的最大长度
+const maxLineSize = 16 * 1024 * 1024
+
+// writeNDJSON 每行一条 JSON 编码的消息
+func writeNDJSON(w io.Writer, msgs []*types.Message) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, msg := range msgs {
+		if err := encoder.Encode(msg); err != nil {
+			return fmt.Errorf("failed to encode message %s: %w", msg.ID, err)
+		}
+	}
+	return nil
+}
+
+// readNDJSON 读取每行一条 JSON 的消息，忽略空行
+func readNDJSON(r io.Reader) ([]*types.Message, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var msgs []*types.Message
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var msg types.Message
+		if err := json.Unmarshal([]byte(text), &msg); err != nil {
+			return nil, fmt.Errorf("invalid JSON on line %d: %w", line, err)
+		}
+		msgs = append(msgs, &msg)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return msgs, nil
+}
@@ -0,0 +1,231 @@
+package tunnel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mq-toolkit/pkg/types"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// sshDialer 经 SSH 跳板机转发连接，SSH 连接断开后在下次拨号时重建
+type sshDialer struct {
+	addr   string
+	config *ssh.ClientConfig
+	agent  net.Conn // ssh-agent 连接，未使用时为 nil
+
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+func newSSHDialer(cfg *types.TunnelConfig) (*sshDialer, error) {
+	d := &sshDialer{addr: net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))}
+
+	auth, err := d.authMethods(cfg)
+	if err != nil {
+		d.Close()
+		return nil, err
+	}
+	hostKeyCallback, err := hostKeyCallback(cfg)
+	if err != nil {
+		d.Close()
+		return nil, err
+	}
+
+	d.config = &ssh.ClientConfig{
+		User:            cfg.Username,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         dialTimeout,
+	}
+	return d, nil
+}
+
+// authMethods 按私钥、ssh-agent、密码的顺序组合认证方式
+func (d *sshDialer) authMethods(cfg *types.TunnelConfig) ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+
+	keyData := []byte(cfg.PrivateKey)
+	if len(keyData) == 0 && cfg.PrivateKeyPath != "" {
+		data, err := os.ReadFile(expandHome(cfg.PrivateKeyPath))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key: %w", err)
+		}
+		keyData = data
+	}
+	if len(keyData) > 0 {
+		var signer ssh.Signer
+		var err error
+		if cfg.Passphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(keyData, []byte(cfg.Passphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey(keyData)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+
+	if cfg.UseAgent {
+		socket := os.Getenv("SSH_AUTH_SOCK")
+		if socket == "" {
+			return nil, fmt.Errorf("ssh-agent is not available: SSH_AUTH_SOCK is not set")
+		}
+		conn, err := net.Dial("unix", socket)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to ssh-agent: %w", err)
+		}
+		d.agent = conn
+		methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+	}
+
+	if cfg.Password != "" {
+		password := cfg.Password
+		methods = append(methods,
+			ssh.Password(password),
+			ssh.KeyboardInteractive(func(_, _ string, questions []string, _ []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i := range answers {
+					answers[i] = password
+				}
+				return answers, nil
+			}),
+		)
+	}
+
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("no SSH authentication method configured: set a password, private key or enable ssh-agent")
+	}
+	return methods, nil
+}
+
+// hostKeyCallback 使用 known_hosts 校验跳板机主机密钥
+func hostKeyCallback(cfg *types.TunnelConfig) (ssh.HostKeyCallback, error) {
+	if cfg.InsecureIgnoreHostKey {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	path := cfg.KnownHostsPath
+	if path == "" {
+		path = "~/.ssh/known_hosts"
+	}
+	callback, err := knownhosts.New(expandHome(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known_hosts %s: %w", path, err)
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := callback(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if errors.As(err, &keyErr) && len(keyErr.Want) == 0 {
+			return fmt.Errorf("host key of %s is not in %s, connect once with ssh to trust it", hostname, path)
+		}
+		return err
+	}, nil
+}
+
+// DialContext 经 SSH 连接转发到 addr，SSH 连接已断开时重连一次
+func (d *sshDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	client, err := d.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := client.DialContext(ctx, network, addr)
+	if err == nil {
+		return conn, nil
+	}
+	if ctx.Err() != nil {
+		return nil, err
+	}
+
+	// SSH 连接可能已失效，丢弃后重试
+	d.reset(client)
+	if client, err = d.connect(ctx); err != nil {
+		return nil, err
+	}
+	conn, err = client.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s through SSH tunnel: %w", addr, err)
+	}
+	return conn, nil
+}
+
+// connect 返回当前 SSH 连接，不存在时建立
+func (d *sshDialer) connect(ctx context.Context) (*ssh.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.client != nil {
+		return d.client, nil
+	}
+
+	netDialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := netDialer.DialContext(ctx, "tcp", d.addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SSH host %s: %w", d.addr, err)
+	}
+	// SSH 握手不支持 context，用截止时间限制
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(dialTimeout)
+	}
+	conn.SetDeadline(deadline)
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, d.addr, d.config)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SSH handshake with %s failed: %w", d.addr, err)
+	}
+	conn.SetDeadline(time.Time{})
+
+	client := ssh.NewClient(sshConn, chans, reqs)
+	d.client = client
+	go func() {
+		client.Wait()
+		d.reset(client)
+	}()
+	return client, nil
+}
+
+// reset 丢弃已失效的 SSH 连接
+func (d *sshDialer) reset(client *ssh.Client) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.client == client {
+		d.client = nil
+		client.Close()
+	}
+}
+
+// Close 关闭 SSH 连接和 ssh-agent 连接
+func (d *sshDialer) Close() error {
+	d.mu.Lock()
+	client := d.client
+	d.client = nil
+	d.mu.Unlock()
+
+	var err error
+	if client != nil {
+		err = client.Close()
+	}
+	if d.agent != nil {
+		d.agent.Close()
+	}
+	return err
+}
+
+// expandHome 展开路径开头的 ~
+func expandHome(path string) string {
+	if path == "~" || len(path) > 1 && path[:2] == "~/" {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
@@ -0,0 +1,155 @@
+// Package tunnel 为各消息队列适配器提供经 SSH 跳板机、SOCKS5 或 HTTP CONNECT 代理的拨号
+//
+// 相同配置的隧道在进程内共享，同一连接的生产者、消费者和管理客户端只建立一条 SSH 连接。
+// 拨号前按 AddressMap 改写目标地址，使 Kafka 等返回的 broker 公告地址也能经隧道访问。
+package tunnel
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mq-toolkit/pkg/types"
+	"net"
+	"sync"
+	"time"
+)
+
+// dialTimeout 连接跳板机或代理的默认超时
+const dialTimeout = 15 * time.Second
+
+// dialer 具体的隧道实现
+type dialer interface {
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+	Close() error
+}
+
+// Tunnel 按配置拨号的共享隧道，使用完毕后调用 Close
+type Tunnel struct {
+	key        string
+	addressMap map[string]string
+	dialer     dialer
+	refs       int
+}
+
+var (
+	poolMu sync.Mutex
+	pool   = make(map[string]*Tunnel)
+)
+
+// Open 打开隧道，cfg 为 nil 时返回直连拨号器；相同配置的隧道共享底层连接
+func Open(cfg *types.TunnelConfig) (*Tunnel, error) {
+	if cfg == nil {
+		cfg = &types.TunnelConfig{}
+	}
+	key, err := configKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	poolMu.Lock()
+	defer poolMu.Unlock()
+	if t, ok := pool[key]; ok {
+		t.refs++
+		return t, nil
+	}
+
+	d, err := newDialer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tunnel{key: key, addressMap: cfg.AddressMap, dialer: d, refs: 1}
+	pool[key] = t
+	return t, nil
+}
+
+func newDialer(cfg *types.TunnelConfig) (dialer, error) {
+	if cfg.Type != types.TunnelDirect && (cfg.Host == "" || cfg.Port <= 0) {
+		return nil, fmt.Errorf("tunnel host and port are required")
+	}
+	switch cfg.Type {
+	case types.TunnelDirect:
+		return directDialer{}, nil
+	case types.TunnelSSH:
+		return newSSHDialer(cfg)
+	case types.TunnelSOCKS5:
+		return newSOCKS5Dialer(cfg)
+	case types.TunnelHTTP:
+		return newHTTPDialer(cfg), nil
+	default:
+		return nil, fmt.Errorf("unsupported tunnel type: %s", cfg.Type)
+	}
+}
+
+// configKey 返回配置的摘要，作为共享隧道的键
+func configKey(cfg *types.TunnelConfig) (string, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Enabled 判断连接是否配置了隧道或地址映射
+func Enabled(cfg *types.TunnelConfig) bool {
+	return cfg != nil && (cfg.Type != types.TunnelDirect || len(cfg.AddressMap) > 0)
+}
+
+// OpenIfEnabled 配置了隧道或地址映射时打开隧道，否则返回 nil，调用方可直接对结果调用 Close
+func OpenIfEnabled(cfg *types.TunnelConfig) (*Tunnel, error) {
+	if !Enabled(cfg) {
+		return nil, nil
+	}
+	return Open(cfg)
+}
+
+// DialContext 经隧道连接 addr，addr 先按地址映射改写
+func (t *Tunnel) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return t.dialer.DialContext(ctx, network, t.Rewrite(addr))
+}
+
+// Dial 与 DialContext 相同，使用默认超时
+func (t *Tunnel) Dial(network, addr string) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+	return t.DialContext(ctx, network, addr)
+}
+
+// Rewrite 按地址映射改写 host:port，未配置映射时原样返回
+func (t *Tunnel) Rewrite(addr string) string {
+	if mapped, ok := t.addressMap[addr]; ok {
+		return mapped
+	}
+	return addr
+}
+
+// Close 释放隧道，最后一个使用者释放时关闭底层连接；t 为 nil 时不做任何事
+func (t *Tunnel) Close() error {
+	if t == nil {
+		return nil
+	}
+	poolMu.Lock()
+	t.refs--
+	last := t.refs == 0
+	if last {
+		delete(pool, t.key)
+	}
+	poolMu.Unlock()
+
+	if last {
+		return t.dialer.Close()
+	}
+	return nil
+}
+
+// directDialer 直连
+type directDialer struct{}
+
+func (directDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	d := net.Dialer{Timeout: dialTimeout}
+	return d.DialContext(ctx, network, addr)
+}
+
+func (directDialer) Close() error { return nil }
@@ -2,6 +2,8 @@ package types
 
 import (
 	"context"
+	"mq-toolkit/pkg/utils"
+	"strings"
 	"time"
 )
 
@@ -12,22 +14,234 @@ const (
 	MQTypeKafka    MQType = "kafka"
 	MQTypeRabbitMQ MQType = "rabbitmq"
 	MQTypeRocketMQ MQType = "rocketmq"
+	MQTypeNATS     MQType = "nats"
+	MQTypeMQTT     MQType = "mqtt"
+	MQTypeRedis    MQType = "redis"
+	MQTypePulsar   MQType = "pulsar"
+	MQTypeAMQP10   MQType = "amqp10"
+	MQTypeSQS      MQType = "sqs"
+	MQTypeMemory   MQType = "memory"
 )
 
+// Capability 后端能力，前端据此显示连接表单和功能开关
+//
+// 连接和消费相关的能力由后端注册时声明，管理相关的能力由客户端实现的可选接口得出。
+type Capability string
+
+const (
+	CapabilityTunnel         Capability = "tunnel"           // 支持隧道和地址映射
+	CapabilityEndpoints      Capability = "endpoints"        // 可配置多个节点
+	CapabilityUserPassword   Capability = "user_password"    // 使用用户名和密码认证
+	CapabilityAWSCredentials Capability = "aws_credentials"  // 使用区域和访问密钥认证
+	CapabilityListTopics     Capability = "list_topics"      // 可以列出主题
+	CapabilityTopicPartition Capability = "topic_partitions" // 主题有分区，创建时可指定分区数
+	CapabilityTopicReplicas  Capability = "topic_replicas"   // 创建主题时可指定副本数
+	CapabilityMultiTopic     Capability = "multi_topic"      // 一次订阅多个主题
+	CapabilityStartTime      Capability = "start_time"       // 消费时从指定时间重放
+	CapabilityStartSequence  Capability = "start_sequence"   // 消费时从指定序号重放
+	CapabilityStartMessageID Capability = "start_message_id" // 消费时从指定消息 ID 浏览
+
+	CapabilityConsumerGroups  Capability = "consumer_groups"  // 可以列出消费组
+	CapabilitySeekGroup       Capability = "seek_group"       // 可以将消费组位置移到指定时间
+	CapabilityBrowse          Capability = "browse"           // 可以查看消息而不消费
+	CapabilityExchanges       Capability = "exchanges"        // 交换机和绑定管理（RabbitMQ）
+	CapabilityStreams         Capability = "streams"          // 流和待确认消息管理（Redis Streams）
+	CapabilityNamespaces      Capability = "namespaces"       // 租户和命名空间管理（Pulsar）
+	CapabilitySubscriptions   Capability = "subscriptions"    // 订阅管理（Pulsar）
+	CapabilityAddresses       Capability = "addresses"        // 地址和队列管理（Artemis）
+	CapabilityQueueAttributes Capability = "queue_attributes" // 队列属性管理（SQS）
+)
+
+// BackendInfo 消息队列后端的描述，由后端注册，前端据此生成连接表单
+type BackendInfo struct {
+	Type        MQType `json:"type"`
+	DisplayName string `json:"display_name"`
+	Order       int    `json:"order"` // 在类型列表中的位置，从小到大
+	DefaultHost string `json:"default_host"`
+	DefaultPort int    `json:"default_port"`
+	// DefaultUsername 用户名和密码留空时使用的账号，只用于提示
+	DefaultUsername string `json:"default_username,omitempty"`
+	// EndpointLabel 其他节点输入框的标签，为空时使用通用标签
+	EndpointLabel string `json:"endpoint_label,omitempty"`
+	// SettingsTitle、SettingsHelp 专用设置区域的标题和说明，Fields 为其中的设置项
+	SettingsTitle string        `json:"settings_title,omitempty"`
+	SettingsHelp  string        `json:"settings_help,omitempty"`
+	Fields        []ConfigField `json:"fields"`
+	// ConsumeHint、CreateTopicHint 消费和新建主题时显示的说明
+	ConsumeHint     string       `json:"consume_hint,omitempty"`
+	CreateTopicHint string       `json:"create_topic_hint,omitempty"`
+	Capabilities    []Capability `json:"capabilities"`
+	// TunnelHelp 不具备 CapabilityTunnel 时在隧道区域显示的说明，例如如何手动转发端口
+	TunnelHelp string `json:"tunnel_help,omitempty"`
+}
+
+// Has 检查后端是否具有能力 c
+func (b *BackendInfo) Has(c Capability) bool {
+	for _, capability := range b.Capabilities {
+		if capability == c {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfigField 连接表单中的一项设置，值以字符串保存在 Extra[Key] 中
+type ConfigField struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	// Kind 为 text、password、number 或 bool，有 Options 时显示为下拉框
+	Kind    string        `json:"kind"`
+	Options []FieldOption `json:"options,omitempty"`
+	// Default 为后端在未设置时使用的值，与之相同的值不保存；bool 类型为 "true" 或空
+	Default string `json:"default,omitempty"`
+	// ShowIf 为另一设置项的键，该项为真或不为空时才显示本项，以 ! 开头时条件取反
+	ShowIf string `json:"show_if,omitempty"`
+	// Connection 为 true 时值保存在连接的同名字段而不是 Extra 中（目前只有 vhost）
+	Connection bool `json:"connection,omitempty"`
+	Mono       bool `json:"mono,omitempty"` // 使用等宽字体，用于地址、路径和密钥
+}
+
+// FieldOption 下拉框的选项
+type FieldOption struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
 // ConnectionConfig 连接配置
 type ConnectionConfig struct {
-	ID       string            `json:"id" gorm:"primaryKey"`
-	Name     string            `json:"name" gorm:"not null"`
-	Type     MQType            `json:"type" gorm:"not null"`
-	Host     string            `json:"host" gorm:"not null"`
-	Port     int               `json:"port" gorm:"not null"`
-	Username string            `json:"username"`
-	Password string            `json:"password"`
-	VHost    string            `json:"vhost"`    // RabbitMQ virtual host
-	GroupID  string            `json:"group_id"` // Kafka consumer group
-	Extra    map[string]string `json:"extra" gorm:"serializer:json"`
-	Created  time.Time         `json:"created" gorm:"autoCreateTime"`
-	Updated  time.Time         `json:"updated" gorm:"autoUpdateTime"`
+	ID           string            `json:"id" gorm:"primaryKey"`
+	Name         string            `json:"name" gorm:"not null"`
+	Type         MQType            `json:"type" gorm:"not null"`
+	Host         string            `json:"host" gorm:"not null"`
+	Port         int               `json:"port" gorm:"not null"`
+	Endpoints    []string          `json:"endpoints" gorm:"serializer:json"` // 其他节点 host:port：Kafka bootstrap、RocketMQ NameServer、RabbitMQ 集群节点
+	Username     string            `json:"username"`
+	Password     string            `json:"password"`
+	VHost        string            `json:"vhost"`         // RabbitMQ virtual host；AMQP 1.0 open 帧中的 hostname
+	GroupID      string            `json:"group_id"`      // Kafka consumer group
+	Region       string            `json:"region"`        // AWS 区域（SQS），为空时使用 us-east-1
+	AccessKey    string            `json:"access_key"`    // AWS 访问密钥 ID
+	SecretKey    string            `json:"secret_key"`    // AWS 秘密访问密钥
+	SessionToken string            `json:"session_token"` // AWS 临时凭证的会话令牌
+	Extra        map[string]string `json:"extra" gorm:"serializer:json"`
+	Tunnel       *TunnelConfig     `json:"tunnel,omitempty" gorm:"serializer:json"`   // SSH 跳板机或代理，为空时直连
+	Timeouts     *TimeoutConfig    `json:"timeouts,omitempty" gorm:"serializer:json"` // 为空或字段为 0 时使用全局配置
+	Created      time.Time         `json:"created" gorm:"autoCreateTime"`
+	Updated      time.Time         `json:"updated" gorm:"autoUpdateTime"`
+
+	// 连接时用激活环境的变量展开主机、其他节点和隧道主机；认证信息（用户名、密码、vhost、AWS 区域和密钥）
+	// 可能本身含有 {{，只在同时开启 ResolveCredentials 时展开
+	ResolveVariables   bool `json:"resolve_variables"`
+	ResolveCredentials bool `json:"resolve_credentials"`
+}
+
+// TunnelType 隧道类型
+type TunnelType string
+
+const (
+	TunnelDirect TunnelType = ""       // 直连，仅应用地址映射
+	TunnelSSH    TunnelType = "ssh"    // SSH 跳板机
+	TunnelSOCKS5 TunnelType = "socks5" // SOCKS5 代理
+	TunnelHTTP   TunnelType = "http"   // HTTP CONNECT 代理
+)
+
+// TunnelConfig 连接使用的隧道配置
+type TunnelConfig struct {
+	Type           TunnelType `json:"type"`
+	Host           string     `json:"host"`
+	Port           int        `json:"port"`
+	Username       string     `json:"username"`
+	Password       string     `json:"password"`
+	PrivateKey     string     `json:"private_key"`      // SSH 私钥内容（PEM），优先于 PrivateKeyPath
+	PrivateKeyPath string     `json:"private_key_path"` // SSH 私钥文件
+	Passphrase     string     `json:"passphrase"`       // SSH 私钥口令
+	UseAgent       bool       `json:"use_agent"`        // 使用 SSH_AUTH_SOCK 指向的 ssh-agent
+	KnownHostsPath string     `json:"known_hosts_path"` // 为空时使用 ~/.ssh/known_hosts
+	// InsecureIgnoreHostKey 跳过跳板机主机密钥校验，仅用于测试环境
+	InsecureIgnoreHostKey bool `json:"insecure_ignore_host_key"`
+	// AddressMap 将 broker 公告的地址（host:port）改写为经隧道可达的地址
+	AddressMap map[string]string `json:"address_map,omitempty"`
+}
+
+// 未配置时使用的默认超时
+const (
+	DefaultDialTimeout    = 10 * time.Second
+	DefaultRequestTimeout = 30 * time.Second
+	DefaultProduceTimeout = 30 * time.Second
+)
+
+// TimeoutConfig 超时设置，单位毫秒，0 表示使用上一级配置
+type TimeoutConfig struct {
+	Dial    int64 `json:"dial,omitempty"`    // 建立连接，包括隧道、握手和认证
+	Request int64 `json:"request,omitempty"` // 管理操作，如测试连接、列出和创建主题
+	Produce int64 `json:"produce,omitempty"` // 发送单条或一批消息
+}
+
+// WithDefaults 返回用 defaults 补全未设置字段后的副本，t 可以为 nil
+func (t *TimeoutConfig) WithDefaults(defaults TimeoutConfig) *TimeoutConfig {
+	merged := defaults
+	if t != nil {
+		if t.Dial > 0 {
+			merged.Dial = t.Dial
+		}
+		if t.Request > 0 {
+			merged.Request = t.Request
+		}
+		if t.Produce > 0 {
+			merged.Produce = t.Produce
+		}
+	}
+	return &merged
+}
+
+// DialTimeout 建立连接的超时
+func (t *TimeoutConfig) DialTimeout() time.Duration {
+	if t == nil || t.Dial <= 0 {
+		return DefaultDialTimeout
+	}
+	return time.Duration(t.Dial) * time.Millisecond
+}
+
+// RequestTimeout 管理操作的超时
+func (t *TimeoutConfig) RequestTimeout() time.Duration {
+	if t == nil || t.Request <= 0 {
+		return DefaultRequestTimeout
+	}
+	return time.Duration(t.Request) * time.Millisecond
+}
+
+// ProduceTimeout 发送消息的超时
+func (t *TimeoutConfig) ProduceTimeout() time.Duration {
+	if t == nil || t.Produce <= 0 {
+		return DefaultProduceTimeout
+	}
+	return time.Duration(t.Produce) * time.Millisecond
+}
+
+// Addresses 返回全部节点地址，主地址在前，其后依次为 Endpoints 和旧版本的 Extra["brokers"]，已去重
+//
+// 未写端口的节点使用主地址的端口，无法解析的节点被忽略。
+func (c *ConnectionConfig) Addresses() []string {
+	var addresses []string
+	seen := make(map[string]bool)
+	add := func(list string) {
+		endpoints, _ := utils.ParseEndpoints(list, c.Port)
+		for _, endpoint := range endpoints {
+			if address := endpoint.String(); !seen[address] {
+				seen[address] = true
+				addresses = append(addresses, address)
+			}
+		}
+	}
+
+	if c.Host != "" {
+		add(utils.Endpoint{Host: c.Host, Port: c.Port}.String())
+	}
+	for _, endpoint := range c.Endpoints {
+		add(endpoint)
+	}
+	add(c.Extra["brokers"])
+	return addresses
 }
 
 // Message 消息结构
@@ -40,6 +254,8 @@ type Message struct {
 	Partition int32             `json:"partition"`
 	Offset    int64             `json:"offset"`
 	Timestamp time.Time         `json:"timestamp"`
+	// SubscriptionID 接收该消息的订阅，仅在消费事件中设置
+	SubscriptionID string `json:"subscription_id,omitempty"`
 }
 
 // ProduceRequest 生产消息请求
@@ -59,6 +275,12 @@ type ConsumeRequest struct {
 	GroupID       string   `json:"group_id"`
 	AutoCommit    bool     `json:"auto_commit"`
 	FromBeginning bool     `json:"from_beginning"`
+	// StartSequence、StartTime 从指定序号或时间开始重放，优先于 FromBeginning；
+	// NATS JetStream 支持两者，Redis Streams、Pulsar 和内存 broker 支持 StartTime
+	StartSequence uint64     `json:"start_sequence,omitempty"`
+	StartTime     *time.Time `json:"start_time,omitempty"`
+	// StartMessageID Pulsar 不指定订阅时从该消息 ID（ledger:entry[:partition[:batch]]）开始浏览，包含该消息
+	StartMessageID string `json:"start_message_id,omitempty"`
 }
 
 // TestResult 测试结果
@@ -68,11 +290,32 @@ type TestResult struct {
 	Latency int64  `json:"latency"` // 延迟（毫秒）
 }
 
+// ConnectionState 连接健康状态
+type ConnectionState string
+
+const (
+	ConnectionStateConnected    ConnectionState = "connected"
+	ConnectionStateReconnecting ConnectionState = "reconnecting"
+	ConnectionStateDisconnected ConnectionState = "disconnected"
+)
+
+// ConnectionStatus 连接或订阅的健康状态，状态变化时通过 connection:status 事件推送
+type ConnectionStatus struct {
+	ConnectionID   string          `json:"connection_id"`
+	SubscriptionID string          `json:"subscription_id,omitempty"` // 为空表示缓存的生产者/管理客户端
+	State          ConnectionState `json:"state"`
+	Error          string          `json:"error,omitempty"`
+	Attempt        int             `json:"attempt,omitempty"`    // 当前重连次数
+	NextRetry      *time.Time      `json:"next_retry,omitempty"` // 下次重连时间
+	Latency        int64           `json:"latency,omitempty"`    // 最近一次探测延迟（毫秒）
+	CheckedAt      time.Time       `json:"checked_at"`
+}
+
 // HistoryRecord represents a single entry in the operation history
 type HistoryRecord struct {
 	ID           string    `gorm:"primaryKey" json:"id"`
 	ConnectionID string    `json:"connection_id"`
-	Type         string    `json:"type"` // "produce", "consume", "test_connection"
+	Type         string    `json:"type"` // 由 HistoryService 的 AddXxxRecord 写入："produce", "consume", "test_connection", "replay", "bridge", "benchmark"
 	Topic        string    `json:"topic"`
 	Success      bool      `json:"success"`
 	Message      string    `json:"message"`
@@ -80,13 +323,444 @@ type HistoryRecord struct {
 	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created"`
 }
 
-// MessageTemplate represents a reusable message template
+// MessageTemplate represents a reusable message template, saving a full produce request
 type MessageTemplate struct {
-	ID        string    `gorm:"primaryKey" json:"id"`
-	Name      string    `json:"name"`
-	Content   string    `json:"content"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+	ID           string            `gorm:"primaryKey" json:"id"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	CollectionID string            `gorm:"index" json:"collectionId"` // 为空表示不属于任何集合
+	Tags         []string          `gorm:"serializer:json" json:"tags"`
+	ConnectionID string            `json:"connectionId"`
+	Topic        string            `json:"topic"`
+	Key          string            `json:"key"`
+	Headers      map[string]string `gorm:"serializer:json" json:"headers"`
+	Partition    *int32            `json:"partition,omitempty"`
+	Content      string            `json:"content"`
+	Variables    map[string]string `gorm:"serializer:json" json:"variables"` // 渲染时的默认变量
+	CreatedAt    time.Time         `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt    time.Time         `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// TemplateCollection 模板集合，ParentID 非空时为子文件夹
+type TemplateCollection struct {
+	ID          string    `gorm:"primaryKey" json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ParentID    string    `gorm:"index" json:"parentId"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// CollectionExportVersion 集合导出文件的格式版本
+const CollectionExportVersion = 1
+
+// CollectionExport 集合导出文件内容，包含集合及其子文件夹和模板
+type CollectionExport struct {
+	Version     int                   `json:"version"`
+	ExportedAt  time.Time             `json:"exportedAt"`
+	Collections []*TemplateCollection `json:"collections"`
+	Templates   []*MessageTemplate    `json:"templates"`
+}
+
+// CollectionImportResult 集合导入结果
+type CollectionImportResult struct {
+	FilePath    string `json:"filePath"`
+	Collections int    `json:"collections"`
+	Templates   int    `json:"templates"`
+}
+
+// SecretMask 返回给前端的密文占位符，提交时原样传回表示保留原值
+const SecretMask = "********"
+
+// BundleVersion 共享包的格式版本
+const BundleVersion = 1
+
+// BundleSecretMode 导出共享包时凭据的处理方式
+type BundleSecretMode string
+
+const (
+	BundleSecretStrip   BundleSecretMode = "strip"   // 移除密码等凭据
+	BundleSecretEncrypt BundleSecretMode = "encrypt" // 使用导出口令加密凭据
+)
+
+// BundleEncryption 共享包中凭据的加密参数，导入时用口令重新派生密钥
+type BundleEncryption struct {
+	Salt     string `json:"salt"`
+	Time     uint32 `json:"time"`
+	Memory   uint32 `json:"memory"`
+	Threads  uint8  `json:"threads"`
+	Verifier string `json:"verifier"`
+}
+
+// Bundle 连接、模板和环境的共享包
+type Bundle struct {
+	Version      int                   `json:"version"`
+	ExportedAt   time.Time             `json:"exported_at"`
+	SecretMode   BundleSecretMode      `json:"secret_mode"`
+	Encryption   *BundleEncryption     `json:"encryption,omitempty"`
+	Connections  []*ConnectionConfig   `json:"connections,omitempty"`
+	Collections  []*TemplateCollection `json:"collections,omitempty"`
+	Templates    []*MessageTemplate    `json:"templates,omitempty"`
+	Environments []*Environment        `json:"environments,omitempty"`
+}
+
+// BundleExportRequest 导出共享包请求，ID 列表为空表示不导出该类内容
+type BundleExportRequest struct {
+	ConnectionIDs  []string         `json:"connection_ids"`
+	TemplateIDs    []string         `json:"template_ids"`
+	CollectionIDs  []string         `json:"collection_ids"` // 包含集合下的文件夹和模板
+	EnvironmentIDs []string         `json:"environment_ids"`
+	SecretMode     BundleSecretMode `json:"secret_mode"` // 默认 strip
+	Passphrase     string           `json:"passphrase"`  // SecretMode 为 encrypt 时必填
+	Format         string           `json:"format"`      // json 或 yaml，默认 json
+}
+
+// ImportConflictMode 导入时名称或ID冲突的处理方式
+type ImportConflictMode string
+
+const (
+	ImportConflictSkip      ImportConflictMode = "skip"
+	ImportConflictRename    ImportConflictMode = "rename"
+	ImportConflictOverwrite ImportConflictMode = "overwrite"
+)
+
+// BundleImportRequest 导入共享包请求
+type BundleImportRequest struct {
+	FilePath   string             `json:"file_path"`
+	Passphrase string             `json:"passphrase"` // 共享包凭据已加密时必填
+	Conflict   ImportConflictMode `json:"conflict"`   // 默认 skip
+}
+
+// ImportItemResult 单个导入项的处理结果
+type ImportItemResult struct {
+	Kind    string `json:"kind"` // connection、collection、template、environment
+	Name    string `json:"name"`
+	Action  string `json:"action"` // created、skipped、renamed、overwritten
+	NewName string `json:"new_name,omitempty"`
+}
+
+// BundleImportResult 共享包导入结果
+type BundleImportResult struct {
+	FilePath string              `json:"file_path"`
+	Items    []*ImportItemResult `json:"items"`
+}
+
+// SecretStatus 凭据加密状态
+type SecretStatus struct {
+	Locked         bool `json:"locked"`          // 使用主密码且尚未解锁
+	UsesPassphrase bool `json:"uses_passphrase"` // false 表示使用本地密钥文件
+}
+
+// EnvironmentVariable 环境变量
+type EnvironmentVariable struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Secret bool   `json:"secret"` // 列表中以 SecretMask 显示
+}
+
+// Environment 命名环境，激活后其变量用于渲染模板、主题名、消息头以及连接地址
+type Environment struct {
+	ID          string                `gorm:"primaryKey" json:"id"`
+	Name        string                `gorm:"uniqueIndex" json:"name"`
+	Description string                `json:"description"`
+	Variables   []EnvironmentVariable `gorm:"serializer:json" json:"variables"`
+	Active      bool                  `json:"active"`
+	CreatedAt   time.Time             `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time             `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// StoredMessage 持久化的消费消息，保留完整的 Message 字段
+//
+// ID 是存储时生成的记录ID；同一条消息可能被多个订阅或重复投递多次消费，broker 的消息ID保存在 MessageID 中。
+type StoredMessage struct {
+	ID             string            `gorm:"primaryKey" json:"id"`
+	MessageID      string            `gorm:"index" json:"message_id"`
+	SubscriptionID string            `gorm:"index" json:"subscription_id"`
+	ConnectionID   string            `gorm:"index" json:"connection_id"`
+	Topic          string            `gorm:"index" json:"topic"`
+	Key            string            `gorm:"index" json:"key"`
+	Value          string            `json:"value"`
+	Headers        map[string]string `gorm:"serializer:json" json:"headers"`
+	Partition      int32             `json:"partition"`
+	Offset         int64             `json:"offset"`
+	Timestamp      time.Time         `gorm:"index" json:"timestamp"`
+	CreatedAt      time.Time         `gorm:"autoCreateTime;index" json:"created"`
+}
+
+// ToMessage 转换为消息结构
+func (m *StoredMessage) ToMessage() *Message {
+	return &Message{
+		ID:        m.MessageID,
+		Topic:     m.Topic,
+		Key:       m.Key,
+		Value:     m.Value,
+		Headers:   m.Headers,
+		Partition: m.Partition,
+		Offset:    m.Offset,
+		Timestamp: m.Timestamp,
+	}
+}
+
+// MessageQuery 已存储消息的查询条件，空字段表示不过滤
+type MessageQuery struct {
+	ConnectionID   string     `json:"connection_id"`
+	SubscriptionID string     `json:"subscription_id"`
+	Topic          string     `json:"topic"`
+	Key            string     `json:"key"`
+	HeaderKey      string     `json:"header_key"`
+	HeaderValue    string     `json:"header_value"`
+	Start          *time.Time `json:"start,omitempty"`
+	End            *time.Time `json:"end,omitempty"`
+	Search         string     `json:"search"` // 全文检索 key/value/headers
+	Limit          int        `json:"limit"`
+	Offset         int        `json:"offset"`
+}
+
+// MessageSearchResult 消息查询结果
+type MessageSearchResult struct {
+	Total    int64            `json:"total"`
+	Messages []*StoredMessage `json:"messages"`
+}
+
+// MessageFormat 消息导入导出格式
+type MessageFormat string
+
+const (
+	MessageFormatNDJSON MessageFormat = "ndjson"
+	MessageFormatCSV    MessageFormat = "csv"
+	MessageFormatKcat   MessageFormat = "kcat" // key<分隔符>value，兼容 kcat -K 与 kafka-console-producer
+)
+
+// ExportRequest 导出消息请求
+type ExportRequest struct {
+	Query     MessageQuery  `json:"query"`
+	Format    MessageFormat `json:"format"`
+	Delimiter string        `json:"delimiter"` // kcat 格式的键值分隔符，默认为制表符
+}
+
+// ImportRequest 导入消息请求，读取文件后批量发送到目标连接
+type ImportRequest struct {
+	FilePath          string            `json:"file_path"`
+	Format            MessageFormat     `json:"format"`
+	Delimiter         string            `json:"delimiter"`
+	ConnectionID      string            `json:"connection_id"`
+	Topic             string            `json:"topic"`     // 目标主题，为空时保留文件中的主题
+	TopicMap          map[string]string `json:"topic_map"` // 原主题 -> 目标主题，优先于 Topic
+	KeyMap            map[string]string `json:"key_map"`   // 原键 -> 目标键
+	PreservePartition bool              `json:"preserve_partition"`
+	BatchSize         int               `json:"batch_size"`
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	FilePath string `json:"file_path"`
+	Total    int    `json:"total"`
+	Produced int    `json:"produced"`
+	Latency  int64  `json:"latency"`
+}
+
+// RenderRequest 模板渲染预览请求，Content 为空时使用 TemplateID 对应的模板
+type RenderRequest struct {
+	TemplateID string            `json:"template_id"`
+	Content    string            `json:"content"`
+	Variables  map[string]string `json:"variables"`
+	Count      int               `json:"count"`     // 渲染条数，默认 1
+	SeqStart   int64             `json:"seq_start"` // {{seq}} 的起始值，默认 1
+}
+
+// RenderProduceRequest 按模板渲染并发送 Count 条消息，Topic、Key 和 Headers 的值同样按模板渲染
+type RenderProduceRequest struct {
+	ConnectionID string            `json:"connection_id"`
+	TemplateID   string            `json:"template_id"`
+	Content      string            `json:"content"`
+	Variables    map[string]string `json:"variables"`
+	Count        int               `json:"count"`
+	SeqStart     int64             `json:"seq_start"`
+	Topic        string            `json:"topic"`
+	Key          string            `json:"key"`
+	Headers      map[string]string `json:"headers"`
+	Partition    *int32            `json:"partition,omitempty"`
+	BatchSize    int               `json:"batch_size"`
+}
+
+// RenderProduceResult 模板批量发送结果
+type RenderProduceResult struct {
+	Total    int   `json:"total"`
+	Produced int   `json:"produced"`
+	Latency  int64 `json:"latency"`
+}
+
+// TopicTransform 主题名转换规则，按 Replace -> Prefix/Suffix 的顺序应用
+type TopicTransform struct {
+	Replace string `json:"replace"` // 要替换的子串
+	With    string `json:"with"`
+	Prefix  string `json:"prefix"`
+	Suffix  string `json:"suffix"`
+}
+
+// Apply 对主题名应用转换
+func (t *TopicTransform) Apply(topic string) string {
+	if t == nil {
+		return topic
+	}
+	if t.Replace != "" {
+		topic = strings.ReplaceAll(topic, t.Replace, t.With)
+	}
+	return t.Prefix + topic + t.Suffix
+}
+
+// ReplayRequest 消息重放请求，消息来源三选一：MessageIDs、Query 或 CaptureFile
+type ReplayRequest struct {
+	MessageIDs      []string        `json:"message_ids"`
+	Query           *MessageQuery   `json:"query,omitempty"`
+	CaptureFile     string          `json:"capture_file"` // 导出的消息文件
+	CaptureFormat   MessageFormat   `json:"capture_format"`
+	ConnectionID    string          `json:"connection_id"` // 目标连接
+	Topic           string          `json:"topic"`         // 目标主题，为空时使用转换后的原主题
+	TopicTransform  *TopicTransform `json:"topic_transform,omitempty"`
+	PreserveKeys    bool            `json:"preserve_keys"`
+	PreserveHeaders bool            `json:"preserve_headers"`
+	PreserveTiming  bool            `json:"preserve_timing"` // 按原消息的时间间隔发送
+	RatePerSecond   float64         `json:"rate_per_second"` // 固定速率，0 表示不限速
+}
+
+// ReplayState 重放任务状态
+type ReplayState string
+
+const (
+	ReplayStateRunning   ReplayState = "running"
+	ReplayStateCompleted ReplayState = "completed"
+	ReplayStateCancelled ReplayState = "cancelled"
+	ReplayStateFailed    ReplayState = "failed"
+)
+
+// ReplayStatus 重放任务进度
+type ReplayStatus struct {
+	ID           string      `json:"id"`
+	ConnectionID string      `json:"connection_id"`
+	State        ReplayState `json:"state"`
+	Total        int         `json:"total"`
+	Sent         int         `json:"sent"`
+	Failed       int         `json:"failed"`
+	Error        string      `json:"error,omitempty"`
+	StartedAt    time.Time   `json:"started_at"`
+	FinishedAt   *time.Time  `json:"finished_at,omitempty"`
+}
+
+// BridgeRequest 跨连接桥接请求，从源连接消费并发送到目标连接
+type BridgeRequest struct {
+	Name               string            `json:"name"`
+	SourceConnectionID string            `json:"source_connection_id"`
+	SourceTopics       []string          `json:"source_topics"`
+	GroupID            string            `json:"group_id"`
+	FromBeginning      bool              `json:"from_beginning"`
+	TargetConnectionID string            `json:"target_connection_id"`
+	TargetTopic        string            `json:"target_topic"` // 为空时使用转换后的源主题
+	TopicTransform     *TopicTransform   `json:"topic_transform,omitempty"`
+	KeyFrom            string            `json:"key_from"`   // 空值保留原键，"none" 清空，"header:<名称>" 取自消息头
+	HeaderMap          map[string]string `json:"header_map"` // 源头名称 -> 目标头名称，目标为空表示丢弃
+	SetHeaders         map[string]string `json:"set_headers"`
+	Filter             string            `json:"filter"` // 过滤表达式，见 internal/filter
+	RatePerSecond      float64           `json:"rate_per_second"`
+	MaxRetries         int               `json:"max_retries"` // 目标发送失败的重试次数
+}
+
+// BridgeState 桥接任务状态
+type BridgeState string
+
+const (
+	BridgeStateRunning BridgeState = "running"
+	BridgeStatePaused  BridgeState = "paused"
+	BridgeStateStopped BridgeState = "stopped"
+	BridgeStateFailed  BridgeState = "failed"
+)
+
+// BridgeStatus 桥接任务状态与吞吐计数
+type BridgeStatus struct {
+	ID                 string      `json:"id"`
+	Name               string      `json:"name"`
+	SourceConnectionID string      `json:"source_connection_id"`
+	SourceTopics       []string    `json:"source_topics"`
+	TargetConnectionID string      `json:"target_connection_id"`
+	TargetTopic        string      `json:"target_topic"`
+	State              BridgeState `json:"state"`
+	Consumed           int64       `json:"consumed"`
+	Produced           int64       `json:"produced"`
+	Filtered           int64       `json:"filtered"`
+	Failed             int64       `json:"failed"`
+	Rate               float64     `json:"rate"` // 最近一秒的发送速率（条/秒）
+	Error              string      `json:"error,omitempty"`
+	StartedAt          time.Time   `json:"started_at"`
+}
+
+// BenchmarkMode 压测模式
+type BenchmarkMode string
+
+const (
+	BenchmarkModeProduce BenchmarkMode = "produce"
+	BenchmarkModeConsume BenchmarkMode = "consume" // 依据发送端写入的时间戳头计算端到端延迟
+)
+
+// BenchmarkState 压测状态
+type BenchmarkState string
+
+const (
+	BenchmarkStateRunning   BenchmarkState = "running"
+	BenchmarkStateCompleted BenchmarkState = "completed"
+	BenchmarkStateCancelled BenchmarkState = "cancelled"
+	BenchmarkStateFailed    BenchmarkState = "failed"
+)
+
+// BenchmarkSentAtHeader 压测消息中记录发送时间（Unix 纳秒）的消息头
+const BenchmarkSentAtHeader = "x-mqtk-sent-at"
+
+// BenchmarkRequest 压测请求，MessageCount 与 DurationSeconds 至少设置一个
+type BenchmarkRequest struct {
+	Mode            BenchmarkMode     `json:"mode"`
+	ConnectionID    string            `json:"connection_id"`
+	Topic           string            `json:"topic"`
+	GroupID         string            `json:"group_id"` // 消费模式使用
+	MessageCount    int               `json:"message_count"`
+	DurationSeconds int               `json:"duration_seconds"`
+	RatePerSecond   float64           `json:"rate_per_second"` // 0 表示尽可能快
+	Concurrency     int               `json:"concurrency"`
+	PayloadSize     int               `json:"payload_size"`     // 随机消息体的字节数
+	Payload         string            `json:"payload"`          // 固定消息体，优先于 PayloadSize
+	Template        string            `json:"template"`         // 消息体模板，每条消息单独渲染，优先于 Payload
+	Variables       map[string]string `json:"variables"`        // 模板变量
+	KeyDistribution string            `json:"key_distribution"` // none、fixed、sequential、random
+	Key             string            `json:"key"`              // fixed 分布使用的键
+	KeyCount        int               `json:"key_count"`        // sequential/random 分布的键数量
+	Headers         map[string]string `json:"headers"`
+}
+
+// BenchmarkStats 压测统计，延迟单位为毫秒
+type BenchmarkStats struct {
+	Messages   int64   `json:"messages"`
+	Errors     int64   `json:"errors"`
+	Bytes      int64   `json:"bytes"`
+	ElapsedMs  int64   `json:"elapsed_ms"`
+	MsgsPerSec float64 `json:"msgs_per_sec"`
+	MBPerSec   float64 `json:"mb_per_sec"`
+	LatencyP50 float64 `json:"latency_p50"`
+	LatencyP95 float64 `json:"latency_p95"`
+	LatencyP99 float64 `json:"latency_p99"`
+	LatencyMax float64 `json:"latency_max"`
+}
+
+// BenchmarkReport 压测报告
+type BenchmarkReport struct {
+	ID           string           `gorm:"primaryKey" json:"id"`
+	Mode         BenchmarkMode    `json:"mode"`
+	ConnectionID string           `gorm:"index" json:"connection_id"`
+	Topic        string           `json:"topic"`
+	State        BenchmarkState   `json:"state"`
+	Error        string           `json:"error,omitempty"`
+	Request      BenchmarkRequest `gorm:"serializer:json" json:"request"`
+	Stats        BenchmarkStats   `gorm:"serializer:json" json:"stats"`
+	StartedAt    time.Time        `json:"started_at"`
+	FinishedAt   *time.Time       `json:"finished_at,omitempty"`
+	CreatedAt    time.Time        `gorm:"autoCreateTime" json:"created"`
 }
 
 // TableName for HistoryRecord
@@ -99,6 +773,26 @@ func (MessageTemplate) TableName() string {
 	return "message_templates"
 }
 
+// TableName for TemplateCollection
+func (TemplateCollection) TableName() string {
+	return "template_collections"
+}
+
+// TableName for Environment
+func (Environment) TableName() string {
+	return "environments"
+}
+
+// TableName for BenchmarkReport
+func (BenchmarkReport) TableName() string {
+	return "benchmark_reports"
+}
+
+// TableName for StoredMessage
+func (StoredMessage) TableName() string {
+	return "consumed_messages"
+}
+
 // LogEntry 日志条目
 type LogEntry struct {
 	Level     string                 `json:"level"`
@@ -122,6 +816,200 @@ type ConsumerGroup struct {
 	Topics  []string `json:"topics"`
 }
 
+// SeekGroupRequest 移动消费组位置请求
+type SeekGroupRequest struct {
+	ConnectionID string    `json:"connection_id"`
+	Group        string    `json:"group"`
+	Topic        string    `json:"topic"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// BrowseRequest 查看消息请求，不确认、不删除消息，也不提交位置
+type BrowseRequest struct {
+	ConnectionID string `json:"connection_id"`
+	Topic        string `json:"topic"`
+	Limit        int    `json:"limit"` // 最多返回的消息数，0 使用默认值
+}
+
+// StreamInfo Redis 流详情
+type StreamInfo struct {
+	Name            string            `json:"name"`
+	Length          int64             `json:"length"`
+	FirstEntryID    string            `json:"first_entry_id"`
+	LastEntryID     string            `json:"last_entry_id"`
+	LastGeneratedID string            `json:"last_generated_id"`
+	Groups          []StreamGroupInfo `json:"groups"`
+}
+
+// StreamGroupInfo 流上的消费组
+type StreamGroupInfo struct {
+	Name            string               `json:"name"`
+	Pending         int64                `json:"pending"`           // 已投递未确认的消息数
+	LastDeliveredID string               `json:"last_delivered_id"` // 最后投递的消息 ID
+	Lag             int64                `json:"lag"`               // 未投递的消息数，-1 表示 broker 无法计算
+	Consumers       []StreamConsumerInfo `json:"consumers"`
+}
+
+// StreamConsumerInfo 流消费组中的消费者
+type StreamConsumerInfo struct {
+	Name    string `json:"name"`
+	Pending int64  `json:"pending"`
+	Idle    int64  `json:"idle"` // 空闲时间（毫秒）
+}
+
+// PendingEntry 已投递未确认的流消息
+type PendingEntry struct {
+	ID            string `json:"id"`
+	Consumer      string `json:"consumer"`
+	Idle          int64  `json:"idle"` // 距上次投递的时间（毫秒）
+	DeliveryCount int64  `json:"delivery_count"`
+}
+
+// StreamGroupRequest 创建或删除流消费组请求
+type StreamGroupRequest struct {
+	ConnectionID string `json:"connection_id"`
+	Stream       string `json:"stream"`
+	Group        string `json:"group"`
+	StartID      string `json:"start_id"` // 创建时的起始 ID："$" 只消费新消息（默认），"0" 从头消费
+}
+
+// TrimStreamRequest 裁剪流请求
+type TrimStreamRequest struct {
+	ConnectionID string `json:"connection_id"`
+	Stream       string `json:"stream"`
+	MaxLen       int64  `json:"max_len"` // 保留的最大消息数
+}
+
+// ClaimPendingRequest 将未确认消息转移给指定消费者的请求
+type ClaimPendingRequest struct {
+	ConnectionID string   `json:"connection_id"`
+	Stream       string   `json:"stream"`
+	Group        string   `json:"group"`
+	Consumer     string   `json:"consumer"`
+	MinIdle      int64    `json:"min_idle"` // 只转移空闲超过该时间（毫秒）的消息
+	IDs          []string `json:"ids"`
+}
+
+// SubscriptionInfo Pulsar 主题上的订阅
+type SubscriptionInfo struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`      // Exclusive、Shared、Failover 或 Key_Shared
+	Backlog   int64    `json:"backlog"`   // 积压的消息数
+	Consumers []string `json:"consumers"` // 当前连接的消费者名称
+}
+
+// SubscriptionRequest 删除订阅或清空积压请求
+type SubscriptionRequest struct {
+	ConnectionID string `json:"connection_id"`
+	Topic        string `json:"topic"`
+	Subscription string `json:"subscription"`
+}
+
+// ResetSubscriptionRequest 将订阅的游标重置到指定时间的请求
+type ResetSubscriptionRequest struct {
+	ConnectionID string    `json:"connection_id"`
+	Topic        string    `json:"topic"`
+	Subscription string    `json:"subscription"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// NamespaceRequest 创建或删除命名空间请求
+type NamespaceRequest struct {
+	ConnectionID string `json:"connection_id"`
+	Namespace    string `json:"namespace"` // 租户/命名空间
+}
+
+// AddressInfo AMQP 1.0 broker（ActiveMQ Artemis）上的地址
+type AddressInfo struct {
+	Name         string   `json:"name"`
+	RoutingTypes []string `json:"routing_types"` // ANYCAST、MULTICAST
+	QueueCount   int      `json:"queue_count"`
+}
+
+// QueueInfo AMQP 1.0 broker（ActiveMQ Artemis）上的队列
+type QueueInfo struct {
+	Name          string `json:"name"`
+	Address       string `json:"address"`
+	RoutingType   string `json:"routing_type"`
+	MessageCount  int64  `json:"message_count"`
+	ConsumerCount int    `json:"consumer_count"`
+	Durable       bool   `json:"durable"`
+}
+
+// CreateAddressRequest 创建地址请求
+type CreateAddressRequest struct {
+	ConnectionID string `json:"connection_id"`
+	Address      string `json:"address"`
+	RoutingType  string `json:"routing_type"` // ANYCAST 或 MULTICAST
+}
+
+// CreateQueueRequest 创建队列请求
+type CreateQueueRequest struct {
+	ConnectionID string `json:"connection_id"`
+	Address      string `json:"address"`
+	Queue        string `json:"queue"`
+	RoutingType  string `json:"routing_type"` // ANYCAST 或 MULTICAST
+	Durable      bool   `json:"durable"`
+}
+
+// QueueAttributes SQS 队列属性
+type QueueAttributes struct {
+	Name       string            `json:"name"`
+	URL        string            `json:"url"`
+	ARN        string            `json:"arn"`
+	FIFO       bool              `json:"fifo"`
+	Messages   int64             `json:"messages"`   // 可接收的消息数（近似值）
+	InFlight   int64             `json:"in_flight"`  // 已接收但未删除的消息数（近似值）
+	Delayed    int64             `json:"delayed"`    // 延迟中的消息数（近似值）
+	Attributes map[string]string `json:"attributes"` // 全部原始属性
+	Redrive    *RedrivePolicy    `json:"redrive"`    // 死信队列策略，未配置时为空
+}
+
+// RedrivePolicy SQS 死信队列策略，消息接收次数超过 MaxReceiveCount 后移入 DeadLetterTargetARN
+type RedrivePolicy struct {
+	DeadLetterTargetARN string `json:"dead_letter_target_arn"`
+	MaxReceiveCount     int    `json:"max_receive_count"`
+}
+
+// SetQueueAttributesRequest 修改队列属性请求
+type SetQueueAttributesRequest struct {
+	ConnectionID string            `json:"connection_id"`
+	Queue        string            `json:"queue"`
+	Attributes   map[string]string `json:"attributes"`
+}
+
+// ExchangeInfo RabbitMQ 交换机
+type ExchangeInfo struct {
+	Name       string `json:"name"`
+	Kind       string `json:"kind"` // direct、fanout、topic、headers
+	Durable    bool   `json:"durable"`
+	AutoDelete bool   `json:"auto_delete"`
+	Internal   bool   `json:"internal"`
+}
+
+// BindingInfo 交换机到队列的绑定
+type BindingInfo struct {
+	Exchange   string `json:"exchange"`
+	Queue      string `json:"queue"`
+	RoutingKey string `json:"routing_key"`
+}
+
+// CreateExchangeRequest 创建交换机请求
+type CreateExchangeRequest struct {
+	ConnectionID string `json:"connection_id"`
+	Exchange     string `json:"exchange"`
+	Kind         string `json:"kind"`
+	Durable      bool   `json:"durable"`
+}
+
+// BindingRequest 绑定或解绑队列请求
+type BindingRequest struct {
+	ConnectionID string `json:"connection_id"`
+	Exchange     string `json:"exchange"`
+	Queue        string `json:"queue"`
+	RoutingKey   string `json:"routing_key"`
+}
+
 // CreateTopicRequest 创建主题请求
 type CreateTopicRequest struct {
 	ConnectionID string `json:"connection_id"`
@@ -153,6 +1041,7 @@ type HistoryService interface {
 	AddProduceRecord(ctx context.Context, connectionID, topic string, success bool, message string, latency int64) error
 	AddConsumeRecord(ctx context.Context, connectionID, topic string, success bool, message string, latency int64) error
 	AddTestRecord(ctx context.Context, connectionID string, success bool, message string, latency int64) error
+	AddReplayRecord(ctx context.Context, connectionID, topic string, success bool, message string, latency int64) error
 	GetRecords(ctx context.Context, limit, offset int) ([]*HistoryRecord, error)
 	ClearRecords(ctx context.Context) error
 }
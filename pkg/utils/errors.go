@@ -1,9 +1,13 @@
 package utils
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"runtime"
 	"strings"
+	"time"
 )
 
 // ErrorType 错误类型
@@ -19,6 +23,7 @@ const (
 	ErrorTypeNetwork      ErrorType = "NETWORK"
 	ErrorTypeConfig       ErrorType = "CONFIG"
 	ErrorTypeSubscription ErrorType = "SUBSCRIPTION"
+	ErrorTypeUnsupported  ErrorType = "UNSUPPORTED"
 )
 
 // AppError 应用错误
@@ -117,6 +122,21 @@ func NewTimeoutError(message string) *AppError {
 	return NewError(ErrorTypeTimeout, "TIMEOUT_001", message)
 }
 
+// WrapTimeout 在 err 由超时引起时返回超时错误，否则原样返回
+//
+// 超时包括 ctx 到达截止时间，以及按 ctx 截止时间设置的连接读写超时。
+func WrapTimeout(ctx context.Context, err error, operation string, timeout time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) ||
+		(errors.As(err, &netErr) && netErr.Timeout()) {
+		return NewTimeoutError(fmt.Sprintf("%s timed out after %s", operation, timeout))
+	}
+	return err
+}
+
 // NewAuthError 创建认证错误
 func NewAuthError(message string) *AppError {
 	return NewError(ErrorTypeAuth, "AUTH_001", message)
@@ -148,6 +168,12 @@ func NewSubscriptionError(message string, cause error) *AppError {
 	return NewErrorWithCause(ErrorTypeSubscription, "SUB_001", message, cause)
 }
 
+// NewUnsupportedError 创建不支持错误，表示连接类型不提供该操作
+func NewUnsupportedError(operation, mqType string) *AppError {
+	return NewErrorWithDetails(ErrorTypeUnsupported, "UNSUP_001",
+		fmt.Sprintf("%s is not supported for this connection type", operation), mqType)
+}
+
 // WrapError 包装错误
 func WrapError(err error, message string) *AppError {
 	if err == nil {
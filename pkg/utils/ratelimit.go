@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter 按固定速率放行的限速器，可被多个 goroutine 共享
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewRateLimiter 创建限速器，perSecond <= 0 表示不限速
+func NewRateLimiter(perSecond float64) *RateLimiter {
+	limiter := &RateLimiter{}
+	if perSecond > 0 {
+		limiter.interval = time.Duration(float64(time.Second) / perSecond)
+	}
+	return limiter
+}
+
+// Wait 阻塞直到可以放行下一次操作，或上下文被取消
+func (r *RateLimiter) Wait(ctx context.Context) error {
+	if r == nil || r.interval <= 0 {
+		return ctx.Err()
+	}
+
+	r.mu.Lock()
+	now := time.Now()
+	if r.next.Before(now) {
+		r.next = now
+	}
+	wait := r.next.Sub(now)
+	r.next = r.next.Add(r.interval)
+	r.mu.Unlock()
+
+	if wait <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -38,7 +38,7 @@ for platform in "${PLATFORMS[@]}"; do
     echo "🔨 构建平台: $platform"
     
     # 构建应用
-    wails build -platform "$platform"
+    wails build -platform "$platform" -tags sqlite_fts5
     
     # 获取平台名称
     platform_name=$(echo "$platform" | tr '/' '-')